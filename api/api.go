@@ -9,7 +9,7 @@ import (
 	"time"
 )
 
-//go:generate mockgen -package api -destination mock.go github.com/evcc-io/evcc/api Charger,ChargeState,PhaseSwitcher,Identifier,Meter,MeterEnergy,Vehicle,ChargeRater,Battery,Tariff,BatteryController
+//go:generate mockgen -package api -destination mock.go github.com/evcc-io/evcc/api Charger,ChargeState,PhaseSwitcher,PhaseReporter,PowerLimiter,CurrentBoundsReporter,Identifier,Meter,MeterEnergy,Vehicle,ChargeRater,Battery,Tariff,BatteryController
 
 // ChargeMode is the charge operation mode. Valid values are off, now, minpv and pv
 type ChargeMode string
@@ -104,6 +104,11 @@ type PhaseVoltages interface {
 	Voltages() (float64, float64, float64, error)
 }
 
+// PhaseReporter provides the number of phases used by the connected vehicle
+type PhaseReporter interface {
+	ActivePhases() (int, error)
+}
+
 // PhasePowers provides signed per-phase power W
 type PhasePowers interface {
 	Powers() (float64, float64, float64, error)
@@ -134,6 +139,11 @@ type CurrentGetter interface {
 	GetMaxCurrent() (float64, error)
 }
 
+// CurrentBoundsReporter provides the charger's hardware min and max current, zero if not limited
+type CurrentBoundsReporter interface {
+	CurrentBounds() (float64, float64, error)
+}
+
 // BatteryController optionally allows to control home battery (dis)charging behaviour
 type BatteryController interface {
 	SetBatteryMode(BatteryMode) error
@@ -153,6 +163,11 @@ type ChargerEx interface {
 	MaxCurrentMillis(current float64) error
 }
 
+// PowerLimiter provides charge power control (W) for chargers controlled by power instead of current, e.g. DC chargers
+type PowerLimiter interface {
+	MaxPower(power float64) error
+}
+
 // PhaseSwitcher provides 1p3p switching
 type PhaseSwitcher interface {
 	Phases1p3p(phases int) error
@@ -163,6 +178,81 @@ type Diagnosis interface {
 	Diagnose()
 }
 
+// ChargerInfo is the charger's identity and diagnostic data. Unknown fields are empty.
+type ChargerInfo struct {
+	Vendor    string `json:"vendor,omitempty"`
+	Model     string `json:"model,omitempty"`
+	Serial    string `json:"serial,omitempty"`
+	Firmware  string `json:"firmware,omitempty"`
+	ErrorCode string `json:"errorCode,omitempty"`
+}
+
+// ChargerInformer provides the charger's identity and diagnostic data
+type ChargerInformer interface {
+	ChargerInfo() (ChargerInfo, error)
+}
+
+// ConnectionMonitor provides the connection state of chargers connecting to evcc, e.g. via ocpp.
+// The last seen time is zero if unknown.
+type ConnectionMonitor interface {
+	Connection() (connected bool, lastSeen time.Time)
+}
+
+// VehicleHealth is the vehicle's auxiliary diagnostic data for display. Unavailable values are zero.
+type VehicleHealth struct {
+	AuxBatteryVoltage float64       `json:"auxBatteryVoltage,omitempty"` // 12V battery voltage (V)
+	AuxBatterySoc     float64       `json:"auxBatterySoc,omitempty"`     // 12V battery soc (%)
+	TirePressure      *TirePressure `json:"tirePressure,omitempty"`
+}
+
+// TirePressure is the vehicle's tire pressure per wheel (bar)
+type TirePressure struct {
+	FrontLeft  float64 `json:"frontLeft"`
+	FrontRight float64 `json:"frontRight"`
+	RearLeft   float64 `json:"rearLeft"`
+	RearRight  float64 `json:"rearRight"`
+}
+
+// VehicleHealthReporter provides the vehicle's auxiliary diagnostic data
+type VehicleHealthReporter interface {
+	Health() (VehicleHealth, error)
+}
+
+// VehicleFuel is the fuel tank data of plug-in hybrid vehicles. Unavailable values are zero.
+type VehicleFuel struct {
+	Level      float64 `json:"level,omitempty"`      // fuel level (%)
+	Range      int64   `json:"range,omitempty"`      // fuel range (km)
+	TotalRange int64   `json:"totalRange,omitempty"` // combined electric and fuel range (km)
+}
+
+// VehicleFuelReporter provides the fuel data of plug-in hybrid vehicles. Soc and range refer to the battery only.
+type VehicleFuelReporter interface {
+	Fuel() (VehicleFuel, error)
+}
+
+// VehicleCable is the charge cable status at the vehicle side, independent of the charger's plug signal.
+// Unavailable values are nil.
+type VehicleCable struct {
+	Plugged *bool `json:"plugged,omitempty"` // cable plugged into the vehicle
+	Locked  *bool `json:"locked,omitempty"`  // cable locked by the vehicle
+}
+
+// VehicleCableReporter provides the charge cable status at the vehicle side
+type VehicleCableReporter interface {
+	Cable() (VehicleCable, error)
+}
+
+// VehicleTemperatures are the temperatures measured by the vehicle (°C). Unavailable values are nil.
+type VehicleTemperatures struct {
+	Outside *float64 `json:"outside,omitempty"` // ambient temperature
+	Cabin   *float64 `json:"cabin,omitempty"`   // interior temperature
+}
+
+// VehicleTemperatureReporter provides the temperatures measured by the vehicle
+type VehicleTemperatureReporter interface {
+	Temperatures() (VehicleTemperatures, error)
+}
+
 // ChargeTimer provides current charge cycle duration
 type ChargeTimer interface {
 	ChargingTime() (time.Duration, error)
@@ -178,11 +268,30 @@ type Identifier interface {
 	Identify() (string, error)
 }
 
+// ChargeTransaction is a charging transaction as recorded by the charger
+type ChargeTransaction struct {
+	ID         string   `json:"id"`
+	IdTag      string   `json:"idTag,omitempty"`
+	External   bool     `json:"external"`             // started at the charger, e.g. by local RFID, instead of by evcc
+	MeterStart *float64 `json:"meterStart,omitempty"` // kWh, nil if the transaction start was not observed
+	MeterStop  *float64 `json:"meterStop,omitempty"`  // kWh, nil while the transaction is running
+}
+
+// ChargeTransactionReporter provides the charger's current or most recent transaction
+type ChargeTransactionReporter interface {
+	ChargeTransaction() (ChargeTransaction, error)
+}
+
 // Authorizer authorizes a charging session by supplying RFID credentials
 type Authorizer interface {
 	Authorize(key string) error
 }
 
+// AuthProber verifies that the persisted authorization is still accepted using a lightweight request
+type AuthProber interface {
+	ProbeAuth() error
+}
+
 // Vehicle represents the EV and it's battery
 type Vehicle interface {
 	Battery
@@ -201,6 +310,17 @@ type VehicleFinishTimer interface {
 	FinishTime() (time.Time, error)
 }
 
+// VehicleDepartureTimer provides the next departure scheduled by the vehicle owner in the vehicle or OEM app
+type VehicleDepartureTimer interface {
+	Departure() (time.Time, error)
+}
+
+// VehicleChargeSchedule provides the earliest charging start permitted by the charging schedule configured in the vehicle or OEM app.
+// Vehicles not delaying charging return zero time.
+type VehicleChargeSchedule interface {
+	ChargeSchedule() (time.Time, error)
+}
+
 // VehicleRange provides the vehicles remaining km range
 type VehicleRange interface {
 	Range() (int64, error)
@@ -226,12 +346,47 @@ type SocLimiter interface {
 	TargetSoc() (float64, error)
 }
 
+// SocLimitController sets the vehicles charge limit
+type SocLimitController interface {
+	SetTargetSoc(soc int) error
+}
+
+// SocSmoother provides the exponential smoothing factor for noisy vehicle soc readings
+type SocSmoother interface {
+	SocSmoothing() float64
+}
+
+// ChargeEfficiencyProvider provides the vehicle's charge efficiency between grid and battery
+type ChargeEfficiencyProvider interface {
+	ChargeEfficiency() float64
+}
+
+// ChargeCurrentLimiter provides the vehicle's min and max charge current, zero if not limited
+type ChargeCurrentLimiter interface {
+	ChargeCurrentLimits() (float64, float64)
+}
+
+// ChargeCurrentLimitReporter provides the charge current limit currently enforced by the vehicle, e.g. due to onboard charger derating, zero if not limited
+type ChargeCurrentLimitReporter interface {
+	ChargeCurrentLimit() (float64, error)
+}
+
+// Discharger sets the vehicle-to-home/grid discharge power of bidirectional chargers
+type Discharger interface {
+	Discharge(power float64) error
+}
+
 // VehicleChargeController allows to start/stop the charging session on the vehicle side
 type VehicleChargeController interface {
 	StartCharge() error
 	StopCharge() error
 }
 
+// FaultResetter resets the charger from a fault state
+type FaultResetter interface {
+	ResetFault() error
+}
+
 // Resurrector provides wakeup calls to the vehicle with an API call or a CP interrupt from the charger
 type Resurrector interface {
 	WakeUp() error
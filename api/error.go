@@ -14,6 +14,12 @@ var ErrSponsorRequired = errors.New("sponsorship required, see https://github.co
 // ErrMissingCredentials indicates that user/password are missing
 var ErrMissingCredentials = errors.New("missing credentials")
 
+// ErrLoginRequired indicates that the persisted authorization has been revoked and login is required
+var ErrLoginRequired = errors.New("login required")
+
+// ErrPendingLink indicates that the vehicle has not yet been linked to the account
+var ErrPendingLink = errors.New("pending link")
+
 // ErrOutdated indicates that result is outdated
 var ErrOutdated = errors.New("outdated")
 
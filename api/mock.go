@@ -1,5 +1,5 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: github.com/evcc-io/evcc/api (interfaces: Charger,ChargeState,PhaseSwitcher,Identifier,Meter,MeterEnergy,Vehicle,ChargeRater,Battery,Tariff,BatteryController)
+// Source: github.com/evcc-io/evcc/api (interfaces: Charger,ChargeState,PhaseSwitcher,PhaseReporter,PowerLimiter,CurrentBoundsReporter,Identifier,Meter,MeterEnergy,Vehicle,ChargeRater,Battery,Tariff,BatteryController)
 
 // Package api is a generated GoMock package.
 package api
@@ -166,6 +166,120 @@ func (mr *MockPhaseSwitcherMockRecorder) Phases1p3p(arg0 interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Phases1p3p", reflect.TypeOf((*MockPhaseSwitcher)(nil).Phases1p3p), arg0)
 }
 
+// MockPhaseReporter is a mock of PhaseReporter interface.
+type MockPhaseReporter struct {
+	ctrl     *gomock.Controller
+	recorder *MockPhaseReporterMockRecorder
+}
+
+// MockPhaseReporterMockRecorder is the mock recorder for MockPhaseReporter.
+type MockPhaseReporterMockRecorder struct {
+	mock *MockPhaseReporter
+}
+
+// NewMockPhaseReporter creates a new mock instance.
+func NewMockPhaseReporter(ctrl *gomock.Controller) *MockPhaseReporter {
+	mock := &MockPhaseReporter{ctrl: ctrl}
+	mock.recorder = &MockPhaseReporterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPhaseReporter) EXPECT() *MockPhaseReporterMockRecorder {
+	return m.recorder
+}
+
+// ActivePhases mocks base method.
+func (m *MockPhaseReporter) ActivePhases() (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ActivePhases")
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ActivePhases indicates an expected call of ActivePhases.
+func (mr *MockPhaseReporterMockRecorder) ActivePhases() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ActivePhases", reflect.TypeOf((*MockPhaseReporter)(nil).ActivePhases))
+}
+
+// MockPowerLimiter is a mock of PowerLimiter interface.
+type MockPowerLimiter struct {
+	ctrl     *gomock.Controller
+	recorder *MockPowerLimiterMockRecorder
+}
+
+// MockPowerLimiterMockRecorder is the mock recorder for MockPowerLimiter.
+type MockPowerLimiterMockRecorder struct {
+	mock *MockPowerLimiter
+}
+
+// NewMockPowerLimiter creates a new mock instance.
+func NewMockPowerLimiter(ctrl *gomock.Controller) *MockPowerLimiter {
+	mock := &MockPowerLimiter{ctrl: ctrl}
+	mock.recorder = &MockPowerLimiterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPowerLimiter) EXPECT() *MockPowerLimiterMockRecorder {
+	return m.recorder
+}
+
+// MaxPower mocks base method.
+func (m *MockPowerLimiter) MaxPower(arg0 float64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MaxPower", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MaxPower indicates an expected call of MaxPower.
+func (mr *MockPowerLimiterMockRecorder) MaxPower(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MaxPower", reflect.TypeOf((*MockPowerLimiter)(nil).MaxPower), arg0)
+}
+
+// MockCurrentBoundsReporter is a mock of CurrentBoundsReporter interface.
+type MockCurrentBoundsReporter struct {
+	ctrl     *gomock.Controller
+	recorder *MockCurrentBoundsReporterMockRecorder
+}
+
+// MockCurrentBoundsReporterMockRecorder is the mock recorder for MockCurrentBoundsReporter.
+type MockCurrentBoundsReporterMockRecorder struct {
+	mock *MockCurrentBoundsReporter
+}
+
+// NewMockCurrentBoundsReporter creates a new mock instance.
+func NewMockCurrentBoundsReporter(ctrl *gomock.Controller) *MockCurrentBoundsReporter {
+	mock := &MockCurrentBoundsReporter{ctrl: ctrl}
+	mock.recorder = &MockCurrentBoundsReporterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCurrentBoundsReporter) EXPECT() *MockCurrentBoundsReporterMockRecorder {
+	return m.recorder
+}
+
+// CurrentBounds mocks base method.
+func (m *MockCurrentBoundsReporter) CurrentBounds() (float64, float64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CurrentBounds")
+	ret0, _ := ret[0].(float64)
+	ret1, _ := ret[1].(float64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// CurrentBounds indicates an expected call of CurrentBounds.
+func (mr *MockCurrentBoundsReporterMockRecorder) CurrentBounds() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CurrentBounds", reflect.TypeOf((*MockCurrentBoundsReporter)(nil).CurrentBounds))
+}
+
 // MockIdentifier is a mock of Identifier interface.
 type MockIdentifier struct {
 	ctrl     *gomock.Controller
@@ -0,0 +1,18 @@
+package api
+
+// PowerFlow is the directional power flow between two parts of the site
+type PowerFlow struct {
+	From  string  `json:"from"`
+	To    string  `json:"to"`
+	Power float64 `json:"power"`
+}
+
+// PowerFlows is a snapshot of the site's power balance and its directional flows
+type PowerFlows struct {
+	Pv         float64     `json:"pv"`         // pv production
+	Grid       float64     `json:"grid"`       // grid import, negative for export
+	Battery    float64     `json:"battery"`    // battery discharge, negative for charging
+	Home       float64     `json:"home"`       // home consumption
+	Loadpoints float64     `json:"loadpoints"` // loadpoint consumption
+	Flows      []PowerFlow `json:"flows"`
+}
@@ -22,7 +22,7 @@ func init() {
 	registry.Add(api.Custom, NewConfigurableFromConfig)
 }
 
-// go:generate go run ../cmd/tools/decorate.go -f decorateCustom -b *Charger -r api.Charger -t "api.ChargerEx,MaxCurrentMillis,func(float64) error" -t "api.Identifier,Identify,func() (string, error)" -t "api.PhaseSwitcher,Phases1p3p,func(int) error" -t "api.Resurrector,WakeUp,func() error" -t "api.Battery,Soc,func() (float64, error)"
+// go:generate go run ../cmd/tools/decorate.go -f decorateCustom -b *Charger -r api.Charger -t "api.ChargerEx,MaxCurrentMillis,func(float64) error" -t "api.Identifier,Identify,func() (string, error)" -t "api.PhaseSwitcher,Phases1p3p,func(int) error" -t "api.Resurrector,WakeUp,func() error" -t "api.Battery,Soc,func() (float64, error)" -t "api.PowerLimiter,MaxPower,func(float64) error"
 
 // NewConfigurableFromConfig creates a new configurable charger
 func NewConfigurableFromConfig(other map[string]interface{}) (api.Charger, error) {
@@ -30,6 +30,8 @@ func NewConfigurableFromConfig(other map[string]interface{}) (api.Charger, error
 		embed                               `mapstructure:",squash"`
 		Status, Enable, Enabled, MaxCurrent provider.Config
 		MaxCurrentMillis                    *provider.Config
+		MaxPower                            *provider.Config
+		Disable                             *provider.Config
 		Identify, Phases1p3p                *provider.Config
 		Wakeup                              *provider.Config
 		Soc                                 *provider.Config
@@ -55,6 +57,16 @@ func NewConfigurableFromConfig(other map[string]interface{}) (api.Charger, error
 		return nil, fmt.Errorf("enable: %w", err)
 	}
 
+	// separate enable and disable actions
+	if cc.Disable != nil {
+		disable, err := provider.NewBoolSetterFromConfig("disable", *cc.Disable)
+		if err != nil {
+			return nil, fmt.Errorf("disable: %w", err)
+		}
+
+		enable = enableDisable(enable, disable)
+	}
+
 	maxcurrent, err := provider.NewIntSetterFromConfig("maxcurrent", cc.MaxCurrent)
 	if err != nil {
 		return nil, fmt.Errorf("maxcurrent: %w", err)
@@ -75,6 +87,15 @@ func NewConfigurableFromConfig(other map[string]interface{}) (api.Charger, error
 		}
 	}
 
+	// decorate power control
+	var maxpower func(float64) error
+	if cc.MaxPower != nil {
+		maxpower, err = provider.NewFloatSetterFromConfig("maxpower", *cc.MaxPower)
+		if err != nil {
+			return nil, fmt.Errorf("maxpower: %w", err)
+		}
+	}
+
 	// decorate phases
 	var phases1p3p func(int) error
 	if cc.Phases1p3p != nil {
@@ -123,7 +144,17 @@ func NewConfigurableFromConfig(other map[string]interface{}) (api.Charger, error
 		}
 	}
 
-	return decorateCustom(c, maxcurrentmillis, identify, phases1p3p, wakeup, soc), nil
+	return decorateCustom(c, maxcurrentmillis, identify, phases1p3p, wakeup, soc, maxpower), nil
+}
+
+// enableDisable combines separate enable and disable actions into a single setter
+func enableDisable(enableS, disableS func(bool) error) func(bool) error {
+	return func(enable bool) error {
+		if enable {
+			return enableS(true)
+		}
+		return disableS(true)
+	}
 }
 
 // NewConfigurable creates a new charger
@@ -6,12 +6,12 @@ import (
 	"github.com/evcc-io/evcc/api"
 )
 
-func decorateCustom(base *Charger, chargerEx func(float64) error, identifier func() (string, error), phaseSwitcher func(int) error, resurrector func() error, battery func() (float64, error)) api.Charger {
+func decorateCustom(base *Charger, chargerEx func(float64) error, identifier func() (string, error), phaseSwitcher func(int) error, resurrector func() error, battery func() (float64, error), powerLimiter func(float64) error) api.Charger {
 	switch {
-	case battery == nil && chargerEx == nil && identifier == nil && phaseSwitcher == nil && resurrector == nil:
+	case battery == nil && chargerEx == nil && identifier == nil && phaseSwitcher == nil && powerLimiter == nil && resurrector == nil:
 		return base
 
-	case battery == nil && chargerEx != nil && identifier == nil && phaseSwitcher == nil && resurrector == nil:
+	case battery == nil && chargerEx != nil && identifier == nil && phaseSwitcher == nil && powerLimiter == nil && resurrector == nil:
 		return &struct {
 			*Charger
 			api.ChargerEx
@@ -22,7 +22,7 @@ func decorateCustom(base *Charger, chargerEx func(float64) error, identifier fun
 			},
 		}
 
-	case battery == nil && chargerEx == nil && identifier != nil && phaseSwitcher == nil && resurrector == nil:
+	case battery == nil && chargerEx == nil && identifier != nil && phaseSwitcher == nil && powerLimiter == nil && resurrector == nil:
 		return &struct {
 			*Charger
 			api.Identifier
@@ -33,7 +33,7 @@ func decorateCustom(base *Charger, chargerEx func(float64) error, identifier fun
 			},
 		}
 
-	case battery == nil && chargerEx != nil && identifier != nil && phaseSwitcher == nil && resurrector == nil:
+	case battery == nil && chargerEx != nil && identifier != nil && phaseSwitcher == nil && powerLimiter == nil && resurrector == nil:
 		return &struct {
 			*Charger
 			api.ChargerEx
@@ -48,7 +48,7 @@ func decorateCustom(base *Charger, chargerEx func(float64) error, identifier fun
 			},
 		}
 
-	case battery == nil && chargerEx == nil && identifier == nil && phaseSwitcher != nil && resurrector == nil:
+	case battery == nil && chargerEx == nil && identifier == nil && phaseSwitcher != nil && powerLimiter == nil && resurrector == nil:
 		return &struct {
 			*Charger
 			api.PhaseSwitcher
@@ -59,7 +59,7 @@ func decorateCustom(base *Charger, chargerEx func(float64) error, identifier fun
 			},
 		}
 
-	case battery == nil && chargerEx != nil && identifier == nil && phaseSwitcher != nil && resurrector == nil:
+	case battery == nil && chargerEx != nil && identifier == nil && phaseSwitcher != nil && powerLimiter == nil && resurrector == nil:
 		return &struct {
 			*Charger
 			api.ChargerEx
@@ -74,7 +74,7 @@ func decorateCustom(base *Charger, chargerEx func(float64) error, identifier fun
 			},
 		}
 
-	case battery == nil && chargerEx == nil && identifier != nil && phaseSwitcher != nil && resurrector == nil:
+	case battery == nil && chargerEx == nil && identifier != nil && phaseSwitcher != nil && powerLimiter == nil && resurrector == nil:
 		return &struct {
 			*Charger
 			api.Identifier
@@ -89,7 +89,7 @@ func decorateCustom(base *Charger, chargerEx func(float64) error, identifier fun
 			},
 		}
 
-	case battery == nil && chargerEx != nil && identifier != nil && phaseSwitcher != nil && resurrector == nil:
+	case battery == nil && chargerEx != nil && identifier != nil && phaseSwitcher != nil && powerLimiter == nil && resurrector == nil:
 		return &struct {
 			*Charger
 			api.ChargerEx
@@ -108,7 +108,7 @@ func decorateCustom(base *Charger, chargerEx func(float64) error, identifier fun
 			},
 		}
 
-	case battery == nil && chargerEx == nil && identifier == nil && phaseSwitcher == nil && resurrector != nil:
+	case battery == nil && chargerEx == nil && identifier == nil && phaseSwitcher == nil && powerLimiter == nil && resurrector != nil:
 		return &struct {
 			*Charger
 			api.Resurrector
@@ -119,7 +119,7 @@ func decorateCustom(base *Charger, chargerEx func(float64) error, identifier fun
 			},
 		}
 
-	case battery == nil && chargerEx != nil && identifier == nil && phaseSwitcher == nil && resurrector != nil:
+	case battery == nil && chargerEx != nil && identifier == nil && phaseSwitcher == nil && powerLimiter == nil && resurrector != nil:
 		return &struct {
 			*Charger
 			api.ChargerEx
@@ -134,7 +134,7 @@ func decorateCustom(base *Charger, chargerEx func(float64) error, identifier fun
 			},
 		}
 
-	case battery == nil && chargerEx == nil && identifier != nil && phaseSwitcher == nil && resurrector != nil:
+	case battery == nil && chargerEx == nil && identifier != nil && phaseSwitcher == nil && powerLimiter == nil && resurrector != nil:
 		return &struct {
 			*Charger
 			api.Identifier
@@ -149,7 +149,7 @@ func decorateCustom(base *Charger, chargerEx func(float64) error, identifier fun
 			},
 		}
 
-	case battery == nil && chargerEx != nil && identifier != nil && phaseSwitcher == nil && resurrector != nil:
+	case battery == nil && chargerEx != nil && identifier != nil && phaseSwitcher == nil && powerLimiter == nil && resurrector != nil:
 		return &struct {
 			*Charger
 			api.ChargerEx
@@ -168,7 +168,7 @@ func decorateCustom(base *Charger, chargerEx func(float64) error, identifier fun
 			},
 		}
 
-	case battery == nil && chargerEx == nil && identifier == nil && phaseSwitcher != nil && resurrector != nil:
+	case battery == nil && chargerEx == nil && identifier == nil && phaseSwitcher != nil && powerLimiter == nil && resurrector != nil:
 		return &struct {
 			*Charger
 			api.PhaseSwitcher
@@ -183,7 +183,7 @@ func decorateCustom(base *Charger, chargerEx func(float64) error, identifier fun
 			},
 		}
 
-	case battery == nil && chargerEx != nil && identifier == nil && phaseSwitcher != nil && resurrector != nil:
+	case battery == nil && chargerEx != nil && identifier == nil && phaseSwitcher != nil && powerLimiter == nil && resurrector != nil:
 		return &struct {
 			*Charger
 			api.ChargerEx
@@ -202,7 +202,7 @@ func decorateCustom(base *Charger, chargerEx func(float64) error, identifier fun
 			},
 		}
 
-	case battery == nil && chargerEx == nil && identifier != nil && phaseSwitcher != nil && resurrector != nil:
+	case battery == nil && chargerEx == nil && identifier != nil && phaseSwitcher != nil && powerLimiter == nil && resurrector != nil:
 		return &struct {
 			*Charger
 			api.Identifier
@@ -221,7 +221,7 @@ func decorateCustom(base *Charger, chargerEx func(float64) error, identifier fun
 			},
 		}
 
-	case battery == nil && chargerEx != nil && identifier != nil && phaseSwitcher != nil && resurrector != nil:
+	case battery == nil && chargerEx != nil && identifier != nil && phaseSwitcher != nil && powerLimiter == nil && resurrector != nil:
 		return &struct {
 			*Charger
 			api.ChargerEx
@@ -244,7 +244,7 @@ func decorateCustom(base *Charger, chargerEx func(float64) error, identifier fun
 			},
 		}
 
-	case battery != nil && chargerEx == nil && identifier == nil && phaseSwitcher == nil && resurrector == nil:
+	case battery != nil && chargerEx == nil && identifier == nil && phaseSwitcher == nil && powerLimiter == nil && resurrector == nil:
 		return &struct {
 			*Charger
 			api.Battery
@@ -255,7 +255,7 @@ func decorateCustom(base *Charger, chargerEx func(float64) error, identifier fun
 			},
 		}
 
-	case battery != nil && chargerEx != nil && identifier == nil && phaseSwitcher == nil && resurrector == nil:
+	case battery != nil && chargerEx != nil && identifier == nil && phaseSwitcher == nil && powerLimiter == nil && resurrector == nil:
 		return &struct {
 			*Charger
 			api.Battery
@@ -270,7 +270,7 @@ func decorateCustom(base *Charger, chargerEx func(float64) error, identifier fun
 			},
 		}
 
-	case battery != nil && chargerEx == nil && identifier != nil && phaseSwitcher == nil && resurrector == nil:
+	case battery != nil && chargerEx == nil && identifier != nil && phaseSwitcher == nil && powerLimiter == nil && resurrector == nil:
 		return &struct {
 			*Charger
 			api.Battery
@@ -285,7 +285,7 @@ func decorateCustom(base *Charger, chargerEx func(float64) error, identifier fun
 			},
 		}
 
-	case battery != nil && chargerEx != nil && identifier != nil && phaseSwitcher == nil && resurrector == nil:
+	case battery != nil && chargerEx != nil && identifier != nil && phaseSwitcher == nil && powerLimiter == nil && resurrector == nil:
 		return &struct {
 			*Charger
 			api.Battery
@@ -304,7 +304,7 @@ func decorateCustom(base *Charger, chargerEx func(float64) error, identifier fun
 			},
 		}
 
-	case battery != nil && chargerEx == nil && identifier == nil && phaseSwitcher != nil && resurrector == nil:
+	case battery != nil && chargerEx == nil && identifier == nil && phaseSwitcher != nil && powerLimiter == nil && resurrector == nil:
 		return &struct {
 			*Charger
 			api.Battery
@@ -319,7 +319,7 @@ func decorateCustom(base *Charger, chargerEx func(float64) error, identifier fun
 			},
 		}
 
-	case battery != nil && chargerEx != nil && identifier == nil && phaseSwitcher != nil && resurrector == nil:
+	case battery != nil && chargerEx != nil && identifier == nil && phaseSwitcher != nil && powerLimiter == nil && resurrector == nil:
 		return &struct {
 			*Charger
 			api.Battery
@@ -338,7 +338,7 @@ func decorateCustom(base *Charger, chargerEx func(float64) error, identifier fun
 			},
 		}
 
-	case battery != nil && chargerEx == nil && identifier != nil && phaseSwitcher != nil && resurrector == nil:
+	case battery != nil && chargerEx == nil && identifier != nil && phaseSwitcher != nil && powerLimiter == nil && resurrector == nil:
 		return &struct {
 			*Charger
 			api.Battery
@@ -357,7 +357,7 @@ func decorateCustom(base *Charger, chargerEx func(float64) error, identifier fun
 			},
 		}
 
-	case battery != nil && chargerEx != nil && identifier != nil && phaseSwitcher != nil && resurrector == nil:
+	case battery != nil && chargerEx != nil && identifier != nil && phaseSwitcher != nil && powerLimiter == nil && resurrector == nil:
 		return &struct {
 			*Charger
 			api.Battery
@@ -380,7 +380,7 @@ func decorateCustom(base *Charger, chargerEx func(float64) error, identifier fun
 			},
 		}
 
-	case battery != nil && chargerEx == nil && identifier == nil && phaseSwitcher == nil && resurrector != nil:
+	case battery != nil && chargerEx == nil && identifier == nil && phaseSwitcher == nil && powerLimiter == nil && resurrector != nil:
 		return &struct {
 			*Charger
 			api.Battery
@@ -395,7 +395,7 @@ func decorateCustom(base *Charger, chargerEx func(float64) error, identifier fun
 			},
 		}
 
-	case battery != nil && chargerEx != nil && identifier == nil && phaseSwitcher == nil && resurrector != nil:
+	case battery != nil && chargerEx != nil && identifier == nil && phaseSwitcher == nil && powerLimiter == nil && resurrector != nil:
 		return &struct {
 			*Charger
 			api.Battery
@@ -414,7 +414,7 @@ func decorateCustom(base *Charger, chargerEx func(float64) error, identifier fun
 			},
 		}
 
-	case battery != nil && chargerEx == nil && identifier != nil && phaseSwitcher == nil && resurrector != nil:
+	case battery != nil && chargerEx == nil && identifier != nil && phaseSwitcher == nil && powerLimiter == nil && resurrector != nil:
 		return &struct {
 			*Charger
 			api.Battery
@@ -433,7 +433,7 @@ func decorateCustom(base *Charger, chargerEx func(float64) error, identifier fun
 			},
 		}
 
-	case battery != nil && chargerEx != nil && identifier != nil && phaseSwitcher == nil && resurrector != nil:
+	case battery != nil && chargerEx != nil && identifier != nil && phaseSwitcher == nil && powerLimiter == nil && resurrector != nil:
 		return &struct {
 			*Charger
 			api.Battery
@@ -456,7 +456,7 @@ func decorateCustom(base *Charger, chargerEx func(float64) error, identifier fun
 			},
 		}
 
-	case battery != nil && chargerEx == nil && identifier == nil && phaseSwitcher != nil && resurrector != nil:
+	case battery != nil && chargerEx == nil && identifier == nil && phaseSwitcher != nil && powerLimiter == nil && resurrector != nil:
 		return &struct {
 			*Charger
 			api.Battery
@@ -475,7 +475,7 @@ func decorateCustom(base *Charger, chargerEx func(float64) error, identifier fun
 			},
 		}
 
-	case battery != nil && chargerEx != nil && identifier == nil && phaseSwitcher != nil && resurrector != nil:
+	case battery != nil && chargerEx != nil && identifier == nil && phaseSwitcher != nil && powerLimiter == nil && resurrector != nil:
 		return &struct {
 			*Charger
 			api.Battery
@@ -498,7 +498,7 @@ func decorateCustom(base *Charger, chargerEx func(float64) error, identifier fun
 			},
 		}
 
-	case battery != nil && chargerEx == nil && identifier != nil && phaseSwitcher != nil && resurrector != nil:
+	case battery != nil && chargerEx == nil && identifier != nil && phaseSwitcher != nil && powerLimiter == nil && resurrector != nil:
 		return &struct {
 			*Charger
 			api.Battery
@@ -521,7 +521,7 @@ func decorateCustom(base *Charger, chargerEx func(float64) error, identifier fun
 			},
 		}
 
-	case battery != nil && chargerEx != nil && identifier != nil && phaseSwitcher != nil && resurrector != nil:
+	case battery != nil && chargerEx != nil && identifier != nil && phaseSwitcher != nil && powerLimiter == nil && resurrector != nil:
 		return &struct {
 			*Charger
 			api.Battery
@@ -547,6 +547,678 @@ func decorateCustom(base *Charger, chargerEx func(float64) error, identifier fun
 				resurrector: resurrector,
 			},
 		}
+
+	case battery == nil && chargerEx == nil && identifier == nil && phaseSwitcher == nil && powerLimiter != nil && resurrector == nil:
+		return &struct {
+			*Charger
+			api.PowerLimiter
+		}{
+			Charger: base,
+			PowerLimiter: &decorateCustomPowerLimiterImpl{
+				powerLimiter: powerLimiter,
+			},
+		}
+
+	case battery == nil && chargerEx != nil && identifier == nil && phaseSwitcher == nil && powerLimiter != nil && resurrector == nil:
+		return &struct {
+			*Charger
+			api.ChargerEx
+			api.PowerLimiter
+		}{
+			Charger: base,
+			ChargerEx: &decorateCustomChargerExImpl{
+				chargerEx: chargerEx,
+			},
+			PowerLimiter: &decorateCustomPowerLimiterImpl{
+				powerLimiter: powerLimiter,
+			},
+		}
+
+	case battery == nil && chargerEx == nil && identifier != nil && phaseSwitcher == nil && powerLimiter != nil && resurrector == nil:
+		return &struct {
+			*Charger
+			api.Identifier
+			api.PowerLimiter
+		}{
+			Charger: base,
+			Identifier: &decorateCustomIdentifierImpl{
+				identifier: identifier,
+			},
+			PowerLimiter: &decorateCustomPowerLimiterImpl{
+				powerLimiter: powerLimiter,
+			},
+		}
+
+	case battery == nil && chargerEx != nil && identifier != nil && phaseSwitcher == nil && powerLimiter != nil && resurrector == nil:
+		return &struct {
+			*Charger
+			api.ChargerEx
+			api.Identifier
+			api.PowerLimiter
+		}{
+			Charger: base,
+			ChargerEx: &decorateCustomChargerExImpl{
+				chargerEx: chargerEx,
+			},
+			Identifier: &decorateCustomIdentifierImpl{
+				identifier: identifier,
+			},
+			PowerLimiter: &decorateCustomPowerLimiterImpl{
+				powerLimiter: powerLimiter,
+			},
+		}
+
+	case battery == nil && chargerEx == nil && identifier == nil && phaseSwitcher != nil && powerLimiter != nil && resurrector == nil:
+		return &struct {
+			*Charger
+			api.PhaseSwitcher
+			api.PowerLimiter
+		}{
+			Charger: base,
+			PhaseSwitcher: &decorateCustomPhaseSwitcherImpl{
+				phaseSwitcher: phaseSwitcher,
+			},
+			PowerLimiter: &decorateCustomPowerLimiterImpl{
+				powerLimiter: powerLimiter,
+			},
+		}
+
+	case battery == nil && chargerEx != nil && identifier == nil && phaseSwitcher != nil && powerLimiter != nil && resurrector == nil:
+		return &struct {
+			*Charger
+			api.ChargerEx
+			api.PhaseSwitcher
+			api.PowerLimiter
+		}{
+			Charger: base,
+			ChargerEx: &decorateCustomChargerExImpl{
+				chargerEx: chargerEx,
+			},
+			PhaseSwitcher: &decorateCustomPhaseSwitcherImpl{
+				phaseSwitcher: phaseSwitcher,
+			},
+			PowerLimiter: &decorateCustomPowerLimiterImpl{
+				powerLimiter: powerLimiter,
+			},
+		}
+
+	case battery == nil && chargerEx == nil && identifier != nil && phaseSwitcher != nil && powerLimiter != nil && resurrector == nil:
+		return &struct {
+			*Charger
+			api.Identifier
+			api.PhaseSwitcher
+			api.PowerLimiter
+		}{
+			Charger: base,
+			Identifier: &decorateCustomIdentifierImpl{
+				identifier: identifier,
+			},
+			PhaseSwitcher: &decorateCustomPhaseSwitcherImpl{
+				phaseSwitcher: phaseSwitcher,
+			},
+			PowerLimiter: &decorateCustomPowerLimiterImpl{
+				powerLimiter: powerLimiter,
+			},
+		}
+
+	case battery == nil && chargerEx != nil && identifier != nil && phaseSwitcher != nil && powerLimiter != nil && resurrector == nil:
+		return &struct {
+			*Charger
+			api.ChargerEx
+			api.Identifier
+			api.PhaseSwitcher
+			api.PowerLimiter
+		}{
+			Charger: base,
+			ChargerEx: &decorateCustomChargerExImpl{
+				chargerEx: chargerEx,
+			},
+			Identifier: &decorateCustomIdentifierImpl{
+				identifier: identifier,
+			},
+			PhaseSwitcher: &decorateCustomPhaseSwitcherImpl{
+				phaseSwitcher: phaseSwitcher,
+			},
+			PowerLimiter: &decorateCustomPowerLimiterImpl{
+				powerLimiter: powerLimiter,
+			},
+		}
+
+	case battery == nil && chargerEx == nil && identifier == nil && phaseSwitcher == nil && powerLimiter != nil && resurrector != nil:
+		return &struct {
+			*Charger
+			api.PowerLimiter
+			api.Resurrector
+		}{
+			Charger: base,
+			PowerLimiter: &decorateCustomPowerLimiterImpl{
+				powerLimiter: powerLimiter,
+			},
+			Resurrector: &decorateCustomResurrectorImpl{
+				resurrector: resurrector,
+			},
+		}
+
+	case battery == nil && chargerEx != nil && identifier == nil && phaseSwitcher == nil && powerLimiter != nil && resurrector != nil:
+		return &struct {
+			*Charger
+			api.ChargerEx
+			api.PowerLimiter
+			api.Resurrector
+		}{
+			Charger: base,
+			ChargerEx: &decorateCustomChargerExImpl{
+				chargerEx: chargerEx,
+			},
+			PowerLimiter: &decorateCustomPowerLimiterImpl{
+				powerLimiter: powerLimiter,
+			},
+			Resurrector: &decorateCustomResurrectorImpl{
+				resurrector: resurrector,
+			},
+		}
+
+	case battery == nil && chargerEx == nil && identifier != nil && phaseSwitcher == nil && powerLimiter != nil && resurrector != nil:
+		return &struct {
+			*Charger
+			api.Identifier
+			api.PowerLimiter
+			api.Resurrector
+		}{
+			Charger: base,
+			Identifier: &decorateCustomIdentifierImpl{
+				identifier: identifier,
+			},
+			PowerLimiter: &decorateCustomPowerLimiterImpl{
+				powerLimiter: powerLimiter,
+			},
+			Resurrector: &decorateCustomResurrectorImpl{
+				resurrector: resurrector,
+			},
+		}
+
+	case battery == nil && chargerEx != nil && identifier != nil && phaseSwitcher == nil && powerLimiter != nil && resurrector != nil:
+		return &struct {
+			*Charger
+			api.ChargerEx
+			api.Identifier
+			api.PowerLimiter
+			api.Resurrector
+		}{
+			Charger: base,
+			ChargerEx: &decorateCustomChargerExImpl{
+				chargerEx: chargerEx,
+			},
+			Identifier: &decorateCustomIdentifierImpl{
+				identifier: identifier,
+			},
+			PowerLimiter: &decorateCustomPowerLimiterImpl{
+				powerLimiter: powerLimiter,
+			},
+			Resurrector: &decorateCustomResurrectorImpl{
+				resurrector: resurrector,
+			},
+		}
+
+	case battery == nil && chargerEx == nil && identifier == nil && phaseSwitcher != nil && powerLimiter != nil && resurrector != nil:
+		return &struct {
+			*Charger
+			api.PhaseSwitcher
+			api.PowerLimiter
+			api.Resurrector
+		}{
+			Charger: base,
+			PhaseSwitcher: &decorateCustomPhaseSwitcherImpl{
+				phaseSwitcher: phaseSwitcher,
+			},
+			PowerLimiter: &decorateCustomPowerLimiterImpl{
+				powerLimiter: powerLimiter,
+			},
+			Resurrector: &decorateCustomResurrectorImpl{
+				resurrector: resurrector,
+			},
+		}
+
+	case battery == nil && chargerEx != nil && identifier == nil && phaseSwitcher != nil && powerLimiter != nil && resurrector != nil:
+		return &struct {
+			*Charger
+			api.ChargerEx
+			api.PhaseSwitcher
+			api.PowerLimiter
+			api.Resurrector
+		}{
+			Charger: base,
+			ChargerEx: &decorateCustomChargerExImpl{
+				chargerEx: chargerEx,
+			},
+			PhaseSwitcher: &decorateCustomPhaseSwitcherImpl{
+				phaseSwitcher: phaseSwitcher,
+			},
+			PowerLimiter: &decorateCustomPowerLimiterImpl{
+				powerLimiter: powerLimiter,
+			},
+			Resurrector: &decorateCustomResurrectorImpl{
+				resurrector: resurrector,
+			},
+		}
+
+	case battery == nil && chargerEx == nil && identifier != nil && phaseSwitcher != nil && powerLimiter != nil && resurrector != nil:
+		return &struct {
+			*Charger
+			api.Identifier
+			api.PhaseSwitcher
+			api.PowerLimiter
+			api.Resurrector
+		}{
+			Charger: base,
+			Identifier: &decorateCustomIdentifierImpl{
+				identifier: identifier,
+			},
+			PhaseSwitcher: &decorateCustomPhaseSwitcherImpl{
+				phaseSwitcher: phaseSwitcher,
+			},
+			PowerLimiter: &decorateCustomPowerLimiterImpl{
+				powerLimiter: powerLimiter,
+			},
+			Resurrector: &decorateCustomResurrectorImpl{
+				resurrector: resurrector,
+			},
+		}
+
+	case battery == nil && chargerEx != nil && identifier != nil && phaseSwitcher != nil && powerLimiter != nil && resurrector != nil:
+		return &struct {
+			*Charger
+			api.ChargerEx
+			api.Identifier
+			api.PhaseSwitcher
+			api.PowerLimiter
+			api.Resurrector
+		}{
+			Charger: base,
+			ChargerEx: &decorateCustomChargerExImpl{
+				chargerEx: chargerEx,
+			},
+			Identifier: &decorateCustomIdentifierImpl{
+				identifier: identifier,
+			},
+			PhaseSwitcher: &decorateCustomPhaseSwitcherImpl{
+				phaseSwitcher: phaseSwitcher,
+			},
+			PowerLimiter: &decorateCustomPowerLimiterImpl{
+				powerLimiter: powerLimiter,
+			},
+			Resurrector: &decorateCustomResurrectorImpl{
+				resurrector: resurrector,
+			},
+		}
+
+	case battery != nil && chargerEx == nil && identifier == nil && phaseSwitcher == nil && powerLimiter != nil && resurrector == nil:
+		return &struct {
+			*Charger
+			api.Battery
+			api.PowerLimiter
+		}{
+			Charger: base,
+			Battery: &decorateCustomBatteryImpl{
+				battery: battery,
+			},
+			PowerLimiter: &decorateCustomPowerLimiterImpl{
+				powerLimiter: powerLimiter,
+			},
+		}
+
+	case battery != nil && chargerEx != nil && identifier == nil && phaseSwitcher == nil && powerLimiter != nil && resurrector == nil:
+		return &struct {
+			*Charger
+			api.Battery
+			api.ChargerEx
+			api.PowerLimiter
+		}{
+			Charger: base,
+			Battery: &decorateCustomBatteryImpl{
+				battery: battery,
+			},
+			ChargerEx: &decorateCustomChargerExImpl{
+				chargerEx: chargerEx,
+			},
+			PowerLimiter: &decorateCustomPowerLimiterImpl{
+				powerLimiter: powerLimiter,
+			},
+		}
+
+	case battery != nil && chargerEx == nil && identifier != nil && phaseSwitcher == nil && powerLimiter != nil && resurrector == nil:
+		return &struct {
+			*Charger
+			api.Battery
+			api.Identifier
+			api.PowerLimiter
+		}{
+			Charger: base,
+			Battery: &decorateCustomBatteryImpl{
+				battery: battery,
+			},
+			Identifier: &decorateCustomIdentifierImpl{
+				identifier: identifier,
+			},
+			PowerLimiter: &decorateCustomPowerLimiterImpl{
+				powerLimiter: powerLimiter,
+			},
+		}
+
+	case battery != nil && chargerEx != nil && identifier != nil && phaseSwitcher == nil && powerLimiter != nil && resurrector == nil:
+		return &struct {
+			*Charger
+			api.Battery
+			api.ChargerEx
+			api.Identifier
+			api.PowerLimiter
+		}{
+			Charger: base,
+			Battery: &decorateCustomBatteryImpl{
+				battery: battery,
+			},
+			ChargerEx: &decorateCustomChargerExImpl{
+				chargerEx: chargerEx,
+			},
+			Identifier: &decorateCustomIdentifierImpl{
+				identifier: identifier,
+			},
+			PowerLimiter: &decorateCustomPowerLimiterImpl{
+				powerLimiter: powerLimiter,
+			},
+		}
+
+	case battery != nil && chargerEx == nil && identifier == nil && phaseSwitcher != nil && powerLimiter != nil && resurrector == nil:
+		return &struct {
+			*Charger
+			api.Battery
+			api.PhaseSwitcher
+			api.PowerLimiter
+		}{
+			Charger: base,
+			Battery: &decorateCustomBatteryImpl{
+				battery: battery,
+			},
+			PhaseSwitcher: &decorateCustomPhaseSwitcherImpl{
+				phaseSwitcher: phaseSwitcher,
+			},
+			PowerLimiter: &decorateCustomPowerLimiterImpl{
+				powerLimiter: powerLimiter,
+			},
+		}
+
+	case battery != nil && chargerEx != nil && identifier == nil && phaseSwitcher != nil && powerLimiter != nil && resurrector == nil:
+		return &struct {
+			*Charger
+			api.Battery
+			api.ChargerEx
+			api.PhaseSwitcher
+			api.PowerLimiter
+		}{
+			Charger: base,
+			Battery: &decorateCustomBatteryImpl{
+				battery: battery,
+			},
+			ChargerEx: &decorateCustomChargerExImpl{
+				chargerEx: chargerEx,
+			},
+			PhaseSwitcher: &decorateCustomPhaseSwitcherImpl{
+				phaseSwitcher: phaseSwitcher,
+			},
+			PowerLimiter: &decorateCustomPowerLimiterImpl{
+				powerLimiter: powerLimiter,
+			},
+		}
+
+	case battery != nil && chargerEx == nil && identifier != nil && phaseSwitcher != nil && powerLimiter != nil && resurrector == nil:
+		return &struct {
+			*Charger
+			api.Battery
+			api.Identifier
+			api.PhaseSwitcher
+			api.PowerLimiter
+		}{
+			Charger: base,
+			Battery: &decorateCustomBatteryImpl{
+				battery: battery,
+			},
+			Identifier: &decorateCustomIdentifierImpl{
+				identifier: identifier,
+			},
+			PhaseSwitcher: &decorateCustomPhaseSwitcherImpl{
+				phaseSwitcher: phaseSwitcher,
+			},
+			PowerLimiter: &decorateCustomPowerLimiterImpl{
+				powerLimiter: powerLimiter,
+			},
+		}
+
+	case battery != nil && chargerEx != nil && identifier != nil && phaseSwitcher != nil && powerLimiter != nil && resurrector == nil:
+		return &struct {
+			*Charger
+			api.Battery
+			api.ChargerEx
+			api.Identifier
+			api.PhaseSwitcher
+			api.PowerLimiter
+		}{
+			Charger: base,
+			Battery: &decorateCustomBatteryImpl{
+				battery: battery,
+			},
+			ChargerEx: &decorateCustomChargerExImpl{
+				chargerEx: chargerEx,
+			},
+			Identifier: &decorateCustomIdentifierImpl{
+				identifier: identifier,
+			},
+			PhaseSwitcher: &decorateCustomPhaseSwitcherImpl{
+				phaseSwitcher: phaseSwitcher,
+			},
+			PowerLimiter: &decorateCustomPowerLimiterImpl{
+				powerLimiter: powerLimiter,
+			},
+		}
+
+	case battery != nil && chargerEx == nil && identifier == nil && phaseSwitcher == nil && powerLimiter != nil && resurrector != nil:
+		return &struct {
+			*Charger
+			api.Battery
+			api.PowerLimiter
+			api.Resurrector
+		}{
+			Charger: base,
+			Battery: &decorateCustomBatteryImpl{
+				battery: battery,
+			},
+			PowerLimiter: &decorateCustomPowerLimiterImpl{
+				powerLimiter: powerLimiter,
+			},
+			Resurrector: &decorateCustomResurrectorImpl{
+				resurrector: resurrector,
+			},
+		}
+
+	case battery != nil && chargerEx != nil && identifier == nil && phaseSwitcher == nil && powerLimiter != nil && resurrector != nil:
+		return &struct {
+			*Charger
+			api.Battery
+			api.ChargerEx
+			api.PowerLimiter
+			api.Resurrector
+		}{
+			Charger: base,
+			Battery: &decorateCustomBatteryImpl{
+				battery: battery,
+			},
+			ChargerEx: &decorateCustomChargerExImpl{
+				chargerEx: chargerEx,
+			},
+			PowerLimiter: &decorateCustomPowerLimiterImpl{
+				powerLimiter: powerLimiter,
+			},
+			Resurrector: &decorateCustomResurrectorImpl{
+				resurrector: resurrector,
+			},
+		}
+
+	case battery != nil && chargerEx == nil && identifier != nil && phaseSwitcher == nil && powerLimiter != nil && resurrector != nil:
+		return &struct {
+			*Charger
+			api.Battery
+			api.Identifier
+			api.PowerLimiter
+			api.Resurrector
+		}{
+			Charger: base,
+			Battery: &decorateCustomBatteryImpl{
+				battery: battery,
+			},
+			Identifier: &decorateCustomIdentifierImpl{
+				identifier: identifier,
+			},
+			PowerLimiter: &decorateCustomPowerLimiterImpl{
+				powerLimiter: powerLimiter,
+			},
+			Resurrector: &decorateCustomResurrectorImpl{
+				resurrector: resurrector,
+			},
+		}
+
+	case battery != nil && chargerEx != nil && identifier != nil && phaseSwitcher == nil && powerLimiter != nil && resurrector != nil:
+		return &struct {
+			*Charger
+			api.Battery
+			api.ChargerEx
+			api.Identifier
+			api.PowerLimiter
+			api.Resurrector
+		}{
+			Charger: base,
+			Battery: &decorateCustomBatteryImpl{
+				battery: battery,
+			},
+			ChargerEx: &decorateCustomChargerExImpl{
+				chargerEx: chargerEx,
+			},
+			Identifier: &decorateCustomIdentifierImpl{
+				identifier: identifier,
+			},
+			PowerLimiter: &decorateCustomPowerLimiterImpl{
+				powerLimiter: powerLimiter,
+			},
+			Resurrector: &decorateCustomResurrectorImpl{
+				resurrector: resurrector,
+			},
+		}
+
+	case battery != nil && chargerEx == nil && identifier == nil && phaseSwitcher != nil && powerLimiter != nil && resurrector != nil:
+		return &struct {
+			*Charger
+			api.Battery
+			api.PhaseSwitcher
+			api.PowerLimiter
+			api.Resurrector
+		}{
+			Charger: base,
+			Battery: &decorateCustomBatteryImpl{
+				battery: battery,
+			},
+			PhaseSwitcher: &decorateCustomPhaseSwitcherImpl{
+				phaseSwitcher: phaseSwitcher,
+			},
+			PowerLimiter: &decorateCustomPowerLimiterImpl{
+				powerLimiter: powerLimiter,
+			},
+			Resurrector: &decorateCustomResurrectorImpl{
+				resurrector: resurrector,
+			},
+		}
+
+	case battery != nil && chargerEx != nil && identifier == nil && phaseSwitcher != nil && powerLimiter != nil && resurrector != nil:
+		return &struct {
+			*Charger
+			api.Battery
+			api.ChargerEx
+			api.PhaseSwitcher
+			api.PowerLimiter
+			api.Resurrector
+		}{
+			Charger: base,
+			Battery: &decorateCustomBatteryImpl{
+				battery: battery,
+			},
+			ChargerEx: &decorateCustomChargerExImpl{
+				chargerEx: chargerEx,
+			},
+			PhaseSwitcher: &decorateCustomPhaseSwitcherImpl{
+				phaseSwitcher: phaseSwitcher,
+			},
+			PowerLimiter: &decorateCustomPowerLimiterImpl{
+				powerLimiter: powerLimiter,
+			},
+			Resurrector: &decorateCustomResurrectorImpl{
+				resurrector: resurrector,
+			},
+		}
+
+	case battery != nil && chargerEx == nil && identifier != nil && phaseSwitcher != nil && powerLimiter != nil && resurrector != nil:
+		return &struct {
+			*Charger
+			api.Battery
+			api.Identifier
+			api.PhaseSwitcher
+			api.PowerLimiter
+			api.Resurrector
+		}{
+			Charger: base,
+			Battery: &decorateCustomBatteryImpl{
+				battery: battery,
+			},
+			Identifier: &decorateCustomIdentifierImpl{
+				identifier: identifier,
+			},
+			PhaseSwitcher: &decorateCustomPhaseSwitcherImpl{
+				phaseSwitcher: phaseSwitcher,
+			},
+			PowerLimiter: &decorateCustomPowerLimiterImpl{
+				powerLimiter: powerLimiter,
+			},
+			Resurrector: &decorateCustomResurrectorImpl{
+				resurrector: resurrector,
+			},
+		}
+
+	case battery != nil && chargerEx != nil && identifier != nil && phaseSwitcher != nil && powerLimiter != nil && resurrector != nil:
+		return &struct {
+			*Charger
+			api.Battery
+			api.ChargerEx
+			api.Identifier
+			api.PhaseSwitcher
+			api.PowerLimiter
+			api.Resurrector
+		}{
+			Charger: base,
+			Battery: &decorateCustomBatteryImpl{
+				battery: battery,
+			},
+			ChargerEx: &decorateCustomChargerExImpl{
+				chargerEx: chargerEx,
+			},
+			Identifier: &decorateCustomIdentifierImpl{
+				identifier: identifier,
+			},
+			PhaseSwitcher: &decorateCustomPhaseSwitcherImpl{
+				phaseSwitcher: phaseSwitcher,
+			},
+			PowerLimiter: &decorateCustomPowerLimiterImpl{
+				powerLimiter: powerLimiter,
+			},
+			Resurrector: &decorateCustomResurrectorImpl{
+				resurrector: resurrector,
+			},
+		}
 	}
 
 	return nil
@@ -584,6 +1256,14 @@ func (impl *decorateCustomPhaseSwitcherImpl) Phases1p3p(phases int) error {
 	return impl.phaseSwitcher(phases)
 }
 
+type decorateCustomPowerLimiterImpl struct {
+	powerLimiter func(float64) error
+}
+
+func (impl *decorateCustomPowerLimiterImpl) MaxPower(power float64) error {
+	return impl.powerLimiter(power)
+}
+
 type decorateCustomResurrectorImpl struct {
 	resurrector func() error
 }
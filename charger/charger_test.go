@@ -0,0 +1,64 @@
+package charger
+
+import (
+	"testing"
+
+	"github.com/evcc-io/evcc/provider/javascript"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigurableEnableDisable(t *testing.T) {
+	js := func(script string) map[string]any {
+		return map[string]any{"source": "js", "vm": "enabledisable", "script": script}
+	}
+
+	c, err := NewConfigurableFromConfig(map[string]any{
+		"status":     js("'B'"),
+		"enabled":    js("enabled"),
+		"enable":     js("enabled = true; enables++"),
+		"disable":    js("enabled = false; disables++"),
+		"maxcurrent": js("maxcurrent"),
+	})
+	require.NoError(t, err)
+
+	vm, err := javascript.RegisteredVM("enabledisable", "")
+	require.NoError(t, err)
+	require.NoError(t, vm.Set("enabled", false))
+	require.NoError(t, vm.Set("enables", 0))
+	require.NoError(t, vm.Set("disables", 0))
+
+	count := func(name string) int64 {
+		v, err := vm.Get(name)
+		require.NoError(t, err)
+		i, err := v.ToInteger()
+		require.NoError(t, err)
+		return i
+	}
+
+	require.NoError(t, c.Enable(true))
+	assert.Equal(t, int64(1), count("enables"))
+	assert.Equal(t, int64(0), count("disables"))
+
+	enabled, err := c.Enabled()
+	require.NoError(t, err)
+	assert.True(t, enabled)
+
+	require.NoError(t, c.Enable(false))
+	assert.Equal(t, int64(1), count("enables"))
+	assert.Equal(t, int64(1), count("disables"))
+
+	enabled, err = c.Enabled()
+	require.NoError(t, err)
+	assert.False(t, enabled)
+}
+
+func TestConfigurableEnableDisableRequiresStatus(t *testing.T) {
+	_, err := NewConfigurableFromConfig(map[string]any{
+		"enabled":    map[string]any{"source": "js", "script": "true"},
+		"enable":     map[string]any{"source": "js", "script": "true"},
+		"disable":    map[string]any{"source": "js", "script": "true"},
+		"maxcurrent": map[string]any{"source": "js", "script": "true"},
+	})
+	assert.ErrorContains(t, err, "status")
+}
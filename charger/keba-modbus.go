@@ -43,6 +43,7 @@ type Keba struct {
 const (
 	kebaRegChargingState   = 1000
 	kebaRegCableState      = 1004
+	kebaRegErrorCode       = 1006
 	kebaRegCurrents        = 1008 // 6 regs, mA
 	kebaRegSerial          = 1014 // leading zeros trimmed
 	kebaRegProduct         = 1016
@@ -50,6 +51,7 @@ const (
 	kebaRegPower           = 1020 // mW
 	kebaRegEnergy          = 1036 // Wh
 	kebaRegVoltages        = 1040 // 6 regs, V
+	kebaRegMaxSupported    = 1110 // mA, cable and dip switch limit
 	kebaRegRfid            = 1500 // hex
 	kebaRegSessionEnergy   = 1502 // Wh
 	kebaRegPhaseSource     = 1550
@@ -220,6 +222,18 @@ func (wb *Keba) MaxCurrentMillis(current float64) error {
 	return err
 }
 
+var _ api.CurrentBoundsReporter = (*Keba)(nil)
+
+// CurrentBounds implements the api.CurrentBoundsReporter interface
+func (wb *Keba) CurrentBounds() (float64, float64, error) {
+	b, err := wb.conn.ReadHoldingRegisters(kebaRegMaxSupported, 2)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return 0, float64(binary.BigEndian.Uint32(b)) / 1e3, nil
+}
+
 // currentPower implements the api.Meter interface
 func (wb *Keba) currentPower() (float64, error) {
 	b, err := wb.conn.ReadHoldingRegisters(kebaRegPower, 2)
@@ -284,15 +298,58 @@ func (wb *Keba) phases1p3p(phases int) error {
 	return err
 }
 
+// kebaSerial decodes the serial number register
+func kebaSerial(b []byte) string {
+	return strings.TrimLeft(strconv.Itoa(int(binary.BigEndian.Uint32(b))), "0")
+}
+
+// kebaFirmware decodes the firmware version register
+func kebaFirmware(b []byte) string {
+	return fmt.Sprintf("%d.%d.%d", b[0], b[1], b[2])
+}
+
+var _ api.ChargerInformer = (*Keba)(nil)
+
+// ChargerInfo implements the api.ChargerInformer interface
+func (wb *Keba) ChargerInfo() (api.ChargerInfo, error) {
+	res := api.ChargerInfo{
+		Vendor: "KEBA",
+	}
+
+	b, err := wb.conn.ReadHoldingRegisters(kebaRegSerial, 2)
+	if err == nil {
+		res.Serial = kebaSerial(b)
+		b, err = wb.conn.ReadHoldingRegisters(kebaRegProduct, 2)
+	}
+
+	if err == nil {
+		res.Model = strconv.Itoa(int(binary.BigEndian.Uint32(b)))
+		b, err = wb.conn.ReadHoldingRegisters(kebaRegFirmware, 2)
+	}
+
+	if err == nil {
+		res.Firmware = kebaFirmware(b)
+		b, err = wb.conn.ReadHoldingRegisters(kebaRegErrorCode, 2)
+	}
+
+	if err == nil {
+		if code := binary.BigEndian.Uint32(b); code != 0 {
+			res.ErrorCode = strconv.Itoa(int(code))
+		}
+	}
+
+	return res, err
+}
+
 var _ api.Diagnosis = (*Keba)(nil)
 
 // Diagnose implements the api.Diagnosis interface
 func (wb *Keba) Diagnose() {
 	if b, err := wb.conn.ReadHoldingRegisters(kebaRegSerial, 2); err == nil {
-		fmt.Printf("\tSerial:\t%s\n", strings.TrimLeft(strconv.Itoa(int(binary.BigEndian.Uint32(b))), "0"))
+		fmt.Printf("\tSerial:\t%s\n", kebaSerial(b))
 	}
 	if b, err := wb.conn.ReadHoldingRegisters(kebaRegFirmware, 2); err == nil {
-		fmt.Printf("\tFirmware:\t%d.%d.%d\n", b[0], b[1], b[2])
+		fmt.Printf("\tFirmware:\t%s\n", kebaFirmware(b))
 	}
 	if b, err := wb.conn.ReadHoldingRegisters(kebaRegProduct, 2); err == nil {
 		fmt.Printf("\tProduct:\t%6d\n", binary.BigEndian.Uint32(b))
@@ -0,0 +1,13 @@
+package charger
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKebaIdentity(t *testing.T) {
+	// recorded serial and firmware registers
+	assert.Equal(t, "18313049", kebaSerial([]byte{0x01, 0x17, 0x6f, 0x59}))
+	assert.Equal(t, "1.15.0", kebaFirmware([]byte{0x01, 0x0f, 0x00, 0x00}))
+}
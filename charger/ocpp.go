@@ -32,6 +32,7 @@ type OCPP struct {
 	phaseSwitching    bool
 	chargingRateUnit  types.ChargingRateUnitType
 	lp                loadpoint.API
+	keys              ocppConfigKeys // vendor-specific configuration keys
 }
 
 const defaultIdTag = "evcc"
@@ -43,6 +44,7 @@ func init() {
 // NewOCPPFromConfig creates a OCPP charger from generic config
 func NewOCPPFromConfig(other map[string]interface{}) (api.Charger, error) {
 	cc := struct {
+		Protocol         string
 		StationId        string
 		IdTag            string
 		Connector        int
@@ -53,7 +55,9 @@ func NewOCPPFromConfig(other map[string]interface{}) (api.Charger, error) {
 		BootNotification *bool
 		GetConfiguration *bool
 		ChargingRateUnit string
+		Keys             ocppConfigKeys
 	}{
+		Protocol:         ocpp.Protocol16,
 		Connector:        1,
 		IdTag:            defaultIdTag,
 		ConnectTimeout:   ocppConnectTimeout,
@@ -66,6 +70,15 @@ func NewOCPPFromConfig(other map[string]interface{}) (api.Charger, error) {
 	}
 
 	boot := cc.BootNotification != nil && *cc.BootNotification
+
+	switch cc.Protocol {
+	case ocpp.Protocol16:
+	case ocpp.Protocol201:
+		return newOCPP201FromConfig(cc.StationId, cc.Connector, cc.IdTag, cc.MeterValues, cc.MeterInterval, boot, cc.ConnectTimeout, cc.Timeout, cc.ChargingRateUnit)
+	default:
+		return nil, fmt.Errorf("invalid protocol: %s", cc.Protocol)
+	}
+
 	noConfig := cc.GetConfiguration != nil && !*cc.GetConfiguration
 
 	c, err := NewOCPP(cc.StationId, cc.Connector, cc.IdTag,
@@ -76,6 +89,10 @@ func NewOCPPFromConfig(other map[string]interface{}) (api.Charger, error) {
 		return c, err
 	}
 
+	if err := c.setConfigKeys(cc.Keys, !noConfig); err != nil {
+		return nil, err
+	}
+
 	var powerG func() (float64, error)
 	if c.hasMeasurement(types.MeasurandPowerActiveImport) {
 		powerG = c.currentPower
@@ -92,7 +109,7 @@ func NewOCPPFromConfig(other map[string]interface{}) (api.Charger, error) {
 	}
 
 	var phasesS func(int) error
-	if c.phaseSwitching {
+	if c.phaseSwitching || c.keys.Phases != "" {
 		phasesS = c.phases1p3p
 	}
 
@@ -279,6 +296,33 @@ func (c *OCPP) Connector() *ocpp.Connector {
 	return c.conn
 }
 
+var _ api.Diagnosis = (*OCPP)(nil)
+
+// Diagnose implements the api.Diagnosis interface
+func (c *OCPP) Diagnose() {
+	fmt.Printf("\tConnected:\t%v\n", c.conn.Connected())
+	if ts, ok := c.conn.LastSeen(); ok {
+		fmt.Printf("\tLast message:\t%v ago\n", time.Since(ts).Round(time.Second))
+	}
+}
+
+var _ api.ConnectionMonitor = (*OCPP)(nil)
+
+// Connection implements the api.ConnectionMonitor interface
+func (c *OCPP) Connection() (bool, time.Time) {
+	ts, _ := c.conn.LastSeen()
+	return c.conn.Connected(), ts
+}
+
+var _ api.ChargerInformer = (*OCPP)(nil)
+
+// ChargerInfo implements the api.ChargerInformer interface
+func (c *OCPP) ChargerInfo() (api.ChargerInfo, error) {
+	res := c.conn.ChargePoint().Info()
+	res.ErrorCode = c.conn.ErrorCode()
+	return res, nil
+}
+
 // hasMeasurement checks if meterValuesSample contains given measurement
 func (c *OCPP) hasMeasurement(val types.Measurand) bool {
 	return slices.Contains(strings.Split(c.meterValuesSample, ","), string(val))
@@ -319,6 +363,12 @@ func (c *OCPP) Status() (api.ChargeStatus, error) {
 
 // Enabled implements the api.Charger interface
 func (c *OCPP) Enabled() (bool, error) {
+	// the charge point starts the transaction asynchronously after accepting the remote start
+	if _, expired := c.conn.RemoteStartPending(); expired {
+		c.log.WARN.Printf("remote start: transaction not started within %v", c.timeout)
+		c.enabled = false
+	}
+
 	return c.enabled, nil
 }
 
@@ -336,6 +386,12 @@ func (c *OCPP) Enable(enable bool) (err error) {
 	if enable {
 		if txn > 0 {
 			// we have the transaction id, treat as enabled
+			c.conn.ClearRemoteStart()
+			return nil
+		}
+
+		if pending, _ := c.conn.RemoteStartPending(); pending {
+			// remote start accepted, waiting for the transaction
 			return nil
 		}
 
@@ -350,30 +406,44 @@ func (c *OCPP) Enable(enable bool) (err error) {
 			request.ConnectorId = &connector
 			request.ChargingProfile = c.getTxChargingProfile(c.current, 0)
 		})
-	} else {
-		// if no transaction is running, the vehicle may have stopped it (which is ok) or an unknown transaction is running
-		if txn == 0 {
-			// we cannot tell if a transaction is really running, so we check the status
-			status, err := c.Status()
-			if err != nil {
-				return err
-			}
-			if status == api.StatusC {
-				return errors.New("cannot disable: unknown transaction running")
-			}
 
+		if err = c.wait(err, rc); err == nil {
+			c.conn.RemoteStartAccepted()
+		}
+
+		return err
+	}
+
+	// remote start accepted but transaction not yet started, wait for it to stop it
+	if txn == 0 && c.conn.ClearRemoteStart() {
+		if txn, err = c.conn.WaitForTransaction(c.timeout); errors.Is(err, api.ErrTimeout) {
+			// transaction never started
 			return nil
 		}
+	}
 
-		err = ocpp.Instance().RemoteStopTransaction(c.conn.ChargePoint().ID(), func(resp *core.RemoteStopTransactionConfirmation, err error) {
-			if err == nil && resp != nil && resp.Status != types.RemoteStartStopStatusAccepted {
-				err = errors.New(string(resp.Status))
-			}
+	// if no transaction is running, the vehicle may have stopped it (which is ok) or an unknown transaction is running
+	if txn == 0 {
+		// we cannot tell if a transaction is really running, so we check the status
+		status, err := c.Status()
+		if err != nil {
+			return err
+		}
+		if status == api.StatusC {
+			return errors.New("cannot disable: unknown transaction running")
+		}
 
-			rc <- err
-		}, txn)
+		return nil
 	}
 
+	err = ocpp.Instance().RemoteStopTransaction(c.conn.ChargePoint().ID(), func(resp *core.RemoteStopTransactionConfirmation, err error) {
+		if err == nil && resp != nil && resp.Status != types.RemoteStartStopStatusAccepted {
+			err = errors.New(string(resp.Status))
+		}
+
+		rc <- err
+	}, txn)
+
 	return c.wait(err, rc)
 }
 
@@ -455,7 +525,13 @@ var _ api.ChargerEx = (*OCPP)(nil)
 
 // MaxCurrentMillis implements the api.ChargerEx interface
 func (c *OCPP) MaxCurrentMillis(current float64) error {
-	err := c.updatePeriod(current)
+	var err error
+	if c.keys.Current != "" {
+		err = c.setCurrentKey(current)
+	} else {
+		err = c.updatePeriod(current)
+	}
+
 	if err == nil {
 		c.current = current
 	}
@@ -479,6 +555,10 @@ func (c *OCPP) currents() (float64, float64, float64, error) {
 
 // Phases1p3p implements the api.PhaseSwitcher interface
 func (c *OCPP) phases1p3p(phases int) error {
+	if c.keys.Phases != "" {
+		return c.setPhasesKey(phases)
+	}
+
 	c.phases = phases
 
 	// NOTE: this will currently _never_ do anything since
@@ -487,11 +567,48 @@ func (c *OCPP) phases1p3p(phases int) error {
 	return c.updatePeriod(c.current)
 }
 
-// // Identify implements the api.Identifier interface
-// Unless charger uses vehicle ID as idTag in authorize.req it is not possible to implement this in ocpp1.6
-// func (c *OCPP) Identify() (string, error) {
-// 	return "", errors.New("not implemented")
-// }
+var _ api.FaultResetter = (*OCPP)(nil)
+
+// ResetFault implements the api.FaultResetter interface
+func (c *OCPP) ResetFault() error {
+	rc := make(chan error, 1)
+
+	err := ocpp.Instance().Reset(c.conn.ChargePoint().ID(), func(resp *core.ResetConfirmation, err error) {
+		if err == nil && resp != nil && resp.Status != core.ResetStatusAccepted {
+			err = fmt.Errorf("Reset failed: %s", resp.Status)
+		}
+
+		rc <- err
+	}, core.ResetTypeSoft)
+
+	return c.wait(err, rc)
+}
+
+var _ api.Identifier = (*OCPP)(nil)
+
+// Identify implements the api.Identifier interface.
+// Chargers supporting ISO 15118 or autocharge start the transaction using the vehicle's EVCCID or MAC as idTag.
+func (c *OCPP) Identify() (string, error) {
+	// ignore transactions started remotely by evcc
+	if id := c.conn.IdTag(); id != c.idtag {
+		return id, nil
+	}
+
+	return "", nil
+}
+
+var _ api.ChargeTransactionReporter = (*OCPP)(nil)
+
+// ChargeTransaction implements the api.ChargeTransactionReporter interface
+func (c *OCPP) ChargeTransaction() (api.ChargeTransaction, error) {
+	txn, err := c.conn.Transaction()
+	if err == nil {
+		// transactions started remotely by evcc use the configured id tag
+		txn.External = txn.IdTag != c.idtag
+	}
+
+	return txn, err
+}
 
 // LoadpointControl implements loadpoint.Controller
 func (c *OCPP) LoadpointControl(lp loadpoint.API) {
@@ -31,6 +31,11 @@ type Connector struct {
 
 	txnCount int // change initial value to the last known global transaction. Needs persistence
 	txnId    int
+	txnC     chan struct{}          // signals transaction start
+	idTag    string                 // transaction id tag, e.g. ISO 15118 EVCCID or autocharge MAC
+	txn      *api.ChargeTransaction // current or last transaction
+
+	remoteStart time.Time // accepted remote start awaiting the transaction
 }
 
 func NewConnector(log *util.Logger, id int, cp *CP, timeout time.Duration) (*Connector, error) {
@@ -40,6 +45,7 @@ func NewConnector(log *util.Logger, id int, cp *CP, timeout time.Duration) (*Con
 		id:           id,
 		clock:        clock.New(),
 		statusC:      make(chan struct{}),
+		txnC:         make(chan struct{}, 1),
 		measurements: make(map[types.Measurand]types.SampledValue),
 		timeout:      timeout,
 	}
@@ -53,6 +59,23 @@ func (conn *Connector) TestClock(clock clock.Clock) {
 	conn.clock = clock
 }
 
+// ErrorCode returns the connector's current error code including vendor details, if any
+func (conn *Connector) ErrorCode() string {
+	conn.mu.Lock()
+	defer conn.mu.Unlock()
+
+	if conn.status == nil || conn.status.ErrorCode == core.NoError {
+		return ""
+	}
+
+	res := string(conn.status.ErrorCode)
+	if conn.status.VendorErrorCode != "" {
+		res += " (" + conn.status.VendorErrorCode + ")"
+	}
+
+	return res
+}
+
 func (conn *Connector) ChargePoint() *CP {
 	return conn.cp
 }
@@ -70,6 +93,16 @@ func (conn *Connector) TriggerMessageRequest(feature remotetrigger.MessageTrigge
 	})
 }
 
+// Connected returns the charge point's connection state
+func (conn *Connector) Connected() bool {
+	return conn.cp.Connected()
+}
+
+// LastSeen returns the time of the charge point's last message while connected
+func (conn *Connector) LastSeen() (time.Time, bool) {
+	return Instance().LastSeen(conn.cp.ID())
+}
+
 // WatchDog triggers meter values messages if older than timeout.
 // Must be wrapped in a goroutine.
 func (conn *Connector) WatchDog(timeout time.Duration) {
@@ -114,6 +147,82 @@ func (conn *Connector) TransactionID() (int, error) {
 	return conn.txnId, nil
 }
 
+// RemoteStartAccepted records a remote start accepted by the charge point that awaits its transaction
+func (conn *Connector) RemoteStartAccepted() {
+	conn.mu.Lock()
+	defer conn.mu.Unlock()
+
+	conn.remoteStart = conn.clock.Now()
+}
+
+// RemoteStartPending returns true if an accepted remote start awaits its transaction.
+// The remote start is cleared once the transaction started or the timeout elapsed, the latter is reported as expired.
+func (conn *Connector) RemoteStartPending() (pending, expired bool) {
+	conn.mu.Lock()
+	defer conn.mu.Unlock()
+
+	switch {
+	case conn.remoteStart.IsZero():
+		return false, false
+	case conn.txnId > 0:
+		conn.remoteStart = time.Time{}
+		return false, false
+	case conn.clock.Since(conn.remoteStart) > conn.timeout:
+		conn.remoteStart = time.Time{}
+		return false, true
+	}
+
+	return true, false
+}
+
+// ClearRemoteStart clears the accepted remote start and returns true if it was awaiting its transaction
+func (conn *Connector) ClearRemoteStart() bool {
+	conn.mu.Lock()
+	defer conn.mu.Unlock()
+
+	res := !conn.remoteStart.IsZero() && conn.txnId == 0
+	conn.remoteStart = time.Time{}
+
+	return res
+}
+
+// WaitForTransaction waits for a transaction to be started and returns its id
+func (conn *Connector) WaitForTransaction(timeout time.Duration) (int, error) {
+	deadline := time.After(timeout)
+
+	for {
+		if txn, err := conn.TransactionID(); err != nil || txn > 0 {
+			return txn, err
+		}
+
+		select {
+		case <-conn.txnC:
+		case <-deadline:
+			return 0, api.ErrTimeout
+		}
+	}
+}
+
+// Transaction returns the current or last transaction
+func (conn *Connector) Transaction() (api.ChargeTransaction, error) {
+	conn.mu.Lock()
+	defer conn.mu.Unlock()
+
+	if conn.txn == nil {
+		return api.ChargeTransaction{}, api.ErrNotAvailable
+	}
+
+	return *conn.txn, nil
+}
+
+// IdTag returns the id tag of the current transaction
+func (conn *Connector) IdTag() string {
+	conn.mu.Lock()
+	defer conn.mu.Unlock()
+
+	return conn.idTag
+}
+
 func (conn *Connector) Status() (api.ChargeStatus, error) {
 	conn.mu.Lock()
 	defer conn.mu.Unlock()
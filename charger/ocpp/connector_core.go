@@ -1,10 +1,13 @@
 package ocpp
 
 import (
+	"strconv"
 	"time"
 
+	"github.com/evcc-io/evcc/api"
 	"github.com/lorenzodonini/ocpp-go/ocpp1.6/core"
 	"github.com/lorenzodonini/ocpp-go/ocpp1.6/types"
+	"github.com/samber/lo"
 )
 
 // timestampValid returns false if status timestamps are outdated
@@ -36,6 +39,11 @@ func (conn *Connector) StatusNotification(request *core.StatusNotificationReques
 		conn.log.TRACE.Printf("ignoring status: %s < %s", request.Timestamp.Time, conn.status.Timestamp)
 	}
 
+	// vehicle disconnected
+	if conn.status.Status == core.ChargePointStatusAvailable {
+		conn.idTag = ""
+	}
+
 	return new(core.StatusNotificationConfirmation), nil
 }
 
@@ -54,6 +62,11 @@ func (conn *Connector) MeterValues(request *core.MeterValuesRequest) (*core.Mete
 	if request.TransactionId != nil && conn.txnId == 0 {
 		conn.log.DEBUG.Printf("hijacking transaction: %d", *request.TransactionId)
 		conn.txnId = *request.TransactionId
+
+		// transaction start was not observed
+		conn.txn = &api.ChargeTransaction{
+			ID: strconv.Itoa(conn.txnId),
+		}
 	}
 
 	for _, meterValue := range request.MeterValue {
@@ -86,6 +99,18 @@ func (conn *Connector) StartTransaction(request *core.StartTransactionRequest) (
 
 	conn.txnCount++
 	conn.txnId = conn.txnCount
+	conn.idTag = request.IdTag
+
+	conn.txn = &api.ChargeTransaction{
+		ID:         strconv.Itoa(conn.txnId),
+		IdTag:      request.IdTag,
+		MeterStart: lo.ToPtr(float64(request.MeterStart) / 1e3),
+	}
+
+	select {
+	case conn.txnC <- struct{}{}:
+	default:
+	}
 
 	res := &core.StartTransactionConfirmation{
 		IdTagInfo: &types.IdTagInfo{
@@ -132,7 +157,12 @@ func (conn *Connector) StopTransaction(request *core.StopTransactionRequest) (*c
 		return res, nil
 	}
 
+	if conn.txn != nil {
+		conn.txn.MeterStop = lo.ToPtr(float64(request.MeterStop) / 1e3)
+	}
+
 	conn.txnId = 0
+	conn.idTag = ""
 
 	res := &core.StopTransactionConfirmation{
 		IdTagInfo: &types.IdTagInfo{
@@ -1,6 +1,10 @@
 package ocpp
 
 const (
+	// Protocol versions
+	Protocol16  = "1.6"
+	Protocol201 = "2.0.1"
+
 	// Core profile keys
 	KeyNumberOfConnectors = "NumberOfConnectors"
 
@@ -17,4 +21,9 @@ const (
 
 	// Alfen specific keys
 	KeyAlfenPlugAndChargeIdentifier = "PlugAndChargeIdentifier"
+
+	// OCPP 2.0.1 components and variables
+	KeySampledDataCtrlr    = "SampledDataCtrlr"
+	KeyTxUpdatedMeasurands = "TxUpdatedMeasurands"
+	KeyTxUpdatedInterval   = "TxUpdatedInterval"
 )
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"sync"
 
+	"github.com/evcc-io/evcc/api"
 	"github.com/evcc-io/evcc/util"
 )
 
@@ -20,7 +21,10 @@ type CP struct {
 	connected bool
 	connectC  chan struct{}
 
+	info api.ChargerInfo // boot notification data
+
 	connectors map[int]*Connector
+	evses      map[int]*EVSE // OCPP 2.0.1
 }
 
 func NewChargePoint(log *util.Logger, id string) *CP {
@@ -30,6 +34,7 @@ func NewChargePoint(log *util.Logger, id string) *CP {
 
 		connectC:   make(chan struct{}),
 		connectors: make(map[int]*Connector),
+		evses:      make(map[int]*EVSE),
 	}
 }
 
@@ -65,6 +70,38 @@ func (cp *CP) connectorByTransactionID(id int) *Connector {
 	return nil
 }
 
+func (cp *CP) registerEVSE(id int, evse *EVSE) error {
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+
+	if _, ok := cp.evses[id]; ok {
+		return fmt.Errorf("evse already registered: %d", id)
+	}
+
+	cp.evses[id] = evse
+	return nil
+}
+
+func (cp *CP) evseByID(id int) *EVSE {
+	cp.mu.RLock()
+	defer cp.mu.RUnlock()
+
+	return cp.evses[id]
+}
+
+func (cp *CP) evseByTransactionID(id string) *EVSE {
+	cp.mu.RLock()
+	defer cp.mu.RUnlock()
+
+	for _, evse := range cp.evses {
+		if txn, err := evse.TransactionID(); err == nil && txn == id {
+			return evse
+		}
+	}
+
+	return nil
+}
+
 func (cp *CP) ID() string {
 	cp.mu.RLock()
 	defer cp.mu.RUnlock()
@@ -72,6 +109,22 @@ func (cp *CP) ID() string {
 	return cp.id
 }
 
+// setInfo stores the charge point's boot notification data
+func (cp *CP) setInfo(info api.ChargerInfo) {
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+
+	cp.info = info
+}
+
+// Info returns the charge point's boot notification data
+func (cp *CP) Info() api.ChargerInfo {
+	cp.mu.RLock()
+	defer cp.mu.RUnlock()
+
+	return cp.info
+}
+
 func (cp *CP) RegisterID(id string) {
 	cp.mu.Lock()
 	defer cp.mu.Unlock()
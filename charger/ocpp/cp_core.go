@@ -4,6 +4,7 @@ import (
 	"errors"
 	"time"
 
+	"github.com/evcc-io/evcc/api"
 	"github.com/lorenzodonini/ocpp-go/ocpp1.6/core"
 	"github.com/lorenzodonini/ocpp-go/ocpp1.6/firmware"
 	"github.com/lorenzodonini/ocpp-go/ocpp1.6/types"
@@ -12,6 +13,7 @@ import (
 const (
 	messageExpiry     = 30 * time.Second
 	transactionExpiry = time.Hour
+	heartbeatInterval = 60 // TODO
 )
 
 var (
@@ -32,9 +34,18 @@ func (cp *CP) Authorize(request *core.AuthorizeRequest) (*core.AuthorizeConfirma
 }
 
 func (cp *CP) BootNotification(request *core.BootNotificationRequest) (*core.BootNotificationConfirmation, error) {
+	if request != nil {
+		cp.setInfo(api.ChargerInfo{
+			Vendor:   request.ChargePointVendor,
+			Model:    request.ChargePointModel,
+			Serial:   request.ChargePointSerialNumber,
+			Firmware: request.FirmwareVersion,
+		})
+	}
+
 	res := &core.BootNotificationConfirmation{
 		CurrentTime: types.NewDateTime(time.Now()),
-		Interval:    60, // TODO
+		Interval:    heartbeatInterval,
 		Status:      core.RegistrationStatusAccepted,
 	}
 
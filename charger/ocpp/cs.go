@@ -4,16 +4,20 @@ import (
 	"errors"
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/evcc-io/evcc/util"
 	ocpp16 "github.com/lorenzodonini/ocpp-go/ocpp1.6"
+	ocpp2 "github.com/lorenzodonini/ocpp-go/ocpp2.0.1"
 )
 
 type CS struct {
 	mu  sync.Mutex
 	log *util.Logger
 	ocpp16.CentralSystem
-	cps map[string]*CP
+	CSMS    ocpp2.CSMS // OCPP 2.0.1 endpoint sharing the websocket server
+	cps     map[string]*CP
+	monitor *monitor
 }
 
 // Register registers a charge point with the central system.
@@ -57,15 +61,34 @@ func (cs *CS) ChargepointByID(id string) (*CP, error) {
 	return cp, nil
 }
 
+// LastSeen returns the time of the charge point's last message while connected
+func (cs *CS) LastSeen(id string) (time.Time, bool) {
+	if cs.monitor == nil {
+		return time.Time{}, false
+	}
+
+	return cs.monitor.LastSeen(id)
+}
+
 // NewChargePoint implements ocpp16.ChargePointConnectionHandler
 func (cs *CS) NewChargePoint(chargePoint ocpp16.ChargePointConnection) {
+	cs.connect(chargePoint.ID())
+}
+
+// ChargePointDisconnected implements ocpp16.ChargePointConnectionHandler
+func (cs *CS) ChargePointDisconnected(chargePoint ocpp16.ChargePointConnection) {
+	cs.disconnect(chargePoint.ID())
+}
+
+// connect associates a connected charge point with its configuration independent of protocol version
+func (cs *CS) connect(id string) {
 	cs.mu.Lock()
 	defer cs.mu.Unlock()
 
 	// check for configured charge point
-	cp, ok := cs.cps[chargePoint.ID()]
+	cp, ok := cs.cps[id]
 	if ok {
-		cs.log.DEBUG.Printf("charge point connected: %s", chargePoint.ID())
+		cs.log.DEBUG.Printf("charge point connected: %s", id)
 
 		// trigger initial connection if charge point is already setup
 		if cp != nil {
@@ -78,12 +101,12 @@ func (cs *CS) NewChargePoint(chargePoint ocpp16.ChargePointConnection) {
 	// check for configured anonymous charge point
 	cp, ok = cs.cps[""]
 	if ok && cp != nil {
-		cs.log.INFO.Printf("charge point connected, registering: %s", chargePoint.ID())
+		cs.log.INFO.Printf("charge point connected, registering: %s", id)
 
 		// update id
-		cp.RegisterID(chargePoint.ID())
+		cp.RegisterID(id)
 
-		cs.cps[chargePoint.ID()] = cp
+		cs.cps[id] = cp
 		delete(cs.cps, "")
 
 		cp.connect(true)
@@ -91,18 +114,17 @@ func (cs *CS) NewChargePoint(chargePoint ocpp16.ChargePointConnection) {
 		return
 	}
 
-	cs.log.WARN.Printf("unknown charge point connected: %s", chargePoint.ID())
+	cs.log.WARN.Printf("unknown charge point connected: %s", id)
 
 	// register unknown charge point
 	// when charge point setup is complete, it will eventually be associated with the connected id
-	cs.cps[chargePoint.ID()] = nil
+	cs.cps[id] = nil
 }
 
-// ChargePointDisconnected implements ocpp16.ChargePointConnectionHandler
-func (cs *CS) ChargePointDisconnected(chargePoint ocpp16.ChargePointConnection) {
-	cs.log.DEBUG.Printf("charge point disconnected: %s", chargePoint.ID())
+func (cs *CS) disconnect(id string) {
+	cs.log.DEBUG.Printf("charge point disconnected: %s", id)
 
-	if cp, err := cs.ChargepointByID(chargePoint.ID()); err == nil {
+	if cp, err := cs.ChargepointByID(id); err == nil {
 		cp.connect(false)
 	}
 }
@@ -0,0 +1,172 @@
+package ocpp
+
+import (
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	ocpp2 "github.com/lorenzodonini/ocpp-go/ocpp2.0.1"
+	"github.com/lorenzodonini/ocpp-go/ocpp2.0.1/authorization"
+	"github.com/lorenzodonini/ocpp-go/ocpp2.0.1/availability"
+	"github.com/lorenzodonini/ocpp-go/ocpp2.0.1/meter"
+	"github.com/lorenzodonini/ocpp-go/ocpp2.0.1/provisioning"
+	"github.com/lorenzodonini/ocpp-go/ocpp2.0.1/remotecontrol"
+	"github.com/lorenzodonini/ocpp-go/ocpp2.0.1/transactions"
+	"github.com/lorenzodonini/ocpp-go/ocpp2.0.1/types"
+)
+
+// csms201 handles OCPP 2.0.1 messages. Charge point registration and connection state is shared with 1.6.
+type csms201 struct {
+	cs *CS
+}
+
+// NewChargingStation implements ocpp2.ChargingStationConnectionHandler
+func (h *csms201) NewChargingStation(chargingStation ocpp2.ChargingStationConnection) {
+	h.cs.connect(chargingStation.ID())
+}
+
+// ChargingStationDisconnected implements ocpp2.ChargingStationConnectionHandler
+func (h *csms201) ChargingStationDisconnected(chargingStation ocpp2.ChargingStationConnection) {
+	h.cs.disconnect(chargingStation.ID())
+}
+
+// cs actions
+
+func (cs *CS) TriggerMessageRequest201(id string, requestedMessage remotecontrol.MessageTrigger, props ...func(request *remotecontrol.TriggerMessageRequest)) {
+	if err := cs.CSMS.TriggerMessage(id, func(request *remotecontrol.TriggerMessageResponse, err error) {
+		log := cs.log.TRACE
+		if err == nil && request != nil && request.Status != remotecontrol.TriggerMessageStatusAccepted {
+			log = cs.log.ERROR
+		}
+
+		var status remotecontrol.TriggerMessageStatus
+		if request != nil {
+			status = request.Status
+		}
+
+		log.Printf("TriggerMessage %s for %s: %+v", requestedMessage, id, status)
+	}, requestedMessage, props...); err != nil {
+		cs.log.ERROR.Printf("send TriggerMessage %s for %s failed: %v", requestedMessage, id, err)
+	}
+}
+
+// cp actions
+
+func (h *csms201) OnAuthorize(id string, request *authorization.AuthorizeRequest) (*authorization.AuthorizeResponse, error) {
+	if _, err := h.cs.ChargepointByID(id); err != nil {
+		return nil, err
+	}
+
+	// TODO check if this authorizes foreign RFID tags
+	res := &authorization.AuthorizeResponse{
+		IdTokenInfo: types.IdTokenInfo{
+			Status: types.AuthorizationStatusAccepted,
+		},
+	}
+
+	return res, nil
+}
+
+func (h *csms201) OnBootNotification(id string, request *provisioning.BootNotificationRequest) (*provisioning.BootNotificationResponse, error) {
+	cp, err := h.cs.ChargepointByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if request != nil {
+		cp.setInfo(api.ChargerInfo{
+			Vendor:   request.ChargingStation.VendorName,
+			Model:    request.ChargingStation.Model,
+			Serial:   request.ChargingStation.SerialNumber,
+			Firmware: request.ChargingStation.FirmwareVersion,
+		})
+	}
+
+	res := &provisioning.BootNotificationResponse{
+		CurrentTime: types.NewDateTime(time.Now()),
+		Interval:    heartbeatInterval,
+		Status:      provisioning.RegistrationStatusAccepted,
+	}
+
+	return res, nil
+}
+
+func (h *csms201) OnNotifyReport(id string, request *provisioning.NotifyReportRequest) (*provisioning.NotifyReportResponse, error) {
+	if _, err := h.cs.ChargepointByID(id); err != nil {
+		return nil, err
+	}
+
+	return new(provisioning.NotifyReportResponse), nil
+}
+
+func (h *csms201) OnHeartbeat(id string, request *availability.HeartbeatRequest) (*availability.HeartbeatResponse, error) {
+	if _, err := h.cs.ChargepointByID(id); err != nil {
+		return nil, err
+	}
+
+	res := &availability.HeartbeatResponse{
+		CurrentTime: *types.NewDateTime(time.Now()),
+	}
+
+	return res, nil
+}
+
+func (h *csms201) OnStatusNotification(id string, request *availability.StatusNotificationRequest) (*availability.StatusNotificationResponse, error) {
+	cp, err := h.cs.ChargepointByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if request == nil {
+		return nil, ErrInvalidRequest
+	}
+
+	evse := cp.evseByID(request.EvseID)
+	if evse == nil {
+		return nil, ErrInvalidConnector
+	}
+
+	return evse.StatusNotification(request)
+}
+
+func (h *csms201) OnMeterValues(id string, request *meter.MeterValuesRequest) (*meter.MeterValuesResponse, error) {
+	cp, err := h.cs.ChargepointByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if request == nil {
+		return nil, ErrInvalidRequest
+	}
+
+	evse := cp.evseByID(request.EvseID)
+	if evse == nil {
+		return nil, ErrInvalidConnector
+	}
+
+	return evse.MeterValues(request)
+}
+
+func (h *csms201) OnTransactionEvent(id string, request *transactions.TransactionEventRequest) (*transactions.TransactionEventResponse, error) {
+	cp, err := h.cs.ChargepointByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if request == nil {
+		return nil, ErrInvalidRequest
+	}
+
+	// evse is only required for the first event of a transaction
+	var evse *EVSE
+	if request.Evse != nil {
+		evse = cp.evseByID(request.Evse.ID)
+	} else {
+		evse = cp.evseByTransactionID(request.TransactionInfo.TransactionID)
+	}
+
+	if evse == nil {
+		return nil, ErrInvalidTransaction
+	}
+
+	return evse.TransactionEvent(request)
+}
@@ -0,0 +1,332 @@
+package ocpp
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/util"
+	"github.com/lorenzodonini/ocpp-go/ocpp2.0.1/availability"
+	"github.com/lorenzodonini/ocpp-go/ocpp2.0.1/remotecontrol"
+	"github.com/lorenzodonini/ocpp-go/ocpp2.0.1/transactions"
+	"github.com/lorenzodonini/ocpp-go/ocpp2.0.1/types"
+)
+
+// EVSE is the OCPP 2.0.1 equivalent of a 1.6 connector
+type EVSE struct {
+	log   *util.Logger
+	mu    sync.Mutex
+	clock clock.Clock // mockable time
+	cp    *CP
+	id    int
+
+	status  *availability.StatusNotificationRequest
+	statusC chan struct{}
+
+	chargingState transactions.ChargingState
+
+	meterUpdated time.Time
+	measurements map[types.Measurand]types.SampledValue
+	timeout      time.Duration
+
+	txnId   string
+	txnC    chan struct{} // signals transaction start
+	idToken string        // transaction id token, e.g. ISO 15118 eMAID or autocharge MAC
+
+	remoteStart time.Time // accepted remote start awaiting the transaction
+}
+
+func NewEVSE(log *util.Logger, id int, cp *CP, timeout time.Duration) (*EVSE, error) {
+	evse := &EVSE{
+		log:          log,
+		cp:           cp,
+		id:           id,
+		clock:        clock.New(),
+		statusC:      make(chan struct{}),
+		txnC:         make(chan struct{}, 1),
+		measurements: make(map[types.Measurand]types.SampledValue),
+		timeout:      timeout,
+	}
+
+	err := cp.registerEVSE(id, evse)
+
+	return evse, err
+}
+
+func (evse *EVSE) TestClock(clock clock.Clock) {
+	evse.clock = clock
+}
+
+// IdToken returns the id token of the current transaction
+func (evse *EVSE) IdToken() string {
+	evse.mu.Lock()
+	defer evse.mu.Unlock()
+
+	return evse.idToken
+}
+
+func (evse *EVSE) ChargePoint() *CP {
+	return evse.cp
+}
+
+func (evse *EVSE) ID() int {
+	return evse.id
+}
+
+func (evse *EVSE) TriggerMessageRequest(feature remotecontrol.MessageTrigger, f ...func(request *remotecontrol.TriggerMessageRequest)) {
+	Instance().TriggerMessageRequest201(evse.cp.ID(), feature, func(request *remotecontrol.TriggerMessageRequest) {
+		request.Evse = &types.EVSE{ID: evse.id}
+		for _, f := range f {
+			f(request)
+		}
+	})
+}
+
+// WatchDog triggers meter values messages if older than timeout.
+// Must be wrapped in a goroutine.
+func (evse *EVSE) WatchDog(timeout time.Duration) {
+	for ; true; <-time.Tick(timeout) {
+		evse.mu.Lock()
+		update := evse.txnId != "" && evse.clock.Since(evse.meterUpdated) > timeout
+		evse.mu.Unlock()
+
+		if update {
+			evse.TriggerMessageRequest(remotecontrol.MessageTriggerMeterValues)
+		}
+	}
+}
+
+// Initialized waits for initial charge point status notification
+func (evse *EVSE) Initialized() error {
+	trigger := time.After(evse.timeout / 2)
+	timeout := time.After(evse.timeout)
+	for {
+		select {
+		case <-evse.statusC:
+			return nil
+
+		case <-trigger:
+			evse.TriggerMessageRequest(remotecontrol.MessageTriggerStatusNotification)
+
+		case <-timeout:
+			return api.ErrTimeout
+		}
+	}
+}
+
+// TransactionID returns the current transaction id
+func (evse *EVSE) TransactionID() (string, error) {
+	if !evse.cp.Connected() {
+		return "", api.ErrTimeout
+	}
+
+	evse.mu.Lock()
+	defer evse.mu.Unlock()
+
+	return evse.txnId, nil
+}
+
+// WaitForTransaction waits for a transaction to be started and returns its id
+func (evse *EVSE) WaitForTransaction(timeout time.Duration) (string, error) {
+	deadline := time.After(timeout)
+
+	for {
+		if txn, err := evse.TransactionID(); err != nil || txn != "" {
+			return txn, err
+		}
+
+		select {
+		case <-evse.txnC:
+		case <-deadline:
+			return "", api.ErrTimeout
+		}
+	}
+}
+
+// RemoteStartAccepted records a remote start accepted by the charge point that awaits its transaction
+func (evse *EVSE) RemoteStartAccepted() {
+	evse.mu.Lock()
+	defer evse.mu.Unlock()
+
+	evse.remoteStart = evse.clock.Now()
+}
+
+// RemoteStartPending returns true if an accepted remote start awaits its transaction.
+// The remote start is cleared once the transaction started or the timeout elapsed, the latter is reported as expired.
+func (evse *EVSE) RemoteStartPending() (pending, expired bool) {
+	evse.mu.Lock()
+	defer evse.mu.Unlock()
+
+	switch {
+	case evse.remoteStart.IsZero():
+		return false, false
+	case evse.txnId != "":
+		evse.remoteStart = time.Time{}
+		return false, false
+	case evse.clock.Since(evse.remoteStart) > evse.timeout:
+		evse.remoteStart = time.Time{}
+		return false, true
+	}
+
+	return true, false
+}
+
+// ClearRemoteStart clears the accepted remote start and returns true if it was awaiting its transaction
+func (evse *EVSE) ClearRemoteStart() bool {
+	evse.mu.Lock()
+	defer evse.mu.Unlock()
+
+	res := !evse.remoteStart.IsZero() && evse.txnId == ""
+	evse.remoteStart = time.Time{}
+
+	return res
+}
+
+func (evse *EVSE) Status() (api.ChargeStatus, error) {
+	evse.mu.Lock()
+	defer evse.mu.Unlock()
+
+	res := api.StatusNone
+
+	if !evse.cp.Connected() {
+		return res, api.ErrTimeout
+	}
+
+	switch evse.status.ConnectorStatus {
+	case availability.ConnectorStatusAvailable, // "Available"
+		availability.ConnectorStatusUnavailable: // "Unavailable"
+		res = api.StatusA
+	case availability.ConnectorStatusOccupied: // "Occupied"
+		// occupied is refined by the transaction's charging state
+		switch evse.chargingState {
+		case transactions.ChargingStateCharging:
+			res = api.StatusC
+		case transactions.ChargingStateIdle:
+			res = api.StatusA
+		default:
+			res = api.StatusB
+		}
+	case availability.ConnectorStatusReserved, // "Reserved"
+		availability.ConnectorStatusFaulted: // "Faulted"
+		return api.StatusF, fmt.Errorf("chargepoint status: %s", evse.status.ConnectorStatus)
+	default:
+		return api.StatusNone, fmt.Errorf("invalid chargepoint status: %s", evse.status.ConnectorStatus)
+	}
+
+	return res, nil
+}
+
+// isMeterTimeout checks if meter values are outdated.
+// Must only be called while holding lock.
+func (evse *EVSE) isMeterTimeout() bool {
+	return evse.timeout > 0 && evse.clock.Since(evse.meterUpdated) > evse.timeout
+}
+
+var _ api.Meter = (*EVSE)(nil)
+
+func (evse *EVSE) CurrentPower() (float64, error) {
+	if !evse.cp.Connected() {
+		return 0, api.ErrTimeout
+	}
+
+	evse.mu.Lock()
+	defer evse.mu.Unlock()
+
+	// zero value on timeout when not charging
+	if evse.isMeterTimeout() {
+		if evse.txnId != "" {
+			return 0, api.ErrTimeout
+		}
+
+		return 0, nil
+	}
+
+	if m, ok := evse.measurements[types.MeasurandPowerActiveImport]; ok {
+		return scale201(m), nil
+	}
+
+	return 0, api.ErrNotAvailable
+}
+
+var _ api.MeterEnergy = (*EVSE)(nil)
+
+func (evse *EVSE) TotalEnergy() (float64, error) {
+	if !evse.cp.Connected() {
+		return 0, api.ErrTimeout
+	}
+
+	evse.mu.Lock()
+	defer evse.mu.Unlock()
+
+	// fallthrough for last value on timeout when not charging
+	if evse.txnId != "" && evse.isMeterTimeout() {
+		return 0, api.ErrTimeout
+	}
+
+	if m, ok := evse.measurements[types.MeasurandEnergyActiveImportRegister]; ok {
+		return scale201(m) / 1e3, nil
+	}
+
+	return 0, api.ErrNotAvailable
+}
+
+// scale201 applies the sampled value's unit prefix and multiplier
+func scale201(s types.SampledValue) float64 {
+	f := s.Value
+
+	if u := s.UnitOfMeasure; u != nil {
+		switch {
+		case strings.HasPrefix(u.Unit, "k"):
+			f *= 1e3
+		case strings.HasPrefix(u.Unit, "m"):
+			f /= 1e3
+		}
+
+		if u.Multiplier != nil {
+			f *= math.Pow10(*u.Multiplier)
+		}
+	}
+
+	return f
+}
+
+func getPhaseKey201(key types.Measurand, phase int) types.Measurand {
+	return key + types.Measurand(fmt.Sprintf("@L%d", phase))
+}
+
+var _ api.PhaseCurrents = (*EVSE)(nil)
+
+func (evse *EVSE) Currents() (float64, float64, float64, error) {
+	if !evse.cp.Connected() {
+		return 0, 0, 0, api.ErrTimeout
+	}
+
+	evse.mu.Lock()
+	defer evse.mu.Unlock()
+
+	// zero value on timeout when not charging
+	if evse.isMeterTimeout() {
+		if evse.txnId != "" {
+			return 0, 0, 0, api.ErrTimeout
+		}
+
+		return 0, 0, 0, nil
+	}
+
+	currents := make([]float64, 0, 3)
+
+	for phase := 1; phase <= 3; phase++ {
+		m, ok := evse.measurements[getPhaseKey201(types.MeasurandCurrentImport, phase)]
+		if !ok {
+			return 0, 0, 0, api.ErrNotAvailable
+		}
+
+		currents = append(currents, scale201(m))
+	}
+
+	return currents[0], currents[1], currents[2], nil
+}
@@ -0,0 +1,162 @@
+package ocpp
+
+import (
+	"time"
+
+	"github.com/lorenzodonini/ocpp-go/ocpp2.0.1/availability"
+	"github.com/lorenzodonini/ocpp-go/ocpp2.0.1/meter"
+	"github.com/lorenzodonini/ocpp-go/ocpp2.0.1/transactions"
+	"github.com/lorenzodonini/ocpp-go/ocpp2.0.1/types"
+)
+
+// timestampValid returns false if status timestamps are outdated
+func (evse *EVSE) timestampValid(t time.Time) bool {
+	// reject if expired
+	if evse.clock.Since(t) > messageExpiry {
+		return false
+	}
+
+	// assume having a timestamp is better than not
+	if evse.status.Timestamp == nil {
+		return true
+	}
+
+	// reject older values than we already have
+	return t.After(evse.status.Timestamp.Time)
+}
+
+func (evse *EVSE) StatusNotification(request *availability.StatusNotificationRequest) (*availability.StatusNotificationResponse, error) {
+	evse.mu.Lock()
+	defer evse.mu.Unlock()
+
+	if evse.status == nil {
+		evse.status = request
+		close(evse.statusC) // signal initial status received
+	} else if request.Timestamp == nil || evse.timestampValid(request.Timestamp.Time) {
+		evse.status = request
+	} else {
+		evse.log.TRACE.Printf("ignoring status: %s < %s", request.Timestamp.Time, evse.status.Timestamp)
+	}
+
+	return new(availability.StatusNotificationResponse), nil
+}
+
+func getSampleKey201(s types.SampledValue) types.Measurand {
+	measurand := s.Measurand
+	if measurand == "" {
+		// default according to specification
+		measurand = types.MeasurandEnergyActiveImportRegister
+	}
+
+	if s.Phase != "" {
+		return measurand + types.Measurand("@"+string(s.Phase))
+	}
+
+	return measurand
+}
+
+// updateMeterValues stores sampled values.
+// Must only be called while holding lock.
+func (evse *EVSE) updateMeterValues(values []types.MeterValue) {
+	for _, meterValue := range values {
+		// ignore old meter value requests
+		if meterValue.Timestamp.Time.After(evse.meterUpdated) {
+			for _, sample := range meterValue.SampledValue {
+				evse.measurements[getSampleKey201(sample)] = sample
+				evse.meterUpdated = evse.clock.Now()
+			}
+		}
+	}
+}
+
+func (evse *EVSE) MeterValues(request *meter.MeterValuesRequest) (*meter.MeterValuesResponse, error) {
+	evse.mu.Lock()
+	defer evse.mu.Unlock()
+
+	evse.updateMeterValues(request.MeterValue)
+
+	return new(meter.MeterValuesResponse), nil
+}
+
+func (evse *EVSE) TransactionEvent(request *transactions.TransactionEventRequest) (*transactions.TransactionEventResponse, error) {
+	evse.mu.Lock()
+	defer evse.mu.Unlock()
+
+	res := new(transactions.TransactionEventResponse)
+
+	// idTokenInfo is required when the request contains an idToken
+	if request.IDToken != nil {
+		res.IDTokenInfo = types.NewIdTokenInfo(types.AuthorizationStatusAccepted)
+	}
+
+	// expired request
+	if request.Timestamp != nil && evse.clock.Since(request.Timestamp.Time) > transactionExpiry {
+		if res.IDTokenInfo != nil {
+			res.IDTokenInfo.Status = types.AuthorizationStatusExpired // reject
+		}
+
+		return res, nil
+	}
+
+	txn := request.TransactionInfo.TransactionID
+
+	if token := request.IDToken; token != nil && token.Type != types.IdTokenTypeNoAuthorization {
+		evse.idToken = token.IdToken
+	}
+
+	switch request.EventType {
+	case transactions.TransactionEventStarted:
+		evse.txnId = txn
+		evse.signalTransaction()
+
+	case transactions.TransactionEventUpdated:
+		if evse.txnId == "" {
+			evse.log.DEBUG.Printf("hijacking transaction: %s", txn)
+			evse.txnId = txn
+			evse.signalTransaction()
+		}
+
+	case transactions.TransactionEventEnded:
+		evse.txnId = ""
+		evse.idToken = ""
+		evse.chargingState = transactions.ChargingStateIdle
+		evse.assumeMeterStopped()
+
+		return res, nil
+	}
+
+	if state := request.TransactionInfo.ChargingState; state != "" {
+		evse.chargingState = state
+	}
+
+	evse.updateMeterValues(request.MeterValue)
+
+	return res, nil
+}
+
+func (evse *EVSE) assumeMeterStopped() {
+	evse.meterUpdated = evse.clock.Now()
+
+	if _, ok := evse.measurements[types.MeasurandPowerActiveImport]; ok {
+		evse.measurements[types.MeasurandPowerActiveImport] = types.SampledValue{
+			Measurand: types.MeasurandPowerActiveImport,
+		}
+	}
+
+	for phase := 1; phase <= 3; phase++ {
+		if _, ok := evse.measurements[getPhaseKey201(types.MeasurandCurrentImport, phase)]; ok {
+			evse.measurements[getPhaseKey201(types.MeasurandCurrentImport, phase)] = types.SampledValue{
+				Measurand: types.MeasurandCurrentImport,
+			}
+		}
+	}
+}
+
+// signalTransaction notifies waiting callers of the transaction start.
+// Must only be called while holding lock.
+func (evse *EVSE) signalTransaction() {
+	select {
+	case evse.txnC <- struct{}{}:
+	default:
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/evcc-io/evcc/util"
+	"github.com/gorilla/websocket"
 	"github.com/lorenzodonini/ocpp-go/ocpp"
 	ocpp16 "github.com/lorenzodonini/ocpp-go/ocpp1.6"
 	"github.com/lorenzodonini/ocpp-go/ocpp1.6/core"
@@ -13,6 +14,16 @@ import (
 	"github.com/lorenzodonini/ocpp-go/ocpp1.6/remotetrigger"
 	"github.com/lorenzodonini/ocpp-go/ocpp1.6/reservation"
 	"github.com/lorenzodonini/ocpp-go/ocpp1.6/smartcharging"
+	types16 "github.com/lorenzodonini/ocpp-go/ocpp1.6/types"
+	ocpp2 "github.com/lorenzodonini/ocpp-go/ocpp2.0.1"
+	"github.com/lorenzodonini/ocpp-go/ocpp2.0.1/authorization"
+	"github.com/lorenzodonini/ocpp-go/ocpp2.0.1/availability"
+	"github.com/lorenzodonini/ocpp-go/ocpp2.0.1/meter"
+	"github.com/lorenzodonini/ocpp-go/ocpp2.0.1/provisioning"
+	"github.com/lorenzodonini/ocpp-go/ocpp2.0.1/remotecontrol"
+	smartcharging2 "github.com/lorenzodonini/ocpp-go/ocpp2.0.1/smartcharging"
+	"github.com/lorenzodonini/ocpp-go/ocpp2.0.1/transactions"
+	types2 "github.com/lorenzodonini/ocpp-go/ocpp2.0.1/types"
 	"github.com/lorenzodonini/ocpp-go/ocppj"
 	"github.com/lorenzodonini/ocpp-go/ws"
 )
@@ -32,21 +43,43 @@ func Instance() *CS {
 		server := ws.NewServer()
 		server.SetTimeoutConfig(timeoutConfig)
 
-		dispatcher := ocppj.NewDefaultServerDispatcher(ocppj.NewFIFOQueueMap(0))
-		dispatcher.SetTimeout(time.Minute)
-
-		endpoint := ocppj.NewServer(server, dispatcher, nil, core.Profile, localauth.Profile, firmware.Profile, reservation.Profile, remotetrigger.Profile, smartcharging.Profile)
-		endpoint.SetInvalidMessageHook(func(client ws.Channel, err *ocpp.Error, rawMessage string, parsedFields []interface{}) *ocpp.Error {
+		invalidMessageHook := func(client ws.Channel, err *ocpp.Error, rawMessage string, parsedFields []interface{}) *ocpp.Error {
 			log.ERROR.Printf("%v (%s)", err, rawMessage)
 			return nil
+		}
+
+		// protocol version is negotiated as websocket subprotocol
+		mux := newMux(server)
+
+		// close half-open connections
+		mux.monitor = newMonitor(log, heartbeatTimeout, func(id string) error {
+			return server.StopConnection(id, websocket.CloseError{Code: websocket.CloseGoingAway, Text: "heartbeat timeout"})
 		})
 
-		cs := ocpp16.NewCentralSystem(endpoint, server)
+		server16 := mux.Endpoint(types16.V16Subprotocol)
+		dispatcher := ocppj.NewDefaultServerDispatcher(ocppj.NewFIFOQueueMap(0))
+		dispatcher.SetTimeout(time.Minute)
+
+		endpoint := ocppj.NewServer(server16, dispatcher, nil, core.Profile, localauth.Profile, firmware.Profile, reservation.Profile, remotetrigger.Profile, smartcharging.Profile)
+		endpoint.SetInvalidMessageHook(invalidMessageHook)
+
+		cs := ocpp16.NewCentralSystem(endpoint, server16)
+
+		server201 := mux.Endpoint(types2.V201Subprotocol)
+		dispatcher201 := ocppj.NewDefaultServerDispatcher(ocppj.NewFIFOQueueMap(0))
+		dispatcher201.SetTimeout(time.Minute)
+
+		endpoint201 := ocppj.NewServer(server201, dispatcher201, nil, authorization.Profile, availability.Profile, meter.Profile, provisioning.Profile, remotecontrol.Profile, smartcharging2.Profile, transactions.Profile)
+		endpoint201.SetInvalidMessageHook(invalidMessageHook)
+
+		csms := ocpp2.NewCSMS(endpoint201, server201)
 
 		instance = &CS{
 			log:           log,
+			monitor:       mux.monitor,
 			cps:           make(map[string]*CP),
 			CentralSystem: cs,
+			CSMS:          csms,
 		}
 
 		ocppj.SetLogger(instance)
@@ -56,12 +89,24 @@ func Instance() *CS {
 		cs.SetChargePointDisconnectedHandler(instance.ChargePointDisconnected)
 		cs.SetFirmwareManagementHandler(instance)
 
+		handler201 := &csms201{instance}
+		csms.SetAuthorizationHandler(handler201)
+		csms.SetAvailabilityHandler(handler201)
+		csms.SetMeterHandler(handler201)
+		csms.SetProvisioningHandler(handler201)
+		csms.SetTransactionsHandler(handler201)
+		csms.SetNewChargingStationHandler(handler201.NewChargingStation)
+		csms.SetChargingStationDisconnectedHandler(handler201.ChargingStationDisconnected)
+
 		go instance.errorHandler(cs.Errors())
+		go instance.errorHandler(csms.Errors())
+		go mux.monitor.Run()
+		go csms.Start(8887, "/{ws}")
 		go cs.Start(8887, "/{ws}")
 
 		// wait for server to start
 		for range time.Tick(10 * time.Millisecond) {
-			if dispatcher.IsRunning() {
+			if dispatcher.IsRunning() && dispatcher201.IsRunning() {
 				break
 			}
 		}
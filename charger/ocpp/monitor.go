@@ -0,0 +1,92 @@
+package ocpp
+
+import (
+	"sync"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/util"
+)
+
+// heartbeatTimeout is the silence after which a connection is considered half-open
+const heartbeatTimeout = 3 * heartbeatInterval * time.Second
+
+// monitor tracks the last message time per websocket client and closes silent
+// connections to make the charge point reconnect
+type monitor struct {
+	mu      sync.Mutex
+	log     *util.Logger
+	clock   clock.Clock
+	timeout time.Duration
+	seen    map[string]time.Time
+	stop    func(id string) error
+}
+
+func newMonitor(log *util.Logger, timeout time.Duration, stop func(id string) error) *monitor {
+	return &monitor{
+		log:     log,
+		clock:   clock.New(),
+		timeout: timeout,
+		seen:    make(map[string]time.Time),
+		stop:    stop,
+	}
+}
+
+// Seen records a message from the client
+func (m *monitor) Seen(id string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.seen[id] = m.clock.Now()
+}
+
+// Remove stops tracking the disconnected client
+func (m *monitor) Remove(id string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.seen, id)
+}
+
+// LastSeen returns the time of the client's last message
+func (m *monitor) LastSeen(id string) (time.Time, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	ts, ok := m.seen[id]
+	return ts, ok
+}
+
+// check closes connections that have been silent for longer than timeout
+func (m *monitor) check() {
+	m.mu.Lock()
+
+	var stale []string
+	for id, ts := range m.seen {
+		if m.clock.Since(ts) > m.timeout {
+			stale = append(stale, id)
+		}
+	}
+
+	m.mu.Unlock()
+
+	for _, id := range stale {
+		ts, _ := m.LastSeen(id)
+		m.log.WARN.Printf("charge point %s silent for %v, reconnecting", id, m.clock.Since(ts).Round(time.Second))
+
+		// don't retry until timeout has elapsed again
+		m.Seen(id)
+
+		if err := m.stop(id); err != nil {
+			m.log.ERROR.Printf("charge point %s: %v", id, err)
+		}
+	}
+}
+
+// Run checks the connections periodically.
+// Must be wrapped in a goroutine.
+func (m *monitor) Run() {
+	for range m.clock.Tick(m.timeout / 3) {
+		m.check()
+	}
+}
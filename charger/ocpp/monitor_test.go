@@ -0,0 +1,89 @@
+package ocpp
+
+import (
+	"crypto/tls"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/util"
+	"github.com/lorenzodonini/ocpp-go/ws"
+	"github.com/stretchr/testify/assert"
+)
+
+type testChannel string
+
+func (c testChannel) ID() string                               { return string(c) }
+func (c testChannel) RemoteAddr() net.Addr                     { return nil }
+func (c testChannel) TLSConnectionState() *tls.ConnectionState { return nil }
+
+func TestMonitorReconnectsStalledConnection(t *testing.T) {
+	clock := clock.NewMock()
+	m := newMux(ws.NewServer())
+
+	var stopped []string
+	m.monitor = newMonitor(util.NewLogger("foo"), heartbeatTimeout, func(id string) error {
+		stopped = append(stopped, id)
+
+		// server closes connection
+		m.disconnectedClient(testChannel(id))
+		return nil
+	})
+	m.monitor.clock = clock
+
+	m.newClient(testChannel("cp1"))
+	m.newClient(testChannel("cp2"))
+
+	// cp1 keeps sending heartbeats, cp2 stalls
+	for i := 0; i < 3; i++ {
+		clock.Add(heartbeatInterval * time.Second)
+		assert.NoError(t, m.message(testChannel("cp1"), nil))
+		m.monitor.check()
+	}
+
+	assert.Empty(t, stopped)
+
+	clock.Add(time.Second)
+	m.monitor.check()
+
+	assert.Equal(t, []string{"cp2"}, stopped)
+
+	_, ok := m.monitor.LastSeen("cp2")
+	assert.False(t, ok, "stalled connection still tracked")
+
+	// charge point reconnects
+	m.newClient(testChannel("cp2"))
+
+	ts, ok := m.monitor.LastSeen("cp2")
+	assert.True(t, ok)
+	assert.Equal(t, clock.Now(), ts)
+
+	m.monitor.check()
+	assert.Len(t, stopped, 1)
+}
+
+func TestMonitorRetriesFailedClose(t *testing.T) {
+	clock := clock.NewMock()
+
+	var stopped int
+	m := newMonitor(util.NewLogger("foo"), time.Minute, func(id string) error {
+		stopped++
+		return assert.AnError
+	})
+	m.clock = clock
+
+	m.Seen("cp")
+
+	clock.Add(2 * time.Minute)
+	m.check()
+	assert.Equal(t, 1, stopped)
+
+	// no retry until timeout elapsed again
+	m.check()
+	assert.Equal(t, 1, stopped)
+
+	clock.Add(2 * time.Minute)
+	m.check()
+	assert.Equal(t, 2, stopped)
+}
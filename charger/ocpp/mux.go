@@ -0,0 +1,165 @@
+package ocpp
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/lorenzodonini/ocpp-go/ws"
+)
+
+// mux shares a single websocket server between multiple OCPP protocol versions.
+// Clients are routed to the protocol endpoint negotiated as websocket subprotocol during handshake.
+type mux struct {
+	*ws.Server
+	mu        sync.Mutex
+	once      sync.Once
+	endpoints map[string]*muxEndpoint // subprotocol -> endpoint
+	clients   map[string]*muxEndpoint // client id -> endpoint
+	monitor   *monitor                // optional connection health monitor
+}
+
+func newMux(server *ws.Server) *mux {
+	m := &mux{
+		Server:    server,
+		endpoints: make(map[string]*muxEndpoint),
+		clients:   make(map[string]*muxEndpoint),
+	}
+
+	server.SetCheckClientHandler(m.checkClient)
+	server.SetNewClientHandler(m.newClient)
+	server.SetDisconnectedClientHandler(m.disconnectedClient)
+	server.SetMessageHandler(m.message)
+
+	return m
+}
+
+// Endpoint returns a websocket server view restricted to the given subprotocol
+func (m *mux) Endpoint(subprotocol string) ws.WsServer {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	e := &muxEndpoint{mux: m, subprotocol: subprotocol}
+	m.endpoints[subprotocol] = e
+	m.Server.AddSupportedSubprotocol(subprotocol)
+
+	return e
+}
+
+// negotiate selects the endpoint for the first requested subprotocol that is supported
+func (m *mux) negotiate(r *http.Request) *muxEndpoint {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, header := range r.Header.Values("Sec-WebSocket-Protocol") {
+		for _, proto := range strings.Split(header, ",") {
+			if e, ok := m.endpoints[strings.TrimSpace(proto)]; ok {
+				return e
+			}
+		}
+	}
+
+	return nil
+}
+
+func (m *mux) endpoint(id string) *muxEndpoint {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.clients[id]
+}
+
+func (m *mux) checkClient(id string, r *http.Request) bool {
+	e := m.negotiate(r)
+	if e == nil {
+		// let the websocket server reject the subprotocol
+		return true
+	}
+
+	if h := e.checkClientHandler; h != nil && !h(id, r) {
+		return false
+	}
+
+	m.mu.Lock()
+	m.clients[id] = e
+	m.mu.Unlock()
+
+	return true
+}
+
+func (m *mux) newClient(channel ws.Channel) {
+	if m.monitor != nil {
+		m.monitor.Seen(channel.ID())
+	}
+
+	if e := m.endpoint(channel.ID()); e != nil && e.newClientHandler != nil {
+		e.newClientHandler(channel)
+	}
+}
+
+func (m *mux) disconnectedClient(channel ws.Channel) {
+	e := m.endpoint(channel.ID())
+
+	m.mu.Lock()
+	delete(m.clients, channel.ID())
+	m.mu.Unlock()
+
+	if m.monitor != nil {
+		m.monitor.Remove(channel.ID())
+	}
+
+	if e != nil && e.disconnectedClientHandler != nil {
+		e.disconnectedClientHandler(channel)
+	}
+}
+
+func (m *mux) message(channel ws.Channel, data []byte) error {
+	if m.monitor != nil {
+		m.monitor.Seen(channel.ID())
+	}
+
+	if e := m.endpoint(channel.ID()); e != nil && e.messageHandler != nil {
+		return e.messageHandler(channel, data)
+	}
+
+	return nil
+}
+
+// muxEndpoint implements ws.WsServer for a single subprotocol
+type muxEndpoint struct {
+	*mux
+	subprotocol string
+
+	checkClientHandler        ws.CheckClientHandler
+	newClientHandler          func(ws.Channel)
+	disconnectedClientHandler func(ws.Channel)
+	messageHandler            func(ws.Channel, []byte) error
+}
+
+var _ ws.WsServer = (*muxEndpoint)(nil)
+
+// Start starts the shared websocket server once, subsequent calls block until the server is stopped
+func (e *muxEndpoint) Start(port int, listenPath string) {
+	e.once.Do(func() {
+		e.Server.Start(port, listenPath)
+	})
+}
+
+func (e *muxEndpoint) SetCheckClientHandler(handler func(id string, r *http.Request) bool) {
+	e.checkClientHandler = handler
+}
+
+func (e *muxEndpoint) SetNewClientHandler(handler func(ws ws.Channel)) {
+	e.newClientHandler = handler
+}
+
+func (e *muxEndpoint) SetDisconnectedClientHandler(handler func(ws ws.Channel)) {
+	e.disconnectedClientHandler = handler
+}
+
+func (e *muxEndpoint) SetMessageHandler(handler func(ws ws.Channel, data []byte) error) {
+	e.messageHandler = handler
+}
+
+// AddSupportedSubprotocol is a no-op since endpoints are bound to a single subprotocol
+func (e *muxEndpoint) AddSupportedSubprotocol(subProto string) {}
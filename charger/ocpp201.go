@@ -0,0 +1,431 @@
+package charger
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/charger/ocpp"
+	"github.com/evcc-io/evcc/core/loadpoint"
+	"github.com/evcc-io/evcc/util"
+	"github.com/lorenzodonini/ocpp-go/ocpp2.0.1/provisioning"
+	"github.com/lorenzodonini/ocpp-go/ocpp2.0.1/remotecontrol"
+	"github.com/lorenzodonini/ocpp-go/ocpp2.0.1/smartcharging"
+	"github.com/lorenzodonini/ocpp-go/ocpp2.0.1/types"
+)
+
+// OCPP201 charger implementation
+type OCPP201 struct {
+	log               *util.Logger
+	evse              *ocpp.EVSE
+	idtag             string
+	enabled           bool
+	phases            int
+	current           float64
+	meterValuesSample string
+	timeout           time.Duration
+	chargingRateUnit  types.ChargingRateUnitType
+	remoteStartId     int
+	lp                loadpoint.API
+}
+
+// defaultMeterValues201 are the measurands used if not configured otherwise
+const defaultMeterValues201 = "Power.Active.Import,Energy.Active.Import.Register"
+
+// newOCPP201FromConfig creates a OCPP 2.0.1 charger from the OCPP charger's config
+func newOCPP201FromConfig(id string, evseId int, idtag string,
+	meterValues string, meterInterval time.Duration,
+	boot bool,
+	connectTimeout, timeout time.Duration,
+	chargingRateUnit string,
+) (api.Charger, error) {
+	c, err := NewOCPP201(id, evseId, idtag, meterValues, meterInterval, boot, connectTimeout, timeout, chargingRateUnit)
+	if err != nil {
+		return c, err
+	}
+
+	var powerG func() (float64, error)
+	if c.hasMeasurement(types.MeasurandPowerActiveImport) {
+		powerG = c.currentPower
+	}
+
+	var totalEnergyG func() (float64, error)
+	if c.hasMeasurement(types.MeasurandEnergyActiveImportRegister) {
+		totalEnergyG = c.totalEnergy
+	}
+
+	var currentsG func() (float64, float64, float64, error)
+	if c.hasMeasurement(types.MeasurandCurrentImport) {
+		currentsG = c.currents
+	}
+
+	return decorateOCPP201(c, powerG, totalEnergyG, currentsG), nil
+}
+
+// go:generate go run ../cmd/tools/decorate.go -f decorateOCPP201 -b *OCPP201 -r api.Charger -t "api.Meter,CurrentPower,func() (float64, error)" -t "api.MeterEnergy,TotalEnergy,func() (float64, error)" -t "api.PhaseCurrents,Currents,func() (float64, float64, float64, error)"
+
+// NewOCPP201 creates OCPP 2.0.1 charger
+func NewOCPP201(id string, evseId int, idtag string,
+	meterValues string, meterInterval time.Duration,
+	boot bool,
+	connectTimeout, timeout time.Duration,
+	chargingRateUnit string,
+) (*OCPP201, error) {
+	unit := "ocpp"
+	if id != "" {
+		unit = id
+	}
+	unit = fmt.Sprintf("%s-%d", unit, evseId)
+
+	log := util.NewLogger(unit)
+
+	cp, err := ocpp.Instance().ChargepointByID(id)
+	if err != nil {
+		cp = ocpp.NewChargePoint(log, id)
+
+		// should not error
+		if err := ocpp.Instance().Register(id, cp); err != nil {
+			return nil, err
+		}
+	}
+
+	evse, err := ocpp.NewEVSE(log, evseId, cp, timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &OCPP201{
+		log:               log,
+		evse:              evse,
+		idtag:             idtag,
+		timeout:           timeout,
+		meterValuesSample: defaultMeterValues201,
+		chargingRateUnit:  types.ChargingRateUnitType(chargingRateUnit),
+	}
+
+	c.log.DEBUG.Printf("waiting for chargepoint: %v", connectTimeout)
+
+	select {
+	case <-time.After(connectTimeout):
+		return nil, api.ErrTimeout
+	case <-cp.HasConnected():
+	}
+
+	// see who's there
+	if boot {
+		evse.TriggerMessageRequest(remotecontrol.MessageTriggerBootNotification)
+	}
+
+	var variables []provisioning.SetVariableData
+
+	if meterValues != "" {
+		variables = append(variables, c.variable(ocpp.KeySampledDataCtrlr, ocpp.KeyTxUpdatedMeasurands, meterValues))
+		c.meterValuesSample = meterValues
+	}
+
+	if meterInterval > 0 {
+		variables = append(variables, c.variable(ocpp.KeySampledDataCtrlr, ocpp.KeyTxUpdatedInterval, strconv.Itoa(int(meterInterval.Seconds()))))
+	}
+
+	if len(variables) > 0 {
+		if err := c.setVariables(variables); err != nil {
+			return nil, err
+		}
+	}
+
+	// get initial meter values
+	if c.hasMeasurement(types.MeasurandPowerActiveImport) || c.hasMeasurement(types.MeasurandEnergyActiveImportRegister) {
+		evse.TriggerMessageRequest(remotecontrol.MessageTriggerMeterValues)
+
+		// HACK: setup watchdog for meter values if not happy with config
+		if meterInterval > 0 {
+			c.log.DEBUG.Println("enabling meter watchdog")
+			go evse.WatchDog(meterInterval)
+		}
+	}
+
+	return c, evse.Initialized()
+}
+
+var _ api.ConnectionMonitor = (*OCPP201)(nil)
+
+// Connection implements the api.ConnectionMonitor interface
+func (c *OCPP201) Connection() (bool, time.Time) {
+	ts, _ := ocpp.Instance().LastSeen(c.evse.ChargePoint().ID())
+	return c.evse.ChargePoint().Connected(), ts
+}
+
+// EVSE returns the evse instance
+func (c *OCPP201) EVSE() *ocpp.EVSE {
+	return c.evse
+}
+
+// hasMeasurement checks if meterValuesSample contains given measurement
+func (c *OCPP201) hasMeasurement(val types.Measurand) bool {
+	return slices.Contains(strings.Split(c.meterValuesSample, ","), string(val))
+}
+
+// variable creates a variable for the given component
+func (c *OCPP201) variable(component, variable, value string) provisioning.SetVariableData {
+	return provisioning.SetVariableData{
+		Component:      types.Component{Name: component},
+		Variable:       types.Variable{Name: variable},
+		AttributeValue: value,
+	}
+}
+
+// setVariables updates CS variables
+func (c *OCPP201) setVariables(data []provisioning.SetVariableData) error {
+	rc := make(chan error, 1)
+
+	err := ocpp.Instance().CSMS.SetVariables(c.evse.ChargePoint().ID(), func(resp *provisioning.SetVariablesResponse, err error) {
+		if err == nil && resp != nil {
+			for _, res := range resp.SetVariableResult {
+				if res.AttributeStatus != provisioning.SetVariableStatusAccepted && res.AttributeStatus != provisioning.SetVariableStatusRebootRequired {
+					err = fmt.Errorf("SetVariables %s.%s failed: %s", res.Component.Name, res.Variable.Name, res.AttributeStatus)
+					break
+				}
+			}
+		}
+
+		rc <- err
+	}, data)
+
+	return c.wait(err, rc)
+}
+
+// wait waits for a CP roundtrip with timeout
+func (c *OCPP201) wait(err error, rc chan error) error {
+	if err == nil {
+		select {
+		case err = <-rc:
+			close(rc)
+		case <-time.After(c.timeout):
+			err = api.ErrTimeout
+		}
+	}
+	return err
+}
+
+// Status implements the api.Charger interface
+func (c *OCPP201) Status() (api.ChargeStatus, error) {
+	return c.evse.Status()
+}
+
+// Enabled implements the api.Charger interface
+func (c *OCPP201) Enabled() (bool, error) {
+	// the charge point starts the transaction asynchronously after accepting the remote start
+	if _, expired := c.evse.RemoteStartPending(); expired {
+		c.log.WARN.Printf("remote start: transaction not started within %v", c.timeout)
+		c.enabled = false
+	}
+
+	return c.enabled, nil
+}
+
+// Enable implements the api.Charger interface
+func (c *OCPP201) Enable(enable bool) (err error) {
+	rc := make(chan error, 1)
+	txn, err := c.evse.TransactionID()
+
+	defer func() {
+		if err == nil {
+			c.enabled = enable
+		}
+	}()
+
+	if enable {
+		if txn != "" {
+			// we have the transaction id, treat as enabled
+			c.evse.ClearRemoteStart()
+			return nil
+		}
+
+		if pending, _ := c.evse.RemoteStartPending(); pending {
+			// remote start accepted, waiting for the transaction
+			return nil
+		}
+
+		c.remoteStartId++
+		idToken := types.IdToken{IdToken: c.idtag, Type: types.IdTokenTypeCentral}
+
+		err = ocpp.Instance().CSMS.RequestStartTransaction(c.evse.ChargePoint().ID(), func(resp *remotecontrol.RequestStartTransactionResponse, err error) {
+			if err == nil && resp != nil && resp.Status != remotecontrol.RequestStartStopStatusAccepted {
+				err = errors.New(string(resp.Status))
+			}
+
+			rc <- err
+		}, c.remoteStartId, idToken, func(request *remotecontrol.RequestStartTransactionRequest) {
+			evseId := c.evse.ID()
+			request.EvseID = &evseId
+			request.ChargingProfile = c.getTxChargingProfile(c.current, "")
+		})
+
+		if err = c.wait(err, rc); err == nil {
+			c.evse.RemoteStartAccepted()
+		}
+
+		return err
+	}
+
+	// remote start accepted but transaction not yet started, wait for it to stop it
+	if txn == "" && c.evse.ClearRemoteStart() {
+		if txn, err = c.evse.WaitForTransaction(c.timeout); errors.Is(err, api.ErrTimeout) {
+			// transaction never started
+			return nil
+		}
+	}
+
+	// if no transaction is running, the vehicle may have stopped it (which is ok) or an unknown transaction is running
+	if txn == "" {
+		// we cannot tell if a transaction is really running, so we check the status
+		status, err := c.Status()
+		if err != nil {
+			return err
+		}
+		if status == api.StatusC {
+			return errors.New("cannot disable: unknown transaction running")
+		}
+
+		return nil
+	}
+
+	err = ocpp.Instance().CSMS.RequestStopTransaction(c.evse.ChargePoint().ID(), func(resp *remotecontrol.RequestStopTransactionResponse, err error) {
+		if err == nil && resp != nil && resp.Status != remotecontrol.RequestStartStopStatusAccepted {
+			err = errors.New(string(resp.Status))
+		}
+
+		rc <- err
+	}, txn)
+
+	return c.wait(err, rc)
+}
+
+func (c *OCPP201) setChargingProfile(profile *types.ChargingProfile) error {
+	rc := make(chan error, 1)
+	err := ocpp.Instance().CSMS.SetChargingProfile(c.evse.ChargePoint().ID(), func(resp *smartcharging.SetChargingProfileResponse, err error) {
+		if err == nil && resp != nil && resp.Status != smartcharging.ChargingProfileStatusAccepted {
+			err = errors.New(string(resp.Status))
+		}
+
+		rc <- err
+	}, c.evse.ID(), profile)
+
+	return c.wait(err, rc)
+}
+
+// updatePeriod sets a single charging schedule period with given current
+func (c *OCPP201) updatePeriod(current float64) error {
+	// current period can only be updated if transaction is active
+	if enabled, err := c.Enabled(); err != nil || !enabled {
+		return err
+	}
+
+	txn, err := c.evse.TransactionID()
+	if err != nil {
+		return err
+	}
+
+	current = math.Trunc(10*current) / 10
+
+	err = c.setChargingProfile(c.getTxChargingProfile(current, txn))
+	if err != nil {
+		err = fmt.Errorf("set charging profile: %w", err)
+	}
+
+	return err
+}
+
+func (c *OCPP201) getTxChargingProfile(current float64, transactionId string) *types.ChargingProfile {
+	phases := c.phases
+	period := types.NewChargingSchedulePeriod(0, current)
+	if c.chargingRateUnit == types.ChargingRateUnitWatts {
+		// get (expectedly) active phases from loadpoint
+		if c.lp != nil {
+			phases = c.lp.GetPhases()
+		}
+		if phases == 0 {
+			phases = 3
+		}
+		period = types.NewChargingSchedulePeriod(0, math.Trunc(230.0*current*float64(phases)))
+	}
+
+	// OCPP assumes phases == 3 if not set
+	if phases != 0 {
+		period.NumberPhases = &phases
+	}
+
+	return &types.ChargingProfile{
+		ID:                     1,
+		TransactionID:          transactionId,
+		StackLevel:             0,
+		ChargingProfilePurpose: types.ChargingProfilePurposeTxProfile,
+		ChargingProfileKind:    types.ChargingProfileKindRelative,
+		ChargingSchedule: []types.ChargingSchedule{{
+			ID:                     1,
+			ChargingRateUnit:       c.chargingRateUnit,
+			ChargingSchedulePeriod: []types.ChargingSchedulePeriod{period},
+		}},
+	}
+}
+
+// MaxCurrent implements the api.Charger interface
+func (c *OCPP201) MaxCurrent(current int64) error {
+	return c.MaxCurrentMillis(float64(current))
+}
+
+var _ api.ChargerEx = (*OCPP201)(nil)
+
+// MaxCurrentMillis implements the api.ChargerEx interface
+func (c *OCPP201) MaxCurrentMillis(current float64) error {
+	err := c.updatePeriod(current)
+	if err == nil {
+		c.current = current
+	}
+	return err
+}
+
+// CurrentPower implements the api.Meter interface
+func (c *OCPP201) currentPower() (float64, error) {
+	return c.evse.CurrentPower()
+}
+
+// TotalEnergy implements the api.MeterTotal interface
+func (c *OCPP201) totalEnergy() (float64, error) {
+	return c.evse.TotalEnergy()
+}
+
+// Currents implements the api.PhaseCurrents interface
+func (c *OCPP201) currents() (float64, float64, float64, error) {
+	return c.evse.Currents()
+}
+
+var _ api.Identifier = (*OCPP201)(nil)
+
+// Identify implements the api.Identifier interface.
+// Chargers supporting ISO 15118 plug and charge start the transaction using the vehicle's eMAID as id token.
+func (c *OCPP201) Identify() (string, error) {
+	// ignore transactions started remotely by evcc
+	if id := c.evse.IdToken(); id != c.idtag {
+		return id, nil
+	}
+
+	return "", nil
+}
+
+var _ api.ChargerInformer = (*OCPP201)(nil)
+
+// ChargerInfo implements the api.ChargerInformer interface
+func (c *OCPP201) ChargerInfo() (api.ChargerInfo, error) {
+	return c.evse.ChargePoint().Info(), nil
+}
+
+// LoadpointControl implements loadpoint.Controller
+func (c *OCPP201) LoadpointControl(lp loadpoint.API) {
+	c.lp = lp
+}
@@ -0,0 +1,137 @@
+package charger
+
+// Code generated by github.com/evcc-io/evcc/cmd/tools/decorate.go. DO NOT EDIT.
+
+import (
+	"github.com/evcc-io/evcc/api"
+)
+
+func decorateOCPP201(base *OCPP201, meter func() (float64, error), meterEnergy func() (float64, error), phaseCurrents func() (float64, float64, float64, error)) api.Charger {
+	switch {
+	case meter == nil && meterEnergy == nil && phaseCurrents == nil:
+		return base
+
+	case meter != nil && meterEnergy == nil && phaseCurrents == nil:
+		return &struct {
+			*OCPP201
+			api.Meter
+		}{
+			OCPP201: base,
+			Meter: &decorateOCPP201MeterImpl{
+				meter: meter,
+			},
+		}
+
+	case meter == nil && meterEnergy != nil && phaseCurrents == nil:
+		return &struct {
+			*OCPP201
+			api.MeterEnergy
+		}{
+			OCPP201: base,
+			MeterEnergy: &decorateOCPP201MeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+		}
+
+	case meter != nil && meterEnergy != nil && phaseCurrents == nil:
+		return &struct {
+			*OCPP201
+			api.Meter
+			api.MeterEnergy
+		}{
+			OCPP201: base,
+			Meter: &decorateOCPP201MeterImpl{
+				meter: meter,
+			},
+			MeterEnergy: &decorateOCPP201MeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+		}
+
+	case meter == nil && meterEnergy == nil && phaseCurrents != nil:
+		return &struct {
+			*OCPP201
+			api.PhaseCurrents
+		}{
+			OCPP201: base,
+			PhaseCurrents: &decorateOCPP201PhaseCurrentsImpl{
+				phaseCurrents: phaseCurrents,
+			},
+		}
+
+	case meter != nil && meterEnergy == nil && phaseCurrents != nil:
+		return &struct {
+			*OCPP201
+			api.Meter
+			api.PhaseCurrents
+		}{
+			OCPP201: base,
+			Meter: &decorateOCPP201MeterImpl{
+				meter: meter,
+			},
+			PhaseCurrents: &decorateOCPP201PhaseCurrentsImpl{
+				phaseCurrents: phaseCurrents,
+			},
+		}
+
+	case meter == nil && meterEnergy != nil && phaseCurrents != nil:
+		return &struct {
+			*OCPP201
+			api.MeterEnergy
+			api.PhaseCurrents
+		}{
+			OCPP201: base,
+			MeterEnergy: &decorateOCPP201MeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+			PhaseCurrents: &decorateOCPP201PhaseCurrentsImpl{
+				phaseCurrents: phaseCurrents,
+			},
+		}
+
+	case meter != nil && meterEnergy != nil && phaseCurrents != nil:
+		return &struct {
+			*OCPP201
+			api.Meter
+			api.MeterEnergy
+			api.PhaseCurrents
+		}{
+			OCPP201: base,
+			Meter: &decorateOCPP201MeterImpl{
+				meter: meter,
+			},
+			MeterEnergy: &decorateOCPP201MeterEnergyImpl{
+				meterEnergy: meterEnergy,
+			},
+			PhaseCurrents: &decorateOCPP201PhaseCurrentsImpl{
+				phaseCurrents: phaseCurrents,
+			},
+		}
+	}
+
+	return nil
+}
+
+type decorateOCPP201MeterImpl struct {
+	meter func() (float64, error)
+}
+
+func (impl *decorateOCPP201MeterImpl) CurrentPower() (float64, error) {
+	return impl.meter()
+}
+
+type decorateOCPP201MeterEnergyImpl struct {
+	meterEnergy func() (float64, error)
+}
+
+func (impl *decorateOCPP201MeterEnergyImpl) TotalEnergy() (float64, error) {
+	return impl.meterEnergy()
+}
+
+type decorateOCPP201PhaseCurrentsImpl struct {
+	phaseCurrents func() (float64, float64, float64, error)
+}
+
+func (impl *decorateOCPP201PhaseCurrentsImpl) Currents() (float64, float64, float64, error) {
+	return impl.phaseCurrents()
+}
@@ -0,0 +1,246 @@
+package charger
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/charger/ocpp"
+	ocpp2 "github.com/lorenzodonini/ocpp-go/ocpp2.0.1"
+	"github.com/lorenzodonini/ocpp-go/ocpp2.0.1/availability"
+	"github.com/lorenzodonini/ocpp-go/ocpp2.0.1/remotecontrol"
+	"github.com/lorenzodonini/ocpp-go/ocpp2.0.1/smartcharging"
+	"github.com/lorenzodonini/ocpp-go/ocpp2.0.1/transactions"
+	"github.com/lorenzodonini/ocpp-go/ocpp2.0.1/types"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestOcpp201(t *testing.T) {
+	suite.Run(t, new(ocpp201TestSuite))
+}
+
+type ocpp201TestSuite struct {
+	suite.Suite
+}
+
+func (suite *ocpp201TestSuite) SetupSuite() {
+	suite.NotNil(ocpp.Instance())
+}
+
+func (suite *ocpp201TestSuite) startChargingStation(id string, evseId int) (ocpp2.ChargingStation, *ChargingStationHandler) {
+	// set a handler for all callback functions
+	handler := &ChargingStationHandler{
+		triggerC: make(chan remotecontrol.MessageTrigger, 1),
+		startC:   make(chan *remotecontrol.RequestStartTransactionRequest, 1),
+		profileC: make(chan *smartcharging.SetChargingProfileRequest, 1),
+	}
+
+	// create charging station with handler
+	cs := ocpp2.NewChargingStation(id, nil, nil)
+	cs.SetProvisioningHandler(handler)
+	cs.SetRemoteControlHandler(handler)
+	cs.SetSmartChargingHandler(handler)
+
+	// let cs handle the trigger messages
+	go func() {
+		for msg := range handler.triggerC {
+			suite.handleTrigger(cs, evseId, msg)
+		}
+	}()
+
+	return cs, handler
+}
+
+func (suite *ocpp201TestSuite) handleTrigger(cs ocpp2.ChargingStation, evseId int, msg remotecontrol.MessageTrigger) {
+	switch msg {
+	case remotecontrol.MessageTriggerStatusNotification:
+		if res, err := cs.StatusNotification(types.NewDateTime(time.Now()), availability.ConnectorStatusAvailable, evseId, 1); err != nil {
+			suite.T().Log("StatusNotification:", err)
+		} else {
+			suite.T().Log("StatusNotification:", res)
+		}
+
+	case remotecontrol.MessageTriggerMeterValues:
+		multiplier := 3
+		if res, err := cs.MeterValues(evseId, []types.MeterValue{
+			{
+				Timestamp: *types.NewDateTime(time.Now()),
+				SampledValue: []types.SampledValue{
+					{Measurand: types.MeasurandPowerActiveImport, Value: 1000},
+					{Measurand: types.MeasurandEnergyActiveImportRegister, Value: 1.2, UnitOfMeasure: &types.UnitOfMeasure{Unit: "Wh", Multiplier: &multiplier}},
+				},
+			},
+		}); err != nil {
+			suite.T().Log("MeterValues:", err)
+		} else {
+			suite.T().Log("MeterValues:", res)
+		}
+
+	default:
+		suite.T().Log(msg)
+	}
+}
+
+func (suite *ocpp201TestSuite) TestConnect() {
+	// charging station - remote
+	cs, handler := suite.startChargingStation("test-201", 1)
+	suite.Require().NoError(cs.Start(ocppTestUrl))
+	suite.Require().True(cs.IsConnected())
+
+	// charging station - local
+	c, err := NewOCPP201("test-201", 1, "evcc", "", 0, false, ocppTestConnectTimeout, ocppTestTimeout, "A")
+	suite.Require().NoError(err)
+
+	// status and meter values
+	{
+		status, err := c.Status()
+		suite.NoError(err)
+		suite.Equal(api.StatusA, status)
+
+		// power
+		f, err := c.currentPower()
+		suite.NoError(err)
+		suite.Equal(1e3, f)
+
+		// energy
+		f, err = c.totalEnergy()
+		suite.NoError(err)
+		suite.Equal(1.2, f)
+	}
+
+	// remote start
+	{
+		suite.Require().NoError(c.Enable(true))
+
+		select {
+		case req := <-handler.startC:
+			suite.Require().NotNil(req.EvseID)
+			suite.Equal(1, *req.EvseID)
+			suite.Equal("evcc", req.IDToken.IdToken)
+		case <-time.After(ocppTestTimeout):
+			suite.Fail("RequestStartTransaction timeout")
+		}
+	}
+
+	// transaction started and charging
+	{
+		_, err := cs.StatusNotification(types.NewDateTime(time.Now().Add(time.Second)), availability.ConnectorStatusOccupied, 1, 1)
+		suite.Require().NoError(err)
+
+		_, err = cs.TransactionEvent(transactions.TransactionEventStarted, types.NewDateTime(time.Now()), transactions.TriggerReasonRemoteStart, 0, transactions.Transaction{
+			TransactionID: "txn-1",
+			ChargingState: transactions.ChargingStateCharging,
+		}, func(request *transactions.TransactionEventRequest) {
+			request.Evse = &types.EVSE{ID: 1}
+		})
+		suite.Require().NoError(err)
+
+		txn, err := c.EVSE().TransactionID()
+		suite.Require().NoError(err)
+		suite.Equal("txn-1", txn)
+
+		status, err := c.Status()
+		suite.NoError(err)
+		suite.Equal(api.StatusC, status)
+	}
+
+	// set current
+	{
+		suite.Require().NoError(c.MaxCurrent(10))
+
+		select {
+		case req := <-handler.profileC:
+			suite.Equal(1, req.EvseID)
+			suite.Equal("txn-1", req.ChargingProfile.TransactionID)
+			suite.Equal(types.ChargingProfilePurposeTxProfile, req.ChargingProfile.ChargingProfilePurpose)
+			suite.Require().Len(req.ChargingProfile.ChargingSchedule, 1)
+			suite.Equal(types.ChargingRateUnitAmperes, req.ChargingProfile.ChargingSchedule[0].ChargingRateUnit)
+			suite.Equal(10.0, req.ChargingProfile.ChargingSchedule[0].ChargingSchedulePeriod[0].Limit)
+		case <-time.After(ocppTestTimeout):
+			suite.Fail("SetChargingProfile timeout")
+		}
+	}
+
+	// transaction ended
+	{
+		_, err := cs.TransactionEvent(transactions.TransactionEventEnded, types.NewDateTime(time.Now()), transactions.TriggerReasonEVCommunicationLost, 1, transactions.Transaction{
+			TransactionID: "txn-1",
+		})
+		suite.Require().NoError(err)
+
+		txn, err := c.EVSE().TransactionID()
+		suite.Require().NoError(err)
+		suite.Equal("", txn)
+
+		f, err := c.currentPower()
+		suite.NoError(err)
+		suite.Equal(0.0, f)
+	}
+
+	// disconnect charging station
+	cs.Stop()
+	suite.Require().False(cs.IsConnected())
+
+	t := time.NewTimer(100 * time.Millisecond)
+WAIT_DISCONNECT:
+	for {
+		select {
+		case <-t.C:
+			suite.Fail("disconnect timeout")
+		case <-time.After(10 * time.Millisecond):
+			if _, err := c.Status(); errors.Is(err, api.ErrTimeout) {
+				break WAIT_DISCONNECT
+			}
+		}
+	}
+}
+
+func (suite *ocpp201TestSuite) TestRemoteStartPending() {
+	cs, handler := suite.startChargingStation("test-201-pending", 1)
+	suite.Require().NoError(cs.Start(ocppTestUrl))
+	suite.Require().True(cs.IsConnected())
+
+	const timeout = 500 * time.Millisecond
+
+	c, err := NewOCPP201("test-201-pending", 1, "evcc", "", 0, false, ocppTestConnectTimeout, timeout, "A")
+	suite.Require().NoError(err)
+
+	suite.Require().NoError(c.Enable(true))
+	<-handler.startC
+
+	enabled, err := c.Enabled()
+	suite.NoError(err)
+	suite.True(enabled)
+
+	// pending remote start is not repeated
+	suite.Require().NoError(c.Enable(true))
+	suite.Empty(handler.startC)
+
+	// transaction not started within timeout
+	time.Sleep(timeout + 100*time.Millisecond)
+
+	enabled, err = c.Enabled()
+	suite.NoError(err)
+	suite.False(enabled)
+
+	// transaction start clears the pending remote start
+	suite.Require().NoError(c.Enable(true))
+	<-handler.startC
+
+	_, err = cs.TransactionEvent(transactions.TransactionEventStarted, types.NewDateTime(time.Now()), transactions.TriggerReasonRemoteStart, 0, transactions.Transaction{
+		TransactionID: "txn-pending",
+		ChargingState: transactions.ChargingStateCharging,
+	}, func(request *transactions.TransactionEventRequest) {
+		request.Evse = &types.EVSE{ID: 1}
+	})
+	suite.Require().NoError(err)
+
+	time.Sleep(timeout + 100*time.Millisecond)
+
+	enabled, err = c.Enabled()
+	suite.NoError(err)
+	suite.True(enabled)
+
+	cs.Stop()
+}
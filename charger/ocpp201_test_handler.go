@@ -0,0 +1,126 @@
+package charger
+
+import (
+	"fmt"
+
+	"github.com/lorenzodonini/ocpp-go/ocpp2.0.1/provisioning"
+	"github.com/lorenzodonini/ocpp-go/ocpp2.0.1/remotecontrol"
+	"github.com/lorenzodonini/ocpp-go/ocpp2.0.1/smartcharging"
+	"github.com/lorenzodonini/ocpp-go/ocpp2.0.1/types"
+)
+
+type ChargingStationHandler struct {
+	triggerC chan remotecontrol.MessageTrigger
+	startC   chan *remotecontrol.RequestStartTransactionRequest
+	profileC chan *smartcharging.SetChargingProfileRequest
+}
+
+// provisioning
+
+func (handler *ChargingStationHandler) OnGetBaseReport(request *provisioning.GetBaseReportRequest) (*provisioning.GetBaseReportResponse, error) {
+	fmt.Printf("%T %+v\n", request, request)
+	return provisioning.NewGetBaseReportResponse(types.GenericDeviceModelStatusAccepted), nil
+}
+
+func (handler *ChargingStationHandler) OnGetReport(request *provisioning.GetReportRequest) (*provisioning.GetReportResponse, error) {
+	fmt.Printf("%T %+v\n", request, request)
+	return provisioning.NewGetReportResponse(types.GenericDeviceModelStatusAccepted), nil
+}
+
+func (handler *ChargingStationHandler) OnGetVariables(request *provisioning.GetVariablesRequest) (*provisioning.GetVariablesResponse, error) {
+	fmt.Printf("%T %+v\n", request, request)
+	return provisioning.NewGetVariablesResponse(nil), nil
+}
+
+func (handler *ChargingStationHandler) OnReset(request *provisioning.ResetRequest) (*provisioning.ResetResponse, error) {
+	fmt.Printf("%T %+v\n", request, request)
+	return provisioning.NewResetResponse(provisioning.ResetStatusAccepted), nil
+}
+
+func (handler *ChargingStationHandler) OnSetNetworkProfile(request *provisioning.SetNetworkProfileRequest) (*provisioning.SetNetworkProfileResponse, error) {
+	fmt.Printf("%T %+v\n", request, request)
+	return provisioning.NewSetNetworkProfileResponse(provisioning.SetNetworkProfileStatusAccepted), nil
+}
+
+func (handler *ChargingStationHandler) OnSetVariables(request *provisioning.SetVariablesRequest) (*provisioning.SetVariablesResponse, error) {
+	fmt.Printf("%T %+v\n", request, request)
+
+	res := make([]provisioning.SetVariableResult, 0, len(request.SetVariableData))
+	for _, data := range request.SetVariableData {
+		res = append(res, provisioning.SetVariableResult{
+			AttributeStatus: provisioning.SetVariableStatusAccepted,
+			Component:       data.Component,
+			Variable:        data.Variable,
+		})
+	}
+
+	return provisioning.NewSetVariablesResponse(res), nil
+}
+
+// remote control
+
+func (handler *ChargingStationHandler) OnRequestStartTransaction(request *remotecontrol.RequestStartTransactionRequest) (*remotecontrol.RequestStartTransactionResponse, error) {
+	fmt.Printf("%T %+v\n", request, request)
+
+	if c := handler.startC; c != nil {
+		select {
+		case c <- request:
+		default:
+		}
+	}
+
+	return remotecontrol.NewRequestStartTransactionResponse(remotecontrol.RequestStartStopStatusAccepted), nil
+}
+
+func (handler *ChargingStationHandler) OnRequestStopTransaction(request *remotecontrol.RequestStopTransactionRequest) (*remotecontrol.RequestStopTransactionResponse, error) {
+	fmt.Printf("%T %+v\n", request, request)
+	return remotecontrol.NewRequestStopTransactionResponse(remotecontrol.RequestStartStopStatusAccepted), nil
+}
+
+func (handler *ChargingStationHandler) OnTriggerMessage(request *remotecontrol.TriggerMessageRequest) (*remotecontrol.TriggerMessageResponse, error) {
+	fmt.Printf("%T %+v\n", request, request)
+
+	if c := handler.triggerC; request != nil && c != nil {
+		select {
+		case c <- request.RequestedMessage:
+		default:
+		}
+	}
+
+	return remotecontrol.NewTriggerMessageResponse(remotecontrol.TriggerMessageStatusAccepted), nil
+}
+
+func (handler *ChargingStationHandler) OnUnlockConnector(request *remotecontrol.UnlockConnectorRequest) (*remotecontrol.UnlockConnectorResponse, error) {
+	fmt.Printf("%T %+v\n", request, request)
+	return remotecontrol.NewUnlockConnectorResponse(remotecontrol.UnlockStatusUnlocked), nil
+}
+
+// smart charging
+
+func (handler *ChargingStationHandler) OnClearChargingProfile(request *smartcharging.ClearChargingProfileRequest) (*smartcharging.ClearChargingProfileResponse, error) {
+	fmt.Printf("%T %+v\n", request, request)
+	return smartcharging.NewClearChargingProfileResponse(smartcharging.ClearChargingProfileStatusAccepted), nil
+}
+
+func (handler *ChargingStationHandler) OnGetChargingProfiles(request *smartcharging.GetChargingProfilesRequest) (*smartcharging.GetChargingProfilesResponse, error) {
+	fmt.Printf("%T %+v\n", request, request)
+	return smartcharging.NewGetChargingProfilesResponse(smartcharging.GetChargingProfileStatusNoProfiles), nil
+}
+
+func (handler *ChargingStationHandler) OnGetCompositeSchedule(request *smartcharging.GetCompositeScheduleRequest) (*smartcharging.GetCompositeScheduleResponse, error) {
+	fmt.Printf("%T %+v\n", request, request)
+	return smartcharging.NewGetCompositeScheduleResponse(smartcharging.GetCompositeScheduleStatusAccepted, request.EvseID), nil
+}
+
+func (handler *ChargingStationHandler) OnSetChargingProfile(request *smartcharging.SetChargingProfileRequest) (*smartcharging.SetChargingProfileResponse, error) {
+	fmt.Printf("%T %+v\n", request, request)
+
+	if c := handler.profileC; c != nil {
+		select {
+		case c <- request:
+		default:
+		}
+	}
+
+	return smartcharging.NewSetChargingProfileResponse(smartcharging.ChargingProfileStatusAccepted), nil
+}
@@ -0,0 +1,95 @@
+package charger
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+
+	"github.com/evcc-io/evcc/charger/ocpp"
+	"github.com/lorenzodonini/ocpp-go/ocpp1.6/core"
+)
+
+// ocppConfigKeys maps the vendor-specific configuration keys of white-label chargers controlling current and phases
+type ocppConfigKeys struct {
+	Current  string // max charge current (A), used instead of charging profiles
+	Phases   string // number of active phases, enables phase switching
+	Phases1p string // value of the phases key for 1p charging
+	Phases3p string // value of the phases key for 3p charging
+}
+
+// keys returns the mapped configuration keys
+func (k ocppConfigKeys) keys() []string {
+	var res []string
+	for _, key := range []string{k.Current, k.Phases} {
+		if key != "" {
+			res = append(res, key)
+		}
+	}
+	return res
+}
+
+// phasesValue returns the phases key value for the given phases
+func (k ocppConfigKeys) phasesValue(phases int) string {
+	if phases == 1 && k.Phases1p != "" {
+		return k.Phases1p
+	}
+	if phases == 3 && k.Phases3p != "" {
+		return k.Phases3p
+	}
+	return strconv.Itoa(phases)
+}
+
+// setConfigKeys applies the configuration key map. Unless disabled, the keys are validated
+// against the charger's configuration to fail early on typos or read-only keys.
+func (c *OCPP) setConfigKeys(keys ocppConfigKeys, validate bool) error {
+	if validate && len(keys.keys()) > 0 {
+		rc := make(chan error, 1)
+
+		err := ocpp.Instance().GetConfiguration(c.conn.ChargePoint().ID(), func(resp *core.GetConfigurationConfirmation, err error) {
+			if err == nil {
+				err = validateConfigKeys(resp, keys.keys())
+			}
+
+			rc <- err
+		}, keys.keys())
+
+		if err := c.wait(err, rc); err != nil {
+			return err
+		}
+	}
+
+	c.keys = keys
+
+	return nil
+}
+
+// validateConfigKeys checks that all keys are supported and writable
+func validateConfigKeys(resp *core.GetConfigurationConfirmation, keys []string) error {
+	for _, key := range keys {
+		idx := slices.IndexFunc(resp.ConfigurationKey, func(k core.ConfigurationKey) bool {
+			return k.Key == key
+		})
+
+		if idx < 0 {
+			return fmt.Errorf("unsupported configuration key: %s", key)
+		}
+
+		if resp.ConfigurationKey[idx].Readonly {
+			return fmt.Errorf("read-only configuration key: %s", key)
+		}
+	}
+
+	return nil
+}
+
+// setCurrentKey sets the max charge current using the mapped configuration key
+func (c *OCPP) setCurrentKey(current float64) error {
+	current = math.Trunc(10*current) / 10
+	return c.configure(c.keys.Current, strconv.FormatFloat(current, 'f', -1, 64))
+}
+
+// setPhasesKey sets the active phases using the mapped configuration key
+func (c *OCPP) setPhasesKey(phases int) error {
+	return c.configure(c.keys.Phases, c.keys.phasesValue(phases))
+}
@@ -2,6 +2,7 @@ package charger
 
 import (
 	"errors"
+	"strconv"
 	"testing"
 	"time"
 
@@ -12,6 +13,7 @@ import (
 	"github.com/lorenzodonini/ocpp-go/ocpp1.6/core"
 	"github.com/lorenzodonini/ocpp-go/ocpp1.6/remotetrigger"
 	"github.com/lorenzodonini/ocpp-go/ocpp1.6/types"
+	"github.com/samber/lo"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -37,10 +39,12 @@ func (suite *ocppTestSuite) SetupSuite() {
 
 func (suite *ocppTestSuite) startChargePoint(id string, connectorId int) ocpp16.ChargePoint {
 	// set a handler for all callback functions
-	handler := &ChargePointHandler{
+	return suite.startChargePointWithHandler(id, connectorId, &ChargePointHandler{
 		triggerC: make(chan remotetrigger.MessageTrigger, 1),
-	}
+	})
+}
 
+func (suite *ocppTestSuite) startChargePointWithHandler(id string, connectorId int, handler *ChargePointHandler) ocpp16.ChargePoint {
 	// create charge point with handler
 	cp := ocpp16.NewChargePoint(id, nil, nil)
 	cp.SetCoreHandler(handler)
@@ -191,3 +195,329 @@ WAIT_DISCONNECT:
 		}
 	}
 }
+
+func (suite *ocppTestSuite) TestIdentify() {
+	const evccid = "DE8EO1234567890"
+
+	cp := suite.startChargePoint("test-pnc", 1)
+	suite.Require().NoError(cp.Start(ocppTestUrl))
+	suite.Require().True(cp.IsConnected())
+
+	c, err := NewOCPP("test-pnc", 1, defaultIdTag, "", 0, false, false, ocppTestConnectTimeout, ocppTestTimeout, "A")
+	suite.Require().NoError(err)
+	c.conn.TestClock(suite.clock)
+
+	// no transaction
+	id, err := c.Identify()
+	suite.NoError(err)
+	suite.Empty(id)
+
+	// vehicle started transaction using its EVCCID
+	res, err := cp.StartTransaction(1, evccid, 0, types.NewDateTime(suite.clock.Now()))
+	suite.Require().NoError(err)
+
+	id, err = c.Identify()
+	suite.NoError(err)
+	suite.Equal(evccid, id)
+
+	_, err = cp.StopTransaction(0, types.NewDateTime(suite.clock.Now()), res.TransactionId)
+	suite.Require().NoError(err)
+
+	id, err = c.Identify()
+	suite.NoError(err)
+	suite.Empty(id)
+
+	// transaction started remotely using own id tag
+	_, err = cp.StartTransaction(1, defaultIdTag, 0, types.NewDateTime(suite.clock.Now()))
+	suite.Require().NoError(err)
+
+	id, err = c.Identify()
+	suite.NoError(err)
+	suite.Empty(id)
+}
+
+func (suite *ocppTestSuite) TestChargerInfo() {
+	cp := suite.startChargePoint("test-info", 1)
+	suite.Require().NoError(cp.Start(ocppTestUrl))
+	suite.Require().True(cp.IsConnected())
+
+	c, err := NewOCPP("test-info", 1, "", "", 0, false, false, ocppTestConnectTimeout, ocppTestTimeout, "A")
+	suite.Require().NoError(err)
+
+	_, err = cp.BootNotification("P30", "KEBA", func(request *core.BootNotificationRequest) {
+		request.ChargePointSerialNumber = "12345678"
+		request.FirmwareVersion = "1.15.0"
+	})
+	suite.Require().NoError(err)
+
+	info, err := c.ChargerInfo()
+	suite.Require().NoError(err)
+	suite.Equal(api.ChargerInfo{Vendor: "KEBA", Model: "P30", Serial: "12345678", Firmware: "1.15.0"}, info)
+
+	// error code
+	_, err = cp.StatusNotification(1, core.GroundFailure, core.ChargePointStatusFaulted, func(request *core.StatusNotificationRequest) {
+		request.VendorErrorCode = "0x42"
+	})
+	suite.Require().NoError(err)
+
+	info, err = c.ChargerInfo()
+	suite.Require().NoError(err)
+	suite.Equal("GroundFailure (0x42)", info.ErrorCode)
+}
+
+func (suite *ocppTestSuite) TestRemoteStartStop() {
+	handler := &ChargePointHandler{
+		triggerC:     make(chan remotetrigger.MessageTrigger, 1),
+		remoteStartC: make(chan *core.RemoteStartTransactionRequest, 1),
+		remoteStopC:  make(chan *core.RemoteStopTransactionRequest, 1),
+	}
+
+	cp := suite.startChargePointWithHandler("test-remote", 1, handler)
+	suite.Require().NoError(cp.Start(ocppTestUrl))
+	suite.Require().True(cp.IsConnected())
+
+	c, err := NewOCPP("test-remote", 1, "remote-tag", "", 0, false, false, ocppTestConnectTimeout, ocppTestTimeout, "A")
+	suite.Require().NoError(err)
+
+	// enable issues remote start using the configured id tag
+	suite.Require().NoError(c.Enable(true))
+
+	start := <-handler.remoteStartC
+	suite.Equal("remote-tag", start.IdTag)
+	suite.Require().NotNil(start.ConnectorId)
+	suite.Equal(1, *start.ConnectorId)
+
+	enabled, err := c.Enabled()
+	suite.NoError(err)
+	suite.True(enabled)
+
+	// pending remote start is not repeated
+	suite.Require().NoError(c.Enable(true))
+	suite.Empty(handler.remoteStartC)
+
+	// charge point confirms the transaction asynchronously
+	res, err := cp.StartTransaction(1, start.IdTag, 0, types.NewDateTime(time.Now()))
+	suite.Require().NoError(err)
+
+	// disable issues remote stop of the running transaction
+	suite.Require().NoError(c.Enable(false))
+
+	stop := <-handler.remoteStopC
+	suite.Equal(res.TransactionId, stop.TransactionId)
+
+	enabled, err = c.Enabled()
+	suite.NoError(err)
+	suite.False(enabled)
+}
+
+func (suite *ocppTestSuite) TestRemoteStartPending() {
+	handler := &ChargePointHandler{
+		triggerC:     make(chan remotetrigger.MessageTrigger, 1),
+		remoteStartC: make(chan *core.RemoteStartTransactionRequest, 1),
+		remoteStopC:  make(chan *core.RemoteStopTransactionRequest, 1),
+	}
+
+	cp := suite.startChargePointWithHandler("test-remote-pending", 1, handler)
+	suite.Require().NoError(cp.Start(ocppTestUrl))
+	suite.Require().True(cp.IsConnected())
+
+	const timeout = 500 * time.Millisecond
+
+	c, err := NewOCPP("test-remote-pending", 1, defaultIdTag, "", 0, false, false, ocppTestConnectTimeout, timeout, "A")
+	suite.Require().NoError(err)
+
+	// transaction not started within timeout
+	suite.Require().NoError(c.Enable(true))
+	<-handler.remoteStartC
+
+	time.Sleep(timeout + 100*time.Millisecond)
+
+	enabled, err := c.Enabled()
+	suite.NoError(err)
+	suite.False(enabled)
+
+	// disable while transaction start is pending waits for the transaction
+	suite.Require().NoError(c.Enable(true))
+	start := <-handler.remoteStartC
+
+	txnC := make(chan int, 1)
+	go func() {
+		time.Sleep(timeout / 5)
+		res, err := cp.StartTransaction(1, start.IdTag, 0, types.NewDateTime(time.Now()))
+		suite.Require().NoError(err)
+		txnC <- res.TransactionId
+	}()
+
+	suite.Require().NoError(c.Enable(false))
+
+	stop := <-handler.remoteStopC
+	suite.Equal(<-txnC, stop.TransactionId)
+}
+
+func (suite *ocppTestSuite) TestRemoteStartRejected() {
+	handler := &ChargePointHandler{
+		triggerC:     make(chan remotetrigger.MessageTrigger, 1),
+		remoteStartC: make(chan *core.RemoteStartTransactionRequest, 1),
+		remoteStatus: types.RemoteStartStopStatusRejected,
+	}
+
+	cp := suite.startChargePointWithHandler("test-remote-rejected", 1, handler)
+	suite.Require().NoError(cp.Start(ocppTestUrl))
+	suite.Require().True(cp.IsConnected())
+
+	c, err := NewOCPP("test-remote-rejected", 1, defaultIdTag, "", 0, false, false, ocppTestConnectTimeout, ocppTestTimeout, "A")
+	suite.Require().NoError(err)
+
+	err = c.Enable(true)
+	suite.EqualError(err, string(types.RemoteStartStopStatusRejected))
+
+	start := <-handler.remoteStartC
+	suite.Equal(defaultIdTag, start.IdTag)
+
+	enabled, err := c.Enabled()
+	suite.NoError(err)
+	suite.False(enabled)
+}
+
+func (suite *ocppTestSuite) TestChargeTransaction() {
+	cp := suite.startChargePoint("test-txn", 1)
+	suite.Require().NoError(cp.Start(ocppTestUrl))
+	suite.Require().True(cp.IsConnected())
+
+	c, err := NewOCPP("test-txn", 1, defaultIdTag, "", 0, false, false, ocppTestConnectTimeout, ocppTestTimeout, "A")
+	suite.Require().NoError(err)
+	c.conn.TestClock(suite.clock)
+
+	// no transaction
+	_, err = c.ChargeTransaction()
+	suite.ErrorIs(err, api.ErrNotAvailable)
+
+	// transaction started remotely by evcc
+	res, err := cp.StartTransaction(1, defaultIdTag, 1234, types.NewDateTime(suite.clock.Now()))
+	suite.Require().NoError(err)
+
+	txn, err := c.ChargeTransaction()
+	suite.Require().NoError(err)
+	suite.Equal(strconv.Itoa(res.TransactionId), txn.ID)
+	suite.False(txn.External)
+	suite.Equal(lo.ToPtr(1.234), txn.MeterStart)
+	suite.Nil(txn.MeterStop)
+
+	_, err = cp.StopTransaction(5678, types.NewDateTime(suite.clock.Now()), res.TransactionId)
+	suite.Require().NoError(err)
+
+	// last transaction remains available after stop
+	txn, err = c.ChargeTransaction()
+	suite.Require().NoError(err)
+	suite.Equal(strconv.Itoa(res.TransactionId), txn.ID)
+	suite.Equal(lo.ToPtr(5.678), txn.MeterStop)
+
+	// transaction started by local rfid
+	res, err = cp.StartTransaction(1, "rfid", 6000, types.NewDateTime(suite.clock.Now()))
+	suite.Require().NoError(err)
+
+	txn, err = c.ChargeTransaction()
+	suite.Require().NoError(err)
+	suite.Equal(strconv.Itoa(res.TransactionId), txn.ID)
+	suite.Equal("rfid", txn.IdTag)
+	suite.True(txn.External)
+	suite.Equal(lo.ToPtr(6.0), txn.MeterStart)
+	suite.Nil(txn.MeterStop)
+
+	_, err = cp.StopTransaction(7000, types.NewDateTime(suite.clock.Now()), res.TransactionId)
+	suite.Require().NoError(err)
+
+	// transaction taken over without start
+	expectedTxn := 99
+
+	_, err = cp.MeterValues(1, []types.MeterValue{
+		{
+			Timestamp: types.NewDateTime(suite.clock.Now()),
+			SampledValue: []types.SampledValue{
+				{Measurand: types.MeasurandPowerActiveImport, Value: "1000"},
+			},
+		},
+	}, func(request *core.MeterValuesRequest) {
+		request.TransactionId = &expectedTxn
+	})
+	suite.Require().NoError(err)
+
+	txn, err = c.ChargeTransaction()
+	suite.Require().NoError(err)
+	suite.Equal("99", txn.ID)
+	suite.True(txn.External)
+	suite.Nil(txn.MeterStart)
+}
+
+func (suite *ocppTestSuite) TestConfigKeys() {
+	sixteen := "16"
+	handler := &ChargePointHandler{
+		triggerC: make(chan remotetrigger.MessageTrigger, 1),
+		changeC:  make(chan *core.ChangeConfigurationRequest, 1),
+		configKeys: []core.ConfigurationKey{
+			{Key: "VendorMaxCurrent", Value: &sixteen},
+			{Key: "VendorPhases", Value: &sixteen},
+			{Key: "VendorFirmware", Value: &sixteen, Readonly: true},
+		},
+	}
+
+	cp := suite.startChargePointWithHandler("test-keys", 1, handler)
+	suite.Require().NoError(cp.Start(ocppTestUrl))
+	suite.Require().True(cp.IsConnected())
+
+	c, err := NewOCPP("test-keys", 1, "", "", 0, false, false, ocppTestConnectTimeout, ocppTestTimeout, "A")
+	suite.Require().NoError(err)
+
+	// keys are validated against the charger's configuration
+	suite.ErrorContains(c.setConfigKeys(ocppConfigKeys{Current: "UnknownKey"}, true), "unsupported")
+	suite.ErrorContains(c.setConfigKeys(ocppConfigKeys{Current: "VendorFirmware"}, true), "read-only")
+
+	suite.Require().NoError(c.setConfigKeys(ocppConfigKeys{
+		Current:  "VendorMaxCurrent",
+		Phases:   "VendorPhases",
+		Phases1p: "single",
+	}, true))
+
+	// current is set using the mapped key without transaction
+	suite.Require().NoError(c.MaxCurrentMillis(10.56))
+
+	req := <-handler.changeC
+	suite.Equal("VendorMaxCurrent", req.Key)
+	suite.Equal("10.5", req.Value)
+
+	// phases are set using the mapped key and values
+	suite.Require().NoError(c.phases1p3p(1))
+
+	req = <-handler.changeC
+	suite.Equal("VendorPhases", req.Key)
+	suite.Equal("single", req.Value)
+
+	suite.Require().NoError(c.phases1p3p(3))
+
+	req = <-handler.changeC
+	suite.Equal("VendorPhases", req.Key)
+	suite.Equal("3", req.Value)
+}
+
+func (suite *ocppTestSuite) TestResetFault() {
+	handler := &ChargePointHandler{
+		triggerC: make(chan remotetrigger.MessageTrigger, 1),
+		resetC:   make(chan *core.ResetRequest, 1),
+	}
+
+	cp := suite.startChargePointWithHandler("test-reset", 1, handler)
+	suite.Require().NoError(cp.Start(ocppTestUrl))
+	suite.Require().True(cp.IsConnected())
+
+	c, err := NewOCPP("test-reset", 1, "", "", 0, false, false, ocppTestConnectTimeout, ocppTestTimeout, "A")
+	suite.Require().NoError(err)
+
+	suite.Require().NoError(c.ResetFault())
+
+	select {
+	case req := <-handler.resetC:
+		suite.Equal(core.ResetTypeSoft, req.Type)
+	case <-time.After(ocppTestTimeout):
+		suite.Fail("missing reset request")
+	}
+}
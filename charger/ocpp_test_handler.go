@@ -9,7 +9,20 @@ import (
 )
 
 type ChargePointHandler struct {
-	triggerC chan remotetrigger.MessageTrigger
+	triggerC     chan remotetrigger.MessageTrigger
+	remoteStartC chan *core.RemoteStartTransactionRequest
+	remoteStopC  chan *core.RemoteStopTransactionRequest
+	remoteStatus types.RemoteStartStopStatus // response status, accepted if empty
+	configKeys   []core.ConfigurationKey     // additional configuration keys
+	changeC      chan *core.ChangeConfigurationRequest
+	resetC       chan *core.ResetRequest
+}
+
+func (handler *ChargePointHandler) remoteStartStopStatus() types.RemoteStartStopStatus {
+	if handler.remoteStatus == "" {
+		return types.RemoteStartStopStatusAccepted
+	}
+	return handler.remoteStatus
 }
 
 func (handler *ChargePointHandler) OnChangeAvailability(request *core.ChangeAvailabilityRequest) (confirmation *core.ChangeAvailabilityConfirmation, err error) {
@@ -19,6 +32,11 @@ func (handler *ChargePointHandler) OnChangeAvailability(request *core.ChangeAvai
 
 func (handler *ChargePointHandler) OnChangeConfiguration(request *core.ChangeConfigurationRequest) (confirmation *core.ChangeConfigurationConfirmation, err error) {
 	fmt.Printf("%T %+v\n", request, request)
+
+	if c := handler.changeC; c != nil {
+		c <- request
+	}
+
 	return core.NewChangeConfigurationConfirmation(core.ConfigurationStatusAccepted), nil
 }
 
@@ -36,7 +54,7 @@ func (handler *ChargePointHandler) OnGetConfiguration(request *core.GetConfigura
 	fmt.Printf("%T %+v\n", request, request)
 	one := "1"
 	meter := "Power.Active.Import,Energy.Active.Import.Register"
-	return core.NewGetConfigurationConfirmation([]core.ConfigurationKey{
+	return core.NewGetConfigurationConfirmation(append([]core.ConfigurationKey{
 		{Key: "AuthorizationKey"},
 		{Key: "NumberOfConnectors", Value: &one},
 		{Key: "ChargeProfileMaxStackLevel", Value: &one},
@@ -44,21 +62,36 @@ func (handler *ChargePointHandler) OnGetConfiguration(request *core.GetConfigura
 		{Key: "MaxChargingProfilesInstalled", Value: &one},
 		{Key: "ChargingScheduleAllowedChargingRateUnit", Value: &one},
 		{Key: "MeterValuesSampledData", Value: &meter},
-	}), nil
+	}, handler.configKeys...)), nil
 }
 
 func (handler *ChargePointHandler) OnRemoteStartTransaction(request *core.RemoteStartTransactionRequest) (confirmation *core.RemoteStartTransactionConfirmation, err error) {
 	fmt.Printf("%T %+v\n", request, request)
-	return core.NewRemoteStartTransactionConfirmation(types.RemoteStartStopStatusAccepted), nil
+
+	if c := handler.remoteStartC; c != nil {
+		c <- request
+	}
+
+	return core.NewRemoteStartTransactionConfirmation(handler.remoteStartStopStatus()), nil
 }
 
 func (handler *ChargePointHandler) OnRemoteStopTransaction(request *core.RemoteStopTransactionRequest) (confirmation *core.RemoteStopTransactionConfirmation, err error) {
 	fmt.Printf("%T %+v\n", request, request)
-	return core.NewRemoteStopTransactionConfirmation(types.RemoteStartStopStatusAccepted), nil
+
+	if c := handler.remoteStopC; c != nil {
+		c <- request
+	}
+
+	return core.NewRemoteStopTransactionConfirmation(handler.remoteStartStopStatus()), nil
 }
 
 func (handler *ChargePointHandler) OnReset(request *core.ResetRequest) (confirmation *core.ResetConfirmation, err error) {
 	fmt.Printf("%T %+v\n", request, request)
+
+	if c := handler.resetC; c != nil {
+		c <- request
+	}
+
 	return core.NewResetConfirmation(core.ResetStatusAccepted), nil
 }
 
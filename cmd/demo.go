@@ -1,19 +1,36 @@
 package cmd
 
 import (
+	"bytes"
 	_ "embed" // for yaml
 	"fmt"
-	"strings"
+	"text/template"
 
+	"github.com/evcc-io/evcc/provider/demo"
 	"github.com/spf13/viper"
 )
 
 //go:embed demo.yaml
 var demoYaml string
 
-func demoConfig(conf *globalConfig) error {
+func demoConfig(conf *globalConfig, scenario string) error {
+	sc, err := demo.ScenarioString(scenario)
+	if err != nil {
+		return err
+	}
+
+	tmpl, err := template.New("demo").Parse(demoYaml)
+	if err != nil {
+		return fmt.Errorf("failed parsing demo config: %w", err)
+	}
+
+	var b bytes.Buffer
+	if err := tmpl.Execute(&b, map[string]any{"Scenario": sc}); err != nil {
+		return fmt.Errorf("failed rendering demo config: %w", err)
+	}
+
 	viper.SetConfigType("yaml")
-	if err := viper.ReadConfig(strings.NewReader(demoYaml)); err != nil {
+	if err := viper.ReadConfig(&b); err != nil {
 		return fmt.Errorf("failed decoding demo config: %w", err)
 	}
 
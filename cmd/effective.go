@@ -0,0 +1,155 @@
+package cmd
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/evcc-io/evcc/util"
+)
+
+const redacted = "*****"
+
+// effectiveConfig returns the running configuration including defaults as generic map with secrets redacted
+func effectiveConfig(conf globalConfig) map[string]any {
+	res, _ := redactValue("", toGeneric(reflect.ValueOf(conf))).(map[string]any)
+	return res
+}
+
+// isSecret returns true if the configuration key holds a secret
+func isSecret(key string) bool {
+	return slices.Contains(secrets, strings.ToLower(key)) || util.IsSecretKey(key)
+}
+
+// redactValue recursively masks secret values while preserving the structure
+func redactValue(key string, v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		res := make(map[string]any, len(v))
+		for k, val := range v {
+			res[k] = redactValue(k, val)
+		}
+		return res
+
+	case []any:
+		res := make([]any, 0, len(v))
+		for _, val := range v {
+			res = append(res, redactValue(key, val))
+		}
+		return res
+
+	case nil:
+		return nil
+	}
+
+	if isSecret(key) {
+		if s, ok := v.(string); ok && s == "" {
+			return s
+		}
+		return redacted
+	}
+
+	// credentials included in uris
+	if s, ok := v.(string); ok && strings.Contains(s, "@") {
+		if u, err := url.Parse(s); err == nil && u.User != nil {
+			u.User = nil
+			return strings.Replace(u.String(), "://", "://"+redacted+"@", 1)
+		}
+	}
+
+	return v
+}
+
+// toGeneric converts the configuration into maps and slices using the configuration file keys
+func toGeneric(v reflect.Value) any {
+	switch v.Kind() {
+	case reflect.Invalid:
+		return nil
+
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return toGeneric(v.Elem())
+
+	case reflect.Struct:
+		res := make(map[string]any)
+		structToGeneric(v, res)
+		return res
+
+	case reflect.Map:
+		if v.IsNil() {
+			return nil
+		}
+		res := make(map[string]any, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			res[strings.ToLower(fmt.Sprint(iter.Key().Interface()))] = toGeneric(iter.Value())
+		}
+		return res
+
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return nil
+		}
+		res := make([]any, 0, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			res = append(res, toGeneric(v.Index(i)))
+		}
+		return res
+	}
+
+	if d, ok := v.Interface().(time.Duration); ok {
+		return d.String()
+	}
+
+	return v.Interface()
+}
+
+// structToGeneric adds the struct fields to the map, inlining squashed and remaining fields
+func structToGeneric(v reflect.Value, res map[string]any) {
+	t := v.Type()
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+
+		name, opts, _ := strings.Cut(f.Tag.Get("mapstructure"), ",")
+		if name == "-" {
+			continue
+		}
+
+		fv := v.Field(i)
+
+		switch {
+		case opts == "squash" || f.Anonymous && name == "":
+			for fv.Kind() == reflect.Pointer {
+				if fv.IsNil() {
+					break
+				}
+				fv = fv.Elem()
+			}
+			if fv.Kind() == reflect.Struct {
+				structToGeneric(fv, res)
+			}
+
+		case opts == "remain":
+			if m, ok := toGeneric(fv).(map[string]any); ok {
+				for k, val := range m {
+					res[k] = val
+				}
+			}
+
+		default:
+			if name == "" {
+				name = f.Name
+			}
+			res[strings.ToLower(name)] = toGeneric(fv)
+		}
+	}
+}
@@ -36,6 +36,12 @@ const (
 	flagStop            = "stop"
 	flagStopDescription = "Stop charging"
 
+	flagDemo            = "demo"
+	flagDemoDescription = "Run with simulated devices using scenario (sunny, cloudy, night)"
+
+	flagCheck            = "check"
+	flagCheckDescription = "Validate configuration and exit"
+
 	flagDigits = "digits"
 	flagDelay  = "delay"
 )
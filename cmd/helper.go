@@ -48,16 +48,18 @@ func unwrap(err error) (res []string) {
 	return
 }
 
+// secrets are the configuration keys redacted for publishing
+var secrets = []string{
+	"mac",                   // infrastructure
+	"sponsortoken", "plant", // global settings
+	"user", "password", "pin", // users
+	"token", "access", "refresh", "accesstoken", "refreshtoken", // tokens, including template variations
+	"ain", "secret", "serial", "deviceid", "machineid", "idtag", // devices
+	"app", "chats", "recipients", // push messaging
+	"vin"} // vehicles
+
 // redact redacts a configuration string
 func redact(src string) string {
-	secrets := []string{
-		"mac",                   // infrastructure
-		"sponsortoken", "plant", // global settings
-		"user", "password", "pin", // users
-		"token", "access", "refresh", "accesstoken", "refreshtoken", // tokens, including template variations
-		"ain", "secret", "serial", "deviceid", "machineid", "idtag", // devices
-		"app", "chats", "recipients", // push messaging
-		"vin"} // vehicles
 	return regexp.
 		MustCompile(fmt.Sprintf(`(?i)\b(%s)\b.*?:.*`, strings.Join(secrets, "|"))).
 		ReplaceAllString(src, "$1: *****")
@@ -0,0 +1,350 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/charger"
+	"github.com/evcc-io/evcc/charger/ocpp"
+	"github.com/evcc-io/evcc/core/loadpoint"
+	"github.com/evcc-io/evcc/core/site"
+	"github.com/evcc-io/evcc/meter"
+	"github.com/evcc-io/evcc/util"
+	"github.com/evcc-io/evcc/util/config"
+)
+
+// reloadResult describes the changes applied by a configuration reload
+type reloadResult struct {
+	Unchanged []string `json:"unchanged"`
+	Added     []string `json:"added"`
+	Updated   []string `json:"updated"`
+	Removed   []string `json:"removed"`
+	Restart   []string `json:"restart"` // changes that can only be applied by restarting
+}
+
+// loadpointHotKeys are the loadpoint settings that can be applied to the running loadpoint
+var loadpointHotKeys = []string{"mode", "priority", "mincurrent", "maxcurrent"}
+
+// reloadConfig reads the config file and applies the differences to the running configuration.
+// Unchanged devices are kept including their connections and tokens.
+func reloadConfig(site site.API) (reloadResult, error) {
+	next := defaultConfig()
+	if err := loadConfigFile(&next); err != nil {
+		return reloadResult{}, err
+	}
+
+	if err := validateConfig(next); err != nil {
+		return reloadResult{}, err
+	}
+
+	res, err := reload(&conf, next, site.Loadpoints())
+	if err == nil {
+		log.INFO.Printf("config reloaded: %d added, %d updated, %d removed, %d unchanged", len(res.Added), len(res.Updated), len(res.Removed), len(res.Unchanged))
+		for _, msg := range res.Restart {
+			log.WARN.Printf("config reload: %s requires restart", msg)
+		}
+	}
+
+	return res, err
+}
+
+// reload applies the next configuration to the devices and loadpoints of the running configuration.
+// The running configuration is updated with all changes that have been applied.
+func reload(running *globalConfig, next globalConfig, loadpoints []loadpoint.API) (reloadResult, error) {
+	var res reloadResult
+
+	// devices referenced by running loadpoints and site cannot be replaced
+	var meters, chargers, vehicles []string
+	for _, lp := range running.Loadpoints {
+		meters = append(meters, stringValue(lp, "meter"))
+		chargers = append(chargers, stringValue(lp, "charger"))
+		vehicles = append(vehicles, stringValue(lp, "vehicle"))
+	}
+	if running.Site != nil {
+		if m, ok := lookup(running.Site, "meters"); ok {
+			meters = append(meters, siteMeterRefs(m)...)
+		}
+	}
+
+	// vehicles assigned at runtime
+	vehicles = append(vehicles, assignedVehicles(loadpoints)...)
+
+	if err := reloadDevices(&res, "meter", config.Meters(), next.Meters, meters, func(cc config.Named) (api.Meter, error) {
+		return meter.NewFromConfig(cc.Type, cc.Other)
+	}, nil); err != nil {
+		return res, err
+	}
+
+	// ocpp chargers remain registered with the shared central system and cannot be recreated
+	if err := reloadDevices(&res, "charger", config.Chargers(), next.Chargers, chargers, func(cc config.Named) (api.Charger, error) {
+		return charger.NewFromConfig(cc.Type, cc.Other)
+	}, isOCPP); err != nil {
+		return res, err
+	}
+
+	// vehicles are hot-swapped by the site's vehicle coordinator
+	if err := reloadDevices(&res, "vehicle", config.Vehicles(), next.Vehicles, vehicles, vehicleInstance, nil); err != nil {
+		return res, err
+	}
+
+	running.Meters = next.Meters
+	running.Chargers = next.Chargers
+	running.Vehicles = next.Vehicles
+
+	if err := reloadLoadpoints(&res, running, next, loadpoints); err != nil {
+		return res, err
+	}
+
+	// remaining global settings are applied on startup only
+	a, b := *running, next
+	a.Meters, a.Chargers, a.Vehicles, a.Loadpoints = nil, nil, nil, nil
+	b.Meters, b.Chargers, b.Vehicles, b.Loadpoints = nil, nil, nil, nil
+	if !reflect.DeepEqual(a, b) {
+		res.Restart = append(res.Restart, "global settings")
+	}
+
+	return res, nil
+}
+
+// reloadDevices replaces the static devices whose configuration has changed and closes replaced or removed instances.
+// Devices in use and devices matching restart are not replaced.
+func reloadDevices[T any](res *reloadResult, class string, h config.Handler[T], static []config.Named, inUse []string, create func(config.Named) (T, error), restart func(T) bool) error {
+	current := make(map[string]config.Device[T])
+	for _, dev := range h.Devices() {
+		// database devices are managed by the config api
+		if _, ok := dev.(config.ConfigurableDevice[T]); !ok {
+			current[dev.Config().Name] = dev
+		}
+	}
+
+	for i, cc := range static {
+		if cc.Name == "" {
+			return fmt.Errorf("cannot create %s %d: missing name", class, i+1)
+		}
+
+		key := fmt.Sprintf("%s '%s'", class, cc.Name)
+
+		dev, ok := current[cc.Name]
+		delete(current, cc.Name)
+
+		if ok && reflect.DeepEqual(dev.Config(), cc) {
+			res.Unchanged = append(res.Unchanged, key)
+			continue
+		}
+
+		if ok && (slices.Contains(inUse, cc.Name) || restart != nil && restart(dev.Instance())) {
+			res.Restart = append(res.Restart, key)
+			continue
+		}
+
+		instance, err := create(cc)
+		if err != nil {
+			return fmt.Errorf("cannot create %s: %w", key, err)
+		}
+
+		if ok {
+			if err := h.Delete(cc.Name); err != nil {
+				return err
+			}
+			closeInstance(key, dev.Instance())
+			res.Updated = append(res.Updated, key)
+		} else {
+			res.Added = append(res.Added, key)
+		}
+
+		if err := h.Add(config.NewStaticDevice(cc, instance)); err != nil {
+			return err
+		}
+	}
+
+	for name, dev := range current {
+		key := fmt.Sprintf("%s '%s'", class, name)
+
+		if slices.Contains(inUse, name) || restart != nil && restart(dev.Instance()) {
+			res.Restart = append(res.Restart, key)
+			continue
+		}
+
+		if err := h.Delete(name); err != nil {
+			return err
+		}
+		closeInstance(key, dev.Instance())
+		res.Removed = append(res.Removed, key)
+	}
+
+	return nil
+}
+
+// closeInstance releases connections and goroutines of a replaced or removed device instance
+func closeInstance(key string, instance any) {
+	if c, ok := instance.(io.Closer); ok {
+		if err := c.Close(); err != nil {
+			log.WARN.Printf("config reload: closing %s failed: %v", key, err)
+		}
+	}
+}
+
+// isOCPP returns true if the charger is registered with the shared ocpp central system
+func isOCPP(c api.Charger) bool {
+	switch c.(type) {
+	case interface{ Connector() *ocpp.Connector }, interface{ EVSE() *ocpp.EVSE }:
+		return true
+	}
+	return false
+}
+
+// assignedVehicles returns the names of the vehicles currently assigned to the loadpoints
+func assignedVehicles(loadpoints []loadpoint.API) []string {
+	var res []string
+
+	for _, lp := range loadpoints {
+		v := lp.GetVehicle()
+		if v == nil {
+			continue
+		}
+
+		for _, dev := range config.Vehicles().Devices() {
+			if dev.Instance() == v {
+				res = append(res, dev.Config().Name)
+			}
+		}
+	}
+
+	return res
+}
+
+// reloadLoadpoints applies changed loadpoint settings to the running loadpoints
+func reloadLoadpoints(res *reloadResult, running *globalConfig, next globalConfig, loadpoints []loadpoint.API) error {
+	if len(next.Loadpoints) != len(running.Loadpoints) || len(loadpoints) != len(running.Loadpoints) {
+		res.Restart = append(res.Restart, "loadpoints")
+		return nil
+	}
+
+	for i, lp := range loadpoints {
+		key := fmt.Sprintf("loadpoint %d", i+1)
+		prev, cur := running.Loadpoints[i], next.Loadpoints[i]
+
+		if reflect.DeepEqual(prev, cur) {
+			res.Unchanged = append(res.Unchanged, key)
+			continue
+		}
+
+		if !reflect.DeepEqual(withoutKeys(prev, loadpointHotKeys), withoutKeys(cur, loadpointHotKeys)) {
+			res.Restart = append(res.Restart, key)
+			continue
+		}
+
+		// removed settings require the defaults applied on startup
+		if slices.ContainsFunc(loadpointHotKeys, func(k string) bool {
+			_, ok := lookup(cur, k)
+			_, wasSet := lookup(prev, k)
+			return wasSet && !ok
+		}) {
+			res.Restart = append(res.Restart, key)
+			continue
+		}
+
+		if err := applyLoadpointSettings(lp, cur); err != nil {
+			return fmt.Errorf("%s: %w", key, err)
+		}
+
+		running.Loadpoints[i] = cur
+		res.Updated = append(res.Updated, key)
+	}
+
+	return nil
+}
+
+// applyLoadpointSettings applies the hot-reloadable settings to the loadpoint
+func applyLoadpointSettings(lp loadpoint.API, other map[string]interface{}) error {
+	var cc struct {
+		Mode       api.ChargeMode
+		Priority   *int
+		MinCurrent float64
+		MaxCurrent float64
+	}
+
+	if err := util.DecodeOther(withKeys(other, loadpointHotKeys), &cc); err != nil {
+		return err
+	}
+
+	if cc.MinCurrent > 0 && cc.MaxCurrent > 0 && cc.MinCurrent > cc.MaxCurrent {
+		return errors.New("minCurrent must not exceed maxCurrent")
+	}
+
+	if cc.Mode != "" {
+		lp.SetMode(cc.Mode)
+	}
+	if cc.Priority != nil {
+		lp.SetPriority(*cc.Priority)
+	}
+	if cc.MinCurrent > 0 {
+		lp.SetMinCurrent(cc.MinCurrent)
+	}
+	if cc.MaxCurrent > 0 {
+		lp.SetMaxCurrent(cc.MaxCurrent)
+	}
+
+	return nil
+}
+
+// withKeys returns the case-insensitive subset of the given keys
+func withKeys(other map[string]interface{}, keys []string) map[string]interface{} {
+	res := make(map[string]interface{})
+	for k, v := range other {
+		if slices.Contains(keys, strings.ToLower(k)) {
+			res[k] = v
+		}
+	}
+	return res
+}
+
+// withoutKeys returns a copy without the given case-insensitive keys
+func withoutKeys(other map[string]interface{}, keys []string) map[string]interface{} {
+	res := make(map[string]interface{})
+	for k, v := range other {
+		if !slices.Contains(keys, strings.ToLower(k)) {
+			res[k] = v
+		}
+	}
+	return res
+}
+
+// stringValue returns the string value of the case-insensitive key
+func stringValue(other map[string]interface{}, key string) string {
+	v, _ := lookup(other, key)
+	s, _ := v.(string)
+	return s
+}
+
+// siteMeterRefs returns the meter references of the site meters config
+func siteMeterRefs(meters interface{}) []string {
+	var res []string
+
+	m, ok := meters.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	for _, v := range m {
+		switch v := v.(type) {
+		case string:
+			res = append(res, v)
+		case []interface{}:
+			for _, ref := range v {
+				if s, ok := ref.(string); ok {
+					res = append(res, s)
+				}
+			}
+		case []string:
+			res = append(res, v...)
+		}
+	}
+
+	return res
+}
@@ -0,0 +1,265 @@
+package cmd
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/charger/ocpp"
+	"github.com/evcc-io/evcc/core/loadpoint"
+	"github.com/evcc-io/evcc/meter"
+	"github.com/evcc-io/evcc/util/config"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func reloadTestConfig(t *testing.T, grid, pv, aux int, maxCurrent int, charger string) globalConfig {
+	t.Helper()
+
+	return readTestConfig(t, fmt.Sprintf(`
+meters:
+- name: reload-grid
+  type: custom
+  power:
+    source: const
+    value: %d
+- name: reload-pv
+  type: custom
+  power:
+    source: const
+    value: %d
+- name: reload-aux
+  type: custom
+  power:
+    source: const
+    value: %d
+site:
+  meters:
+    grid: reload-grid
+loadpoints:
+- title: Garage
+  charger: %s
+  maxCurrent: %d
+`, grid, pv, aux, charger, maxCurrent))
+}
+
+func TestReload(t *testing.T) {
+	running := reloadTestConfig(t, 1000, 2000, 3000, 16, "wallbox")
+
+	for _, cc := range running.Meters {
+		instance, err := meter.NewFromConfig(cc.Type, cc.Other)
+		require.NoError(t, err)
+		require.NoError(t, config.Meters().Add(config.NewStaticDevice(cc, instance)))
+	}
+
+	t.Cleanup(func() {
+		for _, name := range []string{"reload-grid", "reload-pv", "reload-aux", "reload-battery"} {
+			_ = config.Meters().Delete(name)
+		}
+	})
+
+	instances := make(map[string]api.Meter)
+	for _, dev := range config.Meters().Devices() {
+		instances[dev.Config().Name] = dev.Instance()
+	}
+
+	ctrl := gomock.NewController(t)
+	lp := loadpoint.NewMockAPI(ctrl)
+	lp.EXPECT().GetVehicle().Return(nil).AnyTimes()
+
+	// unchanged config
+	res, err := reload(&running, reloadTestConfig(t, 1000, 2000, 3000, 16, "wallbox"), []loadpoint.API{lp})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"meter 'reload-grid'", "meter 'reload-pv'", "meter 'reload-aux'", "loadpoint 1"}, res.Unchanged)
+	assert.Empty(t, res.Updated)
+	assert.Empty(t, res.Restart)
+
+	// grid meter in use by site, pv meter unused, loadpoint current
+	next := reloadTestConfig(t, 1500, 2500, 3000, 32, "wallbox")
+	next.Meters = append(next.Meters, config.Named{
+		Name:  "reload-battery",
+		Type:  "custom",
+		Other: map[string]interface{}{"power": map[string]interface{}{"source": "const", "value": 0}},
+	})
+
+	lp.EXPECT().SetMaxCurrent(32.0)
+
+	res, err = reload(&running, next, []loadpoint.API{lp})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"meter 'reload-aux'"}, res.Unchanged)
+	assert.ElementsMatch(t, []string{"meter 'reload-pv'", "loadpoint 1"}, res.Updated)
+	assert.Equal(t, []string{"meter 'reload-battery'"}, res.Added)
+	assert.Empty(t, res.Removed)
+	assert.Equal(t, []string{"meter 'reload-grid'"}, res.Restart)
+
+	// unchanged devices keep their instances
+	for name, replaced := range map[string]bool{"reload-grid": false, "reload-pv": true, "reload-aux": false} {
+		dev, err := config.Meters().ByName(name)
+		require.NoError(t, err)
+
+		if replaced {
+			assert.NotSame(t, instances[name], dev.Instance(), name)
+		} else {
+			assert.Same(t, instances[name], dev.Instance(), name)
+		}
+	}
+
+	// reload is idempotent
+	res, err = reload(&running, next, []loadpoint.API{lp})
+	require.NoError(t, err)
+	assert.Empty(t, res.Updated)
+	assert.Empty(t, res.Added)
+	assert.Equal(t, []string{"meter 'reload-grid'"}, res.Restart)
+
+	// unused meter removed, loadpoint charger requires restart
+	next = reloadTestConfig(t, 1000, 2500, 3000, 32, "other")
+	next.Meters = next.Meters[:2]
+
+	res, err = reload(&running, next, []loadpoint.API{lp})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"meter 'reload-aux'", "meter 'reload-battery'"}, res.Removed)
+	assert.Equal(t, []string{"loadpoint 1"}, res.Restart)
+
+	_, err = config.Meters().ByName("reload-aux")
+	assert.Error(t, err)
+}
+
+type reloadCharger struct {
+	api.Charger
+	closed bool
+}
+
+func (c *reloadCharger) Close() error {
+	c.closed = true
+	return nil
+}
+
+type reloadOCPP struct {
+	reloadCharger
+}
+
+func (c *reloadOCPP) Connector() *ocpp.Connector {
+	return nil
+}
+
+func TestReloadChargers(t *testing.T) {
+	static := []config.Named{
+		{Name: "reload-wallbox", Type: "custom", Other: map[string]any{"id": 1}},
+		{Name: "reload-removed", Type: "custom"},
+		{Name: "reload-ocpp", Type: "ocpp", Other: map[string]any{"stationid": "foo"}},
+		{Name: "reload-ocpp-removed", Type: "ocpp"},
+	}
+
+	instances := map[string]api.Charger{
+		"reload-wallbox":      new(reloadCharger),
+		"reload-removed":      new(reloadCharger),
+		"reload-ocpp":         new(reloadOCPP),
+		"reload-ocpp-removed": new(reloadOCPP),
+	}
+
+	for _, cc := range static {
+		require.NoError(t, config.Chargers().Add(config.NewStaticDevice(cc, instances[cc.Name])))
+	}
+
+	t.Cleanup(func() {
+		for name := range instances {
+			_ = config.Chargers().Delete(name)
+		}
+	})
+
+	next := []config.Named{
+		{Name: "reload-wallbox", Type: "custom", Other: map[string]any{"id": 2}},
+		{Name: "reload-ocpp", Type: "ocpp", Other: map[string]any{"stationid": "bar"}},
+	}
+
+	var created []string
+	create := func(cc config.Named) (api.Charger, error) {
+		created = append(created, cc.Name)
+		return new(reloadCharger), nil
+	}
+
+	var res reloadResult
+	require.NoError(t, reloadDevices(&res, "charger", config.Chargers(), next, nil, create, isOCPP))
+
+	assert.Equal(t, []string{"reload-wallbox"}, created)
+	assert.Equal(t, []string{"charger 'reload-wallbox'"}, res.Updated)
+	assert.Equal(t, []string{"charger 'reload-removed'"}, res.Removed)
+	assert.ElementsMatch(t, []string{"charger 'reload-ocpp'", "charger 'reload-ocpp-removed'"}, res.Restart)
+
+	// replaced and removed instances are closed, ocpp chargers are kept
+	assert.True(t, instances["reload-wallbox"].(*reloadCharger).closed)
+	assert.True(t, instances["reload-removed"].(*reloadCharger).closed)
+
+	for _, name := range []string{"reload-ocpp", "reload-ocpp-removed"} {
+		dev, err := config.Chargers().ByName(name)
+		require.NoError(t, err)
+		assert.Same(t, instances[name], dev.Instance(), name)
+		assert.False(t, instances[name].(*reloadOCPP).closed, name)
+	}
+}
+
+func TestReloadVehicles(t *testing.T) {
+	vehicleConfig := func(capacity int) globalConfig {
+		return readTestConfig(t, fmt.Sprintf(`
+vehicles:
+- name: reload-car
+  type: custom
+  title: Car
+  capacity: %d
+  soc:
+    source: const
+    value: 50
+- name: reload-other
+  type: custom
+  title: Other
+  capacity: %d
+  soc:
+    source: const
+    value: 50
+loadpoints:
+- title: Garage
+  charger: wallbox
+`, capacity, capacity))
+	}
+
+	running := vehicleConfig(50)
+
+	for _, cc := range running.Vehicles {
+		instance, err := vehicleInstance(cc)
+		require.NoError(t, err)
+		require.NoError(t, config.Vehicles().Add(config.NewStaticDevice(cc, instance)))
+	}
+
+	t.Cleanup(func() {
+		for _, name := range []string{"reload-car", "reload-other"} {
+			_ = config.Vehicles().Delete(name)
+		}
+	})
+
+	dev, err := config.Vehicles().ByName("reload-car")
+	require.NoError(t, err)
+
+	// vehicle assigned at runtime without loadpoint config reference
+	ctrl := gomock.NewController(t)
+	lp := loadpoint.NewMockAPI(ctrl)
+	lp.EXPECT().GetVehicle().Return(dev.Instance()).AnyTimes()
+
+	res, err := reload(&running, vehicleConfig(60), []loadpoint.API{lp})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"vehicle 'reload-car'"}, res.Restart)
+	assert.Equal(t, []string{"vehicle 'reload-other'"}, res.Updated)
+
+	// assigned vehicle is not removed
+	next := vehicleConfig(60)
+	next.Vehicles = nil
+
+	res, err = reload(&running, next, []loadpoint.API{lp})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"vehicle 'reload-car'"}, res.Restart)
+	assert.Equal(t, []string{"vehicle 'reload-other'"}, res.Removed)
+
+	_, err = config.Vehicles().ByName("reload-car")
+	assert.NoError(t, err)
+}
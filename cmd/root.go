@@ -70,6 +70,10 @@ func init() {
 
 	rootCmd.Flags().Bool("profile", false, "Expose pprof profiles")
 	bind(rootCmd, "profile")
+
+	rootCmd.Flags().String(flagDemo, "", flagDemoDescription)
+
+	rootCmd.Flags().Bool(flagCheck, false, flagCheckDescription)
 }
 
 // initConfig reads in config file and ENV variables if set
@@ -116,15 +120,39 @@ func Execute() {
 func runRoot(cmd *cobra.Command, args []string) {
 	// load config and re-configure logging after reading config file
 	var err error
-	if cfgErr := loadConfigFile(&conf); errors.As(cfgErr, &viper.ConfigFileNotFoundError{}) {
+	if scenario := cmd.Flag(flagDemo).Value.String(); scenario != "" {
+		log.INFO.Printf("switching into demo mode: %s", scenario)
+		if err := demoConfig(&conf, scenario); err != nil {
+			log.FATAL.Fatal(err)
+		}
+	} else if cfgErr := loadConfigFile(&conf); errors.As(cfgErr, &viper.ConfigFileNotFoundError{}) {
 		log.INFO.Println("missing config file - switching into demo mode")
-		if err := demoConfig(&conf); err != nil {
+		if err := demoConfig(&conf, ""); err != nil {
 			log.FATAL.Fatal(err)
 		}
 	} else {
 		err = cfgErr
 	}
 
+	// validate config before creating any device
+	if err == nil {
+		err = validateConfig(conf)
+	}
+
+	// dry run
+	if check, _ := cmd.Flags().GetBool(flagCheck); check {
+		if err == nil && cfgFile == "" && cmd.Flag(flagDemo).Value.String() == "" {
+			err = errors.New("missing config file")
+		}
+
+		if err != nil {
+			log.FATAL.Fatal(wrapErrors(err))
+		}
+
+		log.INFO.Println("config ok")
+		os.Exit(0)
+	}
+
 	// network config
 	if viper.GetString("uri") != "" {
 		log.WARN.Println("`uri` is deprecated and will be ignored. Use `network` instead.")
@@ -255,7 +283,7 @@ func runRoot(cmd *cobra.Command, args []string) {
 
 	// show main ui
 	if err == nil {
-		httpd.RegisterSiteHandlers(site, cache)
+		httpd.RegisterSiteHandlers(site, cache, conf.Diagnostics.Token)
 		httpd.RegisterShutdownHandler(func() {
 			log.FATAL.Println("evcc was stopped by user. OS should restart the service. Or restart manually.")
 			once.Do(func() { close(stopC) }) // signal loop to end
@@ -284,6 +312,36 @@ func runRoot(cmd *cobra.Command, args []string) {
 		// allow web access for vehicles
 		configureAuth(conf.Network, config.Instances(config.Vehicles().Devices()), httpd.Router(), valueChan)
 
+		// reload config on request or SIGHUP
+		var reloadMu sync.Mutex
+		reload := func() (any, error) {
+			reloadMu.Lock()
+			defer reloadMu.Unlock()
+			return reloadConfig(site)
+		}
+
+		httpd.RegisterReloadHandler(conf.Diagnostics.Token, reload)
+		httpd.RegisterEffectiveConfigHandler(func() any {
+			reloadMu.Lock()
+			defer reloadMu.Unlock()
+			return effectiveConfig(conf)
+		})
+
+		if conf.Diagnostics.Token != "" {
+			httpd.RegisterDiagnosticsHandler(conf.Diagnostics.Token)
+		}
+
+		go func() {
+			hupC := make(chan os.Signal, 1)
+			signal.Notify(hupC, syscall.SIGHUP)
+
+			for range hupC {
+				if _, err := reload(); err != nil {
+					log.ERROR.Printf("config reload: %v", err)
+				}
+			}
+		}()
+
 		go func() {
 			site.Run(stopC, conf.Interval)
 		}()
@@ -19,9 +19,11 @@ import (
 	"github.com/evcc-io/evcc/charger/eebus"
 	"github.com/evcc-io/evcc/cmd/shutdown"
 	"github.com/evcc-io/evcc/core"
+	"github.com/evcc-io/evcc/core/session"
 	"github.com/evcc-io/evcc/core/site"
 	"github.com/evcc-io/evcc/hems"
 	"github.com/evcc-io/evcc/meter"
+	"github.com/evcc-io/evcc/provider"
 	"github.com/evcc-io/evcc/provider/golang"
 	"github.com/evcc-io/evcc/provider/javascript"
 	"github.com/evcc-io/evcc/provider/mqtt"
@@ -40,6 +42,7 @@ import (
 	"github.com/evcc-io/evcc/util/request"
 	"github.com/evcc-io/evcc/util/sponsor"
 	"github.com/evcc-io/evcc/util/templates"
+	"github.com/evcc-io/evcc/util/transport"
 	"github.com/evcc-io/evcc/vehicle"
 	"github.com/evcc-io/evcc/vehicle/wrapper"
 	"github.com/gorilla/handlers"
@@ -51,32 +54,40 @@ import (
 	"golang.org/x/text/currency"
 )
 
-var conf = globalConfig{
-	Interval: 10 * time.Second,
-	Log:      "info",
-	Network: networkConfig{
-		Schema: "http",
-		Host:   "evcc.local",
-		Port:   7070,
-	},
-	Mqtt: mqttConfig{
-		Topic: "evcc",
-	},
-	Database: dbConfig{
-		Type: "sqlite",
-		Dsn:  "~/.evcc/evcc.db",
-	},
+var conf = defaultConfig()
+
+// defaultConfig returns the configuration defaults applied before reading the config file
+func defaultConfig() globalConfig {
+	return globalConfig{
+		Interval: 10 * time.Second,
+		Log:      "info",
+		Network: networkConfig{
+			Schema: "http",
+			Host:   "evcc.local",
+			Port:   7070,
+		},
+		Mqtt: mqttConfig{
+			Topic: "evcc",
+		},
+		Database: dbConfig{
+			Type: "sqlite",
+			Dsn:  "~/.evcc/evcc.db",
+		},
+	}
 }
 
 type globalConfig struct {
 	URI          interface{} // TODO deprecated
 	Network      networkConfig
+	HTTP         httpConfig
+	Poll         pollConfig
 	Log          string
 	SponsorToken string
 	Plant        string // telemetry plant id
 	Telemetry    bool
 	Metrics      bool
 	Profile      bool
+	Diagnostics  diagnosticsConfig
 	Levels       map[string]string
 	Interval     time.Duration
 	Database     dbConfig
@@ -118,8 +129,9 @@ type proxyConfig struct {
 }
 
 type dbConfig struct {
-	Type string
-	Dsn  string
+	Type      string
+	Dsn       string
+	Retention time.Duration // prune finished sessions older than this, keep all if zero
 }
 
 type messagingConfig struct {
@@ -135,6 +147,22 @@ type tariffConfig struct {
 	Planner  config.Typed
 }
 
+type httpConfig struct {
+	Timeout               time.Duration
+	DialTimeout           time.Duration
+	ResponseHeaderTimeout time.Duration
+	KeepAlive             time.Duration
+}
+
+type diagnosticsConfig struct {
+	Token string // bearer token required for the diagnostic endpoints, disabled if empty
+}
+
+type pollConfig struct {
+	Jitter float64 // fraction of the cache duration added as random offset per provider
+	Seed   int64   // makes the offsets reproducible
+}
+
 type networkConfig struct {
 	Schema string
 	Host   string
@@ -180,14 +208,23 @@ func configureMeters(static []config.Named) error {
 		if cc.Name == "" {
 			return fmt.Errorf("cannot create meter %d: missing name", i+1)
 		}
+	}
 
-		instance, err := meter.NewFromConfig(cc.Type, cc.Other)
-		if err != nil {
-			return fmt.Errorf("cannot create meter '%s': %w", cc.Name, err)
-		}
+	stages, err := initOrder("meter", static, nil)
+	if err != nil {
+		return err
+	}
 
-		if err := config.Meters().Add(config.NewStaticDevice(cc, instance)); err != nil {
-			return err
+	for _, stage := range stages {
+		for _, cc := range stage {
+			instance, err := meter.NewFromConfig(cc.Type, cc.Other)
+			if err != nil {
+				return fmt.Errorf("cannot create meter '%s': %w", cc.Name, err)
+			}
+
+			if err := config.Meters().Add(config.NewStaticDevice(cc, instance)); err != nil {
+				return err
+			}
 		}
 	}
 
@@ -214,24 +251,40 @@ func configureMeters(static []config.Named) error {
 }
 
 func configureChargers(static []config.Named) error {
-	g, _ := errgroup.WithContext(context.Background())
-
 	for i, cc := range static {
 		if cc.Name == "" {
 			return fmt.Errorf("cannot create charger %d: missing name", i+1)
 		}
+	}
 
-		cc := cc
-		g.Go(func() error {
-			instance, err := charger.NewFromConfig(cc.Type, cc.Other)
-			if err != nil {
-				return fmt.Errorf("cannot create charger '%s': %w", cc.Name, err)
-			}
+	stages, err := initOrder("charger", static, meterAvailable)
+	if err != nil {
+		return err
+	}
 
-			return config.Chargers().Add(config.NewStaticDevice(cc, instance))
-		})
+	// stages are initialized in order, devices of a stage concurrently
+	for _, stage := range stages {
+		var g errgroup.Group
+
+		for _, cc := range stage {
+			cc := cc
+			g.Go(func() error {
+				instance, err := charger.NewFromConfig(cc.Type, cc.Other)
+				if err != nil {
+					return fmt.Errorf("cannot create charger '%s': %w", cc.Name, err)
+				}
+
+				return config.Chargers().Add(config.NewStaticDevice(cc, instance))
+			})
+		}
+
+		if err := g.Wait(); err != nil {
+			return err
+		}
 	}
 
+	g, _ := errgroup.WithContext(context.Background())
+
 	// append devices from database
 	configurable, err := config.ConfigurationsByClass(templates.Charger)
 	if err != nil {
@@ -254,6 +307,18 @@ func configureChargers(static []config.Named) error {
 	return g.Wait()
 }
 
+// meterAvailable returns true if the meter has been initialized
+func meterAvailable(name string) bool {
+	_, err := config.Meters().ByName(name)
+	return err == nil
+}
+
+// chargerAvailable returns true if the charger has been initialized
+func chargerAvailable(name string) bool {
+	_, err := config.Chargers().ByName(name)
+	return err == nil
+}
+
 func vehicleInstance(cc config.Named) (api.Vehicle, error) {
 	instance, err := vehicle.NewFromConfig(cc.Type, cc.Other)
 	if err != nil {
@@ -278,7 +343,6 @@ func vehicleInstance(cc config.Named) (api.Vehicle, error) {
 
 func configureVehicles(static []config.Named) error {
 	var mu sync.Mutex
-	g, _ := errgroup.WithContext(context.Background())
 
 	// stable-sort vehicles by name
 	devs1 := make([]config.Device[api.Vehicle], 0, len(static))
@@ -287,22 +351,42 @@ func configureVehicles(static []config.Named) error {
 		if cc.Name == "" {
 			return fmt.Errorf("cannot create vehicle %d: missing name", i+1)
 		}
+	}
 
-		cc := cc
-		g.Go(func() error {
-			instance, err := vehicleInstance(cc)
-			if err != nil {
-				return fmt.Errorf("cannot create vehicle '%s': %w", cc.Name, err)
-			}
+	stages, err := initOrder("vehicle", static, func(name string) bool {
+		return meterAvailable(name) || chargerAvailable(name)
+	})
+	if err != nil {
+		return err
+	}
 
-			mu.Lock()
-			defer mu.Unlock()
-			devs1 = append(devs1, config.NewStaticDevice(cc, instance))
+	// stages are initialized in order, devices of a stage concurrently
+	for _, stage := range stages {
+		var g errgroup.Group
 
-			return nil
-		})
+		for _, cc := range stage {
+			cc := cc
+			g.Go(func() error {
+				instance, err := vehicleInstance(cc)
+				if err != nil {
+					return fmt.Errorf("cannot create vehicle '%s': %w", cc.Name, err)
+				}
+
+				mu.Lock()
+				defer mu.Unlock()
+				devs1 = append(devs1, config.NewStaticDevice(cc, instance))
+
+				return nil
+			})
+		}
+
+		if err := g.Wait(); err != nil {
+			return err
+		}
 	}
 
+	g, _ := errgroup.WithContext(context.Background())
+
 	// append devices from database
 	configurable, err := config.ConfigurationsByClass(templates.Vehicle)
 	if err != nil {
@@ -362,8 +446,16 @@ func configureEnvironment(cmd *cobra.Command, conf globalConfig) (err error) {
 		request.LogHeaders = true
 	}
 
+	// setup http client timeouts before any client is created
+	err = configureHTTP(conf.HTTP)
+
+	// setup poll jitter before any provider is created
+	if err == nil {
+		err = provider.SetJitter(conf.Poll.Jitter, conf.Poll.Seed)
+	}
+
 	// setup machine id
-	if conf.Plant != "" {
+	if err == nil && conf.Plant != "" {
 		err = machine.CustomID(conf.Plant)
 	}
 
@@ -410,8 +502,41 @@ func configureEnvironment(cmd *cobra.Command, conf globalConfig) (err error) {
 	return
 }
 
+// configureHTTP configures the timeouts of the shared http client and transport
+func configureHTTP(conf httpConfig) error {
+	for key, d := range map[string]time.Duration{
+		"timeout":               conf.Timeout,
+		"dialTimeout":           conf.DialTimeout,
+		"responseHeaderTimeout": conf.ResponseHeaderTimeout,
+		"keepAlive":             conf.KeepAlive,
+	} {
+		if d < 0 {
+			return fmt.Errorf("http: invalid %s: %v", key, d)
+		}
+	}
+
+	if conf.Timeout > 0 {
+		request.Timeout = conf.Timeout
+	}
+	if conf.DialTimeout > 0 {
+		transport.DialTimeout = conf.DialTimeout
+	}
+	if conf.ResponseHeaderTimeout > 0 {
+		transport.ResponseHeaderTimeout = conf.ResponseHeaderTimeout
+	}
+	if conf.KeepAlive > 0 {
+		transport.KeepAlive = conf.KeepAlive
+	}
+
+	return nil
+}
+
 // configureDatabase configures session database
 func configureDatabase(conf dbConfig) error {
+	if conf.Retention < 0 {
+		return errors.New("database retention must not be negative")
+	}
+
 	if err := db.NewInstance(conf.Type, conf.Dsn); err != nil {
 		return err
 	}
@@ -436,19 +561,25 @@ func configureDatabase(conf dbConfig) error {
 		}
 	}()
 
+	// prune history at startup and daily
+	if conf.Retention > 0 {
+		go func() {
+			for tick := time.Tick(24 * time.Hour); ; <-tick {
+				if count, err := session.Prune(db.Instance, time.Now().Add(-conf.Retention)); err != nil {
+					log.ERROR.Println("prune sessions:", err)
+				} else if count > 0 {
+					log.INFO.Printf("pruned %d sessions older than %v", count, conf.Retention)
+				}
+			}
+		}()
+	}
+
 	return nil
 }
 
 // configureInflux configures influx database
 func configureInflux(conf server.InfluxConfig, site site.API, in <-chan util.Param) {
-	influx := server.NewInfluxClient(
-		conf.URL,
-		conf.Token,
-		conf.Org,
-		conf.User,
-		conf.Password,
-		conf.Database,
-	)
+	influx := server.NewInfluxClient(conf)
 
 	// eliminate duplicate values
 	dedupe := pipe.NewDeduplicator(30*time.Minute, "vehicleCapacity", "vehicleSoc", "vehicleRange", "vehicleOdometer", "chargedEnergy", "chargeRemainingEnergy")
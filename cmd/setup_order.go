@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/evcc-io/evcc/util/config"
+	"github.com/mitchellh/mapstructure"
+)
+
+// dependsOnKey is the device setting declaring devices that must be initialized first
+const dependsOnKey = "dependsOn"
+
+// dependsOn returns the device with the dependencies removed from its settings and the declared dependencies
+func dependsOn(cc config.Named) (config.Named, []string, error) {
+	var res []string
+
+	other := make(map[string]interface{}, len(cc.Other))
+	for k, v := range cc.Other {
+		if !strings.EqualFold(k, dependsOnKey) {
+			other[k] = v
+			continue
+		}
+
+		if err := mapstructure.WeakDecode(v, &res); err != nil {
+			return cc, nil, fmt.Errorf("invalid %s: %w", dependsOnKey, err)
+		}
+	}
+
+	cc.Other = other
+
+	return cc, res, nil
+}
+
+// initOrder groups the devices into stages such that devices only depend on devices of previous stages.
+// Devices of the same stage may be initialized concurrently. Dependencies not contained in the devices must be available,
+// e.g. devices of classes initialized before. Dependency cycles are reported as error.
+func initOrder(class string, devices []config.Named, available func(string) bool) ([][]config.Named, error) {
+	deps := make(map[string][]string, len(devices))
+	pending := make([]config.Named, 0, len(devices))
+
+	for _, dev := range devices {
+		cc, names, err := dependsOn(dev)
+		if err != nil {
+			return nil, fmt.Errorf("%s '%s': %w", class, dev.Name, err)
+		}
+
+		deps[cc.Name] = names
+		pending = append(pending, cc)
+	}
+
+	for _, dev := range pending {
+		for _, dep := range deps[dev.Name] {
+			if _, ok := deps[dep]; !ok && (available == nil || !available(dep)) {
+				return nil, fmt.Errorf("%s '%s': unknown dependency '%s'", class, dev.Name, dep)
+			}
+		}
+	}
+
+	var res [][]config.Named
+	done := make(map[string]bool, len(pending))
+
+	for len(pending) > 0 {
+		var stage, remaining []config.Named
+
+		for _, dev := range pending {
+			if slices.ContainsFunc(deps[dev.Name], func(dep string) bool {
+				_, configured := deps[dep]
+				return configured && !done[dep]
+			}) {
+				remaining = append(remaining, dev)
+			} else {
+				stage = append(stage, dev)
+			}
+		}
+
+		if len(stage) == 0 {
+			return nil, fmt.Errorf("%s dependency cycle: %s", class, strings.Join(dependencyCycle(remaining[0].Name, deps, done), " -> "))
+		}
+
+		for _, dev := range stage {
+			done[dev.Name] = true
+		}
+
+		res = append(res, stage)
+		pending = remaining
+	}
+
+	return res, nil
+}
+
+// dependencyCycle returns the cycle reached from the unresolved device
+func dependencyCycle(name string, deps map[string][]string, done map[string]bool) []string {
+	var path []string
+
+	for !slices.Contains(path, name) {
+		path = append(path, name)
+
+		// follow the first unresolved dependency, each unresolved device has one
+		for _, dep := range deps[name] {
+			if _, ok := deps[dep]; ok && !done[dep] {
+				name = dep
+				break
+			}
+		}
+	}
+
+	return append(path[slices.Index(path, name):], name)
+}
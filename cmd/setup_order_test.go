@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/evcc-io/evcc/util/config"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func dependentDevice(name string, deps ...any) config.Named {
+	cc := config.Named{Name: name, Type: "template", Other: map[string]interface{}{"template": "demo"}}
+	if len(deps) == 1 {
+		cc.Other[dependsOnKey] = deps[0]
+	} else if len(deps) > 1 {
+		cc.Other[dependsOnKey] = deps
+	}
+	return cc
+}
+
+func stageNames(stages [][]config.Named) [][]string {
+	return lo.Map(stages, func(stage []config.Named, _ int) []string {
+		return lo.Map(stage, func(cc config.Named, _ int) string { return cc.Name })
+	})
+}
+
+func TestInitOrder(t *testing.T) {
+	devices := []config.Named{
+		dependentDevice("wallbox", "gateway"),
+		dependentDevice("gateway"),
+		dependentDevice("carport", "wallbox", "gateway"),
+		dependentDevice("garage", "grid"), // initialized before
+	}
+
+	stages, err := initOrder("charger", devices, func(name string) bool { return name == "grid" })
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{{"gateway", "garage"}, {"wallbox"}, {"carport"}}, stageNames(stages))
+
+	// dependencies are removed from the device settings
+	for _, stage := range stages {
+		for _, cc := range stage {
+			assert.Equal(t, map[string]interface{}{"template": "demo"}, cc.Other, cc.Name)
+		}
+	}
+
+	// configuration is not modified
+	assert.Equal(t, "gateway", devices[0].Other[dependsOnKey])
+
+	// no dependencies
+	stages, err = initOrder("meter", []config.Named{dependentDevice("grid"), dependentDevice("pv")}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{{"grid", "pv"}}, stageNames(stages))
+}
+
+func TestInitOrderErrors(t *testing.T) {
+	// unknown dependency
+	_, err := initOrder("charger", []config.Named{dependentDevice("wallbox", "gateway")}, func(string) bool { return false })
+	assert.EqualError(t, err, "charger 'wallbox': unknown dependency 'gateway'")
+
+	// cycle
+	_, err = initOrder("meter", []config.Named{
+		dependentDevice("grid"),
+		dependentDevice("pv", "grid", "battery"), // depends on the cycle
+		dependentDevice("battery", "inverter"),
+		dependentDevice("inverter", "grid", "battery"),
+	}, nil)
+	assert.EqualError(t, err, "meter dependency cycle: battery -> inverter -> battery")
+
+	// self dependency
+	_, err = initOrder("meter", []config.Named{dependentDevice("grid", "grid")}, nil)
+	assert.EqualError(t, err, "meter dependency cycle: grid -> grid")
+
+	// invalid dependency
+	_, err = initOrder("meter", []config.Named{dependentDevice("grid", map[string]interface{}{"foo": "bar"})}, nil)
+	assert.ErrorContains(t, err, "meter 'grid': invalid dependsOn")
+}
@@ -0,0 +1,189 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core"
+	"github.com/evcc-io/evcc/util"
+	"github.com/evcc-io/evcc/util/config"
+)
+
+// requiredSources are the mandatory provider configurations of custom devices
+var requiredSources = map[string][]string{
+	"meter":   {"power"},
+	"charger": {"status", "enabled", "enable", "maxcurrent"},
+	"vehicle": {"soc"},
+}
+
+// validator collects configuration errors
+type validator struct {
+	errs []error
+}
+
+func (v *validator) errorf(format string, a ...any) {
+	v.errs = append(v.errs, fmt.Errorf(format, a...))
+}
+
+// lookup returns the value of the case-insensitive key
+func lookup(other map[string]interface{}, key string) (interface{}, bool) {
+	for k, v := range other {
+		if strings.EqualFold(k, key) {
+			return v, true
+		}
+	}
+	return nil, false
+}
+
+// devices validates device names, types and required settings and returns the device names
+func (v *validator) devices(class string, devices []config.Named) []string {
+	var names []string
+
+	for i, dev := range devices {
+		if dev.Name == "" {
+			v.errorf("%s %d: missing 'name'", class, i+1)
+			continue
+		}
+
+		if slices.Contains(names, dev.Name) {
+			v.errorf("%s '%s': duplicate name", class, dev.Name)
+		}
+		names = append(names, dev.Name)
+
+		switch strings.ToLower(dev.Type) {
+		case "":
+			v.errorf("%s '%s': missing 'type'", class, dev.Name)
+
+		case "template":
+			if _, ok := lookup(dev.Other, "template"); !ok {
+				v.errorf("%s '%s': missing 'template'", class, dev.Name)
+			}
+
+		case api.Custom:
+			for _, key := range requiredSources[class] {
+				v.source(class, dev.Name, dev.Other, key)
+			}
+		}
+	}
+
+	return names
+}
+
+// dependencies validates that the init dependencies are configured and free of cycles
+func (v *validator) dependencies(class string, devices []config.Named, available []string) {
+	if _, err := initOrder(class, devices, func(name string) bool {
+		return slices.Contains(available, name)
+	}); err != nil {
+		v.errs = append(v.errs, err)
+	}
+}
+
+// source validates a required provider configuration
+func (v *validator) source(class, name string, other map[string]interface{}, key string) {
+	val, ok := lookup(other, key)
+	if !ok || val == nil {
+		v.errorf("%s '%s': missing '%s' source", class, name, key)
+		return
+	}
+
+	cc, ok := val.(map[string]interface{})
+	if !ok {
+		v.errorf("%s '%s': invalid '%s' source", class, name, key)
+		return
+	}
+
+	if src, ok := lookup(cc, "source"); !ok || src == "" {
+		v.errorf("%s '%s': missing 'source' for '%s'", class, name, key)
+	}
+}
+
+// reference validates that the referenced device is configured. Database devices are not known upfront.
+func (v *validator) reference(owner, class, ref string, names []string) {
+	if ref == "" || strings.HasPrefix(ref, "db:") || slices.Contains(names, ref) {
+		return
+	}
+
+	v.errorf("%s: %s '%s' not configured", owner, class, ref)
+}
+
+// validateConfig checks the configuration for missing or inconsistent settings before any device is created
+func validateConfig(conf globalConfig) error {
+	var v validator
+
+	meters := v.devices("meter", conf.Meters)
+	chargers := v.devices("charger", conf.Chargers)
+	vehicles := v.devices("vehicle", conf.Vehicles)
+
+	v.dependencies("meter", conf.Meters, nil)
+	v.dependencies("charger", conf.Chargers, meters)
+	v.dependencies("vehicle", conf.Vehicles, append(slices.Clone(meters), chargers...))
+
+	var loadGroups []string
+
+	if conf.Site != nil {
+		site := core.NewSite()
+		if err := util.DecodeOther(conf.Site, site); err != nil {
+			v.errorf("site: %w", err)
+		} else {
+			v.reference("site", "grid meter", site.Meters.GridMeterRef, meters)
+			for _, ref := range append(site.Meters.PVMetersRef, site.Meters.PVMetersRef_...) {
+				v.reference("site", "pv meter", ref, meters)
+			}
+			for _, ref := range append(site.Meters.BatteryMetersRef, site.Meters.BatteryMetersRef_...) {
+				v.reference("site", "battery meter", ref, meters)
+			}
+			for _, ref := range site.Meters.AuxMetersRef {
+				v.reference("site", "aux meter", ref, meters)
+			}
+			if err := site.PeakWindows.Validate(); err != nil {
+				v.errorf("site: peak windows: %w", err)
+			}
+			for _, lg := range site.LoadGroups {
+				loadGroups = append(loadGroups, lg.Name)
+			}
+		}
+	}
+
+	for i, other := range conf.Loadpoints {
+		var lp core.Loadpoint
+		if err := util.DecodeOther(other, &lp); err != nil {
+			v.errorf("loadpoint %d: %w", i+1, err)
+			continue
+		}
+
+		owner := fmt.Sprintf("loadpoint %d", i+1)
+		if lp.Title_ != "" {
+			owner = fmt.Sprintf("loadpoint '%s'", lp.Title_)
+		}
+
+		if lp.ChargerRef == "" {
+			v.errorf("%s: missing 'charger'", owner)
+		}
+
+		v.reference(owner, "charger", lp.ChargerRef, chargers)
+		v.reference(owner, "meter", lp.MeterRef, meters)
+		v.reference(owner, "vehicle", lp.VehicleRef, vehicles)
+		v.reference(owner, "load group", lp.LoadGroupRef, loadGroups)
+
+		if lp.MinCurrent > 0 && lp.MaxCurrent > 0 && lp.MinCurrent > lp.MaxCurrent {
+			v.errorf("%s: 'minCurrent' (%gA) must not exceed 'maxCurrent' (%gA)", owner, lp.MinCurrent, lp.MaxCurrent)
+		}
+
+		if err := lp.Rules.Validate(); err != nil {
+			v.errorf("%s: %w", owner, err)
+		}
+
+		if err := lp.PeakWindows.Validate(); err != nil {
+			v.errorf("%s: peak windows: %w", owner, err)
+		}
+
+		if !slices.Contains([]int{0, 1, 3}, lp.ConfiguredPhases) {
+			v.errorf("%s: invalid 'phases' %d, must be 1 or 3 (0 for automatic)", owner, lp.ConfiguredPhases)
+		}
+	}
+
+	return errors.Join(v.errs...)
+}
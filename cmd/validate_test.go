@@ -0,0 +1,181 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func readTestConfig(t *testing.T, yaml string) globalConfig {
+	t.Helper()
+
+	var conf globalConfig
+	viper.Reset()
+	viper.SetConfigType("yaml")
+	require.NoError(t, viper.ReadConfig(strings.NewReader(yaml)))
+	require.NoError(t, viper.UnmarshalExact(&conf))
+
+	return conf
+}
+
+func TestValidateConfig(t *testing.T) {
+	conf := readTestConfig(t, `
+meters:
+- name: grid
+  type: template
+  template: shelly-3em
+- name: pv
+  type: custom
+  power:
+    source: const
+    value: 1000
+chargers:
+- name: garage
+  type: custom
+  status:
+    source: const
+    value: C
+  enabled:
+    source: const
+    value: true
+  enable:
+    source: js
+  maxcurrent:
+    source: js
+site:
+  meters:
+    grid: grid
+    pv: [pv]
+loadpoints:
+- title: Garage
+  charger: garage
+  meter: db:1
+  minCurrent: 6
+  maxCurrent: 16
+`)
+
+	assert.NoError(t, validateConfig(conf))
+}
+
+func TestValidateConfigErrors(t *testing.T) {
+	tc := []struct {
+		yaml string
+		msgs []string
+	}{
+		{`
+chargers:
+- name: garage
+  type: custom
+  enabled:
+    source: const
+  enable:
+    value: true
+`, []string{
+			"charger 'garage': missing 'status' source",
+			"charger 'garage': missing 'source' for 'enable'",
+			"charger 'garage': missing 'maxcurrent' source",
+		}},
+		{`
+meters:
+- name: grid
+- type: template
+- name: grid
+  type: template
+`, []string{
+			"meter 'grid': missing 'type'",
+			"meter 2: missing 'name'",
+			"meter 'grid': duplicate name",
+			"meter 'grid': missing 'template'",
+		}},
+		{`
+vehicles:
+- name: car
+  type: custom
+  soc: 50
+`, []string{
+			"vehicle 'car': invalid 'soc' source",
+		}},
+		{`
+site:
+  meters:
+    grid: grid
+    pv: [pv1, pv2]
+`, []string{
+			"site: grid meter 'grid' not configured",
+			"site: pv meter 'pv1' not configured",
+			"site: pv meter 'pv2' not configured",
+		}},
+		{`
+loadpoints:
+- title: Garage
+  vehicle: car
+  minCurrent: 16
+  maxCurrent: 6
+  phases: 2
+- charger: wallbox
+`, []string{
+			"loadpoint 'Garage': missing 'charger'",
+			"loadpoint 'Garage': vehicle 'car' not configured",
+			"loadpoint 'Garage': 'minCurrent' (16A) must not exceed 'maxCurrent' (6A)",
+			"loadpoint 'Garage': invalid 'phases' 2",
+			"loadpoint 2: charger 'wallbox' not configured",
+		}},
+		{`
+site:
+  loadGroups:
+  - name: garage
+    maxCurrent: 32
+loadpoints:
+- title: Garage
+  charger: wallbox
+  loadGroup: carport
+`, []string{
+			"loadpoint 'Garage': load group 'carport' not configured",
+		}},
+		{`
+meters:
+- name: gateway
+  type: template
+  template: demo
+  dependsOn: grid
+- name: grid
+  type: template
+  template: demo
+  dependsOn: gateway
+chargers:
+- name: wallbox
+  type: template
+  template: demo
+  dependsOn: [gateway, modbus]
+`, []string{
+			"meter dependency cycle: gateway -> grid -> gateway",
+			"charger 'wallbox': unknown dependency 'modbus'",
+		}},
+		{`
+loadpoints:
+- title: Garage
+  foo: bar
+`, []string{
+			"loadpoint 1:",
+			"foo",
+		}},
+	}
+
+	for _, tc := range tc {
+		err := validateConfig(readTestConfig(t, tc.yaml))
+		require.Error(t, err, tc.yaml)
+
+		for _, msg := range tc.msgs {
+			assert.Contains(t, err.Error(), msg, tc.yaml)
+		}
+	}
+}
+
+func TestValidateDemoConfig(t *testing.T) {
+	var conf globalConfig
+	require.NoError(t, demoConfig(&conf, ""))
+	assert.NoError(t, validateConfig(conf))
+}
@@ -12,6 +12,7 @@ const (
 	vehicleCapacity        = "vehicleCapacity"        // vehicle battery capacity
 	vehicleDetectionActive = "vehicleDetectionActive" // vehicle detection active
 	vehicleIcon            = "vehicleIcon"            // vehicle icon for ui
+	vehicleManual          = "vehicleManual"          // vehicle assigned manually
 	vehicleOdometer        = "vehicleOdometer"        // vehicle odometer
 	vehiclePresent         = "vehiclePresent"         // vehicle detected
 	vehicleRange           = "vehicleRange"           // vehicle range
@@ -28,4 +29,5 @@ const (
 	targetTime              = "targetTime"              // target charging finish time goal
 	planActive              = "planActive"              // target charging plan has determined current slot to be an active slot
 	planProjectedStart      = "planProjectedStart"      // target charging plan start time (earliest slot)
+	boostRemaining          = "boostRemaining"          // remaining boost duration
 )
@@ -1,8 +1,11 @@
 package core
 
 import (
+	"slices"
 	"sync"
 	"time"
+
+	"golang.org/x/exp/maps"
 )
 
 // Health is a health checker that needs regular updates to stay healthy
@@ -10,6 +13,13 @@ type Health struct {
 	mux     sync.Mutex
 	updated time.Time
 	timeout time.Duration
+	login   map[string]error         // devices requiring login
+	devices map[string]*deviceHealth // devices tracked by error budget
+}
+
+// deviceHealth counts a device's consecutive failures against its error budget
+type deviceHealth struct {
+	budget, failures int
 }
 
 // NewHealth creates new health checker
@@ -40,3 +50,93 @@ func (health *Health) Update() {
 
 	health.updated = time.Now()
 }
+
+// SetLoginRequired marks the device as requiring login, nil errors clear the mark
+func (health *Health) SetLoginRequired(name string, err error) {
+	if health == nil {
+		return
+	}
+
+	health.mux.Lock()
+	defer health.mux.Unlock()
+
+	if err == nil {
+		delete(health.login, name)
+		return
+	}
+
+	if health.login == nil {
+		health.login = make(map[string]error)
+	}
+	health.login[name] = err
+}
+
+// LoginRequired returns the sorted names of devices requiring login
+func (health *Health) LoginRequired() []string {
+	if health == nil {
+		return nil
+	}
+
+	health.mux.Lock()
+	defer health.mux.Unlock()
+
+	res := maps.Keys(health.login)
+	slices.Sort(res)
+
+	return res
+}
+
+// Track records the device's latest read result and returns false while its error budget of consecutive failures is exhausted.
+// Successful reads reset the failures and recover the device. Zero budgets are never exhausted.
+func (health *Health) Track(name string, budget int, err error) bool {
+	if health == nil {
+		return true
+	}
+
+	health.mux.Lock()
+	defer health.mux.Unlock()
+
+	if health.devices == nil {
+		health.devices = make(map[string]*deviceHealth)
+	}
+
+	dev, ok := health.devices[name]
+	if !ok {
+		dev = new(deviceHealth)
+		health.devices[name] = dev
+	}
+
+	dev.budget = budget
+	if err == nil {
+		dev.failures = 0
+	} else {
+		dev.failures++
+	}
+
+	return !dev.exhausted()
+}
+
+// exhausted returns true if the device failed more often than its budget permits
+func (dev *deviceHealth) exhausted() bool {
+	return dev.budget > 0 && dev.failures > dev.budget
+}
+
+// Unhealthy returns the sorted names of devices that exhausted their error budget
+func (health *Health) Unhealthy() []string {
+	if health == nil {
+		return nil
+	}
+
+	health.mux.Lock()
+	defer health.mux.Unlock()
+
+	var res []string
+	for name, dev := range health.devices {
+		if dev.exhausted() {
+			res = append(res, name)
+		}
+	}
+	slices.Sort(res)
+
+	return res
+}
@@ -37,3 +37,11 @@ func sitePower(log *util.Logger, maxGrid, grid, battery, residual float64) float
 
 	return grid + battery + residual
 }
+
+// errorString returns the error message or empty string
+func errorString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
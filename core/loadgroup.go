@@ -0,0 +1,198 @@
+package core
+
+import (
+	"fmt"
+	"math"
+	"slices"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/util"
+)
+
+// LoadGroup is a set of loadpoints sharing a current or power budget independent of the grid connection, e.g. a sub-panel
+type LoadGroup struct {
+	Name       string  `mapstructure:"name"`
+	MaxCurrent float64 `mapstructure:"maxCurrent"` // shared current budget per phase (A)
+	MaxPower   float64 `mapstructure:"maxPower"`   // shared power budget (W)
+
+	log        *util.Logger
+	loadpoints []*Loadpoint
+}
+
+// loadGroupMember is the load group's view of a loadpoint
+type loadGroupMember struct {
+	lp                 *Loadpoint
+	min, max, priority float64
+	phases             float64
+}
+
+// power returns the member's power at given current
+func (m loadGroupMember) power(current float64) float64 {
+	return current * m.phases * Voltage
+}
+
+// setupLoadGroups assigns the loadpoints to their load groups
+func setupLoadGroups(groups []LoadGroup, loadpoints []*Loadpoint) ([]*LoadGroup, error) {
+	var res []*LoadGroup
+
+	for i := range groups {
+		lg := &groups[i]
+		if lg.Name == "" {
+			return nil, fmt.Errorf("load group %d: missing name", i+1)
+		}
+		if lg.MaxCurrent <= 0 && lg.MaxPower <= 0 {
+			return nil, fmt.Errorf("load group %s: missing maxCurrent or maxPower", lg.Name)
+		}
+
+		lg.log = util.NewLogger("loadgroup-" + lg.Name)
+		res = append(res, lg)
+	}
+
+	for _, lp := range loadpoints {
+		if lp.LoadGroupRef == "" {
+			continue
+		}
+
+		idx := slices.IndexFunc(res, func(lg *LoadGroup) bool {
+			return lg.Name == lp.LoadGroupRef
+		})
+		if idx < 0 {
+			return nil, fmt.Errorf("loadpoint %s: load group not found: %s", lp.Title(), lp.LoadGroupRef)
+		}
+
+		res[idx].loadpoints = append(res[idx].loadpoints, lp)
+	}
+
+	return res, nil
+}
+
+// loadpoint returns the load group's loadpoint or nil if the loadpoint doesn't belong to the load group
+func (lg *LoadGroup) loadpoint(lp Updater) *Loadpoint {
+	for _, m := range lg.loadpoints {
+		if Updater(m) == lp {
+			return m
+		}
+	}
+	return nil
+}
+
+// member returns the load group's view of the loadpoint
+func (lg *LoadGroup) member(lp *Loadpoint) loadGroupMember {
+	return loadGroupMember{
+		lp:       lp,
+		min:      lp.effectiveMinCurrent(),
+		max:      lp.effectiveMaxCurrent(),
+		priority: float64(lp.GetPriority()),
+		phases:   float64(max(lp.activePhases(), 1)),
+	}
+}
+
+// participates returns true if the loadpoint competes for the group's budget
+func (lg *LoadGroup) participates(lp *Loadpoint) bool {
+	status := lp.GetStatus()
+	return (status == api.StatusB || status == api.StatusC) && lp.GetMode() != api.ModeOff
+}
+
+// fits returns true if the members' currents at the given level don't exceed the budget
+func (lg *LoadGroup) fits(members []loadGroupMember, level float64) bool {
+	var current, power float64
+	for _, m := range members {
+		current += min(level, m.max)
+		power += m.power(min(level, m.max))
+	}
+
+	return (lg.MaxCurrent <= 0 || current <= lg.MaxCurrent+1e-9) && (lg.MaxPower <= 0 || power <= lg.MaxPower+1e-9)
+}
+
+// fairLevel returns the max common current level fitting the budget. Members below the level use their max current only.
+func (lg *LoadGroup) fairLevel(members []loadGroupMember) float64 {
+	var hi float64
+	for _, m := range members {
+		hi = max(hi, m.max)
+	}
+
+	if lg.fits(members, hi) {
+		return hi
+	}
+
+	lo := 0.0
+	for i := 0; i < 50; i++ {
+		mid := (lo + hi) / 2
+		if lg.fits(members, mid) {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+
+	return lo
+}
+
+// fairShare returns the loadpoint's fair share of the budget. Loadpoints not competing for the budget are not assigned a share.
+// If the budget doesn't allow all participants to charge at their min current, the lowest priority participants are excluded.
+func (lg *LoadGroup) fairShare(lp *Loadpoint) float64 {
+	if !lg.participates(lp) {
+		return 0
+	}
+
+	var members []loadGroupMember
+	for _, m := range lg.loadpoints {
+		if lg.participates(m) {
+			members = append(members, lg.member(m))
+		}
+	}
+
+	for len(members) > 0 {
+		level := lg.fairLevel(members)
+
+		// exclude lowest priority participant that cannot charge at min current
+		drop := -1
+		for i, m := range members {
+			if min(level, m.max) < m.min && (drop < 0 || m.priority <= members[drop].priority) {
+				drop = i
+			}
+		}
+
+		if drop < 0 {
+			idx := slices.IndexFunc(members, func(m loadGroupMember) bool { return m.lp == lp })
+			return min(level, members[idx].max)
+		}
+
+		if members[drop].lp == lp {
+			return 0
+		}
+
+		members = slices.Delete(members, drop, drop+1)
+	}
+
+	return 0
+}
+
+// currentLimit returns the loadpoint's current limit. It is bounded by the loadpoint's fair share
+// and by the budget committed to the other enabled loadpoints, even if not charging.
+func (lg *LoadGroup) currentLimit(lp *Loadpoint) float64 {
+	self := lg.member(lp)
+
+	remainingCurrent, remainingPower := lg.MaxCurrent, lg.MaxPower
+	for _, m := range lg.loadpoints {
+		if m == lp || !m.enabled {
+			continue
+		}
+
+		remainingCurrent -= m.chargeCurrent
+		remainingPower -= lg.member(m).power(m.chargeCurrent)
+	}
+
+	limit := lg.fairShare(lp)
+	if lg.MaxCurrent > 0 {
+		limit = min(limit, remainingCurrent)
+	}
+	if lg.MaxPower > 0 {
+		limit = min(limit, remainingPower/self.power(1))
+	}
+
+	limit = max(0, math.Floor(limit*1e3)/1e3)
+	lg.log.DEBUG.Printf("lp %s current limit: %.3gA", lp.Title(), limit)
+
+	return limit
+}
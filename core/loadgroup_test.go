@@ -0,0 +1,151 @@
+package core
+
+import (
+	"testing"
+
+	evbus "github.com/asaskevich/EventBus"
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/util"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newLoadGroupLoadpoint(t *testing.T, title string, phases int, maxCurrent float64) *Loadpoint {
+	ctrl := gomock.NewController(t)
+	charger := api.NewMockCharger(ctrl)
+	charger.EXPECT().MaxCurrent(gomock.Any()).Return(nil).AnyTimes()
+	charger.EXPECT().Enable(gomock.Any()).Return(nil).AnyTimes()
+
+	return &Loadpoint{
+		log:          util.NewLogger("foo"),
+		bus:          evbus.New(),
+		clock:        clock.NewMock(),
+		charger:      charger,
+		wakeUpTimer:  NewTimer(),
+		Title_:       title,
+		LoadGroupRef: "garage",
+		Mode:         api.ModeNow,
+		MinCurrent:   minA,
+		MaxCurrent:   maxCurrent,
+		phases:       phases,
+		status:       api.StatusC,
+	}
+}
+
+func TestLoadGroupBudget(t *testing.T) {
+	Voltage = 100
+
+	lp1 := newLoadGroupLoadpoint(t, "lp1", 3, 32)
+	lp2 := newLoadGroupLoadpoint(t, "lp2", 3, 32)
+
+	groups, err := setupLoadGroups([]LoadGroup{{Name: "garage", MaxCurrent: 32}}, []*Loadpoint{lp1, lp2})
+	require.NoError(t, err)
+	lg := groups[0]
+
+	current := func(lp *Loadpoint) float64 {
+		if !lp.enabled {
+			return 0
+		}
+		return lp.chargeCurrent
+	}
+
+	update := func(lp *Loadpoint) {
+		lp.setLoadGroupLimit(lg.currentLimit(lp), true)
+		require.NoError(t, lp.setLimit(lp.MaxCurrent, false))
+
+		// combined current never exceeds group budget
+		assert.LessOrEqual(t, current(lp1)+current(lp2), lg.MaxCurrent)
+	}
+
+	// single vehicle uses the entire budget
+	lp2.status = api.StatusA
+	update(lp1)
+	assert.Equal(t, 32.0, current(lp1))
+
+	// second vehicle waits for budget being released
+	lp2.status = api.StatusC
+	update(lp2)
+	assert.Equal(t, 0.0, current(lp2))
+
+	// budget is shared fairly
+	update(lp1)
+	update(lp2)
+	assert.Equal(t, 16.0, current(lp1))
+	assert.Equal(t, 16.0, current(lp2))
+
+	for i := 0; i < 3; i++ {
+		update(lp1)
+		update(lp2)
+	}
+	assert.Equal(t, 16.0, current(lp1))
+	assert.Equal(t, 16.0, current(lp2))
+
+	// budget is released on disconnect
+	lp2.status = api.StatusA
+	update(lp2)
+	assert.Equal(t, 0.0, current(lp2))
+	update(lp1)
+	assert.Equal(t, 32.0, current(lp1))
+}
+
+func TestLoadGroupFairShare(t *testing.T) {
+	Voltage = 100
+
+	tc := []struct {
+		title    string
+		group    LoadGroup
+		lp1, lp2 *Loadpoint
+		prio1    int
+		share1   float64
+		share2   float64
+	}{
+		{"current budget",
+			LoadGroup{Name: "garage", MaxCurrent: 32},
+			newLoadGroupLoadpoint(t, "lp1", 3, 32), newLoadGroupLoadpoint(t, "lp2", 3, 32), 0,
+			16, 16,
+		},
+		{"unused share is redistributed",
+			LoadGroup{Name: "garage", MaxCurrent: 32},
+			newLoadGroupLoadpoint(t, "lp1", 3, 32), newLoadGroupLoadpoint(t, "lp2", 1, 10), 0,
+			22, 10,
+		},
+		{"power budget",
+			LoadGroup{Name: "garage", MaxPower: 6000},
+			newLoadGroupLoadpoint(t, "lp1", 3, 32), newLoadGroupLoadpoint(t, "lp2", 1, 32), 0,
+			15, 15,
+		},
+		{"budget below min current excludes lower priority",
+			LoadGroup{Name: "garage", MaxCurrent: 10},
+			newLoadGroupLoadpoint(t, "lp1", 3, 16), newLoadGroupLoadpoint(t, "lp2", 3, 16), 1,
+			10, 0,
+		},
+	}
+
+	for _, tc := range tc {
+		t.Log(tc.title)
+
+		tc.lp1.Priority_ = tc.prio1
+
+		groups, err := setupLoadGroups([]LoadGroup{tc.group}, []*Loadpoint{tc.lp1, tc.lp2})
+		require.NoError(t, err)
+
+		assert.InDelta(t, tc.share1, groups[0].fairShare(tc.lp1), 1e-3, tc.title)
+		assert.InDelta(t, tc.share2, groups[0].fairShare(tc.lp2), 1e-3, tc.title)
+	}
+}
+
+func TestLoadGroupSetup(t *testing.T) {
+	lp := newLoadGroupLoadpoint(t, "lp", 3, 16)
+
+	_, err := setupLoadGroups([]LoadGroup{{Name: "garage"}}, []*Loadpoint{lp})
+	assert.Error(t, err, "missing budget")
+
+	_, err = setupLoadGroups([]LoadGroup{{Name: "other", MaxCurrent: 16}}, []*Loadpoint{lp})
+	assert.Error(t, err, "unknown group")
+
+	groups, err := setupLoadGroups([]LoadGroup{{Name: "garage", MaxCurrent: 16}}, []*Loadpoint{lp})
+	require.NoError(t, err)
+	assert.Equal(t, lp, groups[0].loadpoint(lp))
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/evcc-io/evcc/core/coordinator"
 	"github.com/evcc-io/evcc/core/loadpoint"
 	"github.com/evcc-io/evcc/core/planner"
+	"github.com/evcc-io/evcc/core/rules"
 	"github.com/evcc-io/evcc/core/session"
 	"github.com/evcc-io/evcc/core/soc"
 	"github.com/evcc-io/evcc/core/wrapper"
@@ -29,21 +30,24 @@ import (
 )
 
 const (
-	evChargeStart         = "start"      // update chargeTimer
-	evChargeStop          = "stop"       // update chargeTimer
-	evChargeCurrent       = "current"    // update fakeChargeMeter
-	evChargePower         = "power"      // update chargeRater
-	evVehicleConnect      = "connect"    // vehicle connected
-	evVehicleDisconnect   = "disconnect" // vehicle disconnected
-	evVehicleSoc          = "soc"        // vehicle soc progress
-	evVehicleUnidentified = "guest"      // vehicle unidentified
+	evChargeStart          = "start"         // update chargeTimer
+	evChargeStop           = "stop"          // update chargeTimer
+	evChargeCurrent        = "current"       // update fakeChargeMeter
+	evChargePower          = "power"         // update chargeRater
+	evVehicleConnect       = "connect"       // vehicle connected
+	evVehicleDisconnect    = "disconnect"    // vehicle disconnected
+	evVehicleSoc           = "soc"           // vehicle soc progress
+	evVehicleUnidentified  = "guest"         // vehicle unidentified
+	evVehicleDisconnectLow = "disconnectlow" // vehicle disconnected below disconnect soc
+	evVehicleSchedule      = "schedule"      // vehicle schedule prevents charging
 
 	pvTimer   = "pv"
 	pvEnable  = "enable"
 	pvDisable = "disable"
 
-	guardTimer  = "guard"
-	guardEnable = "enable"
+	guardTimer   = "guard"
+	guardEnable  = "enable"
+	guardDisable = "disable"
 
 	phaseTimer   = "phase"
 	phaseScale1p = "scale1p"
@@ -76,6 +80,19 @@ type SocConfig struct {
 	Target_  int        `mapstructure:"target"` // TODO deprecated
 	min      int        // Default minimum Soc, guarded by mutex
 	target   int        // Default target Soc, guarded by mutex
+
+	Deadband float64       `mapstructure:"deadband"` // stop charging this many percent below the target soc
+	Margin   time.Duration `mapstructure:"margin"`   // stop charging early by the soc gained during this duration to compensate polling lag
+	Grace    time.Duration `mapstructure:"grace"`    // keep using the last valid soc on vehicle api errors for this duration, zero if unlimited
+	TopUp    float64       `mapstructure:"topUp"`    // hold at the target soc while connected and top up once the soc drops this many percent below
+
+	SyncLimit    bool `mapstructure:"syncLimit"`    // set the vehicle's charge limit to the plan's target soc
+	RestoreLimit bool `mapstructure:"restoreLimit"` // restore the vehicle's previous charge limit when the plan clears
+
+	Reserve int `mapstructure:"reserve"` // never discharge the vehicle below this soc
+
+	Days      DayTargetConfig `mapstructure:"days"`      // default target soc on weekdays and weekends
+	Guarantee GuaranteeConfig `mapstructure:"guarantee"` // daily minimum soc by time of day
 }
 
 // Poll modes
@@ -91,6 +108,7 @@ const (
 type ThresholdConfig struct {
 	Delay     time.Duration
 	Threshold float64
+	Surplus   float64 // pv surplus including the loadpoint's charge power (W), replaces threshold if set
 }
 
 // Task is the task type
@@ -109,33 +127,80 @@ type Loadpoint struct {
 	// exposed public configuration
 	sync.Mutex                // guard status
 	vehicleMux sync.Mutex     // guard vehicle
+	targetMux  sync.Mutex     // serialize target updates
 	Mode       api.ChargeMode `mapstructure:"mode"` // Charge mode, guarded by mutex
 
-	Title_            string   `mapstructure:"title"`    // UI title
-	Priority_         int      `mapstructure:"priority"` // Priority
-	ConfiguredPhases  int      `mapstructure:"phases"`   // Charger configured phase mode 0/1/3
-	ChargerRef        string   `mapstructure:"charger"`  // Charger reference
-	VehicleRef        string   `mapstructure:"vehicle"`  // Vehicle reference
-	VehiclesRef_      []string `mapstructure:"vehicles"` // TODO deprecated
-	MeterRef          string   `mapstructure:"meter"`    // Charge meter reference
+	Title_            string   `mapstructure:"title"`     // UI title
+	Priority_         int      `mapstructure:"priority"`  // Priority
+	ConfiguredPhases  int      `mapstructure:"phases"`    // Charger configured phase mode 0/1/3
+	ChargerRef        string   `mapstructure:"charger"`   // Charger reference
+	VehicleRef        string   `mapstructure:"vehicle"`   // Vehicle reference
+	VehiclesRef_      []string `mapstructure:"vehicles"`  // TODO deprecated
+	MeterRef          string   `mapstructure:"meter"`     // Charge meter reference
+	LoadGroupRef      string   `mapstructure:"loadGroup"` // Load group reference
 	Soc               SocConfig
 	Enable, Disable   ThresholdConfig
-	ResetOnDisconnect bool `mapstructure:"resetOnDisconnect"`
+	SelfConsumption   SelfConsumptionConfig `mapstructure:"selfConsumption"`
+	MinPV             MinPVConfig           `mapstructure:"minPv"`        // min pv production required for starting pv mode charging
+	Presence          PresenceConfig        `mapstructure:"presence"`     // adjust priority by user presence
+	Precondition      PreconditionConfig    `mapstructure:"precondition"` // climate lead time before departure by outside temperature
+	Rules             rules.Rules           `mapstructure:"rules"`
+	PeakWindows       rules.Windows         `mapstructure:"peakWindows"`      // charging is blocked during peak windows
+	ActiveWindows     rules.Windows         `mapstructure:"activeWindows"`    // charging is only permitted during active windows, always if empty
+	Full              FullConfig            `mapstructure:"full"`             // treat vehicle as full before reaching 100%
+	SocSchedule       SocSchedule           `mapstructure:"socSchedule"`      // time-varying target soc
+	VehiclePhases     int                   `mapstructure:"vehiclePhases"`    // phases used by vehicles without phase configuration
+	DisconnectSoc     int                   `mapstructure:"disconnectSoc"`    // notify when vehicles are disconnected below this soc
+	MaxPhaseSwitches  int                   `mapstructure:"maxPhaseSwitches"` // daily limit of automatic phase switches, zero if unlimited
+	ResetOnDisconnect bool                  `mapstructure:"resetOnDisconnect"`
+	SessionTag        string                `mapstructure:"sessionTag"`    // attribute sessions by vehicle or identifier, vehicle falling back to identifier if empty
+	FaultRecovery     FaultRecoveryConfig   `mapstructure:"faultRecovery"` // reset the charger from fault states
 	onDisconnect      api.ActionConfig
 	targetEnergy      float64 // Target charge energy for dumb vehicles in kWh
 
-	MinCurrent    float64       // PV mode: start current	Min+PV mode: min current
-	MaxCurrent    float64       // Max allowed current. Physically ensured by the charger
-	GuardDuration time.Duration // charger enable/disable minimum holding time
+	MinCurrent     float64       // PV mode: start current	Min+PV mode: min current
+	MaxCurrent     float64       // Max allowed current. Physically ensured by the charger
+	GuardDuration  time.Duration // charger enable/disable minimum holding time
+	MinOnDuration  time.Duration // minimum charging duration before disabling
+	MinOffDuration time.Duration // minimum pause duration before re-enabling
+
+	ChargeEfficiency float64 // grid to vehicle battery efficiency, vehicle setting takes precedence
+	MaxPower         float64 // Max charge power of power controlled chargers (W)
 
 	enabled             bool      // Charger enabled state
 	phases              int       // Charger enabled phases, guarded by mutex
 	measuredPhases      int       // Charger physically measured phases
 	chargeCurrent       float64   // Charger current limit
+	phaseLimited        bool      // Charge current limited by site phase current caps
+	phaseCurrentLimit   float64   // Charge current limit imposed by site phase current caps
+	loadGroupLimited    bool      // Charge current limited by load group budget
+	demandLimited       bool      // Charge current limited by site peak demand target
+	demandLimit         float64   // Charge current limit imposed by site peak demand target
+	loadGroupLimit      float64   // Charge current limit imposed by load group budget
+	fuseLimited         bool      // Charge current limited by site main fuse
+	fuseCurrent         float64   // Current per phase left by site main fuse
+	fusePower           float64   // Power left by site main fuse
+	fuseClamped         bool      // Charge current clamped by site main fuse
+	greenPower          float64   // Site pv power available after home consumption
+	pvPower             float64   // Site pv production
+	greenBatteryPower   float64   // Site battery discharge power available after home consumption
+	gridPrice           *float64  // Site grid price for rule evaluation
+	emergencyStop       bool      // Site emergency stop engaged, guarded by mutex
+	generatorLimit      string    // Site generator limit, empty if the generator is not running
+	demandResponseLimit string    // Site demand response limit, empty if no event is active
 	guardUpdated        time.Time // Charger enabled/disabled timestamp
 	socUpdated          time.Time // Soc updated timestamp (poll: connected)
+	socFailed           time.Time // First failed soc update since the last valid soc
+	socRefresh          bool      // Soc update requested by forced vehicle refresh
+	vehicleRefreshed    time.Time // Forced vehicle refresh timestamp
+	limitSynced         int       // Vehicle charge limit set by the plan, zero if not synced
+	limitPrevious       int       // Vehicle charge limit before syncing, zero if unknown
+	vehicleCurrentLimit float64   // Charge current limit enforced by the vehicle, zero if not limited
+	dischargePower      float64   // Vehicle discharge power (W), zero if not discharging
 	vehicleDetect       time.Time // Vehicle connected timestamp
 	phasesSwitched      time.Time // Phase switch timestamp
+	phaseSwitches       int       // Phase switches on the day of phaseSwitchesDay
+	phaseSwitchesDay    time.Time // Start of day of counted phase switches
 	vehicleDetectTicker *clock.Ticker
 	vehicleIdentifier   string
 
@@ -144,9 +209,13 @@ type Loadpoint struct {
 	chargeRater      api.ChargeRater
 	chargedAtStartup float64 // session energy at startup
 
+	chargerMinCurrent, chargerMaxCurrent float64 // charger current bounds, zero if not limited
+
 	chargeMeter    api.Meter   // Charger usage meter
 	vehicle        api.Vehicle // Currently active vehicle
 	defaultVehicle api.Vehicle // Default vehicle (disables detection)
+	vehicleManual  bool        // Vehicle assigned manually for the session (disables detection), guarded by vehicleMux
+	modeManual     bool        // Mode changed manually for the session (overrides vehicle default mode), guarded by mutex
 	coordinator    coordinator.API
 	socEstimator   *soc.Estimator
 
@@ -156,6 +225,39 @@ type Loadpoint struct {
 	planSlotEnd time.Time // current plan slot end time
 	planActive  bool      // charge plan exists and has a currently active slot
 
+	guaranteeDeadline time.Time // deadline of the started daily guarantee, zero if not charging for the guarantee
+
+	// boost
+	boostUntil time.Time      // boost end time
+	boostMode  api.ChargeMode // mode to revert to after boost
+
+	// peak windows
+	sitePeakWindows rules.Windows // Site peak windows blocking charging
+
+	// target soc deadband
+	socStopTarget    int     // target soc the charging stop was latched for
+	socStopThreshold float64 // soc at which charging was stopped
+	topUpTarget      int     // target soc held until the vehicle drifts below the top-up margin
+
+	vehicleScheduleConflict bool // vehicle charging schedule delays charging beyond evcc's command
+
+	dayTargetApplied string // date the day's default target soc was applied
+
+	// charger fault
+	fault         bool         // charger reports a fault state
+	faultAttempts int          // recovery attempts for the current fault
+	faultUpdated  time.Time    // fault start or last recovery attempt
+	faultResetS   func() error // fault reset action
+
+	// user presence
+	presenceG func() (bool, error)
+	present   *bool // last presence state, nil until read
+
+	// vehicle full
+	vehicleFull bool      // vehicle treated as full until disconnected or next plan
+	fullTimer   time.Time // taper current undercut since
+	fullPlanned bool      // plan active, full state has been reset for the plan
+
 	// cached state
 	status         api.ChargeStatus       // Charger status
 	remoteDemand   loadpoint.RemoteDemand // External status demand
@@ -172,6 +274,7 @@ type Loadpoint struct {
 	sessionEnergy           *EnergyMetrics // Stats for charged energy by session
 	chargeRemainingDuration time.Duration  // Remaining charge duration
 	chargeRemainingEnergy   float64        // Remaining charge energy in Wh
+	chargeTimeToTarget      time.Duration  // Computed duration to reach the charge target at current power
 	progress                *Progress      // Step-wise progress indicator
 
 	// session log
@@ -208,6 +311,90 @@ func NewLoadpointFromConfig(log *util.Logger, other map[string]interface{}) (*Lo
 		lp.log.WARN.Println("maxCurrent must be larger than minCurrent")
 	}
 
+	if err := lp.Rules.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := lp.PeakWindows.Validate(); err != nil {
+		return nil, fmt.Errorf("peak windows: %w", err)
+	}
+
+	if err := lp.ActiveWindows.Validate(); err != nil {
+		return nil, fmt.Errorf("active windows: %w", err)
+	}
+
+	if err := validateSurplus(lp.Enable, lp.Disable); err != nil {
+		return nil, err
+	}
+
+	if lp.Soc.Deadband < 0 || lp.Soc.Deadband >= 100 || lp.Soc.Margin < 0 {
+		return nil, errors.New("soc deadband must be between 0 and 100, margin must not be negative")
+	}
+
+	if lp.MaxPower < 0 {
+		return nil, errors.New("max power must not be negative")
+	}
+
+	if err := lp.Soc.Days.Validate(); err != nil {
+		return nil, fmt.Errorf("soc days: %w", err)
+	}
+
+	if err := lp.Soc.Guarantee.Validate(); err != nil {
+		return nil, fmt.Errorf("soc guarantee: %w", err)
+	}
+
+	if lp.Soc.TopUp < 0 || lp.Soc.TopUp >= 100 {
+		return nil, errors.New("soc top-up must be between 0 and 100")
+	}
+
+	if lp.Soc.Reserve < 0 || lp.Soc.Reserve > 100 {
+		return nil, errors.New("soc reserve must be between 0 and 100")
+	}
+
+	if err := session.ValidateTagSource(lp.SessionTag); err != nil {
+		return nil, err
+	}
+
+	if err := lp.Full.Validate(); err != nil {
+		return nil, fmt.Errorf("full: %w", err)
+	}
+
+	if err := lp.MinPV.Validate(); err != nil {
+		return nil, fmt.Errorf("min pv: %w", err)
+	}
+
+	if err := lp.FaultRecovery.Validate(); err != nil {
+		return nil, fmt.Errorf("fault recovery: %w", err)
+	}
+
+	if err := lp.Precondition.Validate(); err != nil {
+		return nil, fmt.Errorf("precondition: %w", err)
+	}
+
+	if err := lp.Presence.Validate(); err != nil {
+		return nil, fmt.Errorf("presence: %w", err)
+	}
+
+	if err := lp.configurePresence(); err != nil {
+		return nil, fmt.Errorf("presence: %w", err)
+	}
+
+	if err := lp.SocSchedule.Validate(); err != nil {
+		return nil, fmt.Errorf("soc schedule: %w", err)
+	}
+
+	if lp.VehiclePhases < 0 || lp.VehiclePhases > 3 {
+		return nil, fmt.Errorf("invalid vehicle phases: %d", lp.VehiclePhases)
+	}
+
+	if lp.DisconnectSoc < 0 || lp.DisconnectSoc > 100 {
+		return nil, fmt.Errorf("invalid disconnect soc: %d", lp.DisconnectSoc)
+	}
+
+	if lp.MaxPhaseSwitches < 0 {
+		return nil, fmt.Errorf("invalid max phase switches: %d", lp.MaxPhaseSwitches)
+	}
+
 	if lp.Soc.Min_ != 0 {
 		lp.log.WARN.Println("Configuring soc.min at loadpoint is deprecated and must be applied per vehicle")
 	}
@@ -251,6 +438,11 @@ func NewLoadpointFromConfig(log *util.Logger, other map[string]interface{}) (*Lo
 	lp.charger = dev.Instance()
 	lp.configureChargerType(lp.charger)
 
+	if err := lp.configureFaultReset(); err != nil {
+		return nil, fmt.Errorf("fault recovery: %w", err)
+	}
+	lp.readChargerCurrentBounds()
+
 	// setup fixed phases:
 	// - simple charger starts with phases config if specified or 3p
 	// - switchable charger starts at 0p since we don't know the current setting
@@ -297,6 +489,8 @@ func NewLoadpoint(log *util.Logger) *Loadpoint {
 		},
 		Enable:        ThresholdConfig{Delay: time.Minute, Threshold: 0},     // t, W
 		Disable:       ThresholdConfig{Delay: 3 * time.Minute, Threshold: 0}, // t, W
+		Full:          FullConfig{Delay: 5 * time.Minute},                    // t
+		FaultRecovery: FaultRecoveryConfig{Delay: time.Minute},               // t
 		GuardDuration: 5 * time.Minute,
 		sessionEnergy: NewEnergyMetrics(),
 		progress:      NewProgress(0, 10),     // soc progress indicator
@@ -318,7 +512,7 @@ func (lp *Loadpoint) collectDefaults() {
 		*actionCfg.Mode = lp.GetMode()
 		*actionCfg.MinCurrent = lp.GetMinCurrent()
 		*actionCfg.MaxCurrent = lp.GetMaxCurrent()
-		*actionCfg.Priority = lp.GetPriority()
+		*actionCfg.Priority = lp.Priority_
 	} else {
 		lp.log.ERROR.Printf("error allocating action config: %v", err)
 	}
@@ -414,6 +608,7 @@ func (lp *Loadpoint) evChargeStartHandler() {
 		if session.Created.IsZero() {
 			session.Created = lp.clock.Now()
 		}
+		lp.sessionTransaction(session)
 	})
 }
 
@@ -475,12 +670,28 @@ func (lp *Loadpoint) evVehicleConnectHandler() {
 func (lp *Loadpoint) evVehicleDisconnectHandler() {
 	lp.log.INFO.Println("car disconnected")
 
+	// advise before vehicle soc is reset
+	lp.disconnectAdvisory()
+
 	// session is persisted during evChargeStopHandler which runs before
 	lp.clearSession()
 
 	// phases are unknown when vehicle disconnects
 	lp.resetMeasuredPhases()
 
+	// next vehicle charges to target without waiting for top-up
+	lp.topUpTarget = 0
+
+	// next vehicle is not full
+	lp.resetVehicleFull()
+
+	// guarantee is planned again for the next vehicle
+	lp.guaranteeDeadline = time.Time{}
+
+	// schedule conflicts are reported again for the next vehicle
+	lp.vehicleScheduleConflict = false
+	lp.publish("vehicleScheduleConflict", false)
+
 	// energy and duration
 	lp.sessionEnergy.Publish("session", lp)
 	lp.publish("chargedEnergy", lp.getChargedEnergy())
@@ -493,6 +704,10 @@ func (lp *Loadpoint) evVehicleDisconnectHandler() {
 	lp.setVehicleIdentifier("")
 	lp.stopVehicleDetection()
 
+	// manual vehicle assignment and mode end with the session
+	lp.setVehicleManual(false)
+	lp.setModeManual(false)
+
 	// set default vehicle (may be nil)
 	lp.setActiveVehicle(lp.defaultVehicle)
 
@@ -549,7 +764,7 @@ func (lp *Loadpoint) evChargeCurrentWrappedMeterHandler(current float64) {
 // applyAction executes the action
 func (lp *Loadpoint) applyAction(actionCfg api.ActionConfig) {
 	if actionCfg.Mode != nil {
-		lp.SetMode(*actionCfg.Mode)
+		lp.applyMode(*actionCfg.Mode)
 	}
 	if min := actionCfg.MinCurrent; min != nil && *min >= *lp.onDisconnect.MinCurrent {
 		lp.SetMinCurrent(*min)
@@ -565,6 +780,34 @@ func (lp *Loadpoint) applyAction(actionCfg api.ActionConfig) {
 	}
 }
 
+// applyMode sets the default charge mode unless the mode was changed manually during the session
+func (lp *Loadpoint) applyMode(mode api.ChargeMode) {
+	lp.Lock()
+	defer lp.Unlock()
+
+	if lp.modeManual {
+		lp.log.DEBUG.Printf("keep manual charge mode: %s", string(lp.Mode))
+		return
+	}
+
+	if _, err := api.ChargeModeString(mode.String()); err != nil {
+		lp.log.ERROR.Printf("invalid charge mode: %s", string(mode))
+		return
+	}
+
+	lp.log.DEBUG.Printf("set default charge mode: %s", string(mode))
+
+	lp.stopBoost()
+	lp.setMode(mode)
+}
+
+// setModeManual sets the manual mode override
+func (lp *Loadpoint) setModeManual(manual bool) {
+	lp.Lock()
+	defer lp.Unlock()
+	lp.modeManual = manual
+}
+
 // Prepare loadpoint configuration by adding missing helper elements
 func (lp *Loadpoint) Prepare(uiChan chan<- util.Param, pushChan chan<- push.Event, lpChan chan<- *Loadpoint) {
 	lp.uiChan = uiChan
@@ -586,6 +829,10 @@ func (lp *Loadpoint) Prepare(uiChan chan<- util.Param, pushChan chan<- push.Even
 
 	lp.publish("enableThreshold", lp.Enable.Threshold)
 	lp.publish("disableThreshold", lp.Disable.Threshold)
+	lp.publish("enableSurplus", lp.Enable.Surplus)
+	lp.publish("disableSurplus", lp.Disable.Surplus)
+	lp.publish(boostRemaining, time.Duration(0))
+	lp.publish("reserveSoc", lp.Soc.Reserve)
 
 	lp.setConfiguredPhases(lp.ConfiguredPhases)
 	lp.publish(phasesEnabled, lp.phases)
@@ -631,7 +878,7 @@ func (lp *Loadpoint) Prepare(uiChan chan<- util.Param, pushChan chan<- push.Even
 		if lp.enabled = enabled; enabled {
 			lp.guardUpdated = lp.clock.Now()
 			// set defined current for use by pv mode
-			_ = lp.setLimit(lp.GetMinCurrent(), false)
+			_ = lp.setLimit(lp.effectiveMinCurrent(), false)
 		}
 	} else {
 		lp.log.ERROR.Printf("charger: %v", err)
@@ -707,15 +954,88 @@ func (lp *Loadpoint) syncCharger() error {
 
 // setLimit applies charger current limits and enables/disables accordingly
 func (lp *Loadpoint) setLimit(chargeCurrent float64, force bool) error {
+	// emergency stop overrides all modes and plans
+	if chargeCurrent > 0 && lp.emergencyStopped() {
+		lp.log.DEBUG.Println("charging prevented by emergency stop")
+		chargeCurrent = 0
+		force = true
+	}
+
+	// generator power is not used for charging
+	if current := lp.generatorCurrent(chargeCurrent); current < chargeCurrent {
+		lp.log.DEBUG.Printf("charge current limited by generator: %.3gA", current)
+		chargeCurrent = current
+		force = true
+	}
+
+	// demand response events reduce charging until the event ends
+	if current := lp.demandResponseCurrent(chargeCurrent); current < chargeCurrent {
+		lp.log.DEBUG.Printf("charge current limited by demand response: %.3gA", current)
+		chargeCurrent = current
+		force = true
+	}
+
+	// peak windows override all modes and plans
+	if chargeCurrent > 0 && lp.peakBlocked() {
+		lp.log.DEBUG.Println("charging prevented by peak window")
+		chargeCurrent = 0
+		force = true
+	}
+
+	// active windows override all modes and plans
+	if chargeCurrent > 0 && lp.inactive() {
+		lp.log.DEBUG.Println("charging prevented outside active window")
+		chargeCurrent = 0
+		force = true
+	}
+
+	// protect phases from overload
+	if lp.phaseLimited && chargeCurrent > lp.phaseCurrentLimit {
+		lp.log.DEBUG.Printf("charge current limited by phase current cap: %.3gA", lp.phaseCurrentLimit)
+		chargeCurrent = lp.phaseCurrentLimit
+		force = true
+	}
+
+	// hold site peak demand target
+	if lp.demandLimited && chargeCurrent > lp.demandLimit {
+		lp.log.DEBUG.Printf("charge current limited by peak demand: %.3gA", lp.demandLimit)
+		chargeCurrent = lp.demandLimit
+		force = true
+	}
+
+	// avoid commanding more than the vehicle accepts, never raising the current above earlier caps
+	if lp.vehicleCurrentLimit > 0 && chargeCurrent > lp.vehicleCurrentLimit {
+		chargeCurrent = min(chargeCurrent, max(lp.vehicleCurrentLimit, lp.effectiveMinCurrent()))
+		lp.log.DEBUG.Printf("charge current limited by vehicle: %.3gA", chargeCurrent)
+	}
+
+	// protect load group from overload
+	if lp.loadGroupLimited && chargeCurrent > lp.loadGroupLimit {
+		lp.log.DEBUG.Printf("charge current limited by load group: %.3gA", lp.loadGroupLimit)
+		chargeCurrent = lp.loadGroupLimit
+		force = true
+	}
+
+	// main fuse ceiling is applied last and cannot be overridden
+	if current, clamped := lp.fuseClamp(chargeCurrent); clamped {
+		chargeCurrent = current
+		force = true
+	}
+
 	// full amps only?
-	if _, ok := lp.charger.(api.ChargerEx); !ok || lp.vehicleHasFeature(api.CoarseCurrent) {
+	if _, ok := lp.charger.(api.ChargerEx); !ok && !lp.powerControlled() || lp.vehicleHasFeature(api.CoarseCurrent) {
 		chargeCurrent = math.Trunc(chargeCurrent)
 	}
 
 	// set current
-	if chargeCurrent != lp.chargeCurrent && chargeCurrent >= lp.GetMinCurrent() {
+	if chargeCurrent != lp.chargeCurrent && chargeCurrent >= lp.effectiveMinCurrent() {
 		var err error
-		if charger, ok := lp.charger.(api.ChargerEx); ok {
+		if charger, ok := lp.charger.(api.PowerLimiter); ok {
+			power := lp.powerSetpoint(chargeCurrent)
+			if err = charger.MaxPower(power); err == nil {
+				lp.log.DEBUG.Printf("max charge power: %.0fW", power)
+			}
+		} else if charger, ok := lp.charger.(api.ChargerEx); ok {
 			err = charger.MaxCurrentMillis(chargeCurrent)
 		} else {
 			err = lp.charger.MaxCurrent(int64(chargeCurrent))
@@ -741,10 +1061,16 @@ func (lp *Loadpoint) setLimit(chargeCurrent float64, force bool) error {
 	}
 
 	// set enabled/disabled
-	if enabled := chargeCurrent >= lp.GetMinCurrent(); enabled != lp.enabled {
-		if remaining := (lp.GuardDuration - lp.clock.Since(lp.guardUpdated)).Truncate(time.Second); remaining > 0 && !force {
-			lp.publishTimer(guardTimer, lp.GuardDuration, guardEnable)
-			return nil
+	if enabled := chargeCurrent >= lp.effectiveMinCurrent(); enabled != lp.enabled {
+		if hold := lp.guardHoldDuration(enabled); !force {
+			if remaining := (hold - lp.clock.Since(lp.guardUpdated)).Truncate(time.Second); remaining > 0 {
+				action := guardDisable
+				if enabled {
+					action = guardEnable
+				}
+				lp.publishTimer(guardTimer, hold, action)
+				return nil
+			}
 		}
 		lp.elapseGuard()
 
@@ -824,7 +1150,7 @@ func (lp *Loadpoint) targetSocReached() bool {
 
 	return lp.Soc.target > 0 &&
 		lp.Soc.target < 100 &&
-		lp.vehicleSoc >= float64(lp.Soc.target)
+		lp.targetSocTopUpReached()
 }
 
 // minSocNotReached checks if minimum is configured and not reached.
@@ -842,7 +1168,7 @@ func (lp *Loadpoint) minSocNotReached() bool {
 		return lp.vehicleSoc < float64(lp.Soc.min)
 	}
 
-	minEnergy := vehicle.Capacity() * float64(lp.Soc.min) / 100 / soc.ChargeEfficiency
+	minEnergy := vehicle.Capacity() * float64(lp.Soc.min) / 100 / lp.chargeEfficiency(vehicle)
 	return minEnergy > 0 && lp.getChargedEnergy() < minEnergy
 }
 
@@ -850,7 +1176,7 @@ func (lp *Loadpoint) minSocNotReached() bool {
 func (lp *Loadpoint) disableUnlessClimater() error {
 	var current float64 // zero disables
 	if lp.vehicleClimateActive() {
-		current = lp.GetMinCurrent()
+		current = lp.effectiveMinCurrent()
 	}
 
 	// reset plan once charge goal is met
@@ -897,6 +1223,8 @@ func statusEvents(prevStatus, status api.ChargeStatus) []string {
 // updateChargerStatus updates charger status and detects car connected/disconnected events
 func (lp *Loadpoint) updateChargerStatus() error {
 	status, err := lp.charger.Status()
+	lp.updateFault(status, err)
+	lp.publishChargerConnection()
 	if err != nil {
 		return err
 	}
@@ -927,6 +1255,39 @@ func (lp *Loadpoint) updateChargerStatus() error {
 	return nil
 }
 
+// vehicleCurrentLimits returns the vehicle's charge current limits, zero if not limited
+func (lp *Loadpoint) vehicleCurrentLimits() (float64, float64) {
+	if v, ok := lp.GetVehicle().(api.ChargeCurrentLimiter); ok {
+		return v.ChargeCurrentLimits()
+	}
+	return 0, 0
+}
+
+// effectiveMaxCurrent returns the max current allowed by loadpoint, charger and vehicle
+func (lp *Loadpoint) effectiveMaxCurrent() float64 {
+	maxCurrent := lp.GetMaxCurrent()
+	if lp.chargerMaxCurrent > 0 {
+		maxCurrent = min(maxCurrent, lp.chargerMaxCurrent)
+	}
+	if _, vehicleMax := lp.vehicleCurrentLimits(); vehicleMax > 0 {
+		maxCurrent = min(maxCurrent, vehicleMax)
+	}
+	return maxCurrent
+}
+
+// effectiveMinCurrent returns the min current required by loadpoint, charger and vehicle.
+// It never exceeds the effective max current.
+func (lp *Loadpoint) effectiveMinCurrent() float64 {
+	minCurrent := lp.GetMinCurrent()
+	if lp.chargerMinCurrent > 0 {
+		minCurrent = max(minCurrent, lp.chargerMinCurrent)
+	}
+	if vehicleMin, _ := lp.vehicleCurrentLimits(); vehicleMin > 0 {
+		minCurrent = max(minCurrent, vehicleMin)
+	}
+	return min(minCurrent, lp.effectiveMaxCurrent())
+}
+
 // effectiveCurrent returns the currently effective charging current
 func (lp *Loadpoint) effectiveCurrent() float64 {
 	if !lp.charging() {
@@ -1031,13 +1392,17 @@ func (lp *Loadpoint) scalePhases(phases int) error {
 func (lp *Loadpoint) fastCharging() error {
 	err := lp.scalePhasesIfAvailable(3)
 	if err == nil {
-		err = lp.setLimit(lp.GetMaxCurrent(), true)
+		err = lp.setLimit(lp.effectiveMaxCurrent(), true)
 	}
 	return err
 }
 
 // pvScalePhases switches phases if necessary and returns if switch occurred
 func (lp *Loadpoint) pvScalePhases(sitePower, minCurrent, maxCurrent float64) bool {
+	if lp.phaseSwitchLimitReached() {
+		return false
+	}
+
 	phases := lp.GetPhases()
 
 	// observed phase state inconsistency
@@ -1075,6 +1440,8 @@ func (lp *Loadpoint) pvScalePhases(sitePower, minCurrent, maxCurrent float64) bo
 		if elapsed := lp.clock.Since(lp.phaseTimer); elapsed >= lp.Disable.Delay {
 			if err := lp.scalePhases(1); err != nil {
 				lp.log.ERROR.Println(err)
+			} else if phases != 1 {
+				lp.countPhaseSwitch()
 			}
 			return true
 		}
@@ -1104,6 +1471,8 @@ func (lp *Loadpoint) pvScalePhases(sitePower, minCurrent, maxCurrent float64) bo
 		if elapsed := lp.clock.Since(lp.phaseTimer); elapsed >= lp.Enable.Delay {
 			if err := lp.scalePhases(3); err != nil {
 				lp.log.ERROR.Println(err)
+			} else if phases != 3 {
+				lp.countPhaseSwitch()
 			}
 			return true
 		}
@@ -1147,8 +1516,8 @@ func (lp *Loadpoint) publishTimer(name string, delay time.Duration, action strin
 // pvMaxCurrent calculates the maximum target current for PV mode
 func (lp *Loadpoint) pvMaxCurrent(mode api.ChargeMode, sitePower float64, batteryBuffered, batteryStart bool) float64 {
 	// read only once to simplify testing
-	minCurrent := lp.GetMinCurrent()
-	maxCurrent := lp.GetMaxCurrent()
+	minCurrent := lp.effectiveMinCurrent()
+	maxCurrent := lp.effectiveMaxCurrent()
 
 	// switch phases up/down
 	if _, ok := lp.charger.(api.PhaseSwitcher); ok {
@@ -1163,15 +1532,20 @@ func (lp *Loadpoint) pvMaxCurrent(mode api.ChargeMode, sitePower float64, batter
 
 	lp.log.DEBUG.Printf("pv charge current: %.3gA = %.3gA + %.3gA (%.0fW @ %dp)", targetCurrent, effectiveCurrent, deltaCurrent, sitePower, activePhases)
 
+	// hold off if charging would not be mostly self-generated
+	selfConsumptionLow := mode == api.ModePV && lp.selfConsumptionTooLow(max(targetCurrent, minCurrent), activePhases)
+	if selfConsumptionLow {
+		targetCurrent = 0
+	}
+
 	// in MinPV mode or under special conditions return at least minCurrent
-	if (mode == api.ModeMinPV || batteryStart || batteryBuffered && lp.charging()) && targetCurrent < minCurrent {
+	if (mode == api.ModeMinPV || !selfConsumptionLow && (batteryStart || batteryBuffered && lp.charging())) && targetCurrent < minCurrent {
 		return minCurrent
 	}
 
 	if mode == api.ModePV && lp.enabled && targetCurrent < minCurrent {
 		// kick off disable sequence
-		if sitePower >= lp.Disable.Threshold && lp.phaseTimer.IsZero() {
-			lp.log.DEBUG.Printf("site power %.0fW >= %.0fW disable threshold", sitePower, lp.Disable.Threshold)
+		if (lp.pvDisableReached(sitePower) || selfConsumptionLow) && lp.phaseTimer.IsZero() {
 
 			if lp.pvTimer.IsZero() {
 				lp.log.DEBUG.Printf("pv disable timer start: %v", lp.Disable.Delay)
@@ -1201,9 +1575,7 @@ func (lp *Loadpoint) pvMaxCurrent(mode api.ChargeMode, sitePower float64, batter
 
 	if mode == api.ModePV && !lp.enabled {
 		// kick off enable sequence
-		if (lp.Enable.Threshold == 0 && targetCurrent >= minCurrent) ||
-			(lp.Enable.Threshold != 0 && sitePower <= lp.Enable.Threshold) {
-			lp.log.DEBUG.Printf("site power %.0fW <= %.0fW enable threshold", sitePower, lp.Enable.Threshold)
+		if !selfConsumptionLow && !lp.pvProductionTooLow(mode) && lp.pvEnableReached(sitePower, targetCurrent, minCurrent) {
 
 			if lp.pvTimer.IsZero() {
 				lp.log.DEBUG.Printf("pv enable timer start: %v", lp.Enable.Delay)
@@ -1266,6 +1638,9 @@ func (lp *Loadpoint) UpdateChargePower() {
 	if err != nil {
 		lp.log.ERROR.Printf("charge meter: %v", err)
 	}
+
+	// charge power is kept from the last valid reading
+	lp.publish("chargeMeterError", errorString(err))
 }
 
 // updateChargeCurrents uses PhaseCurrents interface to count phases with current >=1A
@@ -1274,6 +1649,7 @@ func (lp *Loadpoint) updateChargeCurrents() {
 
 	phaseMeter, ok := lp.chargeMeter.(api.PhaseCurrents)
 	if !ok {
+		lp.updateReportedPhases()
 		return // don't guess
 	}
 
@@ -1296,12 +1672,7 @@ func (lp *Loadpoint) updateChargeCurrents() {
 		}
 
 		if phases >= 1 {
-			lp.Lock()
-			lp.measuredPhases = phases
-			lp.Unlock()
-
-			lp.log.DEBUG.Printf("detected active phases: %dp", phases)
-			lp.publish(phasesActive, phases)
+			lp.setMeasuredPhases(phases)
 		}
 	}
 }
@@ -1370,6 +1741,7 @@ func (lp *Loadpoint) publishChargeProgress() {
 	lp.sessionEnergy.Publish("session", lp)
 	// deprecated: use sessionEnergy instead
 	lp.publish("chargedEnergy", lp.getChargedEnergy())
+	lp.publish("chargedVehicleEnergy", lp.getChargedVehicleEnergy())
 	lp.publish("chargeDuration", lp.chargeDuration)
 	if _, ok := lp.chargeMeter.(api.MeterEnergy); ok {
 		lp.publish("chargeTotalImport", lp.chargeMeterTotal())
@@ -1394,18 +1766,35 @@ func (lp *Loadpoint) publishSocAndRange() {
 
 	if err == nil || lp.chargerHasFeature(api.IntegratedDevice) || lp.vehicleSocPollAllowed() {
 		lp.socUpdated = lp.clock.Now()
+		lp.socRefresh = false
+
+		lp.updateVehicleCurrentLimit()
 
 		f, err := lp.socEstimator.Soc(lp.getChargedEnergy())
 		if err != nil {
-			if errors.Is(err, api.ErrMustRetry) {
+			switch {
+			case errors.Is(err, api.ErrMustRetry):
 				lp.socUpdated = time.Time{}
-			} else {
+			case errors.Is(err, api.ErrPendingLink):
+				lp.log.DEBUG.Printf("vehicle soc: %v", err)
+			default:
 				lp.log.ERROR.Printf("vehicle soc: %v", err)
 			}
 
 			return
 		}
 
+		if lp.socGraceElapsed() {
+			if lp.vehicleSoc > 0 {
+				lp.log.WARN.Printf("vehicle soc: unavailable for %v, ignoring last valid soc", lp.Soc.Grace)
+			}
+
+			lp.vehicleSoc = 0
+			lp.publish(vehicleSoc, lp.vehicleSoc)
+
+			return
+		}
+
 		lp.vehicleSoc = f
 		lp.log.DEBUG.Printf("vehicle soc: %.0f%%", lp.vehicleSoc)
 		lp.publish(vehicleSoc, lp.vehicleSoc)
@@ -1422,6 +1811,9 @@ func (lp *Loadpoint) publishSocAndRange() {
 			}
 		}
 
+		// vehicle charging schedule
+		lp.updateScheduleConflict()
+
 		// use minimum of vehicle and loadpoint
 		socLimit := targetSoc
 		if lp.Soc.target < socLimit {
@@ -1494,6 +1886,20 @@ func (lp *Loadpoint) stopWakeUpTimer() {
 	lp.wakeUpTimer.Stop()
 }
 
+// guardHoldDuration returns the minimum time since the last enable/disable before the charger may be switched to enabled.
+// Minimum on and off durations extend the guard duration but never shorten it.
+func (lp *Loadpoint) guardHoldDuration(enabled bool) time.Duration {
+	hold := lp.GuardDuration
+	if enabled {
+		// currently disabled
+		hold = max(hold, lp.MinOffDuration)
+	} else {
+		// currently enabled
+		hold = max(hold, lp.MinOnDuration)
+	}
+	return hold
+}
+
 // guardGracePeriodElapsed checks if last guard update is within guard grace period
 func (lp *Loadpoint) guardGracePeriodElapsed() bool {
 	return time.Since(lp.guardUpdated) > guardGracePeriod
@@ -1513,6 +1919,12 @@ func (lp *Loadpoint) phaseSwitchCompleted() bool {
 func (lp *Loadpoint) Update(sitePower float64, autoCharge, batteryBuffered, batteryStart bool, greenShare float64, effPrice, effCo2 *float64) {
 	lp.processTasks()
 
+	// revert expired boost before mode is evaluated
+	lp.updateBoost()
+
+	// presence adjusts the priority used by the site's allocation
+	lp.updatePresence()
+
 	// read and publish meters first- charge power has already been updated by the site
 	lp.updateChargeVoltages()
 	lp.updateChargeCurrents()
@@ -1549,6 +1961,11 @@ func (lp *Loadpoint) Update(sitePower float64, autoCharge, batteryBuffered, batt
 	// publish soc after updating charger status to make sure
 	// initial update of connected state matches charger status
 	lp.publishSocAndRange()
+	lp.updateTimeToTarget()
+
+	// sync vehicle charge limit with plan
+	lp.syncVehicleLimit()
+	lp.updateDischarge()
 
 	// sync settings with charger
 	if err := lp.syncCharger(); err != nil {
@@ -1567,9 +1984,35 @@ func (lp *Loadpoint) Update(sitePower float64, autoCharge, batteryBuffered, batt
 
 	// update and publish plan without being short-circuited by modes etc.
 	plannerActive := lp.plannerActive()
+	guaranteed := lp.guaranteeActive()
+
+	// user-defined rules take precedence over modes except off
+	ruleAction, ruleMatched := lp.ruleAction(sitePower)
+
+	peakBlocked := lp.peakBlocked()
+	lp.publish("peakBlocked", peakBlocked)
+
+	inactive := lp.inactive()
+	lp.publish("inactive", inactive)
+
+	vehicleFull := lp.connected() && lp.vehicleFullReached(plannerActive)
+	lp.publish("vehicleFull", vehicleFull)
+
+	// default target soc of the day unless planned
+	lp.applyDayTarget()
+
+	// scheduled soc replaces the target soc
+	socScheduled, scheduled := lp.socScheduleTarget()
+	lp.publish("socScheduled", socScheduled)
 
 	// execute loading strategy
 	switch {
+	case lp.emergencyStopped():
+		err = lp.setLimit(0, true)
+
+	case peakBlocked, inactive:
+		err = lp.setLimit(0, true)
+
 	case !lp.connected():
 		// always disable charger if not connected
 		// https://github.com/evcc-io/evcc/issues/105
@@ -1582,10 +2025,13 @@ func (lp *Loadpoint) Update(sitePower float64, autoCharge, batteryBuffered, batt
 		lp.log.DEBUG.Printf("targetEnergy reached: %.0fkWh > %0.1fkWh", lp.getChargedEnergy()/1e3, lp.targetEnergy)
 		err = lp.disableUnlessClimater()
 
-	case lp.targetSocReached():
+	case !scheduled && lp.targetSocReached():
 		lp.log.DEBUG.Printf("targetSoc reached: %.1f%% > %d%%", lp.vehicleSoc, lp.Soc.target)
 		err = lp.disableUnlessClimater()
 
+	case vehicleFull:
+		err = lp.disableUnlessClimater()
+
 	case lp.remoteControlled(loadpoint.RemoteHardDisable):
 		remoteDisabled = loadpoint.RemoteHardDisable
 		fallthrough
@@ -1593,12 +2039,26 @@ func (lp *Loadpoint) Update(sitePower float64, autoCharge, batteryBuffered, batt
 	case mode == api.ModeOff:
 		err = lp.setLimit(0, true)
 
+	case ruleMatched:
+		err = lp.applyRuleAction(ruleAction)
+
+	// track scheduled soc regardless of pv and tariff
+	case scheduled && lp.vehicleSoc < float64(socScheduled):
+		lp.log.DEBUG.Printf("soc schedule: charging to %d%%", socScheduled)
+		err = lp.fastCharging()
+		lp.resetPhaseTimer()
+		lp.elapsePVTimer() // let PV mode disable immediately afterwards
+
+	// hold scheduled soc unless a plan is active
+	case scheduled && !plannerActive:
+		err = lp.setLimit(0, true)
+
 	// immediate charging
 	case mode == api.ModeNow:
 		err = lp.fastCharging()
 
 	// minimum or target charging
-	case lp.minSocNotReached() || plannerActive:
+	case lp.minSocNotReached() || plannerActive || guaranteed:
 		err = lp.fastCharging()
 		lp.resetPhaseTimer()
 		lp.elapsePVTimer() // let PV mode disable immediately afterwards
@@ -1616,7 +2076,7 @@ func (lp *Loadpoint) Update(sitePower float64, autoCharge, batteryBuffered, batt
 
 		var required bool // false
 		if targetCurrent == 0 && lp.vehicleClimateActive() {
-			targetCurrent = lp.GetMinCurrent()
+			targetCurrent = lp.effectiveMinCurrent()
 			required = true
 		}
 
@@ -57,8 +57,12 @@ type API interface {
 	SetTargetEnergy(float64)
 	// GetTargetSoc returns the charge target soc
 	GetTargetSoc() int
+	// GetReserveSoc returns the soc the vehicle is never discharged below
+	GetReserveSoc() int
 	// SetTargetSoc sets the charge target soc
 	SetTargetSoc(int)
+	// SetTarget sets the charge target soc and time atomically and returns the resulting plan
+	SetTarget(soc int, finishAt time.Time) (time.Duration, api.Rates, error)
 	// GetPlan creates a charging plan
 	GetPlan(targetTime time.Time, maxPower float64) (time.Duration, api.Rates, error)
 	// GetEnableThreshold gets the loadpoint enable threshold
@@ -70,6 +74,11 @@ type API interface {
 	// SetDisableThreshold sets loadpoint disable threshold
 	SetDisableThreshold(threshold float64)
 
+	// GetBoostRemaining returns the remaining boost duration
+	GetBoostRemaining() time.Duration
+	// Boost charges in now mode for the given duration, zero ends boost
+	Boost(time.Duration) error
+
 	// RemoteControl sets remote status demand
 	RemoteControl(string, RemoteDemand)
 
@@ -79,6 +88,8 @@ type API interface {
 
 	// HasChargeMeter determines if a physical charge meter is attached
 	HasChargeMeter() bool
+	// GetChargerInfo returns the charger's identity and diagnostic data if available
+	GetChargerInfo() (api.ChargerInfo, error)
 	// GetChargePower returns the current charging power
 	GetChargePower() float64
 	// GetChargePowerFlexibility returns the flexible amount of current charging power
@@ -95,6 +106,8 @@ type API interface {
 	GetMinPower() float64
 	// GetMaxPower returns the max charging power taking active phases into account
 	GetMaxPower() float64
+	// ResetSessionEnergy resets the session energy and cost counters
+	ResetSessionEnergy()
 
 	//
 	// charge progress
@@ -106,6 +119,8 @@ type API interface {
 	GetRemainingDuration() time.Duration
 	// GetRemainingEnergy is the remaining charge energy in Wh
 	GetRemainingEnergy() float64
+	// GetTimeToTarget is the computed duration to reach the charge target at the current charge power
+	GetTimeToTarget() time.Duration
 
 	//
 	// vehicles
@@ -113,8 +128,22 @@ type API interface {
 
 	// GetVehicle gets the active vehicle
 	GetVehicle() api.Vehicle
-	// SetVehicle sets the active vehicle
+	// SetVehicle manually assigns the active vehicle for the session, nil unassigns the vehicle
 	SetVehicle(vehicle api.Vehicle)
+	// GetVehicleManual returns true if the active vehicle has been assigned manually
+	GetVehicleManual() bool
+	// GetVehicleHealth returns the active vehicle's auxiliary diagnostic data if available
+	GetVehicleHealth() (api.VehicleHealth, error)
+	// GetVehicleFuel returns the active vehicle's fuel data if available
+	GetVehicleFuel() (api.VehicleFuel, error)
+	// GetVehicleCable returns the active vehicle's charge cable status if available
+	GetVehicleCable() (api.VehicleCable, error)
+	// GetVehicleTemperatures returns the active vehicle's temperatures if available and the climate lead time at the outside temperature
+	GetVehicleTemperatures() (api.VehicleTemperatures, time.Duration, error)
+	// GetVehicleDeparture returns the active vehicle's next scheduled departure if available and if it conflicts with the plan's target time
+	GetVehicleDeparture() (time.Time, bool, error)
 	// StartVehicleDetection allows triggering vehicle detection for debugging purposes
 	StartVehicleDetection()
+	// RefreshVehicle forces an immediate vehicle api refresh bypassing the cache once
+	RefreshVehicle() error
 }
@@ -35,6 +35,34 @@ func (m *MockAPI) EXPECT() *MockAPIMockRecorder {
 	return m.recorder
 }
 
+// Boost mocks base method.
+func (m *MockAPI) Boost(arg0 time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Boost", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Boost indicates an expected call of Boost.
+func (mr *MockAPIMockRecorder) Boost(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Boost", reflect.TypeOf((*MockAPI)(nil).Boost), arg0)
+}
+
+// GetBoostRemaining mocks base method.
+func (m *MockAPI) GetBoostRemaining() time.Duration {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBoostRemaining")
+	ret0, _ := ret[0].(time.Duration)
+	return ret0
+}
+
+// GetBoostRemaining indicates an expected call of GetBoostRemaining.
+func (mr *MockAPIMockRecorder) GetBoostRemaining() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBoostRemaining", reflect.TypeOf((*MockAPI)(nil).GetBoostRemaining))
+}
+
 // GetChargePower mocks base method.
 func (m *MockAPI) GetChargePower() float64 {
 	m.ctrl.T.Helper()
@@ -63,6 +91,21 @@ func (mr *MockAPIMockRecorder) GetChargePowerFlexibility() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChargePowerFlexibility", reflect.TypeOf((*MockAPI)(nil).GetChargePowerFlexibility))
 }
 
+// GetChargerInfo mocks base method.
+func (m *MockAPI) GetChargerInfo() (api.ChargerInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetChargerInfo")
+	ret0, _ := ret[0].(api.ChargerInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetChargerInfo indicates an expected call of GetChargerInfo.
+func (mr *MockAPIMockRecorder) GetChargerInfo() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChargerInfo", reflect.TypeOf((*MockAPI)(nil).GetChargerInfo))
+}
+
 // GetDisableThreshold mocks base method.
 func (m *MockAPI) GetDisableThreshold() float64 {
 	m.ctrl.T.Helper()
@@ -261,6 +304,20 @@ func (mr *MockAPIMockRecorder) GetRemainingEnergy() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRemainingEnergy", reflect.TypeOf((*MockAPI)(nil).GetRemainingEnergy))
 }
 
+// GetReserveSoc mocks base method.
+func (m *MockAPI) GetReserveSoc() int {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReserveSoc")
+	ret0, _ := ret[0].(int)
+	return ret0
+}
+
+// GetReserveSoc indicates an expected call of GetReserveSoc.
+func (mr *MockAPIMockRecorder) GetReserveSoc() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReserveSoc", reflect.TypeOf((*MockAPI)(nil).GetReserveSoc))
+}
+
 // GetStatus mocks base method.
 func (m *MockAPI) GetStatus() api.ChargeStatus {
 	m.ctrl.T.Helper()
@@ -317,6 +374,20 @@ func (mr *MockAPIMockRecorder) GetTargetTime() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTargetTime", reflect.TypeOf((*MockAPI)(nil).GetTargetTime))
 }
 
+// GetTimeToTarget mocks base method.
+func (m *MockAPI) GetTimeToTarget() time.Duration {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTimeToTarget")
+	ret0, _ := ret[0].(time.Duration)
+	return ret0
+}
+
+// GetTimeToTarget indicates an expected call of GetTimeToTarget.
+func (mr *MockAPIMockRecorder) GetTimeToTarget() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTimeToTarget", reflect.TypeOf((*MockAPI)(nil).GetTimeToTarget))
+}
+
 // GetVehicle mocks base method.
 func (m *MockAPI) GetVehicle() api.Vehicle {
 	m.ctrl.T.Helper()
@@ -331,6 +402,97 @@ func (mr *MockAPIMockRecorder) GetVehicle() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVehicle", reflect.TypeOf((*MockAPI)(nil).GetVehicle))
 }
 
+// GetVehicleCable mocks base method.
+func (m *MockAPI) GetVehicleCable() (api.VehicleCable, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVehicleCable")
+	ret0, _ := ret[0].(api.VehicleCable)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVehicleCable indicates an expected call of GetVehicleCable.
+func (mr *MockAPIMockRecorder) GetVehicleCable() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVehicleCable", reflect.TypeOf((*MockAPI)(nil).GetVehicleCable))
+}
+
+// GetVehicleDeparture mocks base method.
+func (m *MockAPI) GetVehicleDeparture() (time.Time, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVehicleDeparture")
+	ret0, _ := ret[0].(time.Time)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetVehicleDeparture indicates an expected call of GetVehicleDeparture.
+func (mr *MockAPIMockRecorder) GetVehicleDeparture() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVehicleDeparture", reflect.TypeOf((*MockAPI)(nil).GetVehicleDeparture))
+}
+
+// GetVehicleFuel mocks base method.
+func (m *MockAPI) GetVehicleFuel() (api.VehicleFuel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVehicleFuel")
+	ret0, _ := ret[0].(api.VehicleFuel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVehicleFuel indicates an expected call of GetVehicleFuel.
+func (mr *MockAPIMockRecorder) GetVehicleFuel() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVehicleFuel", reflect.TypeOf((*MockAPI)(nil).GetVehicleFuel))
+}
+
+// GetVehicleHealth mocks base method.
+func (m *MockAPI) GetVehicleHealth() (api.VehicleHealth, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVehicleHealth")
+	ret0, _ := ret[0].(api.VehicleHealth)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVehicleHealth indicates an expected call of GetVehicleHealth.
+func (mr *MockAPIMockRecorder) GetVehicleHealth() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVehicleHealth", reflect.TypeOf((*MockAPI)(nil).GetVehicleHealth))
+}
+
+// GetVehicleManual mocks base method.
+func (m *MockAPI) GetVehicleManual() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVehicleManual")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// GetVehicleManual indicates an expected call of GetVehicleManual.
+func (mr *MockAPIMockRecorder) GetVehicleManual() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVehicleManual", reflect.TypeOf((*MockAPI)(nil).GetVehicleManual))
+}
+
+// GetVehicleTemperatures mocks base method.
+func (m *MockAPI) GetVehicleTemperatures() (api.VehicleTemperatures, time.Duration, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVehicleTemperatures")
+	ret0, _ := ret[0].(api.VehicleTemperatures)
+	ret1, _ := ret[1].(time.Duration)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetVehicleTemperatures indicates an expected call of GetVehicleTemperatures.
+func (mr *MockAPIMockRecorder) GetVehicleTemperatures() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVehicleTemperatures", reflect.TypeOf((*MockAPI)(nil).GetVehicleTemperatures))
+}
+
 // HasChargeMeter mocks base method.
 func (m *MockAPI) HasChargeMeter() bool {
 	m.ctrl.T.Helper()
@@ -345,6 +507,20 @@ func (mr *MockAPIMockRecorder) HasChargeMeter() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasChargeMeter", reflect.TypeOf((*MockAPI)(nil).HasChargeMeter))
 }
 
+// RefreshVehicle mocks base method.
+func (m *MockAPI) RefreshVehicle() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RefreshVehicle")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RefreshVehicle indicates an expected call of RefreshVehicle.
+func (mr *MockAPIMockRecorder) RefreshVehicle() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshVehicle", reflect.TypeOf((*MockAPI)(nil).RefreshVehicle))
+}
+
 // RemoteControl mocks base method.
 func (m *MockAPI) RemoteControl(arg0 string, arg1 RemoteDemand) {
 	m.ctrl.T.Helper()
@@ -357,6 +533,18 @@ func (mr *MockAPIMockRecorder) RemoteControl(arg0, arg1 interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoteControl", reflect.TypeOf((*MockAPI)(nil).RemoteControl), arg0, arg1)
 }
 
+// ResetSessionEnergy mocks base method.
+func (m *MockAPI) ResetSessionEnergy() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ResetSessionEnergy")
+}
+
+// ResetSessionEnergy indicates an expected call of ResetSessionEnergy.
+func (mr *MockAPIMockRecorder) ResetSessionEnergy() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetSessionEnergy", reflect.TypeOf((*MockAPI)(nil).ResetSessionEnergy))
+}
+
 // SetDisableThreshold mocks base method.
 func (m *MockAPI) SetDisableThreshold(arg0 float64) {
 	m.ctrl.T.Helper()
@@ -455,6 +643,22 @@ func (mr *MockAPIMockRecorder) SetPriority(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetPriority", reflect.TypeOf((*MockAPI)(nil).SetPriority), arg0)
 }
 
+// SetTarget mocks base method.
+func (m *MockAPI) SetTarget(arg0 int, arg1 time.Time) (time.Duration, api.Rates, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetTarget", arg0, arg1)
+	ret0, _ := ret[0].(time.Duration)
+	ret1, _ := ret[1].(api.Rates)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// SetTarget indicates an expected call of SetTarget.
+func (mr *MockAPIMockRecorder) SetTarget(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTarget", reflect.TypeOf((*MockAPI)(nil).SetTarget), arg0, arg1)
+}
+
 // SetTargetEnergy mocks base method.
 func (m *MockAPI) SetTargetEnergy(arg0 float64) {
 	m.ctrl.T.Helper()
@@ -0,0 +1,6 @@
+package core
+
+// inactive returns true if charging is blocked outside the loadpoint's active windows
+func (lp *Loadpoint) inactive() bool {
+	return len(lp.ActiveWindows) > 0 && !lp.ActiveWindows.Contains(lp.clock.Now())
+}
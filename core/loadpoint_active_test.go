@@ -0,0 +1,76 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	evbus "github.com/asaskevich/EventBus"
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/rules"
+	"github.com/evcc-io/evcc/util"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestActiveWindows(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	charger := api.NewMockCharger(ctrl)
+
+	// monday
+	clck := clock.NewMock()
+	clck.Set(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+
+	Voltage = 100
+	lp := &Loadpoint{
+		log:            util.NewLogger("foo"),
+		bus:            evbus.New(),
+		clock:          clck,
+		charger:        charger,
+		wakeUpTimer:    NewTimer(),
+		MinCurrent:     minA,
+		MaxCurrent:     maxA,
+		phases:         3,
+		measuredPhases: 3,
+		status:         api.StatusC,
+		Mode:           api.ModeNow,
+		enabled:        true,
+		chargeCurrent:  minA,
+		ActiveWindows: rules.Windows{
+			{From: "17:00", To: "23:00", Days: []string{"mon", "tue", "wed", "thu", "fri"}},
+			{From: "09:00", To: "23:00", Days: []string{"sat", "sun"}},
+		},
+	}
+
+	// outside active window blocks now mode
+	require.True(t, lp.inactive())
+	charger.EXPECT().Enable(false).Return(nil)
+	require.NoError(t, lp.fastCharging())
+	assert.False(t, lp.enabled)
+
+	// boost and min charging are blocked, too
+	require.NoError(t, lp.setLimit(maxA, true))
+	assert.False(t, lp.enabled)
+
+	// weekday evening window charges
+	clck.Add(6 * time.Hour)
+	require.False(t, lp.inactive())
+	charger.EXPECT().MaxCurrent(int64(maxA)).Return(nil)
+	charger.EXPECT().Enable(true).Return(nil)
+	require.NoError(t, lp.fastCharging())
+	assert.True(t, lp.enabled)
+
+	// weekday window does not apply on weekends
+	clck.Set(time.Date(2024, 1, 6, 12, 0, 0, 0, time.UTC))
+	require.False(t, lp.inactive())
+	clck.Set(time.Date(2024, 1, 6, 8, 0, 0, 0, time.UTC))
+	require.True(t, lp.inactive())
+	charger.EXPECT().Enable(false).Return(nil)
+	require.NoError(t, lp.fastCharging())
+	assert.False(t, lp.enabled)
+
+	// no windows are always active
+	lp.ActiveWindows = nil
+	assert.False(t, lp.inactive())
+}
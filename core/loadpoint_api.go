@@ -7,6 +7,7 @@ import (
 
 	"github.com/evcc-io/evcc/api"
 	"github.com/evcc-io/evcc/core/loadpoint"
+	"github.com/evcc-io/evcc/core/session"
 	"github.com/evcc-io/evcc/core/wrapper"
 )
 
@@ -43,6 +44,17 @@ func (lp *Loadpoint) SetMode(mode api.ChargeMode) {
 
 	lp.log.DEBUG.Printf("set charge mode: %s", string(mode))
 
+	// manual mode change overrides the vehicle's default mode for the session
+	lp.modeManual = lp.status == api.StatusB || lp.status == api.StatusC
+
+	// explicit mode change ends boost
+	lp.stopBoost()
+
+	lp.setMode(mode)
+}
+
+// setMode sets loadpoint charge mode (no mutex)
+func (lp *Loadpoint) setMode(mode api.ChargeMode) {
 	// apply immediately
 	if lp.Mode != mode {
 		lp.Mode = mode
@@ -69,6 +81,11 @@ func (lp *Loadpoint) getChargedEnergy() float64 {
 	return lp.sessionEnergy.TotalWh()
 }
 
+// getChargedVehicleEnergy returns the charged energy in Wh that reached the vehicle battery after charge losses
+func (lp *Loadpoint) getChargedVehicleEnergy() float64 {
+	return lp.getChargedEnergy() * lp.chargeEfficiency(lp.GetVehicle())
+}
+
 // GetTargetEnergy returns loadpoint charge target energy
 func (lp *Loadpoint) GetTargetEnergy() float64 {
 	lp.Lock()
@@ -97,11 +114,11 @@ func (lp *Loadpoint) SetTargetEnergy(energy float64) {
 	}
 }
 
-// GetPriority returns the loadpoint priority
+// GetPriority returns the loadpoint priority adjusted by the user's presence
 func (lp *Loadpoint) GetPriority() int {
 	lp.Lock()
 	defer lp.Unlock()
-	return lp.Priority_
+	return lp.effectivePriority()
 }
 
 // SetPriority sets the loadpoint priority
@@ -114,6 +131,7 @@ func (lp *Loadpoint) SetPriority(prio int) {
 	if lp.Priority_ != prio {
 		lp.Priority_ = prio
 		lp.publish("priority", prio)
+		lp.publish("effectivePriority", lp.effectivePriority())
 	}
 }
 
@@ -229,6 +247,39 @@ func (lp *Loadpoint) SetTargetTime(finishAt time.Time) error {
 	return nil
 }
 
+// SetTarget sets target soc and time atomically if the target is reachable and returns the resulting plan
+func (lp *Loadpoint) SetTarget(soc int, finishAt time.Time) (time.Duration, api.Rates, error) {
+	if soc < 0 || soc > 100 {
+		return 0, nil, fmt.Errorf("invalid target soc: %d", soc)
+	}
+
+	if finishAt.IsZero() || !finishAt.After(lp.clock.Now()) {
+		return 0, nil, errors.New("timestamp is in the past")
+	}
+
+	lp.targetMux.Lock()
+	defer lp.targetMux.Unlock()
+
+	requiredDuration := lp.planRequiredDuration(soc, lp.GetMaxPower())
+	if available := lp.clock.Until(finishAt); requiredDuration > available {
+		return requiredDuration, nil, fmt.Errorf("target not reachable: requires %v, available %v", requiredDuration.Round(time.Minute), available.Round(time.Minute))
+	}
+
+	lp.Lock()
+	lp.log.DEBUG.Printf("set target: %d%% at %v", soc, finishAt.Round(time.Second).Local())
+
+	lp.setTargetSoc(soc)
+	lp.setTargetTime(finishAt)
+	lp.requestUpdate()
+	lp.persistVehicleSettings()
+	lp.Unlock()
+
+	plan, err := lp.planner.Plan(requiredDuration, finishAt)
+	plan.Sort()
+
+	return requiredDuration, plan, err
+}
+
 // setTargetTime sets the charge target time
 func (lp *Loadpoint) setTargetTime(finishAt time.Time) {
 	lp.targetTime = finishAt
@@ -304,6 +355,14 @@ func (lp *Loadpoint) HasChargeMeter() bool {
 	return lp.chargeMeter != nil && !isWrapped
 }
 
+// GetChargerInfo returns the charger's identity and diagnostic data. Chargers not providing these return empty data.
+func (lp *Loadpoint) GetChargerInfo() (api.ChargerInfo, error) {
+	if ci, ok := lp.charger.(api.ChargerInformer); ok {
+		return ci.ChargerInfo()
+	}
+	return api.ChargerInfo{}, nil
+}
+
 // GetChargePower returns the current charge power
 func (lp *Loadpoint) GetChargePower() float64 {
 	lp.Lock()
@@ -369,12 +428,12 @@ func (lp *Loadpoint) SetMaxCurrent(current float64) {
 
 // GetMinPower returns the min loadpoint power for a single phase
 func (lp *Loadpoint) GetMinPower() float64 {
-	return Voltage * lp.GetMinCurrent()
+	return Voltage * lp.effectiveMinCurrent()
 }
 
 // GetMaxPower returns the max loadpoint power taking vehicle capabilities and phase scaling into account
 func (lp *Loadpoint) GetMaxPower() float64 {
-	return Voltage * lp.GetMaxCurrent() * float64(lp.maxActivePhases())
+	return Voltage * lp.effectiveMaxCurrent() * float64(lp.maxActivePhases())
 }
 
 // GetPlanActive returns the active state of the planner
@@ -435,11 +494,83 @@ func (lp *Loadpoint) GetVehicle() api.Vehicle {
 	return lp.vehicle
 }
 
+// GetVehicleHealth returns the active vehicle's 12V battery and tire pressure data
+func (lp *Loadpoint) GetVehicleHealth() (api.VehicleHealth, error) {
+	vh, ok := lp.GetVehicle().(api.VehicleHealthReporter)
+	if !ok {
+		return api.VehicleHealth{}, nil
+	}
+
+	res, err := vh.Health()
+	if errors.Is(err, api.ErrNotAvailable) {
+		return api.VehicleHealth{}, nil
+	}
+
+	return res, err
+}
+
+// GetVehicleFuel returns the active plug-in hybrid vehicle's fuel level and ranges
+func (lp *Loadpoint) GetVehicleFuel() (api.VehicleFuel, error) {
+	vf, ok := lp.GetVehicle().(api.VehicleFuelReporter)
+	if !ok {
+		return api.VehicleFuel{}, nil
+	}
+
+	res, err := vf.Fuel()
+	if errors.Is(err, api.ErrNotAvailable) {
+		return api.VehicleFuel{}, nil
+	}
+
+	return res, err
+}
+
+// GetVehicleCable returns whether the charge cable is plugged into and locked by the active vehicle
+func (lp *Loadpoint) GetVehicleCable() (api.VehicleCable, error) {
+	vc, ok := lp.GetVehicle().(api.VehicleCableReporter)
+	if !ok {
+		return api.VehicleCable{}, nil
+	}
+
+	res, err := vc.Cable()
+	if errors.Is(err, api.ErrNotAvailable) {
+		return api.VehicleCable{}, nil
+	}
+
+	return res, err
+}
+
+// GetVehicleDeparture returns the active vehicle's next departure and whether the plan finishes after it
+func (lp *Loadpoint) GetVehicleDeparture() (time.Time, bool, error) {
+	vd, ok := lp.GetVehicle().(api.VehicleDepartureTimer)
+	if !ok {
+		return time.Time{}, false, nil
+	}
+
+	res, err := vd.Departure()
+	if errors.Is(err, api.ErrNotAvailable) {
+		return time.Time{}, false, nil
+	}
+	if err != nil {
+		return time.Time{}, false, err
+	}
+
+	targetTime := lp.GetTargetTime()
+	conflict := !targetTime.IsZero() && res.Before(targetTime)
+	if conflict {
+		lp.log.WARN.Printf("vehicle departure at %v before plan target time %v", res.Round(time.Minute).Local(), targetTime.Round(time.Minute).Local())
+	}
+
+	return res, conflict, nil
+}
+
 // SetVehicle sets the active vehicle
 func (lp *Loadpoint) SetVehicle(vehicle api.Vehicle) {
 	// set desired vehicle (protected by lock, no locking here)
 	lp.setActiveVehicle(vehicle)
 
+	// keep vehicle until disconnected or detection is restarted
+	lp.setVehicleManual(true)
+
 	lp.vehicleMux.Lock()
 	defer lp.vehicleMux.Unlock()
 
@@ -447,9 +578,17 @@ func (lp *Loadpoint) SetVehicle(vehicle api.Vehicle) {
 	lp.stopVehicleDetection()
 }
 
+// GetVehicleManual returns true if the active vehicle has been assigned manually
+func (lp *Loadpoint) GetVehicleManual() bool {
+	lp.vehicleMux.Lock()
+	defer lp.vehicleMux.Unlock()
+	return lp.vehicleManual
+}
+
 // StartVehicleDetection allows triggering vehicle detection for debugging purposes
 func (lp *Loadpoint) StartVehicleDetection() {
-	// reset vehicle
+	// reset vehicle and manual assignment
+	lp.setVehicleManual(false)
 	lp.setActiveVehicle(nil)
 
 	lp.Lock()
@@ -458,3 +597,39 @@ func (lp *Loadpoint) StartVehicleDetection() {
 	// start auto-detect
 	lp.startVehicleDetection()
 }
+
+// ResetSessionEnergy resets the charged energy and cost counters of the current session
+func (lp *Loadpoint) ResetSessionEnergy() {
+	// read devices before locking
+	var chargedEnergy float64
+	if lp.chargeRater != nil {
+		if f, err := lp.chargeRater.ChargedEnergy(); err == nil {
+			chargedEnergy = f
+		}
+	}
+	meterTotal := lp.chargeMeterTotal()
+
+	lp.Lock()
+	defer lp.Unlock()
+
+	lp.log.DEBUG.Println("reset session energy")
+
+	// continue counting from current charge rater value
+	lp.chargedAtStartup = chargedEnergy
+	lp.sessionEnergy.Reset()
+
+	lp.sessionEnergy.Publish("session", lp)
+	lp.publish("chargedEnergy", lp.sessionEnergy.TotalWh())
+
+	lp.updateSession(func(session *session.Session) {
+		session.ChargedEnergy = 0
+		session.SolarPercentage = nil
+		session.Price = nil
+		session.PricePerKWh = nil
+		session.Co2PerKWh = nil
+
+		if meterTotal > 0 {
+			session.MeterStart = &meterTotal
+		}
+	})
+}
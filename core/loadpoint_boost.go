@@ -0,0 +1,85 @@
+package core
+
+import (
+	"errors"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+)
+
+// Boost charges in now mode for the given duration before reverting to the previous mode.
+// A zero duration ends an active boost.
+func (lp *Loadpoint) Boost(d time.Duration) error {
+	lp.Lock()
+	defer lp.Unlock()
+
+	if d < 0 {
+		return errors.New("invalid boost duration")
+	}
+
+	if d == 0 {
+		if !lp.boostUntil.IsZero() {
+			lp.log.DEBUG.Println("boost: cancelled")
+			mode := lp.boostMode
+			lp.stopBoost()
+			lp.setMode(mode)
+		}
+		return nil
+	}
+
+	// keep original mode when extending an active boost
+	if lp.boostUntil.IsZero() {
+		lp.boostMode = lp.Mode
+	}
+
+	lp.boostUntil = lp.clock.Now().Add(d)
+	lp.log.DEBUG.Printf("boost: %v until %v", d, lp.boostUntil.Round(time.Second).Local())
+
+	lp.setMode(api.ModeNow)
+	lp.publish(boostRemaining, d)
+
+	return nil
+}
+
+// GetBoostRemaining returns the remaining boost duration
+func (lp *Loadpoint) GetBoostRemaining() time.Duration {
+	lp.Lock()
+	defer lp.Unlock()
+	return lp.remainingBoost()
+}
+
+// remainingBoost returns the remaining boost duration (no mutex)
+func (lp *Loadpoint) remainingBoost() time.Duration {
+	if lp.boostUntil.IsZero() {
+		return 0
+	}
+	return max(0, lp.boostUntil.Sub(lp.clock.Now())).Round(time.Second)
+}
+
+// stopBoost clears the boost without changing the mode (no mutex)
+func (lp *Loadpoint) stopBoost() {
+	if !lp.boostUntil.IsZero() {
+		lp.boostUntil = time.Time{}
+		lp.publish(boostRemaining, time.Duration(0))
+	}
+}
+
+// updateBoost reverts to the previous mode once boost has expired
+func (lp *Loadpoint) updateBoost() {
+	lp.Lock()
+	defer lp.Unlock()
+
+	if lp.boostUntil.IsZero() {
+		return
+	}
+
+	remaining := lp.remainingBoost()
+	lp.publish(boostRemaining, remaining)
+
+	if remaining == 0 {
+		lp.log.DEBUG.Printf("boost: expired, reverting to %s mode", lp.boostMode)
+		mode := lp.boostMode
+		lp.stopBoost()
+		lp.setMode(mode)
+	}
+}
@@ -0,0 +1,103 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	evbus "github.com/asaskevich/EventBus"
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/util"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBoost(t *testing.T) {
+	clock := clock.NewMock()
+
+	lp := &Loadpoint{
+		log:   util.NewLogger("foo"),
+		clock: clock,
+		Mode:  api.ModePV,
+	}
+
+	require.Error(t, lp.Boost(-time.Minute))
+
+	// boost
+	require.NoError(t, lp.Boost(30*time.Minute))
+	assert.Equal(t, api.ModeNow, lp.GetMode())
+	assert.Equal(t, 30*time.Minute, lp.GetBoostRemaining())
+
+	// extend boost, keep original mode
+	clock.Add(10 * time.Minute)
+	lp.updateBoost()
+	assert.Equal(t, 20*time.Minute, lp.GetBoostRemaining())
+
+	require.NoError(t, lp.Boost(30*time.Minute))
+	assert.Equal(t, 30*time.Minute, lp.GetBoostRemaining())
+
+	// auto-revert
+	clock.Add(30 * time.Minute)
+	lp.updateBoost()
+	assert.Equal(t, api.ModePV, lp.GetMode())
+	assert.Equal(t, time.Duration(0), lp.GetBoostRemaining())
+
+	// cancel
+	require.NoError(t, lp.Boost(30*time.Minute))
+	require.NoError(t, lp.Boost(0))
+	assert.Equal(t, api.ModePV, lp.GetMode())
+
+	// explicit mode change ends boost
+	require.NoError(t, lp.Boost(30*time.Minute))
+	lp.SetMode(api.ModeMinPV)
+	assert.Equal(t, time.Duration(0), lp.GetBoostRemaining())
+
+	clock.Add(time.Hour)
+	lp.updateBoost()
+	assert.Equal(t, api.ModeMinPV, lp.GetMode())
+}
+
+func TestBoostCharging(t *testing.T) {
+	clock := clock.NewMock()
+	ctrl := gomock.NewController(t)
+	charger := api.NewMockCharger(ctrl)
+
+	lp := &Loadpoint{
+		log:           util.NewLogger("foo"),
+		bus:           evbus.New(),
+		clock:         clock,
+		charger:       charger,
+		chargeMeter:   &Null{}, // silence nil panics
+		chargeRater:   &Null{}, // silence nil panics
+		chargeTimer:   &Null{}, // silence nil panics
+		wakeUpTimer:   NewTimer(),
+		sessionEnergy: NewEnergyMetrics(),
+		MinCurrent:    minA,
+		MaxCurrent:    maxA,
+		status:        api.StatusC,
+		Mode:          api.ModeOff,
+	}
+
+	attachListeners(t, lp)
+
+	lp.enabled = true
+	lp.chargeCurrent = float64(minA)
+
+	require.NoError(t, lp.Boost(15*time.Minute))
+
+	// charge at configured max current
+	charger.EXPECT().Enabled().Return(lp.enabled, nil)
+	charger.EXPECT().Status().Return(api.StatusC, nil)
+	charger.EXPECT().MaxCurrent(int64(maxA)).Return(nil)
+	lp.Update(500, false, false, false, 0, nil, nil)
+
+	// revert to off after boost
+	clock.Add(15 * time.Minute)
+	charger.EXPECT().Enabled().Return(lp.enabled, nil)
+	charger.EXPECT().Status().Return(api.StatusC, nil)
+	charger.EXPECT().Enable(false).Return(nil)
+	lp.Update(500, false, false, false, 0, nil, nil)
+
+	assert.Equal(t, api.ModeOff, lp.GetMode())
+}
@@ -0,0 +1,26 @@
+package core
+
+import "github.com/evcc-io/evcc/api"
+
+// readChargerCurrentBounds reads the charger's current bounds and warns if the configured currents exceed them
+func (lp *Loadpoint) readChargerCurrentBounds() {
+	cb, ok := lp.charger.(api.CurrentBoundsReporter)
+	if !ok {
+		return
+	}
+
+	minCurrent, maxCurrent, err := cb.CurrentBounds()
+	if err != nil {
+		lp.log.WARN.Printf("charger current bounds: %v", err)
+		return
+	}
+
+	lp.chargerMinCurrent, lp.chargerMaxCurrent = minCurrent, maxCurrent
+
+	if minCurrent > 0 && lp.MinCurrent < minCurrent {
+		lp.log.WARN.Printf("min current %.3gA below charger minimum %.3gA", lp.MinCurrent, minCurrent)
+	}
+	if maxCurrent > 0 && lp.MaxCurrent > maxCurrent {
+		lp.log.WARN.Printf("max current %.3gA exceeds charger maximum %.3gA", lp.MaxCurrent, maxCurrent)
+	}
+}
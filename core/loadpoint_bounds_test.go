@@ -0,0 +1,51 @@
+package core
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/util"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChargerCurrentBounds(t *testing.T) {
+	tc := []struct {
+		chargerMin, chargerMax float64
+		err                    error
+		effMin, effMax         float64
+	}{
+		{0, 0, nil, 6, 16},                // not limited
+		{0, 13, nil, 6, 13},               // max clamped
+		{8, 32, nil, 8, 16},               // min clamped
+		{20, 32, nil, 16, 16},             // min never exceeds max
+		{8, 13, errors.New("foo"), 6, 16}, // bounds not available
+	}
+
+	for _, tc := range tc {
+		ctrl := gomock.NewController(t)
+
+		charger := &struct {
+			*api.MockCharger
+			*api.MockCurrentBoundsReporter
+		}{
+			api.NewMockCharger(ctrl),
+			api.NewMockCurrentBoundsReporter(ctrl),
+		}
+
+		charger.MockCurrentBoundsReporter.EXPECT().CurrentBounds().Return(tc.chargerMin, tc.chargerMax, tc.err)
+
+		lp := &Loadpoint{
+			log:        util.NewLogger("foo"),
+			charger:    charger,
+			MinCurrent: 6,
+			MaxCurrent: 16,
+		}
+
+		lp.readChargerCurrentBounds()
+
+		assert.Equal(t, tc.effMin, lp.effectiveMinCurrent(), tc)
+		assert.Equal(t, tc.effMax, lp.effectiveMaxCurrent(), tc)
+	}
+}
@@ -0,0 +1,15 @@
+package core
+
+import "github.com/evcc-io/evcc/api"
+
+// publishChargerConnection publishes the connection state of chargers connecting to evcc, e.g. via ocpp (no mutex)
+func (lp *Loadpoint) publishChargerConnection() {
+	c, ok := lp.charger.(api.ConnectionMonitor)
+	if !ok {
+		return
+	}
+
+	connected, lastSeen := c.Connection()
+	lp.publish("chargerConnected", connected)
+	lp.publish("chargerLastSeen", lastSeen)
+}
@@ -0,0 +1,44 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/util"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+type connectionCharger struct {
+	*api.MockCharger
+	connected bool
+	lastSeen  time.Time
+}
+
+func (c *connectionCharger) Connection() (bool, time.Time) {
+	return c.connected, c.lastSeen
+}
+
+func TestChargerConnection(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	uiChan := make(chan util.Param, 2)
+
+	lastSeen := time.Now().Add(-time.Minute)
+	charger := &connectionCharger{MockCharger: api.NewMockCharger(ctrl), connected: true, lastSeen: lastSeen}
+
+	lp := &Loadpoint{
+		log:     util.NewLogger("foo"),
+		charger: charger,
+		uiChan:  uiChan,
+	}
+
+	lp.publishChargerConnection()
+	assert.Equal(t, util.Param{Key: "chargerConnected", Val: true}, <-uiChan)
+	assert.Equal(t, util.Param{Key: "chargerLastSeen", Val: lastSeen}, <-uiChan)
+
+	// chargers without connection monitoring publish nothing
+	lp.charger = api.NewMockCharger(ctrl)
+	lp.publishChargerConnection()
+	assert.Empty(t, uiChan)
+}
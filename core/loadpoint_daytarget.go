@@ -0,0 +1,71 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// DayTargetConfig is the default target soc per day type. Holidays are treated as weekend days.
+type DayTargetConfig struct {
+	Weekday  int      `mapstructure:"weekday"`  // default target soc monday to friday (%), disabled if zero
+	Weekend  int      `mapstructure:"weekend"`  // default target soc on saturday, sunday and holidays (%), disabled if zero
+	Holidays []string `mapstructure:"holidays"` // holiday dates (yyyy-mm-dd)
+}
+
+// dayTargetLayout is the holiday date format
+const dayTargetLayout = time.DateOnly
+
+// Validate validates the day target configuration
+func (c DayTargetConfig) Validate() error {
+	if c.Weekday < 0 || c.Weekday > 100 || c.Weekend < 0 || c.Weekend > 100 {
+		return errors.New("weekday and weekend must be between 0 and 100")
+	}
+	for _, d := range c.Holidays {
+		if _, err := time.Parse(dayTargetLayout, d); err != nil {
+			return fmt.Errorf("invalid holiday: %s", d)
+		}
+	}
+	return nil
+}
+
+// weekend returns true if the day is a weekend day or holiday
+func (c DayTargetConfig) weekend(ts time.Time) bool {
+	return ts.Weekday() == time.Saturday || ts.Weekday() == time.Sunday ||
+		slices.Contains(c.Holidays, ts.Format(dayTargetLayout))
+}
+
+// Target returns the default target soc of the day
+func (c DayTargetConfig) Target(ts time.Time) int {
+	if c.weekend(ts) {
+		return c.Weekend
+	}
+	return c.Weekday
+}
+
+// applyDayTarget sets the default target soc once per day. Targets set during the day are kept until
+// the next day, days with a plan are skipped since the plan's target soc takes precedence.
+func (lp *Loadpoint) applyDayTarget() {
+	now := lp.clock.Now().Local()
+	day := now.Format(dayTargetLayout)
+
+	lp.Lock()
+	defer lp.Unlock()
+
+	if lp.dayTargetApplied == day || !lp.targetTime.IsZero() {
+		return
+	}
+
+	soc := lp.Soc.Days.Target(now)
+	if soc == 0 {
+		return
+	}
+
+	lp.dayTargetApplied = day
+
+	if lp.Soc.target != soc {
+		lp.log.DEBUG.Printf("day target soc: %d%%", soc)
+		lp.setTargetSoc(soc)
+	}
+}
@@ -0,0 +1,93 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDayTarget(t *testing.T) {
+	clck := clock.NewMock()
+
+	lp := &Loadpoint{
+		log:   util.NewLogger("foo"),
+		clock: clck,
+		Soc: SocConfig{
+			Days: DayTargetConfig{
+				Weekday:  80,
+				Weekend:  100,
+				Holidays: []string{"2024-05-01"},
+			},
+		},
+	}
+
+	// monday to sunday including wednesday holiday
+	expected := []struct {
+		day    time.Weekday
+		target int
+	}{
+		{time.Monday, 80},
+		{time.Tuesday, 80},
+		{time.Wednesday, 100},
+		{time.Thursday, 80},
+		{time.Friday, 80},
+		{time.Saturday, 100},
+		{time.Sunday, 100},
+	}
+
+	for i, tc := range expected {
+		clck.Set(time.Date(2024, 4, 29+i, 7, 0, 0, 0, time.Local))
+		assert.Equal(t, tc.day, clck.Now().Weekday())
+
+		lp.applyDayTarget()
+		assert.Equal(t, tc.target, lp.Soc.target, tc.day)
+	}
+}
+
+func TestDayTargetOverride(t *testing.T) {
+	clck := clock.NewMock()
+	clck.Set(time.Date(2024, 4, 29, 7, 0, 0, 0, time.Local)) // monday
+
+	lp := &Loadpoint{
+		log:   util.NewLogger("foo"),
+		clock: clck,
+		Soc: SocConfig{
+			Days: DayTargetConfig{Weekday: 80, Weekend: 100},
+		},
+	}
+
+	lp.applyDayTarget()
+	assert.Equal(t, 80, lp.Soc.target)
+
+	// manual target is kept for the day
+	lp.setTargetSoc(90)
+	clck.Add(time.Hour)
+	lp.applyDayTarget()
+	assert.Equal(t, 90, lp.Soc.target)
+
+	// default is applied next day
+	clck.Add(24 * time.Hour)
+	lp.applyDayTarget()
+	assert.Equal(t, 80, lp.Soc.target)
+
+	// plan overrides the default
+	lp.targetTime = clck.Now().Add(48 * time.Hour)
+	lp.Soc.target = 60
+	clck.Add(24 * time.Hour)
+	lp.applyDayTarget()
+	assert.Equal(t, 60, lp.Soc.target)
+
+	// default is applied once the plan is cleared
+	lp.targetTime = time.Time{}
+	lp.applyDayTarget()
+	assert.Equal(t, 80, lp.Soc.target)
+}
+
+func TestDayTargetValidate(t *testing.T) {
+	assert.NoError(t, DayTargetConfig{Weekday: 80, Weekend: 100, Holidays: []string{"2024-12-25"}}.Validate())
+	assert.Error(t, DayTargetConfig{Weekday: 120}.Validate())
+	assert.Error(t, DayTargetConfig{Holidays: []string{"25.12.2024"}}.Validate())
+}
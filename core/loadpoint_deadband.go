@@ -0,0 +1,43 @@
+package core
+
+// targetSocHysteresis is the soc drop below the stop threshold required to resume charging,
+// preventing soc jitter from restarting a session stopped within the deadband
+const targetSocHysteresis = 1.0
+
+// targetSocStopThreshold returns the soc at which charging is stopped before reaching the target.
+// While charging, the soc expected to be gained during the margin is subtracted (no mutex).
+func (lp *Loadpoint) targetSocStopThreshold() float64 {
+	threshold := float64(lp.Soc.target) - lp.Soc.Deadband
+
+	if lp.Soc.Margin > 0 && lp.chargePower > 0 && lp.charging() {
+		if v := lp.GetVehicle(); v != nil && v.Capacity() > 0 {
+			threshold -= lp.chargePower * lp.Soc.Margin.Hours() * lp.chargeEfficiency(v) / (1e3 * v.Capacity()) * 100
+		}
+	}
+
+	return threshold
+}
+
+// targetSocDeadbandReached checks if the soc is within deadband and margin of the target.
+// Once reached, charging only resumes if soc drops below the stop threshold by more than the hysteresis
+// or the target is changed (no mutex).
+func (lp *Loadpoint) targetSocDeadbandReached() bool {
+	if lp.Soc.Deadband == 0 && lp.Soc.Margin == 0 {
+		return lp.vehicleSoc >= float64(lp.Soc.target)
+	}
+
+	if lp.socStopTarget == lp.Soc.target && lp.vehicleSoc >= lp.socStopThreshold-targetSocHysteresis {
+		return true
+	}
+
+	lp.socStopTarget = 0
+
+	if threshold := lp.targetSocStopThreshold(); lp.vehicleSoc >= threshold {
+		lp.log.DEBUG.Printf("vehicle soc %.1f%% within deadband of %d%% target soc", lp.vehicleSoc, lp.Soc.target)
+		lp.socStopTarget = lp.Soc.target
+		lp.socStopThreshold = threshold
+		return true
+	}
+
+	return false
+}
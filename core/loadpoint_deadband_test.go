@@ -0,0 +1,108 @@
+package core
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/util"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTargetSocDeadbandOvershoot(t *testing.T) {
+	const (
+		capacity = 50.0 // kWh
+		power    = 11e3 // W
+		cycle    = 30 * time.Second
+		poll     = 10   // vehicle reports soc every poll cycles
+		start    = 70.0 // actual soc at start
+		target   = 80
+	)
+
+	tc := []struct {
+		deadband  float64
+		margin    time.Duration
+		overshoot float64 // max overshoot
+	}{
+		{0, 0, 2},            // overshoot by soc gained until next poll
+		{1, 0, 1},            // deadband compensates polling lag
+		{1, poll * cycle, 0}, // margin compensates soc gained until next poll
+	}
+
+	for _, tc := range tc {
+		t.Logf("%+v", tc)
+
+		ctrl := gomock.NewController(t)
+		vehicle := api.NewMockVehicle(ctrl)
+		vehicle.EXPECT().Capacity().Return(capacity).AnyTimes()
+
+		lp := &Loadpoint{
+			log:         util.NewLogger("foo"),
+			vehicle:     vehicle,
+			status:      api.StatusC,
+			chargePower: power,
+			Soc: SocConfig{
+				target:   target,
+				Deadband: tc.deadband,
+				Margin:   tc.margin,
+			},
+		}
+
+		// soc gained per cycle
+		gain := power * cycle.Hours() * 0.9 / (1e3 * capacity) * 100
+
+		actual := start
+		for i := 0; !lp.targetSocReached(); i++ {
+			// vehicle reports truncated soc with polling lag
+			if i%poll == 0 {
+				lp.vehicleSoc = math.Trunc(actual)
+			}
+
+			actual += gain
+			assert.Less(t, actual, 100.0)
+		}
+
+		assert.LessOrEqual(t, actual-target, tc.overshoot)
+		assert.Greater(t, actual, target-tc.deadband-3)
+	}
+}
+
+func TestTargetSocDeadbandHysteresis(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	vehicle := api.NewMockVehicle(ctrl)
+	vehicle.EXPECT().Capacity().Return(50.0).AnyTimes()
+
+	lp := &Loadpoint{
+		log:     util.NewLogger("foo"),
+		vehicle: vehicle,
+		status:  api.StatusB,
+		Soc: SocConfig{
+			target:   80,
+			Deadband: 1,
+		},
+	}
+
+	tc := []struct {
+		soc    float64
+		target int
+		res    bool
+	}{
+		{78, 80, false},
+		{79, 80, true},   // within deadband
+		{78.5, 80, true}, // jitter, keep stopped
+		{78.1, 80, true},
+		{77.9, 80, false}, // dropped below hysteresis
+		{78.5, 80, false},
+		{79, 80, true},
+		{79, 81, false}, // target changed
+		{80, 81, true},
+	}
+
+	for _, tc := range tc {
+		lp.Soc.target = tc.target
+		lp.vehicleSoc = tc.soc
+		assert.Equal(t, tc.res, lp.targetSocReached(), "%+v", tc)
+	}
+}
@@ -0,0 +1,18 @@
+package core
+
+// setDemandResponseLimit applies the site's demand response limit, empty if no event is active (no mutex)
+func (lp *Loadpoint) setDemandResponseLimit(limit string) {
+	lp.demandResponseLimit = limit
+}
+
+// demandResponseCurrent returns the charge current permitted during a demand response event (no mutex)
+func (lp *Loadpoint) demandResponseCurrent(chargeCurrent float64) float64 {
+	switch lp.demandResponseLimit {
+	case demandResponseOff:
+		return 0
+	case demandResponseMin:
+		return min(chargeCurrent, lp.effectiveMinCurrent())
+	default:
+		return chargeCurrent
+	}
+}
@@ -0,0 +1,13 @@
+package core
+
+// disconnectAdvisory notifies if the vehicle is disconnected below the disconnect soc.
+// The advisory does not prevent disconnecting.
+func (lp *Loadpoint) disconnectAdvisory() {
+	if lp.DisconnectSoc == 0 || lp.vehicleSoc == 0 || lp.vehicleSoc >= float64(lp.DisconnectSoc) {
+		return
+	}
+
+	lp.log.WARN.Printf("vehicle disconnected at %.0f%% below %d%%", lp.vehicleSoc, lp.DisconnectSoc)
+	lp.publish("disconnectSoc", lp.DisconnectSoc)
+	lp.pushEvent(evVehicleDisconnectLow)
+}
@@ -0,0 +1,42 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/evcc-io/evcc/push"
+	"github.com/evcc-io/evcc/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDisconnectAdvisory(t *testing.T) {
+	tc := []struct {
+		disconnectSoc int
+		soc           float64
+		event         bool
+	}{
+		{0, 20, false},  // disabled
+		{50, 0, false},  // soc unknown
+		{50, 20, true},  // below threshold
+		{50, 50, false}, // at threshold
+		{50, 80, false}, // above threshold
+	}
+
+	for _, tc := range tc {
+		pushChan := make(chan push.Event, 1)
+
+		lp := &Loadpoint{
+			log:           util.NewLogger("foo"),
+			pushChan:      pushChan,
+			DisconnectSoc: tc.disconnectSoc,
+			vehicleSoc:    tc.soc,
+		}
+
+		lp.disconnectAdvisory()
+
+		if tc.event {
+			assert.Equal(t, push.Event{Event: evVehicleDisconnectLow}, <-pushChan, tc)
+		} else {
+			assert.Empty(t, pushChan, tc)
+		}
+	}
+}
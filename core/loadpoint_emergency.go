@@ -0,0 +1,21 @@
+package core
+
+// emergencyStopped returns true if the site's emergency stop is engaged
+func (lp *Loadpoint) emergencyStopped() bool {
+	lp.Lock()
+	defer lp.Unlock()
+	return lp.emergencyStop
+}
+
+// setEmergencyStop applies the site's emergency stop and immediately disables the charger while engaged
+func (lp *Loadpoint) setEmergencyStop(active bool) {
+	lp.Lock()
+	lp.emergencyStop = active
+	lp.Unlock()
+
+	if active && lp.enabled {
+		if err := lp.setLimit(0, true); err != nil {
+			lp.log.ERROR.Printf("emergency stop: %v", err)
+		}
+	}
+}
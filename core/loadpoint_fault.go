@@ -0,0 +1,93 @@
+package core
+
+import (
+	"errors"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/provider"
+)
+
+// FaultRecoveryConfig defines the automatic recovery of chargers from fault states
+type FaultRecoveryConfig struct {
+	Reset   *provider.Config `mapstructure:"reset"`   // bool setter resetting the fault, e.g. a modbus clear-fault coil, defaults to the charger's reset
+	Retries int              `mapstructure:"retries"` // recovery attempts per fault, recovery is disabled if zero
+	Delay   time.Duration    `mapstructure:"delay"`   // wait time before each recovery attempt
+}
+
+// Validate validates the fault recovery configuration
+func (c FaultRecoveryConfig) Validate() error {
+	if c.Retries < 0 || c.Delay < 0 {
+		return errors.New("retries and delay must not be negative")
+	}
+	return nil
+}
+
+// configureFaultReset creates the fault reset action, the configured action takes precedence over the charger's reset
+func (lp *Loadpoint) configureFaultReset() error {
+	if c := lp.FaultRecovery.Reset; c != nil {
+		set, err := provider.NewBoolSetterFromConfig("reset", *c)
+		if err != nil {
+			return err
+		}
+
+		lp.faultResetS = func() error { return set(true) }
+		return nil
+	}
+
+	if c, ok := lp.charger.(api.FaultResetter); ok {
+		lp.faultResetS = c.ResetFault
+	}
+
+	return nil
+}
+
+// chargerFaulted returns true if the charger status indicates a vehicle or EVSE fault
+func chargerFaulted(status api.ChargeStatus) bool {
+	return status == api.StatusE || status == api.StatusF
+}
+
+// updateFault tracks the charger fault state and attempts recovery up to the configured retries.
+// Communication errors without status leave the fault state unchanged (no mutex).
+func (lp *Loadpoint) updateFault(status api.ChargeStatus, err error) {
+	switch {
+	case chargerFaulted(status):
+		if !lp.fault {
+			lp.log.WARN.Printf("charger fault: %s %v", status, err)
+			lp.fault = true
+			lp.faultAttempts = 0
+			lp.faultUpdated = lp.clock.Now()
+			lp.publish("chargerFault", true)
+			lp.publish("chargerFaultRecoveries", 0)
+		}
+
+		lp.recoverFault()
+
+	case lp.fault && status != api.StatusNone && err == nil:
+		lp.log.INFO.Printf("charger fault cleared after %d recovery attempts", lp.faultAttempts)
+		lp.fault = false
+		lp.publish("chargerFault", false)
+	}
+}
+
+// recoverFault resets the charger once the delay since the fault or the previous attempt has elapsed (no mutex)
+func (lp *Loadpoint) recoverFault() {
+	if lp.faultResetS == nil || lp.faultAttempts >= lp.FaultRecovery.Retries ||
+		lp.clock.Since(lp.faultUpdated) < lp.FaultRecovery.Delay {
+		return
+	}
+
+	lp.faultAttempts++
+	lp.faultUpdated = lp.clock.Now()
+	lp.publish("chargerFaultRecoveries", lp.faultAttempts)
+
+	lp.log.WARN.Printf("charger fault: recovery attempt %d/%d", lp.faultAttempts, lp.FaultRecovery.Retries)
+
+	if err := lp.faultResetS(); err != nil {
+		lp.log.ERROR.Printf("charger fault: recovery: %v", err)
+	}
+
+	if lp.faultAttempts == lp.FaultRecovery.Retries {
+		lp.log.ERROR.Println("charger fault: recovery attempts exhausted")
+	}
+}
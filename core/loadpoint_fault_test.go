@@ -0,0 +1,92 @@
+package core
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/util"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+type faultResetCharger struct {
+	*api.MockCharger
+	resets int
+	err    error
+}
+
+func (c *faultResetCharger) ResetFault() error {
+	c.resets++
+	return c.err
+}
+
+func TestFaultRecovery(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	clck := clock.NewMock()
+
+	charger := &faultResetCharger{MockCharger: api.NewMockCharger(ctrl)}
+
+	lp := &Loadpoint{
+		log:     util.NewLogger("foo"),
+		clock:   clck,
+		charger: charger,
+		FaultRecovery: FaultRecoveryConfig{
+			Retries: 2,
+			Delay:   time.Minute,
+		},
+	}
+	assert.NoError(t, lp.configureFaultReset())
+
+	fault := errors.New("chargepoint status: GroundFailure")
+
+	// fault detected, recovery after delay
+	lp.updateFault(api.StatusF, fault)
+	assert.True(t, lp.fault)
+	assert.Equal(t, 0, charger.resets)
+
+	clck.Add(time.Minute)
+	lp.updateFault(api.StatusF, fault)
+	assert.Equal(t, 1, charger.resets)
+	assert.Equal(t, 1, lp.faultAttempts)
+
+	// communication errors keep the fault
+	lp.updateFault(api.StatusNone, api.ErrTimeout)
+	assert.True(t, lp.fault)
+
+	// charger recovers
+	lp.updateFault(api.StatusB, nil)
+	assert.False(t, lp.fault)
+
+	// retry limit
+	charger.resets = 0
+	for i := 0; i < 5; i++ {
+		lp.updateFault(api.StatusE, nil)
+		clck.Add(time.Minute)
+	}
+	assert.Equal(t, 2, charger.resets)
+	assert.True(t, lp.fault)
+}
+
+func TestFaultRecoveryDisabled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	clck := clock.NewMock()
+
+	charger := &faultResetCharger{MockCharger: api.NewMockCharger(ctrl)}
+
+	lp := &Loadpoint{
+		log:     util.NewLogger("foo"),
+		clock:   clck,
+		charger: charger,
+	}
+	assert.NoError(t, lp.configureFaultReset())
+
+	lp.updateFault(api.StatusF, nil)
+	clck.Add(time.Hour)
+	lp.updateFault(api.StatusF, nil)
+
+	assert.True(t, lp.fault)
+	assert.Equal(t, 0, charger.resets)
+}
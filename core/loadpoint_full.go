@@ -0,0 +1,79 @@
+package core
+
+import (
+	"errors"
+	"time"
+)
+
+// FullConfig defines when a vehicle is treated as full although it never reports 100% soc or keeps tapering
+type FullConfig struct {
+	Soc     float64       // treat vehicle as full at this soc (%)
+	Current float64       // treat vehicle as full if the charge current stays below this value (A)
+	Delay   time.Duration // charge current must stay below the taper current for this long
+}
+
+// Validate validates the full configuration
+func (c FullConfig) Validate() error {
+	if c.Soc < 0 || c.Soc > 100 {
+		return errors.New("soc must be between 0 and 100")
+	}
+	if c.Current < 0 || c.Delay < 0 {
+		return errors.New("current and delay must not be negative")
+	}
+	return nil
+}
+
+// measuredCurrent returns the measured charge current, estimated from charge power if phase currents are not available
+func (lp *Loadpoint) measuredCurrent() float64 {
+	if lp.chargeCurrents != nil {
+		return max(lp.chargeCurrents[0], lp.chargeCurrents[1], lp.chargeCurrents[2])
+	}
+	return lp.chargePower / Voltage / float64(lp.activePhases())
+}
+
+// resetVehicleFull resets the vehicle full state (no mutex)
+func (lp *Loadpoint) resetVehicleFull() {
+	lp.vehicleFull = false
+	lp.fullTimer = time.Time{}
+}
+
+// vehicleFullReached checks if the vehicle has reached the full soc or its charge current has tapered off.
+// Once full, the vehicle remains full until disconnected. Starting a plan resets the full state to allow scheduled top-ups (no mutex).
+func (lp *Loadpoint) vehicleFullReached(plannerActive bool) bool {
+	if plannerActive && !lp.fullPlanned {
+		lp.resetVehicleFull()
+	}
+	lp.fullPlanned = plannerActive
+
+	if lp.vehicleFull {
+		return true
+	}
+
+	if lp.Full.Soc > 0 && lp.vehicleHasSoc() && lp.vehicleSoc >= lp.Full.Soc {
+		lp.log.DEBUG.Printf("vehicle full: soc %.1f%% >= %.0f%%", lp.vehicleSoc, lp.Full.Soc)
+		lp.vehicleFull = true
+		return true
+	}
+
+	// taper current only applies if the offered current is not the cause
+	if lp.Full.Current == 0 || !lp.charging() || lp.chargeCurrent <= lp.Full.Current {
+		lp.fullTimer = time.Time{}
+		return false
+	}
+
+	if current := lp.measuredCurrent(); current >= lp.Full.Current {
+		lp.fullTimer = time.Time{}
+		return false
+	}
+
+	if lp.fullTimer.IsZero() {
+		lp.fullTimer = lp.clock.Now()
+	}
+
+	if lp.clock.Since(lp.fullTimer) >= lp.Full.Delay {
+		lp.log.DEBUG.Printf("vehicle full: charge current below %.3gA for %v", lp.Full.Current, lp.Full.Delay)
+		lp.vehicleFull = true
+	}
+
+	return lp.vehicleFull
+}
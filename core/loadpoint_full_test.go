@@ -0,0 +1,161 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	evbus "github.com/asaskevich/EventBus"
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/util"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVehicleFullTaper(t *testing.T) {
+	const cycle = 30 * time.Second
+
+	clock := clock.NewMock()
+	ctrl := gomock.NewController(t)
+	charger := api.NewMockCharger(ctrl)
+
+	lp := &Loadpoint{
+		log:            util.NewLogger("foo"),
+		bus:            evbus.New(),
+		clock:          clock,
+		charger:        charger,
+		chargeMeter:    &Null{}, // silence nil panics
+		chargeRater:    &Null{}, // silence nil panics
+		chargeTimer:    &Null{}, // silence nil panics
+		wakeUpTimer:    NewTimer(),
+		sessionEnergy:  NewEnergyMetrics(),
+		MinCurrent:     minA,
+		MaxCurrent:     maxA,
+		phases:         3,
+		measuredPhases: 3,
+		status:         api.StatusC,
+		Mode:           api.ModeNow,
+		Full: FullConfig{
+			Current: 3,
+			Delay:   5 * time.Minute,
+		},
+	}
+
+	attachListeners(t, lp)
+
+	lp.enabled = true
+	lp.chargeCurrent = float64(maxA)
+
+	// tapering charge curve, current drops by 0.5A per cycle down to 1A
+	current := float64(maxA)
+
+	var below time.Duration
+	for below < lp.Full.Delay {
+		lp.chargePower = 3 * Voltage * current
+
+		charger.EXPECT().Enabled().Return(true, nil)
+		charger.EXPECT().Status().Return(api.StatusC, nil)
+		lp.Update(0, false, false, false, 0, nil, nil)
+		assert.True(t, lp.enabled, "stopped early at %.1fA", current)
+
+		if current < lp.Full.Current {
+			below += cycle
+		}
+		current = max(current-0.5, 1)
+		clock.Add(cycle)
+	}
+
+	// clean stop once the taper current has been undercut for the delay
+	lp.chargePower = 3 * Voltage * current
+	charger.EXPECT().Enabled().Return(true, nil)
+	charger.EXPECT().Status().Return(api.StatusC, nil)
+	charger.EXPECT().Enable(false).Return(nil)
+	lp.Update(0, false, false, false, 0, nil, nil)
+	assert.False(t, lp.enabled)
+	assert.True(t, lp.vehicleFull)
+
+	// vehicle remains full without charging
+	lp.chargePower = 0
+	clock.Add(cycle)
+	charger.EXPECT().Enabled().Return(false, nil)
+	charger.EXPECT().Status().Return(api.StatusB, nil)
+	lp.Update(0, false, false, false, 0, nil, nil)
+	assert.False(t, lp.enabled)
+}
+
+func TestVehicleFullReached(t *testing.T) {
+	clock := clock.NewMock()
+	ctrl := gomock.NewController(t)
+	vehicle := api.NewMockVehicle(ctrl)
+	vehicle.EXPECT().Phases().Return(0).AnyTimes()
+
+	Voltage = 230
+	lp := &Loadpoint{
+		log:            util.NewLogger("foo"),
+		clock:          clock,
+		vehicle:        vehicle,
+		phases:         1,
+		measuredPhases: 1,
+		status:         api.StatusC,
+		chargeCurrent:  16,
+		Full: FullConfig{
+			Soc:     99,
+			Current: 2,
+			Delay:   time.Minute,
+		},
+	}
+
+	// soc below full
+	lp.vehicleSoc = 98
+	assert.False(t, lp.vehicleFullReached(false))
+
+	// phase currents take precedence over charge power
+	lp.chargePower = 230
+	lp.chargeCurrents = []float64{3, 0, 0}
+	assert.False(t, lp.vehicleFullReached(false))
+
+	// taper current is not considered if the current is limited by the loadpoint
+	lp.chargeCurrents = nil
+	lp.chargeCurrent = 2
+	assert.False(t, lp.vehicleFullReached(false))
+	clock.Add(time.Minute)
+	assert.False(t, lp.vehicleFullReached(false))
+
+	// full soc
+	lp.vehicleSoc = 99
+	assert.True(t, lp.vehicleFullReached(false))
+
+	// remains full
+	lp.vehicleSoc = 98
+	assert.True(t, lp.vehicleFullReached(false))
+
+	// plan start allows scheduled top-up
+	assert.False(t, lp.vehicleFullReached(true))
+	assert.False(t, lp.vehicleFullReached(true))
+
+	// full during plan
+	lp.vehicleSoc = 99.5
+	assert.True(t, lp.vehicleFullReached(true))
+	assert.True(t, lp.vehicleFullReached(false))
+}
+
+func TestVehicleFullDisconnect(t *testing.T) {
+	lp := NewLoadpoint(util.NewLogger("foo"))
+	lp.Full = FullConfig{Soc: 99}
+	lp.wakeUpTimer = NewTimer()
+
+	// populate channels
+	x, y, z := createChannels(t)
+	attachChannels(lp, x, y, z)
+
+	lp.vehicleFull = true
+
+	// charge start does not reset full
+	lp.evChargeStartHandler()
+	assert.True(t, lp.vehicleFullReached(false))
+
+	// disconnect resets full for the next vehicle
+	lp.evVehicleDisconnectHandler()
+	assert.False(t, lp.vehicleFull)
+	assert.False(t, lp.vehicleFullReached(false))
+}
@@ -0,0 +1,18 @@
+package core
+
+// setGeneratorLimit applies the site's generator limit, empty if the generator is not running (no mutex)
+func (lp *Loadpoint) setGeneratorLimit(limit string) {
+	lp.generatorLimit = limit
+}
+
+// generatorCurrent returns the charge current permitted while the generator is running (no mutex)
+func (lp *Loadpoint) generatorCurrent(chargeCurrent float64) float64 {
+	switch lp.generatorLimit {
+	case generatorOff:
+		return 0
+	case generatorMin:
+		return min(chargeCurrent, lp.effectiveMinCurrent())
+	default:
+		return chargeCurrent
+	}
+}
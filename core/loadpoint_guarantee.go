@@ -0,0 +1,107 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/evcc-io/evcc/core/planner"
+)
+
+// guaranteeLayout is the guarantee time of day format
+const guaranteeLayout = "15:04"
+
+// GuaranteeConfig is a daily minimum soc reached by the given time of day, independent of charging plans
+type GuaranteeConfig struct {
+	Soc  int    `mapstructure:"soc"`  // guaranteed soc (%), disabled if zero
+	Time string `mapstructure:"time"` // daily deadline (hh:mm)
+}
+
+// Validate validates the guarantee configuration
+func (c GuaranteeConfig) Validate() error {
+	if c.Soc < 0 || c.Soc > 100 {
+		return errors.New("soc must be between 0 and 100")
+	}
+	if c.Soc == 0 {
+		return nil
+	}
+	if _, err := time.Parse(guaranteeLayout, c.Time); err != nil {
+		return fmt.Errorf("invalid time: %s", c.Time)
+	}
+	return nil
+}
+
+// Deadline returns the next deadline after the given time
+func (c GuaranteeConfig) Deadline(ts time.Time) time.Time {
+	t, _ := time.Parse(guaranteeLayout, c.Time)
+
+	res := time.Date(ts.Year(), ts.Month(), ts.Day(), t.Hour(), t.Minute(), 0, 0, ts.Location())
+	if !res.After(ts) {
+		res = res.AddDate(0, 0, 1)
+	}
+
+	return res
+}
+
+// guaranteeRequiredDuration is the estimated charging duration to reach the guaranteed soc (no mutex)
+func (lp *Loadpoint) guaranteeRequiredDuration(soc int, maxPower float64) time.Duration {
+	v := lp.GetVehicle()
+	if v == nil || v.Capacity() == 0 || maxPower <= 0 {
+		return 0
+	}
+
+	energy := (float64(soc) - lp.vehicleSoc) / 100 * v.Capacity() * 1e3 / lp.chargeEfficiency(v)
+
+	return time.Duration(energy / maxPower * float64(time.Hour))
+}
+
+// guaranteeActive returns true if reaching the daily guaranteed soc by the deadline requires charging now.
+// Plans reaching at least the guaranteed soc before the deadline take precedence, other plans are honoured alongside.
+// Once started, charging continues until the guaranteed soc is reached, even beyond the deadline.
+func (lp *Loadpoint) guaranteeActive() (active bool) {
+	defer func() {
+		lp.publish("guaranteeActive", active)
+	}()
+
+	c := lp.Soc.Guarantee
+	if c.Soc == 0 || !lp.vehicleHasSoc() || lp.vehicleSoc >= float64(c.Soc) {
+		lp.guaranteeDeadline = time.Time{}
+		return false
+	}
+
+	now := lp.clock.Now()
+
+	// continue until the guaranteed soc is reached
+	if !lp.guaranteeDeadline.IsZero() {
+		if now.After(lp.guaranteeDeadline) {
+			lp.log.DEBUG.Println("guarantee: continuing after deadline")
+		}
+		return true
+	}
+
+	deadline := c.Deadline(now.Local())
+
+	if targetTime := lp.GetTargetTime(); !targetTime.IsZero() && !targetTime.After(deadline) && lp.GetTargetSoc() >= c.Soc {
+		lp.log.DEBUG.Printf("guarantee: %d%% covered by plan", c.Soc)
+		return false
+	}
+
+	requiredDuration := lp.guaranteeRequiredDuration(c.Soc, lp.GetMaxPower())
+	plan, err := lp.planner.Plan(requiredDuration, deadline)
+	if err != nil {
+		lp.log.ERROR.Println("guarantee:", err)
+		return false
+	}
+
+	if planner.SlotAt(now, plan).End.IsZero() {
+		if len(plan) > 0 {
+			lp.log.DEBUG.Printf("guarantee: charge %v to %d%% starting at %v", requiredDuration.Round(time.Second), c.Soc, planner.Start(plan).Round(time.Second).Local())
+		}
+		return false
+	}
+
+	lp.log.DEBUG.Printf("guarantee: charging to %d%% until %v", c.Soc, deadline.Round(time.Second).Local())
+	lp.guaranteeDeadline = deadline
+
+	return true
+}
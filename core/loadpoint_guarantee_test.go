@@ -0,0 +1,151 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	evbus "github.com/asaskevich/EventBus"
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/planner"
+	"github.com/evcc-io/evcc/util"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGuaranteeDeadline(t *testing.T) {
+	c := GuaranteeConfig{Soc: 50, Time: "06:00"}
+	require.NoError(t, c.Validate())
+
+	day := time.Date(2023, 1, 2, 0, 0, 0, 0, time.Local)
+
+	assert.Equal(t, day.Add(6*time.Hour), c.Deadline(day))
+	assert.Equal(t, day.Add(6*time.Hour), c.Deadline(day.Add(5*time.Hour)))
+	assert.Equal(t, day.Add(30*time.Hour), c.Deadline(day.Add(6*time.Hour)))
+	assert.Equal(t, day.Add(30*time.Hour), c.Deadline(day.Add(22*time.Hour)))
+
+	assert.NoError(t, GuaranteeConfig{}.Validate())
+	assert.Error(t, GuaranteeConfig{Soc: 50}.Validate())
+	assert.Error(t, GuaranteeConfig{Soc: 101, Time: "06:00"}.Validate())
+}
+
+func TestGuaranteeCharging(t *testing.T) {
+	const (
+		step     = time.Minute
+		capacity = 10.0 // kWh
+	)
+
+	clock := clock.NewMock()
+	clock.Set(time.Date(2023, 1, 2, 0, 0, 0, 0, time.Local))
+
+	ctrl := gomock.NewController(t)
+	charger := api.NewMockCharger(ctrl)
+
+	vehicle := api.NewMockVehicle(ctrl)
+	vehicle.EXPECT().Phases().AnyTimes()
+	vehicle.EXPECT().Capacity().Return(capacity).AnyTimes()
+
+	lp := &Loadpoint{
+		log:              util.NewLogger("foo"),
+		bus:              evbus.New(),
+		clock:            clock,
+		charger:          charger,
+		chargeMeter:      &Null{}, // silence nil panics
+		chargeRater:      &Null{}, // silence nil panics
+		chargeTimer:      &Null{}, // silence nil panics
+		wakeUpTimer:      NewTimer(),
+		sessionEnergy:    NewEnergyMetrics(),
+		planner:          planner.New(util.NewLogger("foo"), nil),
+		vehicle:          vehicle,
+		MinCurrent:       minA,
+		MaxCurrent:       maxA,
+		ChargeEfficiency: 1,
+		phases:           3,
+		measuredPhases:   3,
+		status:           api.StatusC,
+		Mode:             api.ModePV,
+		vehicleSoc:       30,
+		Soc: SocConfig{
+			target:    100,
+			Guarantee: GuaranteeConfig{Soc: 50, Time: "06:00"},
+		},
+	}
+
+	attachListeners(t, lp)
+
+	charger.EXPECT().Enabled().DoAndReturn(func() (bool, error) {
+		return lp.enabled, nil
+	}).AnyTimes()
+	charger.EXPECT().Status().Return(api.StatusC, nil).AnyTimes()
+	charger.EXPECT().MaxCurrent(gomock.Any()).Return(nil).AnyTimes()
+	charger.EXPECT().Enable(gomock.Any()).Return(nil).AnyTimes()
+
+	// soc gained per step at max power
+	gain := lp.GetMaxPower() * step.Hours() / capacity / 10
+	required := time.Duration(2 / (lp.GetMaxPower() / 1e3) * float64(time.Hour))
+	start := clock.Now().Add(6*time.Hour - required)
+
+	var charged bool
+	for end := clock.Now().Add(8 * time.Hour); clock.Now().Before(end); clock.Add(step) {
+		// grid import, no pv surplus
+		lp.Update(20000, false, false, false, 0, nil, nil)
+
+		if lp.enabled {
+			charged = true
+			assert.False(t, clock.Now().Before(start.Add(-step)), "charging too early at %s", clock.Now().Format("15:04"))
+			lp.vehicleSoc += gain
+		}
+
+		// guarantee met by the deadline
+		if clock.Now().Format("15:04") == "06:00" {
+			assert.GreaterOrEqual(t, lp.vehicleSoc, 50.0)
+		}
+	}
+
+	assert.True(t, charged)
+	assert.False(t, lp.enabled)
+	assert.Less(t, lp.vehicleSoc, 50+gain)
+}
+
+func TestGuaranteePlanPrecedence(t *testing.T) {
+	clock := clock.NewMock()
+	clock.Set(time.Date(2023, 1, 2, 5, 50, 0, 0, time.Local))
+
+	ctrl := gomock.NewController(t)
+
+	vehicle := api.NewMockVehicle(ctrl)
+	vehicle.EXPECT().Phases().AnyTimes()
+	vehicle.EXPECT().Capacity().Return(10.0).AnyTimes()
+
+	lp := &Loadpoint{
+		log:              util.NewLogger("foo"),
+		clock:            clock,
+		planner:          planner.New(util.NewLogger("foo"), nil),
+		vehicle:          vehicle,
+		MaxCurrent:       maxA,
+		ChargeEfficiency: 1,
+		phases:           3,
+		vehicleSoc:       20,
+		Soc: SocConfig{
+			target:    80,
+			Guarantee: GuaranteeConfig{Soc: 50, Time: "06:00"},
+		},
+	}
+
+	// plan reaching the guaranteed soc before the deadline takes precedence
+	lp.targetTime = clock.Now().Add(5 * time.Minute)
+	assert.False(t, lp.guaranteeActive())
+
+	// later plans are honoured alongside
+	lp.targetTime = clock.Now().Add(3 * time.Hour)
+	assert.True(t, lp.guaranteeActive())
+
+	// charging continues beyond the deadline until the guaranteed soc is reached
+	clock.Add(time.Hour)
+	assert.True(t, lp.guaranteeActive())
+
+	lp.vehicleSoc = 50
+	assert.False(t, lp.guaranteeActive())
+	assert.True(t, lp.guaranteeDeadline.IsZero())
+}
@@ -0,0 +1,58 @@
+package core
+
+import "github.com/evcc-io/evcc/api"
+
+// syncVehicleLimit sets the vehicle's charge limit to the target soc while a plan is set, making the
+// vehicle stop at the target even if evcc goes offline. Once the plan clears the vehicle's previous
+// limit is restored if configured. Failed updates are retried on the next cycle. (no mutex)
+func (lp *Loadpoint) syncVehicleLimit() {
+	if !lp.Soc.SyncLimit {
+		return
+	}
+
+	vc, ok := lp.GetVehicle().(api.SocLimitController)
+	if !ok {
+		return
+	}
+
+	// plan cleared
+	if lp.targetTime.IsZero() {
+		if lp.limitSynced == 0 {
+			return
+		}
+
+		if lp.Soc.RestoreLimit && lp.limitPrevious > 0 {
+			if err := vc.SetTargetSoc(lp.limitPrevious); err != nil {
+				lp.log.ERROR.Printf("vehicle soc limit: %v", err)
+				return
+			}
+
+			lp.log.DEBUG.Printf("vehicle soc limit: restored %d%%", lp.limitPrevious)
+		}
+
+		lp.limitSynced, lp.limitPrevious = 0, 0
+		return
+	}
+
+	target := lp.Soc.target
+	if target == 0 || target == lp.limitSynced {
+		return
+	}
+
+	// remember the vehicle's own limit before changing it
+	if lp.limitSynced == 0 {
+		if vs, ok := vc.(api.SocLimiter); ok {
+			if limit, err := vs.TargetSoc(); err == nil {
+				lp.limitPrevious = int(limit)
+			}
+		}
+	}
+
+	if err := vc.SetTargetSoc(target); err != nil {
+		lp.log.ERROR.Printf("vehicle soc limit: %v", err)
+		return
+	}
+
+	lp.log.DEBUG.Printf("vehicle soc limit: set %d%%", target)
+	lp.limitSynced = target
+}
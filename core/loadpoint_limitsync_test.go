@@ -0,0 +1,112 @@
+package core
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/util"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+type limitVehicle struct {
+	*api.MockVehicle
+	limit int
+	err   error
+	sets  []int
+}
+
+func (v *limitVehicle) TargetSoc() (float64, error) {
+	return float64(v.limit), nil
+}
+
+func (v *limitVehicle) SetTargetSoc(soc int) error {
+	if v.err != nil {
+		return v.err
+	}
+	v.limit = soc
+	v.sets = append(v.sets, soc)
+	return nil
+}
+
+func TestSyncVehicleLimit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	tc := []struct {
+		name     string
+		restore  bool
+		expected []int
+		limit    int
+	}{
+		{"restore", true, []int{80, 90, 100}, 100},
+		{"keep", false, []int{80, 90}, 90},
+	}
+
+	for _, tc := range tc {
+		t.Run(tc.name, func(t *testing.T) {
+			vehicle := &limitVehicle{MockVehicle: api.NewMockVehicle(ctrl), limit: 100}
+
+			clck := clock.NewMock()
+			lp := &Loadpoint{
+				log:     util.NewLogger("foo"),
+				clock:   clck,
+				vehicle: vehicle,
+			}
+			lp.Soc.SyncLimit = true
+			lp.Soc.RestoreLimit = tc.restore
+			lp.Soc.target = 80
+
+			// no plan
+			lp.syncVehicleLimit()
+			assert.Empty(t, vehicle.sets)
+
+			// plan sets the limit once
+			lp.targetTime = clck.Now().Add(8 * time.Hour)
+			lp.syncVehicleLimit()
+			lp.syncVehicleLimit()
+			assert.Equal(t, []int{80}, vehicle.sets)
+			assert.Equal(t, 100, lp.limitPrevious)
+
+			// plan target changed
+			lp.Soc.target = 90
+			lp.syncVehicleLimit()
+			assert.Equal(t, 90, vehicle.limit)
+
+			// plan cleared
+			lp.targetTime = time.Time{}
+			lp.syncVehicleLimit()
+			lp.syncVehicleLimit()
+			assert.Equal(t, tc.expected, vehicle.sets)
+			assert.Equal(t, tc.limit, vehicle.limit)
+			assert.Zero(t, lp.limitSynced)
+		})
+	}
+}
+
+func TestSyncVehicleLimitRetry(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	vehicle := &limitVehicle{MockVehicle: api.NewMockVehicle(ctrl), limit: 100, err: errors.New("asleep")}
+
+	clck := clock.NewMock()
+	lp := &Loadpoint{
+		log:        util.NewLogger("foo"),
+		clock:      clck,
+		vehicle:    vehicle,
+		targetTime: clck.Now().Add(time.Hour),
+	}
+	lp.Soc.SyncLimit = true
+	lp.Soc.target = 80
+
+	// failed update is retried
+	lp.syncVehicleLimit()
+	assert.Zero(t, lp.limitSynced)
+
+	vehicle.err = nil
+	lp.syncVehicleLimit()
+	assert.Equal(t, []int{80}, vehicle.sets)
+	assert.Equal(t, 80, lp.limitSynced)
+}
@@ -0,0 +1,48 @@
+package core
+
+// setFuseLimit sets the current and power budget left by the site's main fuse
+func (lp *Loadpoint) setFuseLimit(current, power float64, limited bool) {
+	lp.fuseCurrent = current
+	lp.fusePower = power
+	lp.fuseLimited = limited
+}
+
+// commandedCurrent returns the current the charger is allowed to draw per phase (no mutex)
+func (lp *Loadpoint) commandedCurrent() float64 {
+	if !lp.enabled {
+		return 0
+	}
+	return lp.chargeCurrent
+}
+
+// fuseCurrentLimit returns the max charge current within the main fuse budget at the active phases (no mutex)
+func (lp *Loadpoint) fuseCurrentLimit() float64 {
+	return max(0, min(lp.fuseCurrent, lp.fusePower/(float64(lp.activePhases())*Voltage)))
+}
+
+// fuseClamp clamps the charge current to the main fuse budget.
+// Engaging and releasing the clamp is logged as it indicates the site is operating at its limit (no mutex).
+func (lp *Loadpoint) fuseClamp(chargeCurrent float64) (float64, bool) {
+	if !lp.fuseLimited {
+		return chargeCurrent, false
+	}
+
+	limit := lp.fuseCurrentLimit()
+	clamped := chargeCurrent > limit
+
+	if clamped != lp.fuseClamped {
+		if clamped {
+			lp.log.WARN.Printf("main fuse: charge current %.3gA clamped to %.3gA", chargeCurrent, limit)
+		} else {
+			lp.log.INFO.Println("main fuse: charge current within budget")
+		}
+		lp.fuseClamped = clamped
+		lp.publish("mainFuseLimited", clamped)
+	}
+
+	if !clamped {
+		return chargeCurrent, false
+	}
+
+	return limit, true
+}
@@ -0,0 +1,72 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+)
+
+// MinPVConfig requires a minimum absolute pv production before pv mode charging starts, e.g. to avoid short
+// inefficient charges from small winter surpluses
+type MinPVConfig struct {
+	Power  float64            `mapstructure:"power"`  // min pv production (W)
+	Months map[string]float64 `mapstructure:"months"` // min pv production per month (jan..dec), overrides power
+}
+
+// month returns the month of a three-letter month name
+func month(name string) (time.Month, error) {
+	for m := time.January; m <= time.December; m++ {
+		if strings.EqualFold(name, m.String()[:3]) {
+			return m, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid month: %s", name)
+}
+
+// Validate validates the minimum pv configuration
+func (c MinPVConfig) Validate() error {
+	if c.Power < 0 {
+		return fmt.Errorf("invalid power: %.0f", c.Power)
+	}
+	for name, power := range c.Months {
+		if _, err := month(name); err != nil {
+			return err
+		}
+		if power < 0 {
+			return fmt.Errorf("invalid power: %s: %.0f", name, power)
+		}
+	}
+	return nil
+}
+
+// threshold returns the min pv production for the given month
+func (c MinPVConfig) threshold(m time.Month) float64 {
+	for name, power := range c.Months {
+		if mm, err := month(name); err == nil && mm == m {
+			return power
+		}
+	}
+	return c.Power
+}
+
+// setPVPower sets the site's pv production
+func (lp *Loadpoint) setPVPower(power float64) {
+	lp.pvPower = power
+}
+
+// pvProductionTooLow checks if the pv production is below the current month's threshold for starting pv mode charging
+func (lp *Loadpoint) pvProductionTooLow(mode api.ChargeMode) bool {
+	if mode != api.ModePV {
+		return false
+	}
+
+	threshold := lp.MinPV.threshold(lp.clock.Now().Month())
+	if threshold <= 0 || lp.pvPower >= threshold {
+		return false
+	}
+
+	lp.log.DEBUG.Printf("pv production %.0fW < %.0fW required", lp.pvPower, threshold)
+	return true
+}
@@ -0,0 +1,77 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMinPVProduction(t *testing.T) {
+	const phases = 3
+	const minPower = minA * phases * 100 // 1800W
+
+	minPV := MinPVConfig{
+		Power: 2000,
+		Months: map[string]float64{
+			"Dec": 4000,
+			"jan": 4000,
+		},
+	}
+
+	tc := []struct {
+		title     string
+		month     time.Month
+		pv        float64
+		enabled   bool
+		expEnable bool
+	}{
+		{"summer above threshold", time.June, 2500, false, true},
+		{"summer below threshold", time.June, 1900, false, false},
+		{"winter surplus below seasonal threshold", time.January, 2500, false, false},
+		{"winter above seasonal threshold", time.December, 4000, false, true},
+		{"running charge is not stopped", time.January, 2500, true, true},
+	}
+
+	for _, tc := range tc {
+		t.Log(tc.title)
+
+		clck := clock.NewMock()
+		clck.Set(time.Date(2024, tc.month, 15, 12, 0, 0, 0, time.Local))
+
+		Voltage = 100
+		lp := &Loadpoint{
+			log:            util.NewLogger("foo"),
+			clock:          clck,
+			MinCurrent:     minA,
+			MaxCurrent:     maxA,
+			phases:         phases,
+			measuredPhases: phases,
+			status:         api.StatusC,
+			enabled:        tc.enabled,
+			Enable:         ThresholdConfig{Delay: time.Minute},
+			Disable:        ThresholdConfig{Delay: time.Minute},
+			MinPV:          minPV,
+		}
+
+		lp.setPVPower(tc.pv)
+
+		// run enable/disable timers to completion with surplus covering min power
+		var current float64
+		for i := 0; i < 3; i++ {
+			current = lp.pvMaxCurrent(api.ModePV, -minPower, false, false)
+			clck.Add(time.Minute)
+		}
+
+		assert.Equal(t, tc.expEnable, current >= minA, tc.title)
+	}
+}
+
+func TestMinPVValidate(t *testing.T) {
+	assert.NoError(t, MinPVConfig{Months: map[string]float64{"feb": 1000}}.Validate())
+	assert.Error(t, MinPVConfig{Months: map[string]float64{"february": 1000}}.Validate())
+	assert.Error(t, MinPVConfig{Power: -1}.Validate())
+}
@@ -0,0 +1,7 @@
+package core
+
+// peakBlocked returns true if charging is blocked by a loadpoint or site peak window
+func (lp *Loadpoint) peakBlocked() bool {
+	now := lp.clock.Now()
+	return lp.PeakWindows.Contains(now) || lp.sitePeakWindows.Contains(now)
+}
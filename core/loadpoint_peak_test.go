@@ -0,0 +1,76 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	evbus "github.com/asaskevich/EventBus"
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/rules"
+	"github.com/evcc-io/evcc/util"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPeakWindows(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	charger := api.NewMockCharger(ctrl)
+
+	// monday
+	clck := clock.NewMock()
+	clck.Set(time.Date(2024, 1, 1, 18, 0, 0, 0, time.UTC))
+
+	Voltage = 100
+	lp := &Loadpoint{
+		log:            util.NewLogger("foo"),
+		bus:            evbus.New(),
+		clock:          clck,
+		charger:        charger,
+		wakeUpTimer:    NewTimer(),
+		MinCurrent:     minA,
+		MaxCurrent:     maxA,
+		phases:         3,
+		measuredPhases: 3,
+		status:         api.StatusC,
+		Mode:           api.ModeNow,
+		enabled:        true,
+		chargeCurrent:  minA,
+		sitePeakWindows: rules.Windows{
+			{From: "17:00", To: "20:00", Days: []string{"mon", "tue", "wed", "thu", "fri"}},
+		},
+		PeakWindows: rules.Windows{
+			{From: "07:00", To: "09:00"},
+		},
+	}
+
+	// site peak blocks now mode
+	require.True(t, lp.peakBlocked())
+	charger.EXPECT().Enable(false).Return(nil)
+	require.NoError(t, lp.fastCharging())
+	assert.False(t, lp.enabled)
+
+	// boost and min charging are blocked, too
+	require.NoError(t, lp.setLimit(maxA, true))
+	assert.False(t, lp.enabled)
+
+	// off-peak charges
+	clck.Add(2 * time.Hour)
+	require.False(t, lp.peakBlocked())
+	charger.EXPECT().MaxCurrent(int64(maxA)).Return(nil)
+	charger.EXPECT().Enable(true).Return(nil)
+	require.NoError(t, lp.fastCharging())
+	assert.True(t, lp.enabled)
+
+	// loadpoint peak blocks regardless of weekday
+	clck.Set(time.Date(2024, 1, 6, 8, 0, 0, 0, time.UTC))
+	require.True(t, lp.peakBlocked())
+	charger.EXPECT().Enable(false).Return(nil)
+	require.NoError(t, lp.fastCharging())
+	assert.False(t, lp.enabled)
+
+	// site peak does not apply on weekends
+	clck.Set(time.Date(2024, 1, 6, 18, 0, 0, 0, time.UTC))
+	assert.False(t, lp.peakBlocked())
+}
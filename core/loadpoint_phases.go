@@ -1,6 +1,8 @@
 package core
 
 import (
+	"errors"
+
 	"github.com/evcc-io/evcc/api"
 )
 
@@ -46,6 +48,36 @@ func (lp *Loadpoint) resetMeasuredPhases() {
 	lp.publish(phasesActive, lp.activePhases())
 }
 
+// setMeasuredPhases sets the phases detected for the charging session
+func (lp *Loadpoint) setMeasuredPhases(phases int) {
+	lp.Lock()
+	lp.measuredPhases = phases
+	lp.Unlock()
+
+	lp.log.DEBUG.Printf("detected active phases: %dp", phases)
+	lp.publish(phasesActive, phases)
+}
+
+// updateReportedPhases uses the charger's phase report if phase currents are not available
+func (lp *Loadpoint) updateReportedPhases() {
+	pr, ok := lp.charger.(api.PhaseReporter)
+	if !ok || !lp.charging() || !lp.phaseSwitchCompleted() {
+		return
+	}
+
+	phases, err := pr.ActivePhases()
+	if err != nil {
+		if !errors.Is(err, api.ErrNotAvailable) {
+			lp.log.ERROR.Printf("charger phases: %v", err)
+		}
+		return
+	}
+
+	if phases >= 1 && phases <= 3 {
+		lp.setMeasuredPhases(phases)
+	}
+}
+
 // getMeasuredPhases provides synchronized access to measuredPhases
 func (lp *Loadpoint) getMeasuredPhases() int {
 	lp.Lock()
@@ -99,10 +131,50 @@ func (lp *Loadpoint) maxActivePhases() int {
 	return min(expect(vehicle), expect(physical), expect(measured))
 }
 
+// getVehiclePhases returns the vehicle's phases or the loadpoint's default for unknown vehicles
 func (lp *Loadpoint) getVehiclePhases() int {
 	if vehicle := lp.GetVehicle(); vehicle != nil {
-		return vehicle.Phases()
+		if phases := vehicle.Phases(); phases > 0 {
+			return phases
+		}
 	}
 
-	return 0
+	return lp.VehiclePhases
+}
+
+// phaseCurrents returns the loadpoint's current per grid phase. Unless measured, it is
+// estimated from the current limit assuming the active phases are connected in order.
+func (lp *Loadpoint) phaseCurrents() [3]float64 {
+	var res [3]float64
+
+	if len(lp.chargeCurrents) == 3 {
+		copy(res[:], lp.chargeCurrents)
+		return res
+	}
+
+	if lp.charging() {
+		for i := 0; i < lp.activePhases(); i++ {
+			res[i] = lp.chargeCurrent
+		}
+	}
+
+	return res
+}
+
+// setLoadGroupLimit sets the charge current limit imposed by the load group's budget
+func (lp *Loadpoint) setLoadGroupLimit(limit float64, limited bool) {
+	lp.loadGroupLimit = limit
+	lp.loadGroupLimited = limited
+}
+
+// setDemandLimit sets the charge current limit imposed by the site's peak demand target
+func (lp *Loadpoint) setDemandLimit(limit float64, limited bool) {
+	lp.demandLimit = limit
+	lp.demandLimited = limited
+}
+
+// setPhaseCurrentLimit sets the charge current limit imposed by the site's phase current caps
+func (lp *Loadpoint) setPhaseCurrentLimit(limit float64, limited bool) {
+	lp.phaseCurrentLimit = limit
+	lp.phaseLimited = limited
 }
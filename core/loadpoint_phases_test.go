@@ -10,6 +10,8 @@ import (
 	"github.com/evcc-io/evcc/api"
 	"github.com/evcc-io/evcc/util"
 	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 type testCase struct {
@@ -443,3 +445,123 @@ func TestScalePhasesIfAvailable(t *testing.T) {
 		ctrl.Finish()
 	}
 }
+
+func TestPhaseCurrentLimitReducesCurrent(t *testing.T) {
+	clock := clock.NewMock()
+	ctrl := gomock.NewController(t)
+	charger := api.NewMockCharger(ctrl)
+
+	lp := &Loadpoint{
+		log:         util.NewLogger("foo"),
+		bus:         evbus.New(),
+		clock:       clock,
+		charger:     charger,
+		MinCurrent:  minA,
+		MaxCurrent:  maxA,
+		enabled:     true,
+		phases:      3,
+		status:      api.StatusC,
+		wakeUpTimer: NewTimer(),
+	}
+
+	// one phase near its cap
+	lp.chargeCurrents = []float64{maxA, maxA, maxA}
+	limit, limited := (&Site{
+		log:              util.NewLogger("foo"),
+		MaxPhaseCurrents: []float64{32, 32, 32},
+		gridCurrents:     []float64{36, maxA, maxA},
+	}).phaseCurrentLimit(lp.phaseCurrents())
+	lp.setPhaseCurrentLimit(limit, limited)
+
+	charger.EXPECT().MaxCurrent(int64(limit)).Return(nil)
+	require.NoError(t, lp.fastCharging())
+	assert.Equal(t, limit, lp.chargeCurrent)
+
+	// phase overloaded below min current
+	lp.setPhaseCurrentLimit(minA-1, true)
+	charger.EXPECT().Enable(false).Return(nil)
+	require.NoError(t, lp.fastCharging())
+	assert.False(t, lp.enabled)
+
+	// limit lifted
+	lp.setPhaseCurrentLimit(0, false)
+	charger.EXPECT().MaxCurrent(int64(maxA)).Return(nil)
+	charger.EXPECT().Enable(true).Return(nil)
+	require.NoError(t, lp.fastCharging())
+	assert.True(t, lp.enabled)
+}
+
+type phaseCurrentsMeter struct {
+	*Null
+	currents [3]float64
+}
+
+func (m *phaseCurrentsMeter) Currents() (float64, float64, float64, error) {
+	return m.currents[0], m.currents[1], m.currents[2], nil
+}
+
+func TestSessionPhases(t *testing.T) {
+	const power = 3680 // W
+
+	tc := []struct {
+		name    string
+		prepare func(*Loadpoint, *api.MockPhaseReporter)
+		phases  int
+		current float64
+	}{
+		{"unknown", func(lp *Loadpoint, pr *api.MockPhaseReporter) {
+			pr.EXPECT().ActivePhases().Return(0, api.ErrNotAvailable)
+		}, 3, 16.0 / 3},
+		{"measured 1p", func(lp *Loadpoint, _ *api.MockPhaseReporter) {
+			lp.chargeMeter = &phaseCurrentsMeter{currents: [3]float64{16, 0, 0}}
+		}, 1, 16},
+		{"measured 3p", func(lp *Loadpoint, _ *api.MockPhaseReporter) {
+			lp.chargeMeter = &phaseCurrentsMeter{currents: [3]float64{5.3, 5.3, 5.3}}
+		}, 3, 16.0 / 3},
+		{"reported 1p", func(lp *Loadpoint, pr *api.MockPhaseReporter) {
+			pr.EXPECT().ActivePhases().Return(1, nil)
+		}, 1, 16},
+		{"configured 1p", func(lp *Loadpoint, pr *api.MockPhaseReporter) {
+			lp.VehiclePhases = 1
+			pr.EXPECT().ActivePhases().Return(0, api.ErrNotAvailable)
+		}, 1, 16},
+		{"configured limits measured", func(lp *Loadpoint, _ *api.MockPhaseReporter) {
+			lp.VehiclePhases = 1
+			lp.chargeMeter = &phaseCurrentsMeter{currents: [3]float64{5.3, 5.3, 5.3}}
+		}, 1, 16}, // vehicle phases limit active phases, mismatch is logged
+	}
+
+	for _, tc := range tc {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+
+			charger := &struct {
+				*api.MockCharger
+				*api.MockPhaseReporter
+			}{
+				api.NewMockCharger(ctrl),
+				api.NewMockPhaseReporter(ctrl),
+			}
+
+			Voltage = 230
+			lp := &Loadpoint{
+				log:         util.NewLogger("foo"),
+				clock:       clock.NewMock(),
+				charger:     charger,
+				chargeMeter: &Null{},
+				phases:      3,
+				status:      api.StatusC,
+			}
+
+			tc.prepare(lp, charger.MockPhaseReporter)
+			lp.updateChargeCurrents()
+
+			assert.Equal(t, tc.phases, lp.activePhases())
+			assert.InDelta(t, tc.current, powerToCurrent(power, lp.activePhases()), 1e-3)
+
+			// session phases are reset on disconnect
+			lp.resetMeasuredPhases()
+			assert.Equal(t, expect(lp.VehiclePhases), lp.activePhases())
+		})
+	}
+}
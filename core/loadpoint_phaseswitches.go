@@ -0,0 +1,39 @@
+package core
+
+import "time"
+
+// phaseSwitchDay returns the start of the local day (no mutex)
+func (lp *Loadpoint) phaseSwitchDay() time.Time {
+	now := lp.clock.Now()
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+}
+
+// countPhaseSwitch counts the automatic phase switches of the current day (no mutex)
+func (lp *Loadpoint) countPhaseSwitch() {
+	if day := lp.phaseSwitchDay(); !day.Equal(lp.phaseSwitchesDay) {
+		lp.phaseSwitchesDay = day
+		lp.phaseSwitches = 0
+	}
+
+	lp.phaseSwitches++
+
+	if lp.MaxPhaseSwitches > 0 && lp.phaseSwitches == lp.MaxPhaseSwitches {
+		lp.log.WARN.Printf("phase switch limit reached: %d switches today, keeping %dp until tomorrow", lp.phaseSwitches, lp.GetPhases())
+	}
+}
+
+// phaseSwitchLimitReached returns true if the daily automatic phase switch limit is reached (no mutex)
+func (lp *Loadpoint) phaseSwitchLimitReached() bool {
+	if lp.MaxPhaseSwitches == 0 || !lp.phaseSwitchDay().Equal(lp.phaseSwitchesDay) {
+		return false
+	}
+
+	if lp.phaseSwitches >= lp.MaxPhaseSwitches {
+		if !lp.phaseTimer.IsZero() {
+			lp.resetPhaseTimer()
+		}
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,68 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/util"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMaxPhaseSwitches(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	charger := &struct {
+		*api.MockCharger
+		*api.MockPhaseSwitcher
+	}{
+		api.NewMockCharger(ctrl),
+		api.NewMockPhaseSwitcher(ctrl),
+	}
+
+	Voltage = 230
+
+	clock := clock.NewMock()
+	clock.Set(time.Date(2024, 1, 1, 10, 0, 0, 0, time.Local))
+
+	lp := &Loadpoint{
+		log:              util.NewLogger("foo"),
+		clock:            clock,
+		charger:          charger,
+		MinCurrent:       minA,
+		MaxCurrent:       maxA,
+		MaxPhaseSwitches: 2,
+		phases:           3,
+		status:           api.StatusB,
+	}
+
+	charger.MockPhaseSwitcher.EXPECT().Phases1p3p(1).Return(nil).Times(2)
+	charger.MockPhaseSwitcher.EXPECT().Phases1p3p(3).Return(nil).Times(2)
+
+	// manual phase switches are not counted
+	require.NoError(t, lp.scalePhases(1))
+	require.NoError(t, lp.scalePhases(3))
+	assert.Equal(t, 0, lp.phaseSwitches)
+
+	assert.True(t, lp.pvScalePhases(1000, minA, maxA))
+	assert.True(t, lp.pvScalePhases(-10000, minA, maxA))
+	assert.Equal(t, 3, lp.phases)
+	assert.Equal(t, 2, lp.phaseSwitches)
+
+	// limit reached, no further switching although power is insufficient for 3p
+	assert.True(t, lp.phaseSwitchLimitReached())
+	assert.False(t, lp.pvScalePhases(1000, minA, maxA))
+	assert.Equal(t, 3, lp.phases)
+
+	// reset at local midnight
+	clock.Set(time.Date(2024, 1, 2, 0, 0, 0, 0, time.Local))
+	assert.False(t, lp.phaseSwitchLimitReached())
+
+	charger.MockPhaseSwitcher.EXPECT().Phases1p3p(1).Return(nil)
+	assert.True(t, lp.pvScalePhases(1000, minA, maxA))
+	assert.Equal(t, 1, lp.phases)
+	assert.Equal(t, 1, lp.phaseSwitches)
+}
@@ -23,7 +23,7 @@ func (lp *Loadpoint) setPlanActive(active bool) {
 }
 
 // planRequiredDuration is the estimated total charging duration
-func (lp *Loadpoint) planRequiredDuration(maxPower float64) time.Duration {
+func (lp *Loadpoint) planRequiredDuration(targetSoc int, maxPower float64) time.Duration {
 	if energy, ok := lp.remainingChargeEnergy(); ok {
 		return time.Duration(energy * 1e3 / maxPower * float64(time.Hour))
 	}
@@ -33,7 +33,6 @@ func (lp *Loadpoint) planRequiredDuration(maxPower float64) time.Duration {
 	}
 
 	// TODO vehicle soc limit
-	targetSoc := lp.Soc.target
 	if targetSoc == 0 {
 		targetSoc = 100
 	}
@@ -56,7 +55,7 @@ func (lp *Loadpoint) GetPlan(targetTime time.Time, maxPower float64) (time.Durat
 		return 0, nil, nil
 	}
 
-	requiredDuration := lp.planRequiredDuration(maxPower)
+	requiredDuration := lp.planRequiredDuration(lp.Soc.target, maxPower)
 	plan, err := lp.planner.Plan(requiredDuration, targetTime)
 
 	// sort plan by time
@@ -0,0 +1,22 @@
+package core
+
+import "github.com/evcc-io/evcc/api"
+
+// powerControlled returns true if the charger is controlled by power instead of current
+func (lp *Loadpoint) powerControlled() bool {
+	_, ok := lp.charger.(api.PowerLimiter)
+	return ok
+}
+
+// powerSetpoint converts the charge current into the power setpoint of power controlled chargers.
+// Charger and vehicle current limits are already applied to the current, the max power caps the result.
+func (lp *Loadpoint) powerSetpoint(current float64) float64 {
+	power := current * Voltage * float64(lp.activePhases())
+
+	if lp.MaxPower > 0 && power > lp.MaxPower {
+		lp.log.DEBUG.Printf("charge power limited by max power: %.0fW", lp.MaxPower)
+		power = lp.MaxPower
+	}
+
+	return power
+}
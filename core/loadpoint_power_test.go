@@ -0,0 +1,59 @@
+package core
+
+import (
+	"testing"
+
+	evbus "github.com/asaskevich/EventBus"
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/util"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPowerSetpoint(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	charger := &struct {
+		*api.MockCharger
+		*api.MockPowerLimiter
+	}{
+		api.NewMockCharger(ctrl),
+		api.NewMockPowerLimiter(ctrl),
+	}
+
+	Voltage = 100
+	lp := &Loadpoint{
+		log:            util.NewLogger("foo"),
+		bus:            evbus.New(),
+		clock:          clock.NewMock(),
+		charger:        charger,
+		wakeUpTimer:    NewTimer(),
+		MinCurrent:     minA,
+		MaxCurrent:     maxA,
+		phases:         3,
+		measuredPhases: 3,
+		status:         api.StatusC,
+		enabled:        true,
+	}
+
+	// current is converted to power without rounding to full amps
+	charger.MockPowerLimiter.EXPECT().MaxPower(3 * 100 * 7.5).Return(nil)
+	require.NoError(t, lp.setLimit(7.5, false))
+	assert.Equal(t, 7.5, lp.chargeCurrent)
+
+	// unchanged current is not sent again
+	require.NoError(t, lp.setLimit(7.5, false))
+
+	// power is clamped to max power
+	lp.MaxPower = 3000
+	charger.MockPowerLimiter.EXPECT().MaxPower(3000.0).Return(nil)
+	require.NoError(t, lp.setLimit(maxA, false))
+
+	// power follows the active phases
+	lp.MaxPower = 0
+	lp.measuredPhases = 1
+	charger.MockPowerLimiter.EXPECT().MaxPower(1 * 100 * 8.0).Return(nil)
+	require.NoError(t, lp.setLimit(8, false))
+}
@@ -0,0 +1,78 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+)
+
+// PreconditionLead is the climate lead time before departure at the given outside temperature
+type PreconditionLead struct {
+	Temperature float64       `mapstructure:"temperature"` // outside temperature (°C)
+	Lead        time.Duration `mapstructure:"lead"`        // climate lead time
+}
+
+// PreconditionConfig varies the climate lead time before departure by outside temperature.
+// Lead times are interpolated between the configured temperatures and kept constant outside.
+type PreconditionConfig struct {
+	Lead      time.Duration      `mapstructure:"lead"`      // lead time if the outside temperature is unknown
+	LeadTimes []PreconditionLead `mapstructure:"leadTimes"` // lead time by outside temperature
+}
+
+// Validate validates the precondition configuration
+func (c PreconditionConfig) Validate() error {
+	if c.Lead < 0 {
+		return errors.New("lead must not be negative")
+	}
+	for i, l := range c.LeadTimes {
+		if l.Lead < 0 {
+			return fmt.Errorf("lead time %d: lead must not be negative", i+1)
+		}
+		if i > 0 && l.Temperature <= c.LeadTimes[i-1].Temperature {
+			return fmt.Errorf("lead time %d: temperatures must be ascending", i+1)
+		}
+	}
+	return nil
+}
+
+// LeadTime returns the climate lead time at the outside temperature, nil if unknown
+func (c PreconditionConfig) LeadTime(outside *float64) time.Duration {
+	if outside == nil || len(c.LeadTimes) == 0 {
+		return c.Lead
+	}
+
+	temp := *outside
+
+	i := slices.IndexFunc(c.LeadTimes, func(l PreconditionLead) bool {
+		return l.Temperature >= temp
+	})
+
+	switch i {
+	case -1:
+		return c.LeadTimes[len(c.LeadTimes)-1].Lead
+	case 0:
+		return c.LeadTimes[0].Lead
+	}
+
+	lo, hi := c.LeadTimes[i-1], c.LeadTimes[i]
+	frac := (temp - lo.Temperature) / (hi.Temperature - lo.Temperature)
+
+	return (lo.Lead + time.Duration(frac*float64(hi.Lead-lo.Lead))).Round(time.Minute)
+}
+
+// GetVehicleTemperatures returns the active vehicle's temperatures and the climate lead time for the outside temperature
+func (lp *Loadpoint) GetVehicleTemperatures() (api.VehicleTemperatures, time.Duration, error) {
+	var res api.VehicleTemperatures
+
+	if vt, ok := lp.GetVehicle().(api.VehicleTemperatureReporter); ok {
+		var err error
+		if res, err = vt.Temperatures(); err != nil && !errors.Is(err, api.ErrNotAvailable) {
+			return res, 0, err
+		}
+	}
+
+	return res, lp.Precondition.LeadTime(res.Outside), nil
+}
@@ -0,0 +1,89 @@
+package core
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/util"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPreconditionLeadTime(t *testing.T) {
+	c := PreconditionConfig{
+		Lead: 15 * time.Minute,
+		LeadTimes: []PreconditionLead{
+			{Temperature: -10, Lead: 45 * time.Minute},
+			{Temperature: 10, Lead: 15 * time.Minute},
+			{Temperature: 30, Lead: 25 * time.Minute},
+		},
+	}
+	require.NoError(t, c.Validate())
+
+	tc := []struct {
+		temp *float64
+		lead time.Duration
+	}{
+		{nil, 15 * time.Minute},             // unknown
+		{lo.ToPtr(-20.0), 45 * time.Minute}, // below coldest
+		{lo.ToPtr(-10.0), 45 * time.Minute}, // coldest
+		{lo.ToPtr(0.0), 30 * time.Minute},   // interpolated
+		{lo.ToPtr(10.0), 15 * time.Minute},  // mild
+		{lo.ToPtr(20.0), 20 * time.Minute},  // interpolated towards hot
+		{lo.ToPtr(40.0), 25 * time.Minute},  // above hottest
+	}
+
+	for _, tc := range tc {
+		assert.Equal(t, tc.lead, c.LeadTime(tc.temp), tc.temp)
+	}
+
+	// default only
+	assert.Equal(t, 10*time.Minute, PreconditionConfig{Lead: 10 * time.Minute}.LeadTime(lo.ToPtr(0.0)))
+
+	// invalid
+	assert.Error(t, PreconditionConfig{LeadTimes: []PreconditionLead{{Temperature: 10}, {Temperature: 0}}}.Validate())
+	assert.Error(t, PreconditionConfig{Lead: -time.Minute}.Validate())
+}
+
+type temperatureVehicle struct {
+	api.Vehicle
+	res api.VehicleTemperatures
+	err error
+}
+
+func (v *temperatureVehicle) Temperatures() (api.VehicleTemperatures, error) {
+	return v.res, v.err
+}
+
+func TestVehicleTemperatures(t *testing.T) {
+	vehicle := &temperatureVehicle{res: api.VehicleTemperatures{Outside: lo.ToPtr(-10.0)}}
+
+	lp := &Loadpoint{
+		log:     util.NewLogger("foo"),
+		vehicle: vehicle,
+		Precondition: PreconditionConfig{
+			Lead:      15 * time.Minute,
+			LeadTimes: []PreconditionLead{{Temperature: -10, Lead: 45 * time.Minute}},
+		},
+	}
+
+	res, lead, err := lp.GetVehicleTemperatures()
+	require.NoError(t, err)
+	assert.Equal(t, -10.0, *res.Outside)
+	assert.Nil(t, res.Cabin)
+	assert.Equal(t, 45*time.Minute, lead)
+
+	// missing temperatures use the default lead time
+	vehicle.res, vehicle.err = api.VehicleTemperatures{}, api.ErrNotAvailable
+	res, lead, err = lp.GetVehicleTemperatures()
+	require.NoError(t, err)
+	assert.Nil(t, res.Outside)
+	assert.Equal(t, 15*time.Minute, lead)
+
+	vehicle.err = errors.New("timeout")
+	_, _, err = lp.GetVehicleTemperatures()
+	assert.Error(t, err)
+}
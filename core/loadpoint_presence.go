@@ -0,0 +1,68 @@
+package core
+
+import (
+	"errors"
+
+	"github.com/evcc-io/evcc/provider"
+)
+
+// PresenceConfig adjusts the loadpoint priority depending on the user's presence, e.g. detected by phone or geofence
+type PresenceConfig struct {
+	Source *provider.Config `mapstructure:"source"` // bool getter returning true while the user is present
+	Home   int              `mapstructure:"home"`   // priority offset while present
+	Away   int              `mapstructure:"away"`   // priority offset while absent
+}
+
+// Validate validates the presence configuration
+func (c PresenceConfig) Validate() error {
+	if c.Source == nil && (c.Home != 0 || c.Away != 0) {
+		return errors.New("missing source")
+	}
+	return nil
+}
+
+// configurePresence creates the presence source
+func (lp *Loadpoint) configurePresence() error {
+	if lp.Presence.Source == nil {
+		return nil
+	}
+
+	var err error
+	lp.presenceG, err = provider.NewBoolGetterFromConfig(*lp.Presence.Source)
+	return err
+}
+
+// effectivePriority returns the configured priority adjusted by the user's presence (no mutex)
+func (lp *Loadpoint) effectivePriority() int {
+	switch {
+	case lp.present == nil:
+		return lp.Priority_
+	case *lp.present:
+		return lp.Priority_ + lp.Presence.Home
+	default:
+		return lp.Priority_ + lp.Presence.Away
+	}
+}
+
+// updatePresence reads the presence signal. The last state is kept if the source cannot be read.
+func (lp *Loadpoint) updatePresence() {
+	if lp.presenceG == nil {
+		return
+	}
+
+	present, err := lp.presenceG()
+	if err != nil {
+		lp.log.ERROR.Printf("presence: %v", err)
+		return
+	}
+
+	lp.Lock()
+	defer lp.Unlock()
+
+	if lp.present == nil || *lp.present != present {
+		lp.log.DEBUG.Printf("presence: %t", present)
+		lp.present = &present
+		lp.publish("present", present)
+		lp.publish("effectivePriority", lp.effectivePriority())
+	}
+}
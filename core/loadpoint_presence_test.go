@@ -0,0 +1,85 @@
+package core
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/loadpoint"
+	"github.com/evcc-io/evcc/core/prioritizer"
+	"github.com/evcc-io/evcc/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPresencePriority(t *testing.T) {
+	var present bool
+	var err error
+
+	lp := &Loadpoint{
+		log:       util.NewLogger("foo"),
+		clock:     clock.NewMock(),
+		Priority_: 1,
+		Presence:  PresenceConfig{Home: 2, Away: -1},
+		presenceG: func() (bool, error) { return present, err },
+	}
+
+	// unknown presence
+	assert.Equal(t, 1, lp.GetPriority())
+
+	lp.updatePresence()
+	assert.Equal(t, 0, lp.GetPriority())
+
+	present = true
+	lp.updatePresence()
+	assert.Equal(t, 3, lp.GetPriority())
+
+	// last state is kept on error
+	present, err = false, errors.New("timeout")
+	lp.updatePresence()
+	assert.Equal(t, 3, lp.GetPriority())
+
+	// configured priority changes are kept
+	lp.SetPriority(5)
+	assert.Equal(t, 5, lp.Priority_)
+	assert.Equal(t, 7, lp.GetPriority())
+}
+
+func TestPresenceAllocation(t *testing.T) {
+	var present bool
+
+	newLoadpoint := func(prio int) *Loadpoint {
+		return &Loadpoint{
+			log:              util.NewLogger("foo"),
+			clock:            clock.NewMock(),
+			Mode:             api.ModePV,
+			status:           api.StatusC,
+			Priority_:        prio,
+			MinCurrent:       minA,
+			MaxCurrent:       16,
+			ConfiguredPhases: 1,
+			phases:           1,
+		}
+	}
+
+	home := newLoadpoint(0)
+	home.Presence = PresenceConfig{Home: 2}
+	home.presenceG = func() (bool, error) { return present, nil }
+	other := newLoadpoint(1)
+
+	lps := []loadpoint.API{home, other}
+	surplus := other.GetMaxPower() // sufficient for one loadpoint only
+
+	// absent user's loadpoint is served last
+	home.updatePresence()
+	res := prioritizer.Allocate(surplus, lps)
+	assert.Equal(t, 0.0, res[home])
+	assert.Equal(t, surplus, res[other])
+
+	// present user's loadpoint is served first
+	present = true
+	home.updatePresence()
+	res = prioritizer.Allocate(surplus, lps)
+	assert.Equal(t, surplus, res[home])
+	assert.Equal(t, 0.0, res[other])
+}
@@ -0,0 +1,56 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/provider"
+)
+
+// vehicleRefreshInterval is the minimum interval between forced vehicle refreshes
+const vehicleRefreshInterval = time.Minute
+
+// RefreshVehicle forces an immediate vehicle api refresh bypassing the cache once.
+// Refreshes are rate-limited to protect the vehicle api.
+func (lp *Loadpoint) RefreshVehicle() error {
+	lp.Lock()
+	defer lp.Unlock()
+
+	if lp.vehicle == nil {
+		return errors.New("no vehicle")
+	}
+
+	if !lp.vehicleRefreshed.IsZero() {
+		if remaining := vehicleRefreshInterval - lp.clock.Since(lp.vehicleRefreshed); remaining > 0 {
+			return fmt.Errorf("%w: refresh rate-limited for %v", api.ErrMustRetry, remaining.Round(time.Second))
+		}
+	}
+
+	lp.log.DEBUG.Println("vehicle api refresh")
+	provider.ResetCachedBy(vehiclePackage(lp.vehicle))
+
+	lp.vehicleRefreshed = lp.clock.Now()
+	lp.socRefresh = true
+	lp.requestUpdate()
+
+	return nil
+}
+
+// vehiclePackage returns the package implementing the vehicle.
+// Decorated vehicles are anonymous structs embedding the implementation.
+func vehiclePackage(v api.Vehicle) string {
+	t := reflect.TypeOf(v)
+	for {
+		switch {
+		case t.Kind() == reflect.Pointer:
+			t = t.Elem()
+		case t.Name() == "" && t.Kind() == reflect.Struct && t.NumField() > 0:
+			t = t.Field(0).Type
+		default:
+			return t.PkgPath()
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	evbus "github.com/asaskevich/EventBus"
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/soc"
+	"github.com/evcc-io/evcc/provider"
+	"github.com/evcc-io/evcc/util"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type cachedSocVehicle struct {
+	*api.MockVehicle
+	socG func() (float64, error)
+}
+
+func (v *cachedSocVehicle) Soc() (float64, error) {
+	return v.socG()
+}
+
+func TestRefreshVehicle(t *testing.T) {
+	clock := clock.NewMock()
+	ctrl := gomock.NewController(t)
+	charger := api.NewMockCharger(ctrl)
+
+	value, reads := 50.0, 0
+	vehicle := &cachedSocVehicle{
+		MockVehicle: api.NewMockVehicle(ctrl),
+		socG: provider.Cached(func() (float64, error) {
+			reads++
+			return value, nil
+		}, time.Hour),
+	}
+	vehicle.MockVehicle.EXPECT().Capacity().Return(float64(10)).AnyTimes()
+
+	lp := &Loadpoint{
+		log:           util.NewLogger("foo"),
+		bus:           evbus.New(),
+		clock:         clock,
+		charger:       charger,
+		vehicle:       vehicle,
+		socEstimator:  soc.NewEstimator(util.NewLogger("foo"), charger, vehicle, false, soc.ChargeEfficiency),
+		sessionEnergy: NewEnergyMetrics(),
+		status:        api.StatusB,
+		Soc: SocConfig{
+			Poll: PollConfig{
+				Mode:     pollCharging,
+				Interval: pollInterval,
+			},
+		},
+	}
+
+	// initial soc
+	lp.publishSocAndRange()
+	assert.Equal(t, 50.0, lp.vehicleSoc)
+	assert.Equal(t, 1, reads)
+
+	// soc not polled while connected and not charging
+	value = 60
+	clock.Add(5 * time.Minute)
+	lp.publishSocAndRange()
+	assert.Equal(t, 50.0, lp.vehicleSoc)
+	assert.Equal(t, 1, reads)
+
+	// forced refresh bypasses cache once
+	require.NoError(t, lp.RefreshVehicle())
+	lp.publishSocAndRange()
+	assert.Equal(t, 60.0, lp.vehicleSoc)
+	assert.Equal(t, 2, reads)
+
+	value = 70
+	lp.publishSocAndRange()
+	assert.Equal(t, 60.0, lp.vehicleSoc)
+	assert.Equal(t, 2, reads)
+
+	// rate-limited
+	clock.Add(30 * time.Second)
+	assert.ErrorIs(t, lp.RefreshVehicle(), api.ErrMustRetry)
+
+	clock.Add(30 * time.Second)
+	require.NoError(t, lp.RefreshVehicle())
+	lp.publishSocAndRange()
+	assert.Equal(t, 70.0, lp.vehicleSoc)
+	assert.Equal(t, 3, reads)
+
+	// no vehicle
+	lp.vehicle = nil
+	clock.Add(time.Minute)
+	assert.Error(t, lp.RefreshVehicle())
+}
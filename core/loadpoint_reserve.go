@@ -0,0 +1,58 @@
+package core
+
+import (
+	"errors"
+
+	"github.com/evcc-io/evcc/api"
+)
+
+// GetReserveSoc returns the soc the vehicle is never discharged below
+func (lp *Loadpoint) GetReserveSoc() int {
+	lp.Lock()
+	defer lp.Unlock()
+	return lp.Soc.Reserve
+}
+
+// dischargeAllowed returns true if the vehicle soc is known and above the reserve (no mutex)
+func (lp *Loadpoint) dischargeAllowed() bool {
+	return lp.vehicleSoc > 0 && lp.vehicleSoc > float64(lp.Soc.Reserve)
+}
+
+// setDischargePower sets the vehicle discharge power. All discharge logic must use it to honour the reserve soc. (no mutex)
+func (lp *Loadpoint) setDischargePower(power float64) error {
+	d, ok := lp.charger.(api.Discharger)
+	if !ok {
+		if power > 0 {
+			return errors.New("charger does not support discharging")
+		}
+		return nil
+	}
+
+	if power > 0 && !lp.dischargeAllowed() {
+		lp.log.DEBUG.Printf("discharge prevented at soc %.0f%% (<= %d%% reserve)", lp.vehicleSoc, lp.Soc.Reserve)
+		power = 0
+	}
+
+	if power == lp.dischargePower {
+		return nil
+	}
+
+	if err := d.Discharge(power); err != nil {
+		return err
+	}
+
+	lp.log.DEBUG.Printf("discharge power: %.0fW", power)
+	lp.dischargePower = power
+	lp.publish("dischargePower", power)
+
+	return nil
+}
+
+// updateDischarge stops discharging once the vehicle reaches the reserve soc (no mutex)
+func (lp *Loadpoint) updateDischarge() {
+	if lp.dischargePower > 0 && !lp.dischargeAllowed() {
+		if err := lp.setDischargePower(0); err != nil {
+			lp.log.ERROR.Printf("discharge: %v", err)
+		}
+	}
+}
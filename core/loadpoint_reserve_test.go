@@ -0,0 +1,77 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/util"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type dischargeCharger struct {
+	*api.MockCharger
+	power float64
+	calls int
+}
+
+func (c *dischargeCharger) Discharge(power float64) error {
+	c.power = power
+	c.calls++
+	return nil
+}
+
+func TestDischargeReserve(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	charger := &dischargeCharger{MockCharger: api.NewMockCharger(ctrl)}
+
+	lp := &Loadpoint{
+		log:        util.NewLogger("foo"),
+		clock:      clock.NewMock(),
+		charger:    charger,
+		Soc:        SocConfig{Reserve: 30},
+		vehicleSoc: 50,
+	}
+
+	// discharge above reserve
+	require.NoError(t, lp.setDischargePower(5000))
+	assert.Equal(t, 5000.0, charger.power)
+
+	// discharge continues above reserve
+	lp.vehicleSoc = 31
+	lp.updateDischarge()
+	assert.Equal(t, 5000.0, charger.power)
+	assert.Equal(t, 1, charger.calls)
+
+	// discharge stops at reserve
+	lp.vehicleSoc = 30
+	lp.updateDischarge()
+	assert.Equal(t, 0.0, charger.power)
+	assert.Equal(t, 0.0, lp.dischargePower)
+
+	// discharge cannot be restarted at or below reserve
+	require.NoError(t, lp.setDischargePower(5000))
+	assert.Equal(t, 0.0, charger.power)
+
+	// nor with unknown soc
+	lp.vehicleSoc = 0
+	lp.Soc.Reserve = 0
+	require.NoError(t, lp.setDischargePower(5000))
+	assert.Equal(t, 0.0, charger.power)
+	assert.Equal(t, 2, charger.calls)
+}
+
+func TestDischargeUnsupported(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	lp := &Loadpoint{
+		log:        util.NewLogger("foo"),
+		charger:    api.NewMockCharger(ctrl),
+		vehicleSoc: 50,
+	}
+
+	assert.Error(t, lp.setDischargePower(5000))
+	assert.NoError(t, lp.setDischargePower(0))
+}
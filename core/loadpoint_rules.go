@@ -0,0 +1,49 @@
+package core
+
+import (
+	"github.com/evcc-io/evcc/core/rules"
+)
+
+// setGridPrice sets the current grid price for rule evaluation, nil if unknown
+func (lp *Loadpoint) setGridPrice(price *float64) {
+	lp.gridPrice = price
+}
+
+// ruleAction evaluates the loadpoint's rules and returns the action of the first matching rule
+func (lp *Loadpoint) ruleAction(sitePower float64) (rules.Action, bool) {
+	if len(lp.Rules) == 0 {
+		return "", false
+	}
+
+	state := rules.State{
+		Time:      lp.clock.Now(),
+		Price:     lp.gridPrice,
+		PvSurplus: lp.chargePower - sitePower,
+	}
+
+	if lp.vehicleSoc > 0 {
+		soc := lp.vehicleSoc
+		state.Soc = &soc
+	}
+
+	action, idx, ok := lp.Rules.Evaluate(state)
+	if ok {
+		lp.log.DEBUG.Printf("rule %d matched: %s", idx+1, action)
+	}
+
+	lp.publish("ruleAction", action)
+
+	return action, ok
+}
+
+// applyRuleAction executes the rule's action
+func (lp *Loadpoint) applyRuleAction(action rules.Action) error {
+	switch action {
+	case rules.Boost:
+		return lp.fastCharging()
+	case rules.Charge:
+		return lp.setLimit(lp.effectiveMinCurrent(), false)
+	default:
+		return lp.setLimit(0, false)
+	}
+}
@@ -0,0 +1,74 @@
+package core
+
+import (
+	"testing"
+
+	evbus "github.com/asaskevich/EventBus"
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/rules"
+	"github.com/evcc-io/evcc/util"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRuleActions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	charger := api.NewMockCharger(ctrl)
+
+	cheap, soc, surplus := 0.1, 80.0, 3000.0
+
+	Voltage = 100
+	lp := &Loadpoint{
+		log:            util.NewLogger("foo"),
+		bus:            evbus.New(),
+		clock:          clock.NewMock(),
+		charger:        charger,
+		wakeUpTimer:    NewTimer(),
+		MinCurrent:     minA,
+		MaxCurrent:     maxA,
+		phases:         3,
+		measuredPhases: 3,
+		status:         api.StatusC,
+		vehicleSoc:     50,
+		Rules: rules.Rules{
+			{Price: &cheap, Soc: &soc, Action: rules.Boost},
+			{PvSurplus: &surplus, Action: rules.Charge},
+		},
+	}
+	require.NoError(t, lp.Rules.Validate())
+
+	// no price, no surplus
+	_, ok := lp.ruleAction(0)
+	assert.False(t, ok)
+
+	// cheap price boosts
+	price := 0.05
+	lp.setGridPrice(&price)
+
+	action, ok := lp.ruleAction(-5000)
+	require.True(t, ok)
+	assert.Equal(t, rules.Boost, action)
+
+	charger.EXPECT().MaxCurrent(int64(maxA)).Return(nil)
+	charger.EXPECT().Enable(true).Return(nil)
+	require.NoError(t, lp.applyRuleAction(action))
+	assert.Equal(t, maxA, lp.chargeCurrent)
+
+	// vehicle soc above rule soc falls through to pv surplus rule, including own charge power
+	lp.vehicleSoc = 90
+	lp.chargePower = 2000
+	action, ok = lp.ruleAction(-1000)
+	require.True(t, ok)
+	assert.Equal(t, rules.Charge, action)
+
+	charger.EXPECT().MaxCurrent(int64(minA)).Return(nil)
+	require.NoError(t, lp.applyRuleAction(action))
+	assert.Equal(t, minA, lp.chargeCurrent)
+
+	// hold disables charging
+	charger.EXPECT().Enable(false).Return(nil)
+	require.NoError(t, lp.applyRuleAction(rules.Hold))
+	assert.False(t, lp.enabled)
+}
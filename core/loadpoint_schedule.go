@@ -0,0 +1,50 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/evcc-io/evcc/core/rules"
+)
+
+// SocScheduleEntry is the soc the vehicle is kept at during a recurring time window
+type SocScheduleEntry struct {
+	rules.Window `mapstructure:",squash"`
+	Soc          int `mapstructure:"soc"` // target soc (%)
+}
+
+// SocSchedule is a time-varying target soc. The first entry containing the current time wins.
+type SocSchedule []SocScheduleEntry
+
+// Validate checks all schedule entries
+func (s SocSchedule) Validate() error {
+	var errs []error
+	for i, e := range s {
+		if err := e.Window.Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("entry %d: %w", i+1, err))
+		}
+		if e.Soc <= 0 || e.Soc > 100 {
+			errs = append(errs, fmt.Errorf("entry %d: soc must be between 1 and 100", i+1))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Target returns the scheduled target soc at the given time
+func (s SocSchedule) Target(ts time.Time) (int, bool) {
+	for _, e := range s {
+		if e.Contains(ts) {
+			return e.Soc, true
+		}
+	}
+	return 0, false
+}
+
+// socScheduleTarget returns the currently scheduled target soc. The schedule requires a vehicle soc to be tracked.
+func (lp *Loadpoint) socScheduleTarget() (int, bool) {
+	if len(lp.SocSchedule) == 0 || !lp.vehicleHasSoc() {
+		return 0, false
+	}
+	return lp.SocSchedule.Target(lp.clock.Now())
+}
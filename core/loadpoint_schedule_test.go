@@ -0,0 +1,130 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	evbus "github.com/asaskevich/EventBus"
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/rules"
+	"github.com/evcc-io/evcc/util"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSocScheduleTarget(t *testing.T) {
+	s := SocSchedule{
+		{Window: rules.Window{From: "06:00", To: "08:00", Days: []string{"mon"}}, Soc: 100},
+		{Window: rules.Window{From: "22:00", To: "08:00"}, Soc: 60},
+	}
+	require.NoError(t, s.Validate())
+
+	// 2023-01-02 is a monday
+	monday := time.Date(2023, 1, 2, 0, 0, 0, 0, time.Local)
+
+	tc := []struct {
+		ts  time.Duration
+		soc int
+		ok  bool
+	}{
+		{0, 60, true},
+		{7 * time.Hour, 100, true}, // first entry wins
+		{8 * time.Hour, 0, false},
+		{22 * time.Hour, 60, true},
+		{31 * time.Hour, 60, true}, // tuesday
+	}
+
+	for _, tc := range tc {
+		soc, ok := s.Target(monday.Add(tc.ts))
+		assert.Equal(t, tc.soc, soc, "%+v", tc)
+		assert.Equal(t, tc.ok, ok, "%+v", tc)
+	}
+
+	assert.Error(t, SocSchedule{{Window: rules.Window{From: "06:00", To: "08:00"}}}.Validate())
+	assert.Error(t, SocSchedule{{Window: rules.Window{From: "6", To: "08:00"}, Soc: 50}}.Validate())
+}
+
+func TestSocScheduleCurve(t *testing.T) {
+	const (
+		step = 5 * time.Minute
+		gain = 1.0 // soc gained per step while charging
+	)
+
+	clock := clock.NewMock()
+	clock.Set(time.Date(2023, 1, 2, 0, 0, 0, 0, time.Local))
+
+	ctrl := gomock.NewController(t)
+	charger := api.NewMockCharger(ctrl)
+
+	vehicle := api.NewMockVehicle(ctrl)
+	vehicle.EXPECT().Phases().AnyTimes()
+
+	lp := &Loadpoint{
+		log:            util.NewLogger("foo"),
+		bus:            evbus.New(),
+		clock:          clock,
+		charger:        charger,
+		chargeMeter:    &Null{}, // silence nil panics
+		chargeRater:    &Null{}, // silence nil panics
+		chargeTimer:    &Null{}, // silence nil panics
+		wakeUpTimer:    NewTimer(),
+		sessionEnergy:  NewEnergyMetrics(),
+		vehicle:        vehicle,
+		MinCurrent:     minA,
+		MaxCurrent:     maxA,
+		phases:         3,
+		measuredPhases: 3,
+		status:         api.StatusC,
+		Mode:           api.ModePV,
+		vehicleSoc:     50,
+		Soc: SocConfig{
+			target: 100,
+		},
+		SocSchedule: SocSchedule{
+			{Window: rules.Window{From: "06:00", To: "08:00"}, Soc: 100},
+			{Window: rules.Window{From: "22:00", To: "06:00"}, Soc: 60},
+		},
+	}
+
+	attachListeners(t, lp)
+
+	charger.EXPECT().Enabled().DoAndReturn(func() (bool, error) {
+		return lp.enabled, nil
+	}).AnyTimes()
+	charger.EXPECT().Status().Return(api.StatusC, nil).AnyTimes()
+	charger.EXPECT().MaxCurrent(gomock.Any()).Return(nil).AnyTimes()
+	charger.EXPECT().Enable(gomock.Any()).Return(nil).AnyTimes()
+
+	// soc at the given time of day
+	expect := map[string]float64{
+		"00:30": 56, // charging to night target
+		"06:00": 60, // holding night target regardless of target soc
+		"07:00": 72, // charging to trip target
+		"08:00": 84, // schedule ends
+		"10:00": 84, // pv mode without surplus
+	}
+
+	for now := clock.Now(); clock.Now().Before(now.Add(10*time.Hour + step)); clock.Add(step) {
+		if soc, ok := expect[clock.Now().Format("15:04")]; ok {
+			assert.Equal(t, soc, lp.vehicleSoc, clock.Now().Format("15:04"))
+		}
+
+		// grid import, no pv surplus
+		lp.Update(20000, false, false, false, 0, nil, nil)
+
+		if lp.enabled {
+			lp.vehicleSoc += gain
+		}
+
+		if target, ok := lp.SocSchedule.Target(clock.Now()); ok {
+			assert.LessOrEqual(t, lp.vehicleSoc, float64(target), clock.Now().Format("15:04"))
+		}
+	}
+
+	// schedule holds soc during cheap tariff
+	clock.Set(time.Date(2023, 1, 2, 23, 0, 0, 0, time.Local))
+	lp.Update(20000, true, false, false, 0, nil, nil)
+	assert.False(t, lp.enabled)
+}
@@ -0,0 +1,46 @@
+package core
+
+// SelfConsumptionConfig defines the minimum share of self-generated power required for PV charging
+type SelfConsumptionConfig struct {
+	MinRatio float64 `mapstructure:"minRatio"` // min share of self-generated charge power in %
+	Battery  bool    `mapstructure:"battery"`  // count home battery discharge as self-generated
+}
+
+// setGreenPower sets the site's self-generated power available to the loadpoint, i.e. after home consumption
+func (lp *Loadpoint) setGreenPower(pv, battery float64) {
+	lp.greenPower = pv
+	lp.greenBatteryPower = battery
+}
+
+// selfConsumptionRatio returns the share of self-generated power when charging at the given current
+func (lp *Loadpoint) selfConsumptionRatio(current float64, phases int) float64 {
+	green := lp.greenPower
+	if lp.SelfConsumption.Battery {
+		green += lp.greenBatteryPower
+	}
+
+	power := current * float64(phases) * Voltage
+	if power <= 0 {
+		if green > 0 {
+			return 1
+		}
+		return 0
+	}
+
+	return min(max(green, 0), power) / power
+}
+
+// selfConsumptionTooLow checks if the self-generated share of charging at the given current is below the configured ratio
+func (lp *Loadpoint) selfConsumptionTooLow(current float64, phases int) bool {
+	if lp.SelfConsumption.MinRatio <= 0 {
+		return false
+	}
+
+	ratio := 100 * lp.selfConsumptionRatio(current, phases)
+	if ratio < lp.SelfConsumption.MinRatio {
+		lp.log.DEBUG.Printf("self-consumption ratio %.0f%% < %.0f%% required", ratio, lp.SelfConsumption.MinRatio)
+		return true
+	}
+
+	return false
+}
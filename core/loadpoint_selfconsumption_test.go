@@ -0,0 +1,103 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSelfConsumptionRatio(t *testing.T) {
+	const phases = 3
+	const minPower = minA * phases * 100 // 1800W
+
+	tc := []struct {
+		title              string
+		minRatio           float64
+		countBattery       bool
+		pv, battery        float64 // green power after home consumption
+		site               float64
+		enabled, expEnable bool
+	}{
+		{"no ratio configured", 0, false, 1000, 0, -1000, true, true},
+		{"pv covers min power", 80, false, minPower, 0, -minPower, false, true},
+		{"pv covers ratio", 50, false, 0.5 * minPower, 0, -minPower, false, true},
+		{"pv below ratio", 60, false, 0.5 * minPower, 0, -minPower, false, false},
+		{"pv below ratio while charging", 60, false, 0.5 * minPower, 0, 0, true, false},
+		{"battery not counted", 60, false, 0.5 * minPower, 0.5 * minPower, -minPower, false, false},
+		{"battery counted", 60, true, 0.5 * minPower, 0.5 * minPower, -minPower, false, true},
+		{"battery only", 50, true, 0, minPower, -minPower, false, true},
+		{"battery only not counted", 50, false, 0, minPower, -minPower, true, false},
+	}
+
+	for _, tc := range tc {
+		t.Log(tc.title)
+
+		clck := clock.NewMock()
+
+		Voltage = 100
+		lp := &Loadpoint{
+			log:            util.NewLogger("foo"),
+			clock:          clck,
+			MinCurrent:     minA,
+			MaxCurrent:     maxA,
+			phases:         phases,
+			measuredPhases: phases,
+			status:         api.StatusC,
+			enabled:        tc.enabled,
+			Enable:         ThresholdConfig{Delay: time.Minute},
+			Disable:        ThresholdConfig{Delay: time.Minute},
+			SelfConsumption: SelfConsumptionConfig{
+				MinRatio: tc.minRatio,
+				Battery:  tc.countBattery,
+			},
+		}
+
+		lp.setGreenPower(tc.pv, tc.battery)
+
+		// run enable/disable timers to completion
+		var current float64
+		for i := 0; i < 3; i++ {
+			current = lp.pvMaxCurrent(api.ModePV, tc.site, false, false)
+			clck.Add(time.Minute)
+		}
+
+		assert.Equal(t, tc.expEnable, current >= minA, tc.title)
+	}
+}
+
+func TestSelfConsumptionOverridesBatteryBuffer(t *testing.T) {
+	clck := clock.NewMock()
+
+	Voltage = 100
+	lp := &Loadpoint{
+		log:            util.NewLogger("foo"),
+		clock:          clck,
+		MinCurrent:     minA,
+		MaxCurrent:     maxA,
+		phases:         3,
+		measuredPhases: 3,
+		status:         api.StatusC,
+		enabled:        true,
+		Disable:        ThresholdConfig{Delay: time.Minute},
+		SelfConsumption: SelfConsumptionConfig{
+			MinRatio: 90,
+		},
+	}
+
+	// battery buffered charging would be supplied by battery only
+	lp.setGreenPower(0, 5000)
+
+	// disable timer started
+	assert.Equal(t, minA, lp.pvMaxCurrent(api.ModePV, 0, true, false))
+
+	// disable timer elapsed
+	clck.Add(time.Minute)
+	assert.Equal(t, 0.0, lp.pvMaxCurrent(api.ModePV, 0, true, false))
+
+	// minpv mode is not affected
+	assert.Equal(t, minA, lp.pvMaxCurrent(api.ModeMinPV, 0, false, false))
+}
@@ -1,6 +1,8 @@
 package core
 
 import (
+	"errors"
+
 	"github.com/evcc-io/evcc/api"
 	"github.com/evcc-io/evcc/core/session"
 )
@@ -43,6 +45,48 @@ func (lp *Loadpoint) createSession() {
 	}
 }
 
+// sessionTransaction links the charger's transaction to the session.
+// The charger's transaction meter readings take precedence since they are used for billing.
+func (lp *Loadpoint) sessionTransaction(s *session.Session) {
+	c, ok := lp.charger.(api.ChargeTransactionReporter)
+	if !ok {
+		return
+	}
+
+	txn, err := c.ChargeTransaction()
+	if err != nil {
+		if !errors.Is(err, api.ErrNotAvailable) {
+			lp.log.ERROR.Printf("charge transaction: %v", err)
+		}
+		return
+	}
+
+	// ignore the previous session's transaction
+	if s.Transaction == "" && txn.MeterStop != nil {
+		return
+	}
+
+	// keep the first transaction if charging was resumed in a new one
+	if s.Transaction != "" && s.Transaction != txn.ID {
+		if txn.MeterStop != nil {
+			s.MeterStop = txn.MeterStop
+		}
+		return
+	}
+
+	if s.Transaction == "" && txn.External {
+		lp.log.DEBUG.Printf("transaction %s started at charger (id tag: %s)", txn.ID, txn.IdTag)
+	}
+
+	s.Transaction = txn.ID
+	if txn.MeterStart != nil {
+		s.MeterStart = txn.MeterStart
+	}
+	if txn.MeterStop != nil {
+		s.MeterStop = txn.MeterStop
+	}
+}
+
 // stopSession ends a charging session segment and persists the session.
 func (lp *Loadpoint) stopSession() {
 	s := lp.session
@@ -62,6 +106,9 @@ func (lp *Loadpoint) stopSession() {
 		s.MeterStop = &meterStop
 	}
 
+	lp.sessionTransaction(s)
+	s.SetTag(lp.SessionTag)
+
 	if chargedEnergy := lp.getChargedEnergy() / 1e3; chargedEnergy > s.ChargedEnergy {
 		lp.sessionEnergy.Update(chargedEnergy)
 	}
@@ -72,6 +119,7 @@ func (lp *Loadpoint) stopSession() {
 	s.PricePerKWh = lp.sessionEnergy.PricePerKWh()
 	s.Co2PerKWh = lp.sessionEnergy.Co2PerKWh()
 	s.ChargedEnergy = lp.sessionEnergy.TotalWh() / 1e3
+	s.VehicleEnergy = s.ChargedEnergy * lp.chargeEfficiency(lp.GetVehicle())
 	s.ChargeDuration = &lp.chargeDuration
 
 	lp.db.Persist(s)
@@ -7,9 +7,12 @@ import (
 	"github.com/benbjohnson/clock"
 	"github.com/evcc-io/evcc/api"
 	"github.com/evcc-io/evcc/core/session"
+	"github.com/evcc-io/evcc/core/soc"
+	"github.com/evcc-io/evcc/core/wrapper"
 	serverdb "github.com/evcc-io/evcc/server/db"
 	"github.com/evcc-io/evcc/util"
 	"github.com/golang/mock/gomock"
+	"github.com/samber/lo"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -65,6 +68,7 @@ func TestSession(t *testing.T) {
 	lp.stopSession()
 	assert.NotNil(t, lp.session)
 	assert.Equal(t, lp.getChargedEnergy()/1e3, lp.session.ChargedEnergy)
+	assert.InDelta(t, lp.getChargedEnergy()/1e3*soc.ChargeEfficiency, lp.session.VehicleEnergy, 1e-6)
 	assert.Equal(t, clock.Now(), lp.session.Finished)
 
 	s, err := db.Sessions()
@@ -182,3 +186,163 @@ func createMockSessions(db *session.DB, clock *clock.Mock) []*session.Session {
 	}
 	return sessions
 }
+
+func TestResetSessionEnergy(t *testing.T) {
+	var err error
+	serverdb.Instance, err = serverdb.New("sqlite", ":memory:")
+	assert.NoError(t, err)
+
+	db, err := session.NewStore("foo", serverdb.Instance)
+	assert.NoError(t, err)
+
+	clock := clock.NewMock()
+	ctrl := gomock.NewController(t)
+
+	mm := api.NewMockMeter(ctrl)
+	me := api.NewMockMeterEnergy(ctrl)
+	rt := api.NewMockChargeRater(ctrl)
+
+	type EnergyDecorator struct {
+		api.Meter
+		api.MeterEnergy
+	}
+
+	lp := &Loadpoint{
+		log:           util.NewLogger("foo"),
+		clock:         clock,
+		db:            db,
+		chargeMeter:   &EnergyDecorator{Meter: mm, MeterEnergy: me},
+		chargeRater:   rt,
+		chargeTimer:   wrapper.NewChargeTimer(),
+		sessionEnergy: NewEnergyMetrics(),
+	}
+
+	// charging session
+	me.EXPECT().TotalEnergy().Return(10.0, nil)
+	lp.createSession()
+	lp.updateSession(func(session *session.Session) {
+		session.Created = lp.clock.Now()
+	})
+
+	rt.EXPECT().ChargedEnergy().Return(2.0, nil)
+	me.EXPECT().TotalEnergy().Return(12.0, nil)
+	lp.publishChargeProgress()
+	assert.Equal(t, 2e3, lp.getChargedEnergy())
+
+	// reset
+	rt.EXPECT().ChargedEnergy().Return(2.0, nil)
+	me.EXPECT().TotalEnergy().Return(12.0, nil)
+	lp.ResetSessionEnergy()
+	assert.Equal(t, 0.0, lp.getChargedEnergy())
+
+	// reset is persisted
+	var s session.Session
+	assert.NoError(t, serverdb.Instance.First(&s, lp.session.ID).Error)
+	assert.Equal(t, 0.0, s.ChargedEnergy)
+	if assert.NotNil(t, s.MeterStart) {
+		assert.Equal(t, 12.0, *s.MeterStart)
+	}
+
+	// only energy charged after reset is counted
+	rt.EXPECT().ChargedEnergy().Return(3.5, nil)
+	me.EXPECT().TotalEnergy().Return(13.5, nil)
+	lp.publishChargeProgress()
+	assert.Equal(t, 1.5e3, lp.getChargedEnergy())
+}
+
+type transactionCharger struct {
+	api.Charger
+	txn api.ChargeTransaction
+	err error
+}
+
+func (c *transactionCharger) ChargeTransaction() (api.ChargeTransaction, error) {
+	return c.txn, c.err
+}
+
+func TestSessionTransaction(t *testing.T) {
+	charger := &transactionCharger{err: api.ErrNotAvailable}
+
+	lp := &Loadpoint{
+		log:     util.NewLogger("foo"),
+		charger: charger,
+	}
+
+	// no transaction
+	s := new(session.Session)
+	lp.sessionTransaction(s)
+	assert.Empty(t, s.Transaction)
+
+	// previous session's transaction is not linked
+	charger.txn, charger.err = api.ChargeTransaction{ID: "1", MeterStart: lo.ToPtr(1.0), MeterStop: lo.ToPtr(2.0)}, nil
+	lp.sessionTransaction(s)
+	assert.Empty(t, s.Transaction)
+
+	// transaction started by local rfid is linked at charge start
+	charger.txn = api.ChargeTransaction{ID: "2", IdTag: "rfid", External: true, MeterStart: lo.ToPtr(2.0)}
+	lp.sessionTransaction(s)
+	assert.Equal(t, "2", s.Transaction)
+	assert.Equal(t, lo.ToPtr(2.0), s.MeterStart)
+	assert.Nil(t, s.MeterStop)
+
+	// transaction meter stop is used at session stop
+	s.MeterStop = lo.ToPtr(4.9)
+	charger.txn.MeterStop = lo.ToPtr(5.0)
+	lp.sessionTransaction(s)
+	assert.Equal(t, "2", s.Transaction)
+	assert.Equal(t, lo.ToPtr(2.0), s.MeterStart)
+	assert.Equal(t, lo.ToPtr(5.0), s.MeterStop)
+
+	// resumed charging keeps the first transaction
+	charger.txn = api.ChargeTransaction{ID: "3", MeterStart: lo.ToPtr(5.0), MeterStop: lo.ToPtr(7.0)}
+	lp.sessionTransaction(s)
+	assert.Equal(t, "2", s.Transaction)
+	assert.Equal(t, lo.ToPtr(2.0), s.MeterStart)
+	assert.Equal(t, lo.ToPtr(7.0), s.MeterStop)
+}
+
+func TestSessionTag(t *testing.T) {
+	var err error
+	serverdb.Instance, err = serverdb.New("sqlite", ":memory:")
+	assert.NoError(t, err)
+
+	db, err := session.NewStore("foo", serverdb.Instance)
+	assert.NoError(t, err)
+
+	lp := &Loadpoint{
+		log:           util.NewLogger("foo"),
+		clock:         clock.NewMock(),
+		db:            db,
+		sessionEnergy: NewEnergyMetrics(),
+	}
+
+	charge := func(vehicle, identifier string) {
+		lp.createSession()
+		lp.updateSession(func(s *session.Session) {
+			s.Created = lp.clock.Now()
+			s.Vehicle = vehicle
+			s.Identifier = identifier
+		})
+		lp.sessionEnergy.Update(1)
+		lp.stopSession()
+		lp.clearSession()
+		lp.sessionEnergy = NewEnergyMetrics()
+	}
+
+	charge("blue", "0815")
+	charge("", "0815")
+	charge("", "")
+	charge("blue", "")
+
+	s, err := db.Sessions()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"blue", "0815", session.TagGuest, "blue"}, lo.Map(s, func(s session.Session, _ int) string {
+		return s.Tag
+	}))
+
+	assert.Equal(t, []session.TagSummary{
+		{Tag: "0815", Sessions: 1, ChargedEnergy: 1},
+		{Tag: "blue", Sessions: 2, ChargedEnergy: 2},
+		{Tag: session.TagGuest, Sessions: 1, ChargedEnergy: 1},
+	}, s.ByTag())
+}
@@ -0,0 +1,21 @@
+package core
+
+import "time"
+
+// socGraceElapsed tracks vehicle soc api errors recovered by the estimator. Failed updates are retried
+// on the next cycle. It returns true once the last valid soc has been used for longer than the grace period.
+func (lp *Loadpoint) socGraceElapsed() bool {
+	if !lp.socEstimator.Stale() {
+		lp.socFailed = time.Time{}
+		return false
+	}
+
+	// retry
+	lp.socUpdated = time.Time{}
+
+	if lp.socFailed.IsZero() {
+		lp.socFailed = lp.clock.Now()
+	}
+
+	return lp.Soc.Grace > 0 && lp.clock.Since(lp.socFailed) >= lp.Soc.Grace
+}
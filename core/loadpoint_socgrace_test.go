@@ -0,0 +1,74 @@
+package core
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	evbus "github.com/asaskevich/EventBus"
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/soc"
+	"github.com/evcc-io/evcc/util"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSocGrace(t *testing.T) {
+	clock := clock.NewMock()
+	ctrl := gomock.NewController(t)
+	charger := api.NewMockCharger(ctrl)
+	vehicle := api.NewMockVehicle(ctrl)
+
+	vehicle.EXPECT().Capacity().Return(float64(10)).AnyTimes()
+
+	lp := &Loadpoint{
+		log:           util.NewLogger("foo"),
+		bus:           evbus.New(),
+		clock:         clock,
+		charger:       charger,
+		vehicle:       vehicle,
+		socEstimator:  soc.NewEstimator(util.NewLogger("foo"), charger, vehicle, false, soc.ChargeEfficiency),
+		sessionEnergy: NewEnergyMetrics(),
+		status:        api.StatusC,
+		Soc: SocConfig{
+			Grace: 5 * time.Minute,
+		},
+	}
+
+	tc := []struct {
+		err error
+		soc float64 // expected soc
+	}{
+		{nil, 50},
+		// brief error burst keeps last valid soc
+		{errors.New("foo"), 50},
+		{errors.New("foo"), 50},
+		{nil, 52},
+		// grace elapsed
+		{errors.New("foo"), 52},
+		{errors.New("foo"), 52},
+		{errors.New("foo"), 52},
+		{errors.New("foo"), 52},
+		{errors.New("foo"), 52},
+		{errors.New("foo"), 0},
+		// recovered
+		{nil, 55},
+	}
+
+	for i, tc := range tc {
+		if tc.err != nil {
+			vehicle.EXPECT().Soc().Return(0.0, tc.err)
+		} else {
+			vehicle.EXPECT().Soc().Return(tc.soc, nil)
+		}
+
+		lp.publishSocAndRange()
+		assert.Equal(t, tc.soc, lp.vehicleSoc, i)
+
+		// failed updates are retried
+		assert.Equal(t, tc.err != nil, lp.socUpdated.IsZero(), i)
+
+		clock.Add(time.Minute)
+	}
+}
@@ -0,0 +1,64 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+)
+
+// validateSurplus checks that starting pv charging requires at least the surplus needed to sustain it
+func validateSurplus(enable, disable ThresholdConfig) error {
+	if enable.Surplus < 0 || disable.Surplus < 0 {
+		return errors.New("pv surplus must not be negative")
+	}
+
+	if enable.Surplus > 0 && enable.Surplus < disable.Surplus {
+		return fmt.Errorf("pv enable surplus (%.0fW) must not be below disable surplus (%.0fW)", enable.Surplus, disable.Surplus)
+	}
+
+	return nil
+}
+
+// pvSurplus returns the pv surplus available to the loadpoint including its own charge power
+func (lp *Loadpoint) pvSurplus(sitePower float64) float64 {
+	return lp.chargePower - sitePower
+}
+
+// pvEnableReached returns true if the surplus is sufficient to start pv charging.
+// The enable surplus replaces the enable threshold if configured.
+func (lp *Loadpoint) pvEnableReached(sitePower, targetCurrent, minCurrent float64) bool {
+	if lp.Enable.Surplus > 0 {
+		surplus := lp.pvSurplus(sitePower)
+		if surplus >= lp.Enable.Surplus {
+			lp.log.DEBUG.Printf("pv surplus %.0fW >= %.0fW enable surplus", surplus, lp.Enable.Surplus)
+			return true
+		}
+		return false
+	}
+
+	if lp.Enable.Threshold == 0 && targetCurrent >= minCurrent || lp.Enable.Threshold != 0 && sitePower <= lp.Enable.Threshold {
+		lp.log.DEBUG.Printf("site power %.0fW <= %.0fW enable threshold", sitePower, lp.Enable.Threshold)
+		return true
+	}
+
+	return false
+}
+
+// pvDisableReached returns true if the surplus is insufficient to sustain pv charging.
+// The disable surplus replaces the disable threshold if configured.
+func (lp *Loadpoint) pvDisableReached(sitePower float64) bool {
+	if lp.Disable.Surplus > 0 {
+		surplus := lp.pvSurplus(sitePower)
+		if surplus < lp.Disable.Surplus {
+			lp.log.DEBUG.Printf("pv surplus %.0fW < %.0fW disable surplus", surplus, lp.Disable.Surplus)
+			return true
+		}
+		return false
+	}
+
+	if sitePower >= lp.Disable.Threshold {
+		lp.log.DEBUG.Printf("site power %.0fW >= %.0fW disable threshold", sitePower, lp.Disable.Threshold)
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,100 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/util"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPVSurplusThresholds(t *testing.T) {
+	const dt = time.Minute
+	const phases = 3
+	const minPower = minA * 100 * phases
+
+	type se struct {
+		site    float64
+		delay   time.Duration // test case delay since start
+		current float64
+	}
+	tc := []struct {
+		title   string
+		enabled bool
+		series  []se
+	}{
+		{"min power met but below enable surplus", false, []se{
+			{-2000, 0, 0},
+			{-2000, dt + 1, 0},
+		}},
+		{"enable surplus met", false, []se{
+			{-3000, 0, 0},
+			{-3000, dt - 1, 0},
+			{-3000, dt + 1, minA},
+		}},
+		{"enable surplus not sustained resets timer", false, []se{
+			{-3000, 0, 0},
+			{-2999, dt - 1, 0},
+			{-3000, dt + 1, 0},
+			{-3000, 2*dt + 1, minA},
+		}},
+		{"sustain above disable surplus while importing", true, []se{
+			{minPower - 1300, 0, minA},
+			{minPower - 1300, dt + 1, minA},
+		}},
+		{"disable below disable surplus", true, []se{
+			{minPower - 900, 0, minA},
+			{minPower - 900, dt - 1, minA},
+			{minPower - 900, dt + 1, 0},
+		}},
+	}
+
+	for _, tc := range tc {
+		t.Log(tc.title)
+
+		clck := clock.NewMock()
+		ctrl := gomock.NewController(t)
+		charger := api.NewMockCharger(ctrl)
+
+		Voltage = 100
+		lp := &Loadpoint{
+			log:            util.NewLogger("foo"),
+			clock:          clck,
+			charger:        charger,
+			MinCurrent:     minA,
+			MaxCurrent:     maxA,
+			phases:         phases,
+			measuredPhases: phases,
+			status:         api.StatusC,
+			Enable:         ThresholdConfig{Delay: dt, Surplus: 3000},
+			Disable:        ThresholdConfig{Delay: dt, Surplus: 1000},
+		}
+
+		if tc.enabled {
+			lp.chargeCurrent = minA
+			lp.chargePower = minPower
+		}
+
+		start := clck.Now()
+
+		for step, se := range tc.series {
+			clck.Set(start.Add(se.delay))
+
+			lp.enabled = tc.enabled
+			current := lp.pvMaxCurrent(api.ModePV, se.site, false, false)
+			assert.Equal(t, se.current, current, "%s step %d", tc.title, step)
+		}
+	}
+}
+
+func TestValidateSurplus(t *testing.T) {
+	assert.NoError(t, validateSurplus(ThresholdConfig{}, ThresholdConfig{}))
+	assert.NoError(t, validateSurplus(ThresholdConfig{Surplus: 3000}, ThresholdConfig{Surplus: 1000}))
+	assert.NoError(t, validateSurplus(ThresholdConfig{Surplus: 1000}, ThresholdConfig{Surplus: 1000}))
+	assert.NoError(t, validateSurplus(ThresholdConfig{}, ThresholdConfig{Surplus: 1000}))
+	assert.Error(t, validateSurplus(ThresholdConfig{Surplus: 1000}, ThresholdConfig{Surplus: 3000}))
+	assert.Error(t, validateSurplus(ThresholdConfig{Surplus: -1}, ThresholdConfig{}))
+}
@@ -1,17 +1,21 @@
 package core
 
 import (
+	"sync"
 	"testing"
 	"time"
 
 	evbus "github.com/asaskevich/EventBus"
 	"github.com/benbjohnson/clock"
 	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/coordinator"
+	"github.com/evcc-io/evcc/core/planner"
 	"github.com/evcc-io/evcc/core/soc"
 	"github.com/evcc-io/evcc/push"
 	"github.com/evcc-io/evcc/util"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 const (
@@ -388,7 +392,7 @@ func TestDisableAndEnableAtTargetSoc(t *testing.T) {
 	// wrap vehicle with estimator
 	vehicle.EXPECT().Capacity().Return(float64(10))
 	vehicle.EXPECT().Phases().Return(0).AnyTimes()
-	socEstimator := soc.NewEstimator(util.NewLogger("foo"), charger, vehicle, false)
+	socEstimator := soc.NewEstimator(util.NewLogger("foo"), charger, vehicle, false, soc.ChargeEfficiency)
 
 	lp := &Loadpoint{
 		log:         util.NewLogger("foo"),
@@ -784,3 +788,231 @@ func TestMinSoc(t *testing.T) {
 		assert.Equal(t, tc.res, lp.minSocNotReached(), tc)
 	}
 }
+
+func TestMinOnOffDuration(t *testing.T) {
+	clock := clock.NewMock()
+	ctrl := gomock.NewController(t)
+	charger := api.NewMockCharger(ctrl)
+
+	lp := &Loadpoint{
+		log:            util.NewLogger("foo"),
+		bus:            evbus.New(),
+		clock:          clock,
+		charger:        charger,
+		wakeUpTimer:    NewTimer(),
+		MinCurrent:     minA,
+		MaxCurrent:     maxA,
+		GuardDuration:  time.Minute,
+		MinOnDuration:  10 * time.Minute,
+		MinOffDuration: 5 * time.Minute,
+	}
+
+	// start charging
+	charger.EXPECT().MaxCurrent(int64(minA)).Return(nil)
+	charger.EXPECT().Enable(true).Return(nil)
+	require.NoError(t, lp.setLimit(minA, false))
+	require.True(t, lp.enabled)
+	ctrl.Finish()
+
+	// guard elapsed, but not minimum on duration
+	clock.Add(9 * time.Minute)
+	require.NoError(t, lp.setLimit(0, false))
+	assert.True(t, lp.enabled, "stopped before minimum on duration")
+	ctrl.Finish()
+
+	// minimum on duration elapsed
+	clock.Add(time.Minute)
+	charger.EXPECT().Enable(false).Return(nil)
+	require.NoError(t, lp.setLimit(0, false))
+	assert.False(t, lp.enabled)
+	ctrl.Finish()
+
+	// guard elapsed, but not minimum off duration
+	clock.Add(4 * time.Minute)
+	require.NoError(t, lp.setLimit(minA, false))
+	assert.False(t, lp.enabled, "started before minimum off duration")
+	ctrl.Finish()
+
+	// minimum off duration elapsed
+	clock.Add(time.Minute)
+	charger.EXPECT().Enable(true).Return(nil)
+	require.NoError(t, lp.setLimit(minA, false))
+	assert.True(t, lp.enabled)
+	ctrl.Finish()
+
+	// forced stop overrides minimum on duration
+	charger.EXPECT().Enable(false).Return(nil)
+	require.NoError(t, lp.setLimit(0, true))
+	assert.False(t, lp.enabled)
+	ctrl.Finish()
+}
+
+func TestGuardHoldDuration(t *testing.T) {
+	tc := []struct {
+		guard, on, off  time.Duration
+		enable, disable time.Duration
+	}{
+		{5 * time.Minute, 0, 0, 5 * time.Minute, 5 * time.Minute},
+		{5 * time.Minute, time.Minute, time.Minute, 5 * time.Minute, 5 * time.Minute},
+		{5 * time.Minute, 10 * time.Minute, 0, 5 * time.Minute, 10 * time.Minute},
+		{5 * time.Minute, 0, 15 * time.Minute, 15 * time.Minute, 5 * time.Minute},
+	}
+
+	for _, tc := range tc {
+		lp := &Loadpoint{
+			GuardDuration:  tc.guard,
+			MinOnDuration:  tc.on,
+			MinOffDuration: tc.off,
+		}
+
+		assert.Equal(t, tc.enable, lp.guardHoldDuration(true), tc)
+		assert.Equal(t, tc.disable, lp.guardHoldDuration(false), tc)
+	}
+}
+
+func TestSetTarget(t *testing.T) {
+	Voltage = 100
+	clock := clock.NewMock()
+
+	lp := &Loadpoint{
+		log:           util.NewLogger("foo"),
+		clock:         clock,
+		charger:       api.NewMockCharger(gomock.NewController(t)),
+		MaxCurrent:    10,
+		phases:        3,
+		targetEnergy:  6, // kWh at 3kW
+		sessionEnergy: NewEnergyMetrics(),
+		coordinator:   coordinator.NewDummy(),
+		planner:       planner.New(util.NewLogger("foo"), nil),
+	}
+
+	// unreachable target is rejected without changes
+	_, _, err := lp.SetTarget(80, clock.Now().Add(time.Hour))
+	assert.ErrorContains(t, err, "not reachable")
+	assert.Equal(t, 0, lp.GetTargetSoc())
+	assert.True(t, lp.GetTargetTime().IsZero())
+
+	// invalid targets are rejected
+	_, _, err = lp.SetTarget(101, clock.Now().Add(3*time.Hour))
+	assert.Error(t, err)
+	_, _, err = lp.SetTarget(80, clock.Now().Add(-time.Hour))
+	assert.Error(t, err)
+
+	// reachable target is applied including plan
+	finishAt := clock.Now().Add(3 * time.Hour)
+	duration, plan, err := lp.SetTarget(80, finishAt)
+	require.NoError(t, err)
+	assert.Equal(t, 2*time.Hour, duration)
+	assert.Equal(t, 80, lp.GetTargetSoc())
+	assert.Equal(t, finishAt, lp.GetTargetTime())
+	require.Len(t, plan, 1)
+	assert.Equal(t, finishAt, plan[0].End)
+	assert.Equal(t, finishAt.Add(-2*time.Hour), plan[0].Start)
+}
+
+func TestSetTargetConcurrent(t *testing.T) {
+	Voltage = 100
+	clock := clock.NewMock()
+
+	lp := &Loadpoint{
+		log:           util.NewLogger("foo"),
+		clock:         clock,
+		charger:       api.NewMockCharger(gomock.NewController(t)),
+		MaxCurrent:    10,
+		phases:        3,
+		sessionEnergy: NewEnergyMetrics(),
+		coordinator:   coordinator.NewDummy(),
+	}
+
+	var wg sync.WaitGroup
+	for i := 1; i <= 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, _, err := lp.SetTarget(10*i, clock.Now().Add(time.Duration(i)*time.Hour))
+			assert.NoError(t, err)
+		}(i)
+	}
+	wg.Wait()
+
+	// soc and time always belong to the same update
+	soc := lp.GetTargetSoc()
+	assert.Equal(t, clock.Now().Add(time.Duration(soc/10)*time.Hour), lp.GetTargetTime())
+}
+
+type currentLimitVehicle struct {
+	*api.MockVehicle
+	min, max float64
+}
+
+func (v *currentLimitVehicle) ChargeCurrentLimits() (float64, float64) {
+	return v.min, v.max
+}
+
+func TestVehicleCurrentLimits(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	tc := []struct {
+		lpMin, lpMax, vMin, vMax float64
+		expMin, expMax           float64
+	}{
+		{6, 16, 0, 0, 6, 16},   // no vehicle limits
+		{6, 16, 8, 10, 8, 10},  // vehicle more restrictive
+		{6, 16, 4, 32, 6, 16},  // loadpoint more restrictive
+		{6, 16, 0, 10, 6, 10},  // vehicle max only
+		{6, 16, 20, 0, 16, 16}, // vehicle min above loadpoint max
+		{10, 16, 6, 8, 8, 8},   // vehicle max below loadpoint min
+	}
+
+	for _, tc := range tc {
+		t.Log(tc)
+
+		lp := &Loadpoint{
+			log:        util.NewLogger("foo"),
+			MinCurrent: tc.lpMin,
+			MaxCurrent: tc.lpMax,
+			vehicle:    &currentLimitVehicle{api.NewMockVehicle(ctrl), tc.vMin, tc.vMax},
+		}
+
+		assert.Equal(t, tc.expMin, lp.effectiveMinCurrent(), "min")
+		assert.Equal(t, tc.expMax, lp.effectiveMaxCurrent(), "max")
+	}
+}
+
+func TestVehicleCurrentClamping(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	charger := api.NewMockCharger(ctrl)
+
+	vehicle := &currentLimitVehicle{api.NewMockVehicle(ctrl), 8, 10}
+	vehicle.MockVehicle.EXPECT().Phases().Return(0).AnyTimes()
+
+	Voltage = 100
+	lp := &Loadpoint{
+		log:            util.NewLogger("foo"),
+		bus:            evbus.New(),
+		clock:          clock.NewMock(),
+		charger:        charger,
+		wakeUpTimer:    NewTimer(),
+		MinCurrent:     minA,
+		MaxCurrent:     maxA,
+		phases:         3,
+		measuredPhases: 3,
+		status:         api.StatusC,
+		vehicle:        vehicle,
+	}
+
+	// fast charging is limited to vehicle max current
+	charger.EXPECT().MaxCurrent(int64(10)).Return(nil)
+	charger.EXPECT().Enable(true).Return(nil)
+	require.NoError(t, lp.fastCharging())
+	assert.Equal(t, 10.0, lp.chargeCurrent)
+	assert.Equal(t, 3*100*10.0, lp.GetMaxPower())
+
+	// pv surplus above vehicle max current
+	assert.Equal(t, 10.0, lp.pvMaxCurrent(api.ModePV, -3*100*maxA, false, false))
+
+	// current below vehicle min current disables charging
+	charger.EXPECT().Enable(false).Return(nil)
+	require.NoError(t, lp.setLimit(7, true))
+	assert.False(t, lp.enabled)
+}
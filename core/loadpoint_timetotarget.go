@@ -0,0 +1,52 @@
+package core
+
+import (
+	"time"
+)
+
+// GetTimeToTarget is the computed duration to reach the charge target at the current charge power
+func (lp *Loadpoint) GetTimeToTarget() time.Duration {
+	lp.Lock()
+	defer lp.Unlock()
+	return lp.chargeTimeToTarget
+}
+
+// timeToTarget computes the duration to reach the target energy or soc from measured or, if not yet charging,
+// commanded power. It does not depend on the vehicle providing a finish estimate. (no mutex)
+func (lp *Loadpoint) timeToTarget() time.Duration {
+	if !lp.connected() || !lp.enabled {
+		return 0
+	}
+
+	power := lp.chargePower
+	if power <= 0 {
+		power = lp.chargeCurrent * float64(lp.activePhases()) * Voltage
+	}
+	if power <= 0 {
+		return 0
+	}
+
+	energy, ok := lp.remainingChargeEnergy()
+	if !ok {
+		if lp.socEstimator == nil || lp.vehicleSoc <= 0 {
+			return 0
+		}
+
+		targetSoc := lp.Soc.target
+		if targetSoc == 0 {
+			targetSoc = 100
+		}
+
+		energy = lp.socEstimator.RemainingChargeEnergy(targetSoc)
+	}
+
+	return time.Duration(energy * 1e3 / power * float64(time.Hour)).Round(time.Second)
+}
+
+// updateTimeToTarget recomputes and publishes the time to target as charge power changes (no mutex)
+func (lp *Loadpoint) updateTimeToTarget() {
+	if d := lp.timeToTarget(); d != lp.chargeTimeToTarget {
+		lp.chargeTimeToTarget = d
+		lp.publish("chargeTimeToTarget", d)
+	}
+}
@@ -0,0 +1,76 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/soc"
+	"github.com/evcc-io/evcc/util"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimeToTarget(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	vehicle := api.NewMockVehicle(ctrl)
+	vehicle.EXPECT().Capacity().Return(50.0).AnyTimes()
+	vehicle.EXPECT().Phases().Return(0).AnyTimes()
+	vehicle.EXPECT().Soc().Return(50.0, nil).AnyTimes()
+
+	estimator := soc.NewEstimator(util.NewLogger("foo"), nil, vehicle, false, 1)
+	_, err := estimator.Soc(0)
+	assert.NoError(t, err)
+
+	lp := &Loadpoint{
+		log:            util.NewLogger("foo"),
+		vehicle:        vehicle,
+		socEstimator:   estimator,
+		sessionEnergy:  NewEnergyMetrics(),
+		status:         api.StatusC,
+		enabled:        true,
+		phases:         3,
+		measuredPhases: 3,
+		chargeCurrent:  16,
+		vehicleSoc:     50,
+		Soc: SocConfig{
+			target: 80,
+		},
+	}
+
+	// 15kWh remaining at 10kW
+	lp.chargePower = 10e3
+	lp.updateTimeToTarget()
+	assert.Equal(t, 90*time.Minute, lp.GetTimeToTarget())
+
+	// estimate follows power
+	lp.chargePower = 5e3
+	lp.updateTimeToTarget()
+	assert.Equal(t, 3*time.Hour, lp.GetTimeToTarget())
+
+	// commanded power before charging starts
+	lp.chargePower = 0
+	lp.status = api.StatusB
+	lp.chargeCurrent = 10
+	lp.updateTimeToTarget()
+	assert.Equal(t, time.Duration(15e3/(3*Voltage*10)*float64(time.Hour)).Round(time.Second), lp.GetTimeToTarget())
+
+	// target reached
+	lp.Soc.target = 50
+	lp.updateTimeToTarget()
+	assert.Zero(t, lp.GetTimeToTarget())
+
+	// energy target without vehicle
+	lp.vehicle = nil
+	lp.socEstimator = nil
+	lp.targetEnergy = 11
+	lp.chargePower = 11e3
+	lp.updateTimeToTarget()
+	assert.Equal(t, time.Hour, lp.GetTimeToTarget())
+
+	// disconnected
+	lp.status = api.StatusA
+	lp.updateTimeToTarget()
+	assert.Zero(t, lp.GetTimeToTarget())
+}
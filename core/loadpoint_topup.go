@@ -0,0 +1,30 @@
+package core
+
+// targetSocTopUpReached holds the target soc as reached once charging stopped there until the vehicle's
+// self-discharge drops the soc below the target by the top-up margin. The top-up then charges back
+// to the target according to the charge mode (no mutex).
+func (lp *Loadpoint) targetSocTopUpReached() bool {
+	reached := lp.targetSocDeadbandReached()
+	if lp.Soc.TopUp <= 0 {
+		return reached
+	}
+
+	if reached {
+		lp.topUpTarget = lp.Soc.target
+		return true
+	}
+
+	if lp.topUpTarget != lp.Soc.target {
+		lp.topUpTarget = 0
+		return false
+	}
+
+	if lp.vehicleSoc > float64(lp.Soc.target)-lp.Soc.TopUp {
+		return true
+	}
+
+	lp.log.DEBUG.Printf("vehicle soc %.1f%% below %d%% target soc by top-up margin, topping up", lp.vehicleSoc, lp.Soc.target)
+	lp.topUpTarget = 0
+
+	return false
+}
@@ -0,0 +1,99 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/util"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTargetSocTopUp(t *testing.T) {
+	tc := []struct {
+		topUp   float64
+		restart int // number of charging restarts
+	}{
+		{0, 50}, // trickle charging on every soc drop
+		{5, 10}, // periodic top-up every 5% self-discharge
+	}
+
+	for _, tc := range tc {
+		t.Logf("%+v", tc)
+
+		ctrl := gomock.NewController(t)
+		vehicle := api.NewMockVehicle(ctrl)
+
+		lp := &Loadpoint{
+			log:     util.NewLogger("foo"),
+			vehicle: vehicle,
+			status:  api.StatusB,
+			Soc: SocConfig{
+				target: 80,
+				TopUp:  tc.topUp,
+			},
+		}
+
+		var restart int
+		charging := true
+		lp.vehicleSoc = 70
+
+		// simulate 1% charge or 0.5% self-discharge per cycle
+		for i := 0; i < 160; i++ {
+			reached := lp.targetSocReached()
+			if charging == reached {
+				charging = !reached
+				if charging {
+					restart++
+				}
+			}
+
+			if charging {
+				lp.vehicleSoc++
+			} else {
+				lp.vehicleSoc -= 0.5
+			}
+
+			if i < 10 {
+				continue
+			}
+
+			assert.GreaterOrEqual(t, lp.vehicleSoc, 80-tc.topUp-1)
+			assert.LessOrEqual(t, lp.vehicleSoc, 81.0)
+		}
+
+		assert.Equal(t, tc.restart, restart)
+	}
+}
+
+func TestTargetSocTopUpTargetChange(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	lp := &Loadpoint{
+		log:     util.NewLogger("foo"),
+		vehicle: api.NewMockVehicle(ctrl),
+		status:  api.StatusB,
+		Soc: SocConfig{
+			target: 80,
+			TopUp:  5,
+		},
+	}
+
+	lp.vehicleSoc = 80
+	assert.True(t, lp.targetSocReached())
+
+	// hold
+	lp.vehicleSoc = 76
+	assert.True(t, lp.targetSocReached())
+
+	// raised target charges immediately
+	lp.Soc.target = 90
+	assert.False(t, lp.targetSocReached())
+
+	// disconnect clears hold
+	lp.vehicleSoc = 90
+	assert.True(t, lp.targetSocReached())
+	lp.topUpTarget = 0
+	lp.vehicleSoc = 89
+	assert.False(t, lp.targetSocReached())
+}
@@ -36,6 +36,15 @@ func (lp *Loadpoint) setVehicleIdentifier(id string) {
 	}
 }
 
+// setVehicleManual sets the manual vehicle assignment
+func (lp *Loadpoint) setVehicleManual(manual bool) {
+	lp.vehicleMux.Lock()
+	lp.vehicleManual = manual
+	lp.vehicleMux.Unlock()
+
+	lp.publish(vehicleManual, manual)
+}
+
 // identifyVehicle reads vehicle identification from charger
 func (lp *Loadpoint) identifyVehicle() {
 	identifier, ok := lp.charger.(api.Identifier)
@@ -59,6 +68,11 @@ func (lp *Loadpoint) identifyVehicle() {
 	if id != "" {
 		lp.log.DEBUG.Println("charger vehicle id:", id)
 
+		// don't override manual assignment
+		if lp.GetVehicleManual() {
+			return
+		}
+
 		if vehicle := lp.selectVehicleByID(id); vehicle != nil {
 			lp.stopVehicleDetection()
 			lp.setActiveVehicle(vehicle)
@@ -138,13 +152,16 @@ func (lp *Loadpoint) setActiveVehicle(vehicle api.Vehicle) {
 
 	if vehicle != nil {
 		lp.socUpdated = time.Time{}
+		lp.socFailed = time.Time{}
+		lp.limitSynced, lp.limitPrevious = 0, 0
+		lp.vehicleCurrentLimit = 0
 
 		// resolve optional config
 		var estimate bool
 		if lp.Soc.Estimate == nil || *lp.Soc.Estimate {
 			estimate = true
 		}
-		lp.socEstimator = soc.NewEstimator(lp.log, lp.charger, vehicle, estimate)
+		lp.socEstimator = soc.NewEstimator(lp.log, lp.charger, vehicle, estimate, lp.chargeEfficiency(vehicle))
 
 		lp.publish(vehiclePresent, true)
 		lp.publish(vehicleTitle, vehicle.Title())
@@ -288,6 +305,11 @@ func (lp *Loadpoint) vehicleUnidentified() bool {
 
 // vehicleDefaultOrDetect will assign and update default vehicle or start detection
 func (lp *Loadpoint) vehicleDefaultOrDetect() {
+	// assigned manually before connecting
+	if lp.GetVehicleManual() {
+		return
+	}
+
 	if lp.defaultVehicle != nil {
 		if lp.vehicle != lp.defaultVehicle {
 			lp.setActiveVehicle(lp.defaultVehicle)
@@ -323,7 +345,7 @@ func (lp *Loadpoint) stopVehicleDetection() {
 
 // identifyVehicleByStatus validates if the active vehicle is still connected to the loadpoint
 func (lp *Loadpoint) identifyVehicleByStatus() {
-	if len(lp.coordinatedVehicles()) == 0 {
+	if len(lp.coordinatedVehicles()) == 0 || lp.GetVehicleManual() {
 		return
 	}
 
@@ -370,8 +392,8 @@ func (lp *Loadpoint) vehicleClimatePollAllowed() bool {
 
 // vehicleSocPollAllowed validates charging state against polling mode
 func (lp *Loadpoint) vehicleSocPollAllowed() bool {
-	// always update soc when charging
-	if lp.charging() {
+	// always update soc when charging or refresh requested
+	if lp.charging() || lp.socRefresh {
 		return true
 	}
 
@@ -416,3 +438,18 @@ func (lp *Loadpoint) vehicleClimateActive() bool {
 
 	return false
 }
+
+// chargeEfficiency returns the grid to battery efficiency of the vehicle or loadpoint
+func (lp *Loadpoint) chargeEfficiency(vehicle api.Vehicle) float64 {
+	if v, ok := vehicle.(api.ChargeEfficiencyProvider); ok {
+		if eff := v.ChargeEfficiency(); eff > 0 && eff <= 1 {
+			return eff
+		}
+	}
+
+	if lp.ChargeEfficiency > 0 && lp.ChargeEfficiency <= 1 {
+		return lp.ChargeEfficiency
+	}
+
+	return soc.ChargeEfficiency
+}
@@ -39,7 +39,7 @@ func TestPublishSocAndRange(t *testing.T) {
 		chargeMeter:   &Null{}, // silence nil panics
 		chargeRater:   &Null{}, // silence nil panics
 		chargeTimer:   &Null{}, // silence nil panics
-		socEstimator:  soc.NewEstimator(log, charger, vehicle, false),
+		socEstimator:  soc.NewEstimator(log, charger, vehicle, false, soc.ChargeEfficiency),
 		sessionEnergy: NewEnergyMetrics(),
 		MinCurrent:    minA,
 		MaxCurrent:    maxA,
@@ -292,6 +292,79 @@ func TestApplyVehicleDefaults(t *testing.T) {
 	assertConfig(lp, oi)
 }
 
+func TestManualVehicle(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	newVehicle := func(title, id string) *api.MockVehicle {
+		v := api.NewMockVehicle(ctrl)
+		v.EXPECT().Title().Return(title).AnyTimes()
+		v.EXPECT().Icon().Return("").AnyTimes()
+		v.EXPECT().Capacity().AnyTimes()
+		v.EXPECT().Phases().AnyTimes()
+		v.EXPECT().OnIdentified().AnyTimes()
+		v.EXPECT().Identifiers().Return([]string{id}).AnyTimes()
+		return v
+	}
+
+	manual := newVehicle("manual", "foo")
+	detected := newVehicle("detected", "bar")
+
+	charger := struct {
+		*api.MockCharger
+		*api.MockIdentifier
+	}{
+		MockCharger:    api.NewMockCharger(ctrl),
+		MockIdentifier: api.NewMockIdentifier(ctrl),
+	}
+
+	lp := NewLoadpoint(util.NewLogger("foo"))
+	lp.charger = charger
+	lp.defaultVehicle = detected
+	lp.coordinator = coordinator.NewAdapter(lp, coordinator.New(util.NewLogger("foo"), []api.Vehicle{manual, detected}))
+
+	// populate channels
+	x, y, z := createChannels(t)
+	attachChannels(lp, x, y, z)
+
+	// assigned before connecting, default vehicle is not applied
+	lp.SetVehicle(manual)
+	assert.True(t, lp.GetVehicleManual())
+
+	lp.evVehicleConnectHandler()
+	assert.Equal(t, manual, lp.GetVehicle())
+	assert.True(t, lp.vehicleDetect.IsZero(), "detection must not be started")
+
+	// charger identifies another vehicle
+	charger.MockIdentifier.EXPECT().Identify().Return("bar", nil)
+	lp.identifyVehicle()
+	assert.Equal(t, manual, lp.GetVehicle())
+	assert.Equal(t, "bar", lp.vehicleIdentifier)
+
+	// status detection
+	lp.identifyVehicleByStatus()
+	assert.Equal(t, manual, lp.GetVehicle())
+
+	// manually unassigned vehicle remains unassigned
+	lp.SetVehicle(nil)
+	lp.identifyVehicleByStatus()
+	assert.Nil(t, lp.GetVehicle())
+	assert.True(t, lp.GetVehicleManual())
+
+	// restarting detection ends manual assignment
+	lp.StartVehicleDetection()
+	assert.False(t, lp.GetVehicleManual())
+
+	// manual assignment ends with the session
+	lp.SetVehicle(manual)
+	lp.evVehicleDisconnectHandler()
+	assert.False(t, lp.GetVehicleManual())
+	assert.Equal(t, detected, lp.GetVehicle())
+
+	charger.MockIdentifier.EXPECT().Identify().Return("foo", nil)
+	lp.identifyVehicle()
+	assert.Equal(t, manual, lp.GetVehicle())
+}
+
 func TestReconnectVehicle(t *testing.T) {
 	tc := []struct {
 		name      string
@@ -373,3 +446,97 @@ func TestReconnectVehicle(t *testing.T) {
 		})
 	}
 }
+
+type efficiencyVehicle struct {
+	*api.MockVehicle
+	efficiency float64
+}
+
+func (v *efficiencyVehicle) ChargeEfficiency() float64 {
+	return v.efficiency
+}
+
+func TestChargeEfficiency(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	tc := []struct {
+		lp, vehicle, expected float64
+	}{
+		{0, 0, soc.ChargeEfficiency},   // default
+		{0.85, 0, 0.85},                // loadpoint
+		{0.85, 0.95, 0.95},             // vehicle takes precedence
+		{1.5, 0, soc.ChargeEfficiency}, // invalid
+	}
+
+	for _, tc := range tc {
+		t.Log(tc)
+
+		vehicle := &efficiencyVehicle{api.NewMockVehicle(ctrl), tc.vehicle}
+
+		lp := NewLoadpoint(util.NewLogger("foo"))
+		lp.ChargeEfficiency = tc.lp
+		lp.vehicle = vehicle
+
+		assert.Equal(t, tc.expected, lp.chargeEfficiency(vehicle))
+
+		// grid-side energy is kept for billing
+		lp.sessionEnergy.Update(10)
+		assert.Equal(t, 10e3, lp.getChargedEnergy())
+		assert.InDelta(t, 10e3*tc.expected, lp.getChargedVehicleEnergy(), 1e-6)
+	}
+}
+
+func TestVehicleDefaultMode(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	newVehicle := func(title string, mode api.ChargeMode) *api.MockVehicle {
+		v := api.NewMockVehicle(ctrl)
+		v.EXPECT().Title().Return(title).AnyTimes()
+		v.EXPECT().Icon().Return("").AnyTimes()
+		v.EXPECT().Capacity().AnyTimes()
+		v.EXPECT().Phases().AnyTimes()
+		v.EXPECT().OnIdentified().Return(api.ActionConfig{Mode: &mode}).AnyTimes()
+		return v
+	}
+
+	work := newVehicle("work", api.ModePV)
+	family := newVehicle("family", api.ModeNow)
+
+	lp := NewLoadpoint(util.NewLogger("foo"))
+	lp.collectDefaults()
+
+	// populate channels
+	x, y, z := createChannels(t)
+	attachChannels(lp, x, y, z)
+
+	lp.status = api.StatusB
+
+	// mode follows the assigned vehicle
+	lp.setActiveVehicle(work)
+	assert.Equal(t, api.ModePV, lp.GetMode())
+
+	lp.setActiveVehicle(family)
+	assert.Equal(t, api.ModeNow, lp.GetMode())
+
+	// manual mode change overrides the vehicle default for the session
+	lp.SetMode(api.ModeMinPV)
+	lp.setActiveVehicle(work)
+	assert.Equal(t, api.ModeMinPV, lp.GetMode())
+
+	// next session applies the vehicle default again
+	lp.status = api.StatusA
+	lp.evVehicleDisconnectHandler()
+	assert.False(t, lp.modeManual)
+
+	lp.status = api.StatusB
+	lp.setActiveVehicle(family)
+	assert.Equal(t, api.ModeNow, lp.GetMode())
+
+	// mode changes while disconnected do not override the vehicle default
+	lp.status = api.StatusA
+	lp.setActiveVehicle(nil)
+	lp.SetMode(api.ModeOff)
+	lp.status = api.StatusB
+	lp.setActiveVehicle(work)
+	assert.Equal(t, api.ModePV, lp.GetMode())
+}
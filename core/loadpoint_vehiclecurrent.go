@@ -0,0 +1,32 @@
+package core
+
+import (
+	"errors"
+
+	"github.com/evcc-io/evcc/api"
+)
+
+// updateVehicleCurrentLimit reads the charge current limit enforced by the vehicle.
+// The last limit is kept if the vehicle cannot be read. (no mutex)
+func (lp *Loadpoint) updateVehicleCurrentLimit() {
+	v, ok := lp.GetVehicle().(api.ChargeCurrentLimitReporter)
+	if !ok {
+		lp.vehicleCurrentLimit = 0
+		return
+	}
+
+	limit, err := v.ChargeCurrentLimit()
+	if err != nil {
+		if !errors.Is(err, api.ErrNotAvailable) {
+			lp.log.ERROR.Printf("vehicle current limit: %v", err)
+		}
+		return
+	}
+
+	if limit != lp.vehicleCurrentLimit {
+		lp.log.DEBUG.Printf("vehicle current limit: %.3gA", limit)
+		lp.publish("vehicleCurrentLimit", limit)
+	}
+
+	lp.vehicleCurrentLimit = limit
+}
@@ -0,0 +1,101 @@
+package core
+
+import (
+	"testing"
+
+	evbus "github.com/asaskevich/EventBus"
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/util"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type enforcedLimitVehicle struct {
+	api.Vehicle
+	limit float64
+}
+
+func (v *enforcedLimitVehicle) ChargeCurrentLimit() (float64, error) {
+	return v.limit, nil
+}
+
+func TestVehicleCurrentLimit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	charger := api.NewMockCharger(ctrl)
+
+	vehicle := &enforcedLimitVehicle{Vehicle: api.NewMockVehicle(ctrl), limit: 10}
+
+	lp := &Loadpoint{
+		log:            util.NewLogger("foo"),
+		bus:            evbus.New(),
+		clock:          clock.NewMock(),
+		charger:        charger,
+		vehicle:        vehicle,
+		wakeUpTimer:    NewTimer(),
+		MinCurrent:     minA,
+		MaxCurrent:     maxA,
+		phases:         3,
+		measuredPhases: 3,
+		status:         api.StatusC,
+		enabled:        true,
+		chargeCurrent:  minA,
+	}
+
+	// commanded current respects the vehicle's limit
+	lp.updateVehicleCurrentLimit()
+	charger.EXPECT().MaxCurrent(int64(10)).Return(nil)
+	require.NoError(t, lp.setLimit(maxA, false))
+	assert.Equal(t, 10.0, lp.chargeCurrent)
+
+	// lower currents are not affected
+	charger.EXPECT().MaxCurrent(int64(8)).Return(nil)
+	require.NoError(t, lp.setLimit(8, false))
+	assert.Equal(t, 8.0, lp.chargeCurrent)
+
+	// vehicle limit below min current keeps charging at min current
+	vehicle.limit = 3
+	lp.updateVehicleCurrentLimit()
+	charger.EXPECT().MaxCurrent(int64(minA)).Return(nil)
+	require.NoError(t, lp.setLimit(maxA, false))
+	assert.Equal(t, float64(minA), lp.chargeCurrent)
+
+	// limit lifted
+	vehicle.limit = 0
+	lp.updateVehicleCurrentLimit()
+	charger.EXPECT().MaxCurrent(int64(maxA)).Return(nil)
+	require.NoError(t, lp.setLimit(maxA, false))
+	assert.Equal(t, float64(maxA), lp.chargeCurrent)
+}
+
+func TestVehicleCurrentLimitBelowCap(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	charger := api.NewMockCharger(ctrl)
+
+	vehicle := &enforcedLimitVehicle{Vehicle: api.NewMockVehicle(ctrl), limit: 3}
+
+	lp := &Loadpoint{
+		log:            util.NewLogger("foo"),
+		bus:            evbus.New(),
+		clock:          clock.NewMock(),
+		charger:        charger,
+		vehicle:        vehicle,
+		wakeUpTimer:    NewTimer(),
+		MinCurrent:     minA,
+		MaxCurrent:     maxA,
+		phases:         3,
+		measuredPhases: 3,
+		status:         api.StatusC,
+		enabled:        true,
+		chargeCurrent:  minA,
+	}
+
+	lp.updateVehicleCurrentLimit()
+
+	// phase cap below min current disables charging despite the vehicle limit
+	lp.setPhaseCurrentLimit(4, true)
+	charger.EXPECT().Enable(false).Return(nil)
+	require.NoError(t, lp.setLimit(maxA, false))
+	assert.False(t, lp.enabled)
+}
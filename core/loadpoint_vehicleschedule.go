@@ -0,0 +1,46 @@
+package core
+
+import (
+	"errors"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+)
+
+// scheduleConflict returns true if the vehicle's charging schedule delays charging beyond evcc's command.
+// This is the case if charging is enabled now or the plan's target time is before the vehicle permits charging (no mutex).
+func (lp *Loadpoint) scheduleConflict(start time.Time) bool {
+	if start.IsZero() || !start.After(lp.clock.Now()) {
+		return false
+	}
+
+	return lp.enabled || !lp.targetTime.IsZero() && lp.targetTime.Before(start)
+}
+
+// updateScheduleConflict reads the vehicle's charging schedule and warns once per conflict (no mutex)
+func (lp *Loadpoint) updateScheduleConflict() {
+	vs, ok := lp.GetVehicle().(api.VehicleChargeSchedule)
+	if !ok {
+		return
+	}
+
+	start, err := vs.ChargeSchedule()
+	if err != nil && !errors.Is(err, api.ErrNotAvailable) {
+		lp.log.ERROR.Printf("vehicle charge schedule: %v", err)
+		return
+	}
+
+	conflict := err == nil && lp.scheduleConflict(start)
+	if conflict == lp.vehicleScheduleConflict {
+		return
+	}
+
+	lp.vehicleScheduleConflict = conflict
+	lp.publish("vehicleScheduleConflict", conflict)
+
+	if conflict {
+		lp.log.WARN.Printf("vehicle charge schedule delays charging until %v, check the schedule in the vehicle app", start.Round(time.Minute).Local())
+		lp.publish("vehicleChargeSchedule", start)
+		lp.pushEvent(evVehicleSchedule)
+	}
+}
@@ -0,0 +1,75 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/push"
+	"github.com/evcc-io/evcc/util"
+	"github.com/stretchr/testify/assert"
+)
+
+type scheduleVehicle struct {
+	api.Vehicle
+	start time.Time
+	err   error
+}
+
+func (v *scheduleVehicle) ChargeSchedule() (time.Time, error) {
+	return v.start, v.err
+}
+
+func TestVehicleScheduleConflict(t *testing.T) {
+	clck := clock.NewMock()
+	pushChan := make(chan push.Event, 1)
+
+	vehicle := &scheduleVehicle{}
+
+	lp := &Loadpoint{
+		log:      util.NewLogger("foo"),
+		clock:    clck,
+		pushChan: pushChan,
+		vehicle:  vehicle,
+	}
+
+	// car set to charge only after 02:00
+	twoAM := clck.Now().Add(2 * time.Hour)
+
+	tc := []struct {
+		title      string
+		start      time.Time
+		err        error
+		enabled    bool
+		targetTime time.Time
+		conflict   bool
+		event      bool
+	}{
+		{"schedule not delaying charging", time.Time{}, nil, true, time.Time{}, false, false},
+		{"delayed start while evcc is not charging", twoAM, nil, false, time.Time{}, false, false},
+		{"delayed start overrides charging", twoAM, nil, true, time.Time{}, true, true},
+		{"conflict is reported once", twoAM, nil, true, time.Time{}, true, false},
+		{"schedule unavailable", twoAM, api.ErrNotAvailable, true, time.Time{}, false, false},
+		{"plan finishes after schedule start", twoAM, nil, false, twoAM.Add(4 * time.Hour), false, false},
+		{"plan finishes before schedule start", twoAM, nil, false, twoAM.Add(-time.Hour), true, true},
+		{"schedule start passed", clck.Now(), nil, true, time.Time{}, false, false},
+	}
+
+	for _, tc := range tc {
+		t.Log(tc.title)
+
+		vehicle.start, vehicle.err = tc.start, tc.err
+		lp.enabled = tc.enabled
+		lp.targetTime = tc.targetTime
+
+		lp.updateScheduleConflict()
+		assert.Equal(t, tc.conflict, lp.vehicleScheduleConflict, tc.title)
+
+		if tc.event {
+			assert.Equal(t, push.Event{Event: evVehicleSchedule}, <-pushChan, tc.title)
+		} else {
+			assert.Empty(t, pushChan, tc.title)
+		}
+	}
+}
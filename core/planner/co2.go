@@ -0,0 +1,58 @@
+package planner
+
+import (
+	"slices"
+
+	"github.com/evcc-io/evcc/api"
+)
+
+// SetCo2 sets the co2 tariff used to prefer the greenest among equally priced slots
+func (t *Planner) SetCo2(co2 api.Tariff) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.co2 = co2
+}
+
+// sortRates sorts the rates by cost. Equally priced slots are sorted by co2 emission if available.
+func (t *Planner) sortRates(rates api.Rates) {
+	t.mu.Lock()
+	co2 := t.co2
+	t.mu.Unlock()
+
+	var co2Rates api.Rates
+	if co2 != nil {
+		var err error
+		if co2Rates, err = co2.Rates(); err != nil {
+			t.log.DEBUG.Printf("co2 tariff: %v", err)
+		}
+	}
+
+	if len(co2Rates) == 0 {
+		slices.SortStableFunc(rates, sortByCost)
+		return
+	}
+
+	slices.SortStableFunc(rates, sortByCostAndCo2(co2Rates))
+}
+
+// sortByCostAndCo2 returns a sortFunc for slices.Sort using co2 emission as tie-breaker for equal cost
+func sortByCostAndCo2(co2 api.Rates) func(i, j api.Rate) int {
+	return func(i, j api.Rate) int {
+		if i.Price != j.Price {
+			return sortByCost(i, j)
+		}
+
+		ci, erri := co2.Current(i.Start)
+		cj, errj := co2.Current(j.Start)
+
+		if erri == nil && errj == nil && ci.Price != cj.Price {
+			if ci.Price < cj.Price {
+				return -1
+			}
+			return +1
+		}
+
+		return sortByCost(i, j)
+	}
+}
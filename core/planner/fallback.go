@@ -0,0 +1,88 @@
+package planner
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+)
+
+// FallbackMode is the planner behaviour while tariff data is unavailable
+type FallbackMode string
+
+const (
+	FallbackNone     FallbackMode = ""         // keep failing, planner inactive
+	FallbackPV       FallbackMode = "pv"       // don't plan, charge according to loadpoint mode only
+	FallbackDeadline FallbackMode = "deadline" // start as late as possible to reach the target time, ignoring cost
+)
+
+// Fallback configures the planner behaviour while tariff data is unavailable
+type Fallback struct {
+	Mode  FallbackMode
+	Grace time.Duration // tariff outage duration before fallback engages
+}
+
+// Validate checks the fallback configuration
+func (f Fallback) Validate() error {
+	switch f.Mode {
+	case FallbackNone, FallbackPV, FallbackDeadline:
+	default:
+		return fmt.Errorf("invalid tariff fallback mode: %s", f.Mode)
+	}
+
+	if f.Grace < 0 {
+		return fmt.Errorf("invalid tariff fallback grace period: %v", f.Grace)
+	}
+
+	return nil
+}
+
+// SetFallback sets the behaviour while tariff data is unavailable
+func (t *Planner) SetFallback(f Fallback) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.fallback = f
+}
+
+// unavailable handles tariff errors. Once the outage exceeds the grace period, the fallback plan is returned.
+func (t *Planner) unavailable(simplePlan api.Rates, err error) (api.Rates, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.fallback.Mode == FallbackNone {
+		return simplePlan, err
+	}
+
+	if t.failed.IsZero() {
+		t.failed = t.clock.Now()
+	}
+
+	if outage := t.clock.Since(t.failed); outage < t.fallback.Grace {
+		return simplePlan, err
+	}
+
+	if !t.fallbackActive {
+		t.fallbackActive = true
+		t.log.WARN.Printf("plan: tariff unavailable since %v, using %s fallback: %v", t.failed.Round(time.Second).Local(), t.fallback.Mode, err)
+	}
+
+	if t.fallback.Mode == FallbackDeadline {
+		return simplePlan, nil
+	}
+
+	return nil, nil
+}
+
+// available resets the outage state once tariff data has returned
+func (t *Planner) available() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.fallbackActive {
+		t.log.INFO.Printf("plan: tariff available again, leaving %s fallback", t.fallback.Mode)
+	}
+
+	t.failed = time.Time{}
+	t.fallbackActive = false
+}
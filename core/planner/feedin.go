@@ -0,0 +1,41 @@
+package planner
+
+import (
+	"github.com/evcc-io/evcc/api"
+)
+
+// SetFeedIn sets the feed-in tariff. Slots where exporting earns more than importing costs are
+// priced at the feed-in price as charging would forgo the feed-in revenue.
+func (t *Planner) SetFeedIn(feedin api.Tariff) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.feedin = feedin
+}
+
+// opportunityRates returns the rates priced at the higher of import and feed-in price
+func (t *Planner) opportunityRates(rates api.Rates) api.Rates {
+	t.mu.Lock()
+	feedin := t.feedin
+	t.mu.Unlock()
+
+	if feedin == nil {
+		return rates
+	}
+
+	feedinRates, err := feedin.Rates()
+	if err != nil {
+		t.log.DEBUG.Printf("feed-in tariff: %v", err)
+		return rates
+	}
+
+	res := make(api.Rates, 0, len(rates))
+	for _, r := range rates {
+		if fr, err := feedinRates.Current(r.Start); err == nil && fr.Price > r.Price {
+			r.Price = fr.Price
+		}
+		res = append(res, r)
+	}
+
+	return res
+}
@@ -0,0 +1,46 @@
+package planner
+
+import (
+	"sort"
+
+	"github.com/evcc-io/evcc/api"
+	"golang.org/x/exp/maps"
+)
+
+// SolarBands is a solar forecast with confidence bands keyed by percentile, e.g. P10, P50 and P90.
+// Rate prices are the forecast solar power (W).
+type SolarBands map[int]api.Rates
+
+// Percentile returns the forecast at the given percentile. Between bands the solar power is interpolated linearly,
+// outside the bands the nearest band is used.
+func (b SolarBands) Percentile(p int) api.Rates {
+	if len(b) == 0 {
+		return nil
+	}
+
+	keys := maps.Keys(b)
+	sort.Ints(keys)
+
+	i := sort.SearchInts(keys, p)
+	switch {
+	case i < len(keys) && keys[i] == p:
+		return b[p]
+	case i == 0:
+		return b[keys[0]]
+	case i == len(keys):
+		return b[keys[len(keys)-1]]
+	}
+
+	lo, hi := keys[i-1], keys[i]
+	w := float64(p-lo) / float64(hi-lo)
+
+	res := make(api.Rates, 0, len(b[lo]))
+	for _, r := range b[lo] {
+		if upper, err := b[hi].Current(r.Start); err == nil {
+			r.Price += w * (upper.Price - r.Price)
+		}
+		res = append(res, r)
+	}
+
+	return res
+}
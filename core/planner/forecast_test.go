@@ -0,0 +1,84 @@
+package planner
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSolarBandsPercentile(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	bands := SolarBands{
+		10: rates([]float64{1000, 0}, start, time.Hour),
+		50: rates([]float64{3000, 2000}, start, time.Hour),
+		90: rates([]float64{5000, 4000}, start, time.Hour),
+	}
+
+	prices := func(rr api.Rates) []float64 {
+		var res []float64
+		for _, r := range rr {
+			res = append(res, r.Price)
+		}
+		return res
+	}
+
+	tc := []struct {
+		percentile int
+		expected   []float64
+	}{
+		{50, []float64{3000, 2000}},
+		{25, []float64{1750, 750}},
+		{70, []float64{4000, 3000}},
+		{5, []float64{1000, 0}},     // below lowest band
+		{95, []float64{5000, 4000}}, // above highest band
+	}
+
+	for _, tc := range tc {
+		assert.Equal(t, tc.expected, prices(bands.Percentile(tc.percentile)), tc.percentile)
+	}
+
+	assert.Nil(t, SolarBands(nil).Percentile(50))
+}
+
+func TestSimulatePercentile(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	sim := Simulation{
+		Rates:      rates([]float64{0.30, 0.30, 0.15, 0.30}, start, time.Hour),
+		Soc:        50,
+		TargetSoc:  65,
+		TargetTime: start.Add(4 * time.Hour),
+		Capacity:   50,   // kWh
+		Power:      7500, // W
+		Efficiency: 1,
+		SolarBands: SolarBands{
+			10: rates([]float64{0, 0, 0, 0}, start, time.Hour),
+			50: rates([]float64{7500, 0, 0, 0}, start, time.Hour),
+			90: rates([]float64{7500, 0, 0, 0}, start, time.Hour),
+		},
+	}
+
+	// median forecast plans the solar slot
+	res, err := Simulate(sim)
+	require.NoError(t, err)
+	assert.Equal(t, api.Rates{
+		{Start: start, End: start.Add(time.Hour), Price: 0},
+	}, res.Plan)
+
+	// conservative forecast falls back to the cheap grid slot
+	sim.Percentile = 25
+
+	res, err = Simulate(sim)
+	require.NoError(t, err)
+	assert.Equal(t, api.Rates{
+		{Start: start.Add(2 * time.Hour), End: start.Add(3 * time.Hour), Price: 0.15},
+	}, res.Plan)
+
+	sim.Percentile = 101
+	_, err = Simulate(sim)
+	assert.Error(t, err)
+}
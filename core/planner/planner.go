@@ -1,7 +1,7 @@
 package planner
 
 import (
-	"slices"
+	"sync"
 	"time"
 
 	"github.com/benbjohnson/clock"
@@ -14,6 +14,13 @@ type Planner struct {
 	log    *util.Logger
 	clock  clock.Clock // mockable time
 	tariff api.Tariff
+
+	mu             sync.Mutex
+	feedin         api.Tariff // optional feed-in tariff
+	co2            api.Tariff // optional co2 tariff
+	fallback       Fallback
+	failed         time.Time // start of tariff outage
+	fallbackActive bool
 }
 
 // New creates a price planner
@@ -34,7 +41,7 @@ func (t *Planner) plan(rates api.Rates, requiredDuration time.Duration, targetTi
 
 	for _, source := range rates {
 		// slot not relevant
-		if source.Start.After(targetTime) || source.Start.Equal(targetTime) || source.End.Before(t.clock.Now()) {
+		if source.Start.After(targetTime) || source.Start.Equal(targetTime) || !source.End.After(t.clock.Now()) {
 			continue
 		}
 
@@ -99,10 +106,15 @@ func (t *Planner) Plan(requiredDuration time.Duration, targetTime time.Time) (ap
 	}
 
 	rates, err := t.tariff.Rates()
+	if err != nil {
+		return t.unavailable(simplePlan, err)
+	}
+
+	t.available()
 
 	// treat like normal target charging if we don't have rates
-	if len(rates) == 0 || err != nil {
-		return simplePlan, err
+	if len(rates) == 0 {
+		return simplePlan, nil
 	}
 
 	// consume remaining time
@@ -110,11 +122,14 @@ func (t *Planner) Plan(requiredDuration time.Duration, targetTime time.Time) (ap
 		requiredDuration = t.clock.Until(targetTime)
 	}
 
+	// charging must not forgo higher feed-in revenue
+	rates = t.opportunityRates(rates)
+
 	// rates are by default sorted by date, oldest to newest
 	last := rates[len(rates)-1].End
 
-	// sort rates by price and time
-	slices.SortStableFunc(rates, sortByCost)
+	// sort rates by price, co2 and time
+	t.sortRates(rates)
 
 	// reduce planning horizon to available rates
 	if targetTime.After(last) {
@@ -1,6 +1,7 @@
 package planner
 
 import (
+	"errors"
 	"slices"
 	"testing"
 	"time"
@@ -234,3 +235,164 @@ func TestChargeAfterTargetTime(t *testing.T) {
 	assert.NoError(t, err)
 	assert.False(t, !SlotAt(clock.Now(), plan).IsEmpty(), "should not start past target time")
 }
+
+func TestFallback(t *testing.T) {
+	clock := clock.NewMock()
+	ctrl := gomock.NewController(t)
+
+	trf := api.NewMockTariff(ctrl)
+
+	p := &Planner{
+		log:    util.NewLogger("foo"),
+		clock:  clock,
+		tariff: trf,
+	}
+
+	target := clock.Now().Add(6 * time.Hour)
+	outage := errors.New("outage")
+
+	for _, tc := range []struct {
+		mode FallbackMode
+		plan bool
+	}{
+		{FallbackPV, false},
+		{FallbackDeadline, true},
+	} {
+		t.Log(tc.mode)
+
+		p.SetFallback(Fallback{Mode: tc.mode, Grace: 15 * time.Minute})
+
+		// outage within grace period
+		trf.EXPECT().Rates().Return(nil, outage).Times(2)
+
+		_, err := p.Plan(time.Hour, target)
+		assert.ErrorIs(t, err, outage)
+
+		clock.Add(10 * time.Minute)
+		_, err = p.Plan(time.Hour, target)
+		assert.ErrorIs(t, err, outage)
+
+		// fallback engaged
+		clock.Add(5 * time.Minute)
+		trf.EXPECT().Rates().Return(nil, outage)
+
+		plan, err := p.Plan(time.Hour, target)
+		assert.NoError(t, err)
+		assert.True(t, p.fallbackActive)
+
+		if tc.plan {
+			assert.Equal(t, api.Rates{{Start: target.Add(-time.Hour), End: target}}, plan)
+		} else {
+			assert.Empty(t, plan)
+		}
+
+		// recovery
+		trf.EXPECT().Rates().Return(rates([]float64{20, 60, 10, 80, 40, 90}, clock.Now(), time.Hour), nil)
+
+		plan, err = p.Plan(time.Hour, target)
+		assert.NoError(t, err)
+		assert.False(t, p.fallbackActive)
+		assert.Equal(t, 10.0, AverageCost(plan))
+
+		// new outage restarts grace period
+		trf.EXPECT().Rates().Return(nil, outage)
+
+		_, err = p.Plan(time.Hour, target)
+		assert.ErrorIs(t, err, outage)
+
+		trf.EXPECT().Rates().Return(rates([]float64{20}, clock.Now(), time.Hour), nil)
+		_, err = p.Plan(time.Hour, target)
+		assert.NoError(t, err)
+	}
+}
+
+func TestFallbackNone(t *testing.T) {
+	clock := clock.NewMock()
+	ctrl := gomock.NewController(t)
+
+	trf := api.NewMockTariff(ctrl)
+	trf.EXPECT().Rates().AnyTimes().Return(nil, errors.New("outage"))
+
+	p := &Planner{
+		log:    util.NewLogger("foo"),
+		clock:  clock,
+		tariff: trf,
+	}
+
+	clock.Add(24 * time.Hour)
+	_, err := p.Plan(time.Hour, clock.Now().Add(6*time.Hour))
+	assert.Error(t, err)
+
+	assert.Error(t, Fallback{Mode: "foo"}.Validate())
+	assert.Error(t, Fallback{Mode: FallbackPV, Grace: -time.Minute}.Validate())
+	assert.NoError(t, Fallback{Mode: FallbackDeadline}.Validate())
+}
+
+func TestFeedIn(t *testing.T) {
+	clock := clock.NewMock()
+	ctrl := gomock.NewController(t)
+
+	trf := api.NewMockTariff(ctrl)
+	trf.EXPECT().Rates().AnyTimes().Return(rates([]float64{20, 60, 10, 80}, clock.Now(), time.Hour), nil)
+
+	feedin := api.NewMockTariff(ctrl)
+	feedin.EXPECT().Rates().AnyTimes().Return(rates([]float64{5, 5, 30, 5}, clock.Now(), time.Hour), nil)
+
+	p := &Planner{
+		log:    util.NewLogger("foo"),
+		clock:  clock,
+		tariff: trf,
+	}
+
+	// cheapest import
+	plan, err := p.Plan(time.Hour, clock.Now().Add(4*time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, clock.Now().Add(2*time.Hour), Start(plan))
+
+	// selling at 30 earns more than buying at 20 costs
+	p.SetFeedIn(feedin)
+
+	plan, err = p.Plan(time.Hour, clock.Now().Add(4*time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, clock.Now(), Start(plan))
+	assert.Equal(t, 20.0, AverageCost(plan))
+}
+
+func TestCo2TieBreaker(t *testing.T) {
+	clock := clock.NewMock()
+	ctrl := gomock.NewController(t)
+
+	trf := api.NewMockTariff(ctrl)
+	trf.EXPECT().Rates().AnyTimes().DoAndReturn(func() (api.Rates, error) {
+		return rates([]float64{20, 10, 10, 10, 30}, clock.Now(), time.Hour), nil
+	})
+
+	co2 := api.NewMockTariff(ctrl)
+
+	p := &Planner{
+		log:    util.NewLogger("foo"),
+		clock:  clock,
+		tariff: trf,
+	}
+
+	// latest of the cheapest slots without co2 data
+	plan, err := p.Plan(time.Hour, clock.Now().Add(5*time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, clock.Now().Add(3*time.Hour), Start(plan))
+
+	// greenest of the cheapest slots, cheaper slot preferred over greener slot
+	p.SetCo2(co2)
+	co2.EXPECT().Rates().Return(rates([]float64{50, 400, 200, 300, 100}, clock.Now(), time.Hour), nil)
+
+	plan, err = p.Plan(time.Hour, clock.Now().Add(5*time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, clock.Now().Add(2*time.Hour), Start(plan))
+	assert.Equal(t, 10.0, AverageCost(plan))
+
+	// co2 unavailable
+	co2.EXPECT().Rates().Return(nil, errors.New("foo"))
+
+	plan, err = p.Plan(time.Hour, clock.Now().Add(5*time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, clock.Now().Add(3*time.Hour), Start(plan))
+}
@@ -0,0 +1,167 @@
+package planner
+
+import (
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/soc"
+	"github.com/evcc-io/evcc/util"
+)
+
+// Simulation is a hypothetical planner run that does not affect any live state
+type Simulation struct {
+	Now        time.Time  `json:"now"`        // start of the simulation, defaults to the start of the first rate
+	Rates      api.Rates  `json:"rates"`      // grid price curve
+	Solar      api.Rates  `json:"solar"`      // optional solar forecast, price is the solar power (W)
+	SolarBands SolarBands `json:"solarBands"` // optional solar forecast confidence bands, replaces solar
+	Percentile int        `json:"percentile"` // solar forecast percentile to plan against, defaults to 50
+	Soc        float64    `json:"soc"`        // vehicle soc at start (%)
+	TargetSoc  float64    `json:"targetSoc"`  // vehicle target soc (%)
+	TargetTime time.Time  `json:"targetTime"` // deadline
+	Capacity   float64    `json:"capacity"`   // vehicle battery capacity (kWh)
+	Power      float64    `json:"power"`      // max charge power (W)
+	Efficiency float64    `json:"efficiency"` // charge efficiency, defaults to soc.ChargeEfficiency
+}
+
+// SimulationResult is the computed schedule and its cost
+type SimulationResult struct {
+	Duration time.Duration // required charge duration
+	Plan     api.Rates     // charging slots by start time, priced at the effective grid price
+	Energy   float64       // charged energy (kWh)
+	Cost     float64       // charging cost, solar energy is free
+}
+
+// simulationTariff provides a fixed set of rates
+type simulationTariff api.Rates
+
+func (t simulationTariff) Rates() (api.Rates, error) {
+	return slices.Clone(api.Rates(t)), nil
+}
+
+func (t simulationTariff) Type() api.TariffType {
+	return api.TariffTypePriceForecast
+}
+
+// Validate validates the simulation inputs
+func (s Simulation) Validate() error {
+	if len(s.Rates) == 0 {
+		return errors.New("missing rates")
+	}
+	if s.TargetTime.IsZero() {
+		return errors.New("missing target time")
+	}
+	if s.Capacity <= 0 {
+		return errors.New("capacity must be positive")
+	}
+	if s.Power <= 0 {
+		return errors.New("power must be positive")
+	}
+	if s.Soc < 0 || s.Soc > 100 || s.TargetSoc < 0 || s.TargetSoc > 100 {
+		return errors.New("soc must be between 0 and 100")
+	}
+	if !s.TargetTime.After(s.start()) {
+		return errors.New("target time must be after start of simulation")
+	}
+	if s.Percentile < 0 || s.Percentile > 100 {
+		return errors.New("percentile must be between 0 and 100")
+	}
+	return nil
+}
+
+// start returns the start of the simulation, defaulting to the start of the earliest rate
+func (s Simulation) start() time.Time {
+	if !s.Now.IsZero() || len(s.Rates) == 0 {
+		return s.Now
+	}
+
+	res := s.Rates[0].Start
+	for _, r := range s.Rates[1:] {
+		if r.Start.Before(res) {
+			res = r.Start
+		}
+	}
+
+	return res
+}
+
+// duration returns the time required for charging to the target soc at constant power
+func (s Simulation) duration() time.Duration {
+	efficiency := s.Efficiency
+	if efficiency <= 0 || efficiency > 1 {
+		efficiency = soc.ChargeEfficiency
+	}
+
+	energy := max(0, s.TargetSoc-s.Soc) / 100 * s.Capacity / efficiency // kWh
+	return time.Duration(energy * 1e3 / s.Power * float64(time.Hour))
+}
+
+// solar returns the solar forecast at the planned percentile.
+// Planning against a low percentile is conservative and prefers grid slots where a solar shortfall is likely.
+func (s Simulation) solar() api.Rates {
+	if len(s.SolarBands) == 0 {
+		return s.Solar
+	}
+
+	p := s.Percentile
+	if p == 0 {
+		p = 50
+	}
+
+	return s.SolarBands.Percentile(p)
+}
+
+// effectiveRates prices the rates at the share of power not covered by the solar forecast
+func (s Simulation) effectiveRates() api.Rates {
+	res := make(api.Rates, 0, len(s.Rates))
+	forecast := s.solar()
+
+	for _, r := range s.Rates {
+		if solar, err := forecast.Current(r.Start); err == nil {
+			r.Price *= 1 - min(max(solar.Price, 0), s.Power)/s.Power
+		}
+		res = append(res, r)
+	}
+
+	return res
+}
+
+// Simulate runs the planner for the given inputs. The result is deterministic as it only depends on the inputs.
+func Simulate(s Simulation) (SimulationResult, error) {
+	if err := s.Validate(); err != nil {
+		return SimulationResult{}, err
+	}
+
+	rates := s.effectiveRates()
+	rates.Sort()
+
+	clck := clock.NewMock()
+	clck.Set(s.start())
+
+	t := New(util.NewLogger("planner"), simulationTariff(rates))
+	t.clock = clck
+
+	duration := s.duration()
+
+	plan, err := t.Plan(duration, s.TargetTime)
+	if err != nil {
+		return SimulationResult{}, err
+	}
+
+	plan.Sort()
+
+	res := SimulationResult{
+		Duration: duration,
+		Plan:     plan,
+	}
+
+	for _, slot := range plan {
+		energy := slot.End.Sub(slot.Start).Hours() * s.Power / 1e3
+		res.Energy += energy
+		res.Cost += energy * slot.Price
+	}
+
+	return res, nil
+}
@@ -0,0 +1,88 @@
+package planner
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSimulate(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	sim := Simulation{
+		Rates:      rates([]float64{0.30, 0.20, 0.10, 0.40}, start, time.Hour),
+		Soc:        50,
+		TargetSoc:  80,
+		TargetTime: start.Add(4 * time.Hour),
+		Capacity:   45,   // kWh
+		Power:      7500, // W
+		Efficiency: 0.9,
+	}
+
+	// 13.5kWh / 0.9 at 7.5kW
+	res, err := Simulate(sim)
+	require.NoError(t, err)
+	assert.Equal(t, 2*time.Hour, res.Duration)
+	assert.Equal(t, api.Rates{
+		{Start: start.Add(time.Hour), End: start.Add(2 * time.Hour), Price: 0.20},
+		{Start: start.Add(2 * time.Hour), End: start.Add(3 * time.Hour), Price: 0.10},
+	}, res.Plan)
+	assert.InDelta(t, 15, res.Energy, 1e-9)
+	assert.InDelta(t, 7.5*0.20+7.5*0.10, res.Cost, 1e-9)
+
+	// deterministic output for same inputs
+	again, err := Simulate(sim)
+	require.NoError(t, err)
+	assert.Equal(t, res, again)
+
+	// solar covering the first slot makes it free
+	sim.Solar = rates([]float64{7500, 0, 0, 0}, start, time.Hour)
+
+	res, err = Simulate(sim)
+	require.NoError(t, err)
+	assert.Equal(t, api.Rates{
+		{Start: start, End: start.Add(time.Hour), Price: 0},
+		{Start: start.Add(2 * time.Hour), End: start.Add(3 * time.Hour), Price: 0.10},
+	}, res.Plan)
+	assert.InDelta(t, 7.5*0.10, res.Cost, 1e-9)
+
+	// simulation starting later skips past slots
+	sim.Now = start.Add(time.Hour)
+
+	res, err = Simulate(sim)
+	require.NoError(t, err)
+	assert.Equal(t, api.Rates{
+		{Start: start.Add(time.Hour), End: start.Add(2 * time.Hour), Price: 0.20},
+		{Start: start.Add(2 * time.Hour), End: start.Add(3 * time.Hour), Price: 0.10},
+	}, res.Plan)
+}
+
+func TestSimulateValidate(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	sim := Simulation{
+		Rates:      rates([]float64{0.30}, start, time.Hour),
+		TargetSoc:  80,
+		TargetTime: start.Add(time.Hour),
+		Capacity:   50,
+		Power:      11000,
+	}
+	assert.NoError(t, sim.Validate())
+
+	for _, s := range []Simulation{
+		{TargetTime: sim.TargetTime, Capacity: 50, Power: 11000},
+		{Rates: sim.Rates, Capacity: 50, Power: 11000},
+		{Rates: sim.Rates, TargetTime: sim.TargetTime, Power: 11000},
+		{Rates: sim.Rates, TargetTime: sim.TargetTime, Capacity: 50},
+		{Rates: sim.Rates, TargetTime: sim.TargetTime, Capacity: 50, Power: 11000, Soc: 101, TargetSoc: 80},
+		{Rates: sim.Rates, TargetTime: sim.TargetTime, Capacity: 50, Power: 11000, TargetSoc: -1},
+		{Rates: sim.Rates, TargetTime: start, Capacity: 50, Power: 11000, TargetSoc: 80},
+		{Now: sim.TargetTime, Rates: sim.Rates, TargetTime: sim.TargetTime, Capacity: 50, Power: 11000, TargetSoc: 80},
+	} {
+		_, err := Simulate(s)
+		assert.Error(t, err)
+	}
+}
@@ -0,0 +1,81 @@
+package prioritizer
+
+import (
+	"slices"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/loadpoint"
+)
+
+// AllocationConfig configures the site-wide distribution of pv surplus
+type AllocationConfig struct {
+	Enabled       bool    // distribute surplus by loadpoint priority
+	MaxGridImport float64 // grid import allowed in addition to the surplus (W)
+}
+
+// participates returns if the loadpoint's power is controlled by surplus allocation
+func participates(lp loadpoint.API) bool {
+	mode := lp.GetMode()
+	return (mode == api.ModePV || mode == api.ModeMinPV) && lp.GetStatus() != api.StatusA
+}
+
+// Allocate distributes the surplus and the current charge power of pv loadpoints across these loadpoints by descending priority.
+// The result contains all participating loadpoints.
+// Min+PV loadpoints are guaranteed their minimum power. Loadpoints of equal priority are served in given order.
+// Loadpoints that cannot be given their minimum power receive nothing, so lower priorities ramp down first.
+// Connected loadpoints that are not charging receive at most their minimum power, which is sufficient to start charging
+// but does not hold back surplus from lower priorities while the vehicle does not draw power.
+func Allocate(surplus float64, lps []loadpoint.API) map[loadpoint.API]float64 {
+	res := make(map[loadpoint.API]float64)
+	available := surplus
+
+	var active []loadpoint.API
+	for _, lp := range lps {
+		if participates(lp) {
+			active = append(active, lp)
+			available += lp.GetChargePower()
+			res[lp] = 0
+		}
+	}
+
+	// minimum guarantees
+	for _, lp := range active {
+		if lp.GetMode() == api.ModeMinPV {
+			res[lp] = lp.GetMinPower()
+			available -= res[lp]
+		}
+	}
+
+	slices.SortStableFunc(active, func(a, b loadpoint.API) int {
+		return b.GetPriority() - a.GetPriority()
+	})
+
+	for _, lp := range active {
+		if available <= 0 {
+			break
+		}
+
+		minPower, maxPower := lp.GetMinPower(), lp.GetMaxPower()
+		if lp.GetStatus() != api.StatusC {
+			maxPower = minPower
+		}
+
+		if lp.GetMode() == api.ModeMinPV {
+			// minimum already assigned
+			power := min(available, max(0, maxPower-minPower))
+			res[lp] += power
+			available -= power
+			continue
+		}
+
+		if available < minPower {
+			continue
+		}
+
+		power := min(available, maxPower)
+		res[lp] = power
+		available -= power
+	}
+
+	return res
+}
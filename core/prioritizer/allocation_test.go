@@ -0,0 +1,123 @@
+package prioritizer
+
+import (
+	"testing"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/core/loadpoint"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func allocationLoadpoint(ctrl *gomock.Controller, prio int, mode api.ChargeMode, maxPower float64) *loadpoint.MockAPI {
+	return statusLoadpoint(ctrl, prio, mode, api.StatusC, maxPower)
+}
+
+func statusLoadpoint(ctrl *gomock.Controller, prio int, mode api.ChargeMode, status api.ChargeStatus, maxPower float64) *loadpoint.MockAPI {
+	lp := loadpoint.NewMockAPI(ctrl)
+	lp.EXPECT().GetPriority().Return(prio).AnyTimes()
+	lp.EXPECT().GetMode().Return(mode).AnyTimes()
+	lp.EXPECT().GetStatus().Return(status).AnyTimes()
+	lp.EXPECT().GetChargePower().Return(0.0).AnyTimes()
+	lp.EXPECT().GetMinPower().Return(1380.0).AnyTimes()
+	lp.EXPECT().GetMaxPower().Return(maxPower).AnyTimes()
+	return lp
+}
+
+func TestAllocate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	lo := allocationLoadpoint(ctrl, 0, api.ModePV, 11040)
+	mid := allocationLoadpoint(ctrl, 1, api.ModePV, 3680)
+	hi := allocationLoadpoint(ctrl, 2, api.ModePV, 11040)
+
+	lps := []loadpoint.API{lo, mid, hi}
+
+	// shrinking budget ramps down lower priorities first
+	tc := []struct {
+		surplus, lo, mid, hi float64
+	}{
+		{30000, 11040, 3680, 11040},
+		{20000, 5280, 3680, 11040},
+		{14000, 0, 2960, 11040},
+		{12000, 0, 0, 11040},
+		{5000, 0, 0, 5000},
+		{1000, 0, 0, 0},
+	}
+
+	for _, tc := range tc {
+		res := Allocate(tc.surplus, lps)
+
+		assert.Len(t, res, 3, tc)
+		assert.Equal(t, tc.lo, res[lo], "lo", tc)
+		assert.Equal(t, tc.mid, res[mid], "mid", tc)
+		assert.Equal(t, tc.hi, res[hi], "hi", tc)
+	}
+}
+
+func TestAllocateMinimumGuarantee(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	lo := allocationLoadpoint(ctrl, 0, api.ModeMinPV, 11040)
+	hi := allocationLoadpoint(ctrl, 1, api.ModePV, 11040)
+
+	lps := []loadpoint.API{lo, hi}
+
+	res := Allocate(1000, lps)
+	assert.Equal(t, 1380.0, res[lo])
+	assert.Equal(t, 0.0, res[hi])
+
+	res = Allocate(5000, lps)
+	assert.Equal(t, 1380.0, res[lo])
+	assert.Equal(t, 3620.0, res[hi])
+
+	res = Allocate(15000, lps)
+	assert.Equal(t, 3960.0, res[lo])
+	assert.Equal(t, 11040.0, res[hi])
+}
+
+func TestAllocateParticipants(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	now := loadpoint.NewMockAPI(ctrl)
+	now.EXPECT().GetMode().Return(api.ModeNow).AnyTimes()
+
+	disconnected := loadpoint.NewMockAPI(ctrl)
+	disconnected.EXPECT().GetMode().Return(api.ModePV).AnyTimes()
+	disconnected.EXPECT().GetStatus().Return(api.StatusA).AnyTimes()
+
+	pv := loadpoint.NewMockAPI(ctrl)
+	pv.EXPECT().GetPriority().Return(0).AnyTimes()
+	pv.EXPECT().GetMode().Return(api.ModePV).AnyTimes()
+	pv.EXPECT().GetStatus().Return(api.StatusC).AnyTimes()
+	pv.EXPECT().GetChargePower().Return(2000.0).AnyTimes()
+	pv.EXPECT().GetMinPower().Return(1380.0).AnyTimes()
+	pv.EXPECT().GetMaxPower().Return(11040.0).AnyTimes()
+
+	// current charge power is re-distributed
+	res := Allocate(-500, []loadpoint.API{now, disconnected, pv})
+	assert.Equal(t, map[loadpoint.API]float64{pv: 1500}, res)
+}
+
+func TestAllocateIdle(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	// connected but not charging, e.g. target reached or vehicle full
+	idle := statusLoadpoint(ctrl, 2, api.ModePV, api.StatusB, 11040)
+	minpv := statusLoadpoint(ctrl, 1, api.ModeMinPV, api.StatusB, 11040)
+	lo := allocationLoadpoint(ctrl, 0, api.ModePV, 11040)
+
+	lps := []loadpoint.API{lo, minpv, idle}
+
+	// idle loadpoints are limited to their minimum power
+	res := Allocate(10000, lps)
+	assert.Equal(t, 1380.0, res[idle])
+	assert.Equal(t, 1380.0, res[minpv])
+	assert.Equal(t, 7240.0, res[lo])
+
+	// insufficient for idle loadpoint to start
+	res = Allocate(2000, lps)
+	assert.Equal(t, 0.0, res[idle])
+	assert.Equal(t, 1380.0, res[minpv])
+	assert.Equal(t, 0.0, res[lo])
+}
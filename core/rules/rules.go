@@ -0,0 +1,112 @@
+package rules
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Action is the charging decision of a rule
+type Action string
+
+const (
+	Charge Action = "charge" // charge at minimum current
+	Boost  Action = "boost"  // charge at maximum current
+	Hold   Action = "hold"   // do not charge
+)
+
+// Rule maps a set of conditions to an action. All configured conditions must be met for the rule to match.
+type Rule struct {
+	Price     *float64 `mapstructure:"price"`     // grid price below
+	Soc       *float64 `mapstructure:"soc"`       // vehicle soc below (%)
+	From      string   `mapstructure:"from"`      // time window start (hh:mm)
+	To        string   `mapstructure:"to"`        // time window end (hh:mm), may wrap midnight
+	PvSurplus *float64 `mapstructure:"pvSurplus"` // pv surplus at least (W)
+	Action    Action   `mapstructure:"action"`
+}
+
+// State is the input for evaluating rules. Unknown values are nil and never meet a condition.
+type State struct {
+	Time      time.Time
+	Price     *float64
+	Soc       *float64
+	PvSurplus float64
+}
+
+// Rules are evaluated in order. The first matching rule wins.
+type Rules []Rule
+
+const timeFormat = "15:04"
+
+// minutes returns the minutes since midnight
+func minutes(s string) (int, error) {
+	t, err := time.Parse(timeFormat, s)
+	if err != nil {
+		return 0, err
+	}
+	return 60*t.Hour() + t.Minute(), nil
+}
+
+// Validate checks the rules for invalid actions and conditions
+func (r Rules) Validate() error {
+	var errs []error
+
+	for i, rule := range r {
+		switch rule.Action = Action(strings.ToLower(string(rule.Action))); rule.Action {
+		case Charge, Boost, Hold:
+		default:
+			errs = append(errs, fmt.Errorf("rule %d: invalid action: %s", i+1, rule.Action))
+		}
+
+		if (rule.From == "") != (rule.To == "") {
+			errs = append(errs, fmt.Errorf("rule %d: time window requires from and to", i+1))
+		}
+
+		for _, s := range []string{rule.From, rule.To} {
+			if _, err := minutes(s); s != "" && err != nil {
+				errs = append(errs, fmt.Errorf("rule %d: invalid time: %s", i+1, s))
+			}
+		}
+
+		if rule.Price == nil && rule.Soc == nil && rule.From == "" && rule.PvSurplus == nil {
+			errs = append(errs, fmt.Errorf("rule %d: missing condition", i+1))
+		}
+
+		r[i] = rule
+	}
+
+	return errors.Join(errs...)
+}
+
+// Matches returns true if all of the rule's conditions are met
+func (rule Rule) Matches(s State) bool {
+	if rule.Price != nil && (s.Price == nil || *s.Price >= *rule.Price) {
+		return false
+	}
+
+	if rule.Soc != nil && (s.Soc == nil || *s.Soc >= *rule.Soc) {
+		return false
+	}
+
+	if rule.From != "" && !(Window{From: rule.From, To: rule.To}).Contains(s.Time) {
+		return false
+	}
+
+	if rule.PvSurplus != nil && s.PvSurplus < *rule.PvSurplus {
+		return false
+	}
+
+	return true
+}
+
+// Evaluate returns the action and index of the first matching rule
+func (r Rules) Evaluate(s State) (Action, int, bool) {
+	for i, rule := range r {
+		if rule.Matches(s) {
+			return rule.Action, i, true
+		}
+	}
+
+	return "", -1, false
+}
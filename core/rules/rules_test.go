@@ -0,0 +1,73 @@
+package rules
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func TestEvaluate(t *testing.T) {
+	night := time.Date(2024, 1, 1, 23, 30, 0, 0, time.UTC)
+	noon := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	afternoon := time.Date(2024, 1, 1, 15, 0, 0, 0, time.UTC)
+
+	rules := Rules{
+		{Soc: ptr(20.0), Action: Boost},                              // 1: emergency
+		{From: "11:00", To: "13:00", Action: "Hold"},                 // 2: midday
+		{Price: ptr(0.10), Soc: ptr(80.0), Action: Charge},           // 3: cheap
+		{From: "22:00", To: "06:00", Soc: ptr(60.0), Action: Charge}, // 4: night
+		{PvSurplus: ptr(1500.0), Action: Boost},                      // 5: sunny
+		{Price: ptr(1.0), Action: Hold},                              // 6: otherwise hold
+	}
+	require.NoError(t, rules.Validate())
+	assert.Equal(t, Hold, rules[1].Action, "action normalized")
+
+	tc := []struct {
+		title  string
+		state  State
+		action Action
+		idx    int
+	}{
+		{"low soc boosts regardless of price", State{Time: afternoon, Price: ptr(0.5), Soc: ptr(15.0)}, Boost, 0},
+		{"cheap price below soc", State{Time: afternoon, Price: ptr(0.05), Soc: ptr(50.0)}, Charge, 2},
+		{"cheap price above soc", State{Time: afternoon, Price: ptr(0.05), Soc: ptr(90.0)}, Hold, 5},
+		{"night window wraps midnight", State{Time: night, Price: ptr(0.3), Soc: ptr(50.0)}, Charge, 3},
+		{"night window early morning", State{Time: night.Add(6 * time.Hour), Price: ptr(0.3), Soc: ptr(50.0)}, Charge, 3},
+		{"night window ended", State{Time: night.Add(7 * time.Hour), Price: ptr(0.3), Soc: ptr(50.0)}, Hold, 5},
+		{"earlier hold wins over later boost", State{Time: noon, Price: ptr(0.05), Soc: ptr(50.0), PvSurplus: 2000}, Hold, 1},
+		{"pv surplus without price", State{Time: afternoon, Soc: ptr(90.0), PvSurplus: 2000}, Boost, 4},
+		{"unknown soc never matches soc condition", State{Time: night, Price: ptr(0.05), PvSurplus: 0}, Hold, 5},
+		{"nothing matches", State{Time: afternoon}, "", -1},
+	}
+
+	for _, tc := range tc {
+		action, idx, ok := rules.Evaluate(tc.state)
+		assert.Equal(t, tc.action, action, tc.title)
+		assert.Equal(t, tc.idx, idx, tc.title)
+		assert.Equal(t, tc.idx >= 0, ok, tc.title)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tc := []struct {
+		rule Rule
+		err  string
+	}{
+		{Rule{Soc: ptr(50.0), Action: "start"}, "invalid action"},
+		{Rule{From: "22:00", Action: Charge}, "requires from and to"},
+		{Rule{From: "22:00", To: "25:00", Action: Charge}, "invalid time"},
+		{Rule{Action: Charge}, "missing condition"},
+	}
+
+	for _, tc := range tc {
+		err := Rules{tc.rule}.Validate()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), tc.err)
+	}
+}
@@ -0,0 +1,114 @@
+package rules
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Window is a recurring daily time window, optionally limited to weekdays
+type Window struct {
+	From string   `mapstructure:"from"` // window start (hh:mm)
+	To   string   `mapstructure:"to"`   // window end (hh:mm), may wrap midnight
+	Days []string `mapstructure:"days"` // weekdays the window starts on (mon..sun), every day if empty
+}
+
+// Windows is a list of time windows
+type Windows []Window
+
+var weekdays = []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+
+// weekday returns the weekday of the abbreviated name
+func weekday(s string) (time.Weekday, bool) {
+	for i, d := range weekdays {
+		if strings.EqualFold(s, d) {
+			return time.Weekday(i), true
+		}
+	}
+	return 0, false
+}
+
+// Validate checks the window for invalid times and weekdays
+func (w Window) Validate() error {
+	var errs []error
+
+	for _, s := range []string{w.From, w.To} {
+		if _, err := minutes(s); err != nil {
+			errs = append(errs, fmt.Errorf("invalid time: %q", s))
+		}
+	}
+
+	for _, d := range w.Days {
+		if _, ok := weekday(d); !ok {
+			errs = append(errs, fmt.Errorf("invalid day: %s", d))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Validate checks all windows
+func (w Windows) Validate() error {
+	var errs []error
+	for i, window := range w {
+		if err := window.Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("window %d: %w", i+1, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// startsOn returns true if the window starts on the given weekday
+func (w Window) startsOn(day time.Weekday) bool {
+	if len(w.Days) == 0 {
+		return true
+	}
+
+	for _, d := range w.Days {
+		if wd, ok := weekday(d); ok && wd == day {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Contains returns true if the time is within the window. A window wrapping midnight belongs to the day it starts on.
+func (w Window) Contains(ts time.Time) bool {
+	from, err := minutes(w.From)
+	if err != nil {
+		return false
+	}
+
+	to, err := minutes(w.To)
+	if err != nil {
+		return false
+	}
+
+	now := 60*ts.Hour() + ts.Minute()
+
+	if from <= to {
+		return from <= now && now < to && w.startsOn(ts.Weekday())
+	}
+
+	// wrap midnight
+	switch {
+	case now >= from:
+		return w.startsOn(ts.Weekday())
+	case now < to:
+		return w.startsOn(ts.AddDate(0, 0, -1).Weekday())
+	default:
+		return false
+	}
+}
+
+// Contains returns true if the time is within any of the windows
+func (w Windows) Contains(ts time.Time) bool {
+	for _, window := range w {
+		if window.Contains(ts) {
+			return true
+		}
+	}
+	return false
+}
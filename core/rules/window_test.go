@@ -0,0 +1,51 @@
+package rules
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWindowContains(t *testing.T) {
+	// 2024-01-01 is a monday
+	at := func(day, hour, min int) time.Time {
+		return time.Date(2024, 1, day, hour, min, 0, 0, time.UTC)
+	}
+
+	evening := Window{From: "17:00", To: "20:00", Days: []string{"mon", "Tue", "wed", "thu", "fri"}}
+	night := Window{From: "22:00", To: "06:00", Days: []string{"fri"}}
+
+	tc := []struct {
+		title  string
+		window Window
+		ts     time.Time
+		res    bool
+	}{
+		{"weekday peak start", evening, at(1, 17, 0), true},
+		{"weekday peak end excluded", evening, at(1, 20, 0), false},
+		{"weekday before peak", evening, at(1, 16, 59), false},
+		{"weekend", evening, at(6, 18, 0), false},
+		{"every day", Window{From: "17:00", To: "20:00"}, at(7, 18, 0), true},
+		{"wrap midnight start day", night, at(5, 23, 0), true},
+		{"wrap midnight belongs to start day", night, at(6, 5, 59), true},
+		{"wrap midnight other day", night, at(5, 5, 0), false},
+		{"wrap midnight outside", night, at(5, 12, 0), false},
+	}
+
+	for _, tc := range tc {
+		assert.Equal(t, tc.res, tc.window.Contains(tc.ts), tc.title)
+	}
+
+	assert.True(t, Windows{night, evening}.Contains(at(2, 18, 0)))
+	assert.False(t, Windows{}.Contains(at(2, 18, 0)))
+}
+
+func TestWindowValidate(t *testing.T) {
+	assert.NoError(t, Windows{{From: "17:00", To: "20:00", Days: []string{"Mon"}}}.Validate())
+
+	err := Windows{{From: "17:00"}, {From: "25:00", To: "06:00", Days: []string{"monday"}}}.Validate()
+	assert.ErrorContains(t, err, `window 1: invalid time: ""`)
+	assert.ErrorContains(t, err, `window 2: invalid time: "25:00"`)
+	assert.ErrorContains(t, err, "invalid day: monday")
+}
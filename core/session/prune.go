@@ -0,0 +1,21 @@
+package session
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Prune removes finished sessions created before the given time and returns the number of removed sessions.
+// Open sessions are kept. Sessions are removed in a single transaction.
+func Prune(db *gorm.DB, before time.Time) (int64, error) {
+	var count int64
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		res := tx.Where("created < ?", before).Not("finished = ?", time.Time{}).Delete(new(Session))
+		count = res.RowsAffected
+		return res.Error
+	})
+
+	return count, err
+}
@@ -0,0 +1,53 @@
+package session
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evcc-io/evcc/server/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrune(t *testing.T) {
+	gdb, err := db.New("sqlite", ":memory:")
+	require.NoError(t, err)
+
+	sqlDB, err := gdb.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+
+	_, err = NewStore("foo", gdb)
+	require.NoError(t, err)
+
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	retention := 30 * 24 * time.Hour
+
+	for _, s := range []Session{
+		{Created: now.Add(-60 * 24 * time.Hour), Finished: now.Add(-60*24*time.Hour + time.Hour), ChargedEnergy: 1},
+		{Created: now.Add(-31 * 24 * time.Hour), Finished: now.Add(-31*24*time.Hour + time.Hour), ChargedEnergy: 2},
+		{Created: now.Add(-60 * 24 * time.Hour), ChargedEnergy: 3}, // open
+		{Created: now.Add(-29 * 24 * time.Hour), Finished: now.Add(-29*24*time.Hour + time.Hour), ChargedEnergy: 4},
+		{Created: now.Add(-time.Hour), Finished: now, ChargedEnergy: 5},
+	} {
+		require.NoError(t, gdb.Create(&s).Error)
+	}
+
+	count, err := Prune(gdb, now.Add(-retention))
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+
+	var res Sessions
+	require.NoError(t, gdb.Order("id").Find(&res).Error)
+
+	var energies []float64
+	for _, s := range res {
+		energies = append(energies, s.ChargedEnergy)
+	}
+	assert.Equal(t, []float64{3, 4, 5}, energies)
+
+	// nothing left to prune
+	count, err = Prune(gdb, now.Add(-retention))
+	require.NoError(t, err)
+	assert.Zero(t, count)
+}
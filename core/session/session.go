@@ -21,15 +21,18 @@ import (
 // Session is a single charging session
 type Session struct {
 	ID              uint           `json:"id" csv:"-" gorm:"primarykey"`
-	Created         time.Time      `json:"created"`
+	Created         time.Time      `json:"created" gorm:"index"`
 	Finished        time.Time      `json:"finished"`
-	Loadpoint       string         `json:"loadpoint"`
+	Loadpoint       string         `json:"loadpoint" gorm:"index"`
 	Identifier      string         `json:"identifier"`
-	Vehicle         string         `json:"vehicle"`
+	Transaction     string         `json:"transaction"`
+	Vehicle         string         `json:"vehicle" gorm:"index"`
+	Tag             string         `json:"tag" gorm:"index"`
 	Odometer        *float64       `json:"odometer" format:"int"`
 	MeterStart      *float64       `json:"meterStart" csv:"Meter Start (kWh)" gorm:"column:meter_start_kwh"`
 	MeterStop       *float64       `json:"meterStop" csv:"Meter Stop (kWh)" gorm:"column:meter_end_kwh"`
 	ChargedEnergy   float64        `json:"chargedEnergy" csv:"Charged Energy (kWh)" gorm:"column:charged_kwh"`
+	VehicleEnergy   float64        `json:"vehicleEnergy" csv:"Vehicle Energy (kWh)" gorm:"column:vehicle_kwh"`
 	ChargeDuration  *time.Duration `json:"chargeDuration" csv:"Charge Duration" gorm:"column:charge_duration"`
 	SolarPercentage *float64       `json:"solarPercentage" csv:"Solar (%)" gorm:"column:solar_percentage"`
 	Price           *float64       `json:"price" csv:"Price" gorm:"column:price"`
@@ -0,0 +1,88 @@
+package session
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Tag sources
+const (
+	TagVehicle    = "vehicle"    // tag by assigned vehicle
+	TagIdentifier = "identifier" // tag by charger identifier, e.g. OCPP id tag
+
+	TagGuest = "guest" // sessions without vehicle or identifier
+)
+
+// ValidateTagSource validates the session tag source. Empty tags by vehicle and falls back to the identifier.
+func ValidateTagSource(source string) error {
+	switch source {
+	case "", TagVehicle, TagIdentifier:
+		return nil
+	default:
+		return fmt.Errorf("invalid session tag: %s", source)
+	}
+}
+
+// SetTag attributes the session to its vehicle or identifier according to source
+func (s *Session) SetTag(source string) {
+	var tags []string
+	switch source {
+	case TagVehicle:
+		tags = []string{s.Vehicle}
+	case TagIdentifier:
+		tags = []string{s.Identifier}
+	default:
+		tags = []string{s.Vehicle, s.Identifier}
+	}
+
+	s.Tag = TagGuest
+	for _, tag := range tags {
+		if tag != "" {
+			s.Tag = tag
+			break
+		}
+	}
+}
+
+// TagSummary aggregates the sessions of a single tag
+type TagSummary struct {
+	Tag           string  `json:"tag"`
+	Sessions      int     `json:"sessions"`
+	ChargedEnergy float64 `json:"chargedEnergy"` // kWh
+	Price         float64 `json:"price"`
+}
+
+// ByTag aggregates sessions per tag ordered by tag. Untagged sessions are attributed to the guest tag.
+func (t Sessions) ByTag() []TagSummary {
+	m := make(map[string]*TagSummary)
+
+	for _, s := range t {
+		tag := s.Tag
+		if tag == "" {
+			tag = TagGuest
+		}
+
+		ts, ok := m[tag]
+		if !ok {
+			ts = &TagSummary{Tag: tag}
+			m[tag] = ts
+		}
+
+		ts.Sessions++
+		ts.ChargedEnergy += s.ChargedEnergy
+		if s.Price != nil {
+			ts.Price += *s.Price
+		}
+	}
+
+	res := make([]TagSummary, 0, len(m))
+	for _, ts := range m {
+		res = append(res, *ts)
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Tag < res[j].Tag
+	})
+
+	return res
+}
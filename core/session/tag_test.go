@@ -0,0 +1,46 @@
+package session
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetTag(t *testing.T) {
+	tc := []struct {
+		source, vehicle, identifier, tag string
+	}{
+		{"", "blue", "0815", "blue"},
+		{"", "", "0815", "0815"},
+		{"", "", "", TagGuest},
+		{TagVehicle, "", "0815", TagGuest},
+		{TagIdentifier, "blue", "0815", "0815"},
+		{TagIdentifier, "blue", "", TagGuest},
+	}
+
+	for _, tc := range tc {
+		s := Session{Vehicle: tc.vehicle, Identifier: tc.identifier}
+		s.SetTag(tc.source)
+		assert.Equal(t, tc.tag, s.Tag, tc)
+	}
+
+	assert.Error(t, ValidateTagSource("foo"))
+}
+
+func TestByTag(t *testing.T) {
+	price := func(f float64) *float64 { return &f }
+
+	res := Sessions{
+		{Tag: "blue", ChargedEnergy: 10, Price: price(3)},
+		{Tag: "red", ChargedEnergy: 5},
+		{Tag: "blue", ChargedEnergy: 2, Price: price(0.5)},
+		{Tag: TagGuest, ChargedEnergy: 1, Price: price(1)},
+		{ChargedEnergy: 4}, // persisted before tagging
+	}.ByTag()
+
+	assert.Equal(t, []TagSummary{
+		{Tag: "blue", Sessions: 2, ChargedEnergy: 12, Price: 3.5},
+		{Tag: TagGuest, Sessions: 2, ChargedEnergy: 5, Price: 1},
+		{Tag: "red", Sessions: 1, ChargedEnergy: 5},
+	}, res)
+}
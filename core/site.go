@@ -4,18 +4,22 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"slices"
 	"sync"
 	"time"
 
 	"github.com/avast/retry-go/v4"
+	"github.com/benbjohnson/clock"
 	"github.com/evcc-io/evcc/api"
 	"github.com/evcc-io/evcc/cmd/shutdown"
 	"github.com/evcc-io/evcc/core/coordinator"
 	"github.com/evcc-io/evcc/core/loadpoint"
 	"github.com/evcc-io/evcc/core/planner"
 	"github.com/evcc-io/evcc/core/prioritizer"
+	"github.com/evcc-io/evcc/core/rules"
 	"github.com/evcc-io/evcc/core/session"
 	"github.com/evcc-io/evcc/core/soc"
+	"github.com/evcc-io/evcc/provider"
 	"github.com/evcc-io/evcc/push"
 	"github.com/evcc-io/evcc/server/db"
 	"github.com/evcc-io/evcc/server/db/settings"
@@ -33,10 +37,32 @@ type Updater interface {
 	Update(availablePower float64, autoCharge, batteryBuffered, batteryStart bool, greenShare float64, effectivePrice, effectiveCo2 *float64)
 }
 
+// greenPowerReceiver is a loadpoint that considers the site's self-generated power
+type greenPowerReceiver interface {
+	setGreenPower(pv, battery float64)
+}
+
+// pvPowerReceiver is a loadpoint that considers the site's pv production
+type pvPowerReceiver interface {
+	setPVPower(power float64)
+}
+
+// gridPriceReceiver is a loadpoint that considers the site's current grid price
+type gridPriceReceiver interface {
+	setGridPrice(price *float64)
+}
+
+// phaseCurrentLimiter is a loadpoint that is limited by the site's per-phase current caps
+type phaseCurrentLimiter interface {
+	phaseCurrents() [3]float64
+	setPhaseCurrentLimit(limit float64, limited bool)
+}
+
 // meterMeasurement is used as slice element for publishing structured data
 type meterMeasurement struct {
 	Power  float64 `json:"power"`
 	Energy float64 `json:"energy,omitempty"`
+	Error  string  `json:"error,omitempty"` // values are invalid if the meter failed
 }
 
 // batteryMeasurement is used as slice element for publishing structured data
@@ -45,29 +71,50 @@ type batteryMeasurement struct {
 	Energy   float64 `json:"energy,omitempty"`
 	Soc      float64 `json:"soc,omitempty"`
 	Capacity float64 `json:"capacity,omitempty"`
+	Error    string  `json:"error,omitempty"` // values are invalid if the meter failed
 }
 
 // Site is the main configuration container. A site can host multiple loadpoints.
 type Site struct {
 	uiChan       chan<- util.Param // client push messages
+	pushChan     chan<- push.Event // notifications
 	lpUpdateChan chan *Loadpoint
 
 	*Health
 
 	sync.Mutex
-	log *util.Logger
+	log   *util.Logger
+	clock clock.Clock // mockable time
 
 	// configuration
-	Title                             string       `mapstructure:"title"`         // UI title
-	Voltage                           float64      `mapstructure:"voltage"`       // Operating voltage. 230V for Germany.
-	ResidualPower                     float64      `mapstructure:"residualPower"` // PV meter only: household usage. Grid meter: household safety margin
-	Meters                            MetersConfig // Meter references
-	PrioritySoc                       float64      `mapstructure:"prioritySoc"`                       // prefer battery up to this Soc
-	BufferSoc                         float64      `mapstructure:"bufferSoc"`                         // continue charging on battery above this Soc
-	BufferStartSoc                    float64      `mapstructure:"bufferStartSoc"`                    // start charging on battery above this Soc
-	MaxGridSupplyWhileBatteryCharging float64      `mapstructure:"maxGridSupplyWhileBatteryCharging"` // ignore battery charging if AC consumption is above this value
-	SmartCostLimit                    float64      `mapstructure:"smartCostLimit"`                    // always charge if cost is below this value
-	BatteryDischargeControl           bool         `mapstructure:"batteryDischargeControl"`           // shall discharge of home battery be adjusted
+	Title                             string                       `mapstructure:"title"`         // UI title
+	Voltage                           float64                      `mapstructure:"voltage"`       // Operating voltage. 230V for Germany.
+	ResidualPower                     float64                      `mapstructure:"residualPower"` // PV meter only: household usage. Grid meter: household safety margin
+	Meters                            MetersConfig                 // Meter references
+	PrioritySoc                       float64                      `mapstructure:"prioritySoc"`                       // prefer battery up to this Soc
+	PrioritySocHysteresis             float64                      `mapstructure:"prioritySocHysteresis"`             // keep yielding to vehicles until the battery Soc drops this much below prioritySoc
+	BufferSoc                         float64                      `mapstructure:"bufferSoc"`                         // continue charging on battery above this Soc
+	BufferStartSoc                    float64                      `mapstructure:"bufferStartSoc"`                    // start charging on battery above this Soc
+	MaxGridSupplyWhileBatteryCharging float64                      `mapstructure:"maxGridSupplyWhileBatteryCharging"` // ignore battery charging if AC consumption is above this value
+	SmartCostLimit                    float64                      `mapstructure:"smartCostLimit"`                    // always charge if cost is below this value
+	BatteryDischargeControl           bool                         `mapstructure:"batteryDischargeControl"`           // shall discharge of home battery be adjusted
+	MaxPhaseCurrents                  []float64                    `mapstructure:"maxPhaseCurrents"`                  // per-phase grid current caps, 0 for uncapped phases
+	TariffFallback                    planner.Fallback             `mapstructure:"tariffFallback"`                    // planner behaviour while tariff data is unavailable
+	SurplusAllocation                 prioritizer.AllocationConfig `mapstructure:"surplusAllocation"`                 // distribute pv surplus by loadpoint priority
+	BatteryProtection                 BatteryProtection            `mapstructure:"batteryProtection"`                 // prevent charging vehicles from battery discharge
+	BatteryArbitrage                  BatteryArbitrage             `mapstructure:"batteryArbitrage"`                  // charge vehicles from battery discharge during high prices
+	BatteryCycleCost                  BatteryCycleCost             `mapstructure:"batteryCycleCost"`                  // charge vehicles from grid while cheaper than cycling the battery
+	LoadGroups                        []LoadGroup                  `mapstructure:"loadGroups"`                        // loadpoints sharing a current or power budget
+	EmergencyStop                     *provider.Config             `mapstructure:"emergencyStop"`                     // input engaging the emergency stop
+	Generator                         Generator                    `mapstructure:"generator"`                         // limit charging while running on generator
+	DemandResponse                    DemandResponse               `mapstructure:"demandResponse"`                    // reduce charging during grid operator demand response events
+	PeakWindows                       rules.Windows                `mapstructure:"peakWindows"`                       // charging is blocked during peak windows
+	ZeroExport                        ZeroExport                   `mapstructure:"zeroExport"`                        // keep grid export near zero
+	AuthProbe                         AuthProbe                    `mapstructure:"authProbe"`                         // verify vehicle authorization at startup
+	NetMetering                       NetMetering                  `mapstructure:"netMetering"`                       // charge from accumulated export credit
+	PeakDemand                        PeakDemand                   `mapstructure:"peakDemand"`                        // keep monthly peak demand below target
+	ErrorBudget                       ErrorBudget                  `mapstructure:"errorBudget"`                       // failed reads tolerated before meters become unhealthy
+	MainFuse                          MainFuse                     `mapstructure:"mainFuse"`                          // hard ceiling of the commanded charge currents
 
 	// meters
 	gridMeter     api.Meter   // Grid usage meter
@@ -77,17 +124,51 @@ type Site struct {
 
 	tariffs     tariff.Tariffs           // Tariff
 	loadpoints  []*Loadpoint             // Loadpoints
+	loadGroups  []*LoadGroup             // Load groups
 	coordinator *coordinator.Coordinator // Vehicles
 	prioritizer *prioritizer.Prioritizer // Power budgets
 	stats       *Stats                   // Stats
 
 	// cached state
 	gridPower    float64         // Grid power
+	gridCurrents []float64       // Grid phase currents (signed)
 	pvPower      float64         // PV power
 	batteryPower float64         // Battery charge power
 	batterySoc   float64         // Battery soc
 	batteryMode  api.BatteryMode // Battery discharge currently enabled
 
+	batteryArbitrageActive bool // Battery discharge used for charging
+	batteryYielding        bool // Battery yields surplus to vehicles above prioritySoc
+	batteryCycleCostActive bool // Grid used for charging instead of battery discharge
+
+	deviceUnhealthy map[string]bool // devices with exhausted error budget
+
+	emergencyStopG func() (bool, error) // emergency stop input
+	emergencyStop  bool                 // emergency stop latched
+
+	generatorG      func() (bool, error) // generator running input
+	generatorActive bool                 // generator running
+
+	demandResponseG      func() (bool, error)  // demand response event input
+	demandResponseEvents []DemandResponseEvent // participated demand response events
+
+	// zero export
+	zeroExportClock clock.Clock
+	curtailS        func(float64) error // pv curtailment setter
+	curtailPower    float64             // pv power currently curtailed
+	exportPower     float64             // grid export power
+	exportTimer     time.Time           // export above tolerance since
+	exportExceeded  bool                // export could not be absorbed
+
+	gridCost gridCost   // grid import cost and feed-in revenue
+	demand   peakDemand // monthly peak demand
+
+	// net metering
+	netBalanceG func() (float64, error) // credit balance
+	netImportG  func() (float64, error) // total import energy
+	netExportG  func() (float64, error) // total export energy
+	netBalance  float64                 // credit balance
+
 	publishCache map[string]any // store last published values to avoid unnecessary republishing
 }
 
@@ -132,12 +213,112 @@ func NewSiteFromConfig(
 		})
 	}
 
+	if err := site.TariffFallback.Validate(); err != nil {
+		return nil, err
+	}
+
 	tariff := site.GetTariff(PlannerTariff)
 
+	var err error
+	if site.loadGroups, err = setupLoadGroups(site.LoadGroups, loadpoints); err != nil {
+		return nil, err
+	}
+
+	if site.EmergencyStop != nil {
+		if site.emergencyStopG, err = provider.NewBoolGetterFromConfig(*site.EmergencyStop); err != nil {
+			return nil, fmt.Errorf("emergency stop: %w", err)
+		}
+	}
+
+	if site.Generator.Active != nil {
+		if err := site.Generator.Validate(); err != nil {
+			return nil, fmt.Errorf("generator: %w", err)
+		}
+		if site.generatorG, err = provider.NewBoolGetterFromConfig(*site.Generator.Active); err != nil {
+			return nil, fmt.Errorf("generator: %w", err)
+		}
+	}
+
+	if site.DemandResponse.Active != nil {
+		if err := site.DemandResponse.Validate(); err != nil {
+			return nil, fmt.Errorf("demand response: %w", err)
+		}
+		if site.demandResponseG, err = provider.NewBoolGetterFromConfig(*site.DemandResponse.Active); err != nil {
+			return nil, fmt.Errorf("demand response: %w", err)
+		}
+	}
+
+	if err := site.PeakWindows.Validate(); err != nil {
+		return nil, fmt.Errorf("peak windows: %w", err)
+	}
+
+	if err := site.BatteryArbitrage.Validate(); err != nil {
+		return nil, fmt.Errorf("battery arbitrage: %w", err)
+	}
+
+	if err := site.ErrorBudget.Validate(); err != nil {
+		return nil, fmt.Errorf("error budget: %w", err)
+	}
+
+	if err := site.BatteryCycleCost.Validate(); err != nil {
+		return nil, fmt.Errorf("battery cycle cost: %w", err)
+	}
+
+	if err := site.AuthProbe.Validate(); err != nil {
+		return nil, fmt.Errorf("auth probe: %w", err)
+	}
+
+	if err := site.ZeroExport.Validate(); err != nil {
+		return nil, fmt.Errorf("zero export: %w", err)
+	}
+
+	if site.ZeroExport.Enabled && site.ZeroExport.Curtail != nil {
+		if site.curtailS, err = provider.NewFloatSetterFromConfig("curtail", *site.ZeroExport.Curtail); err != nil {
+			return nil, fmt.Errorf("zero export: curtail: %w", err)
+		}
+	}
+
+	if err := site.PeakDemand.Validate(); err != nil {
+		return nil, fmt.Errorf("peak demand: %w", err)
+	}
+
+	site.demand.interval = site.PeakDemand.Interval
+	if tz := site.PeakDemand.Timezone; tz != "" {
+		site.demand.loc, _ = time.LoadLocation(tz)
+	}
+
+	if err := site.MainFuse.Validate(); err != nil {
+		return nil, fmt.Errorf("main fuse: %w", err)
+	}
+
+	if site.MainFuse.enabled() {
+		site.log.INFO.Printf("main fuse: commanded charge currents of all loadpoints limited to %s", site.MainFuse)
+	}
+
+	if err := site.NetMetering.Validate(); err != nil {
+		return nil, fmt.Errorf("net metering: %w", err)
+	}
+
+	if site.NetMetering.Enabled {
+		if err := site.configureNetMetering(); err != nil {
+			return nil, fmt.Errorf("net metering: %w", err)
+		}
+	}
+
 	// give loadpoints access to vehicles and database
 	for _, lp := range loadpoints {
+		lp.sitePeakWindows = site.PeakWindows
 		lp.coordinator = coordinator.NewAdapter(lp, site.coordinator)
 		lp.planner = planner.New(lp.log, tariff)
+		lp.planner.SetFallback(site.TariffFallback)
+		if tariff != nil && tariff.Type() != api.TariffTypeCo2 {
+			if site.tariffs.FeedIn != nil {
+				lp.planner.SetFeedIn(site.tariffs.FeedIn)
+			}
+			if site.tariffs.Co2 != nil {
+				lp.planner.SetCo2(site.tariffs.Co2)
+			}
+		}
 
 		if db.Instance != nil {
 			var err error
@@ -209,6 +390,15 @@ func NewSiteFromConfig(
 		return nil, errors.New("missing either grid or pv meter")
 	}
 
+	if len(site.MaxPhaseCurrents) > 0 {
+		if len(site.MaxPhaseCurrents) != 3 {
+			return nil, errors.New("maxPhaseCurrents must contain 3 phases")
+		}
+		if _, ok := site.gridMeter.(api.PhaseCurrents); !ok {
+			return nil, errors.New("maxPhaseCurrents requires grid meter with phase currents")
+		}
+	}
+
 	if site.BufferStartSoc != 0 && site.BufferStartSoc <= site.BufferSoc {
 		site.log.WARN.Println("bufferStartSoc must be larger than bufferSoc")
 	}
@@ -217,6 +407,10 @@ func NewSiteFromConfig(
 		site.log.WARN.Println("bufferSoc must be larger than prioritySoc")
 	}
 
+	if site.PrioritySocHysteresis < 0 {
+		return nil, errors.New("prioritySocHysteresis must not be negative")
+	}
+
 	return site, nil
 }
 
@@ -226,6 +420,24 @@ func NewSite() *Site {
 		log:          util.NewLogger("site"),
 		publishCache: make(map[string]any),
 		Voltage:      230, // V
+		TariffFallback: planner.Fallback{
+			Grace: 15 * time.Minute,
+		},
+		ZeroExport: ZeroExport{
+			Tolerance: 50, // W
+			Delay:     time.Minute,
+		},
+		AuthProbe: AuthProbe{
+			Delay: 5 * time.Second,
+		},
+		Generator: Generator{
+			Limit: generatorOff,
+		},
+		DemandResponse: DemandResponse{
+			Limit: demandResponseOff,
+		},
+		clock:           clock.New(),
+		zeroExportClock: clock.New(),
 	}
 
 	return lp
@@ -256,6 +468,9 @@ func (site *Site) restoreSettings() {
 	if v, err := settings.Bool("site.batteryDischargeControl"); err == nil {
 		site.BatteryDischargeControl = v
 	}
+	if v, err := settings.Bool("site.emergencyStop"); err == nil {
+		site.emergencyStop = v
+	}
 }
 
 func meterCapabilities(name string, meter interface{}) string {
@@ -415,6 +630,7 @@ func (site *Site) updateMeters() error {
 		site.pvPower = 0
 
 		mm := make([]meterMeasurement, len(site.pvMeters))
+		refs := append(slices.Clone(site.Meters.PVMetersRef), site.Meters.PVMetersRef_...)
 
 		for i, meter := range site.pvMeters {
 			// pv power
@@ -439,13 +655,17 @@ func (site *Site) updateMeters() error {
 				if err == nil {
 					totalEnergy += energy
 				} else {
-					site.log.ERROR.Printf("pv %d energy: %v", i+1, err)
+					err = fmt.Errorf("pv %d energy: %v", i+1, err)
+					site.log.ERROR.Println(err)
 				}
 			}
 
+			site.trackDevice(meterName(refs, i, "pv"), err)
+
 			mm[i] = meterMeasurement{
 				Power:  power,
 				Energy: energy,
+				Error:  errorString(err),
 			}
 		}
 
@@ -465,6 +685,7 @@ func (site *Site) updateMeters() error {
 		site.batterySoc = 0
 
 		mm := make([]batteryMeasurement, len(site.batteryMeters))
+		refs := append(slices.Clone(site.Meters.BatteryMetersRef), site.Meters.BatteryMetersRef_...)
 
 		for i, meter := range site.batteryMeters {
 			// battery power
@@ -479,7 +700,8 @@ func (site *Site) updateMeters() error {
 					site.log.DEBUG.Printf("battery %d power: %.0fW", i+1, power)
 				}
 			} else {
-				site.log.ERROR.Printf("battery %d power: %v", i+1, err)
+				err = fmt.Errorf("battery %d power: %v", i+1, err)
+				site.log.ERROR.Println(err)
 			}
 
 			// battery energy (discharge)
@@ -489,16 +711,18 @@ func (site *Site) updateMeters() error {
 				if err == nil {
 					totalEnergy += energy
 				} else {
-					site.log.ERROR.Printf("battery %d energy: %v", i+1, err)
+					err = fmt.Errorf("battery %d energy: %v", i+1, err)
+					site.log.ERROR.Println(err)
 				}
 			}
 
 			// battery soc and capacity
 			var batSoc, capacity float64
 			if meter, ok := meter.(api.Battery); ok {
-				batSoc, err = soc.Guard(meter.Soc())
+				var socErr error
+				batSoc, socErr = soc.Guard(meter.Soc())
 
-				if err == nil {
+				if socErr == nil {
 					// weigh soc by capacity and accumulate total capacity
 					weighedSoc := batSoc
 					if m, ok := meter.(api.BatteryCapacity); ok {
@@ -512,15 +736,20 @@ func (site *Site) updateMeters() error {
 						site.log.DEBUG.Printf("battery %d soc: %.0f%%", i+1, batSoc)
 					}
 				} else {
-					site.log.ERROR.Printf("battery %d soc: %v", i+1, err)
+					socErr = fmt.Errorf("battery %d soc: %v", i+1, socErr)
+					site.log.ERROR.Println(socErr)
+					err = errors.Join(err, socErr)
 				}
 			}
 
+			site.trackDevice(meterName(refs, i, "battery"), err)
+
 			mm[i] = batteryMeasurement{
 				Power:    power,
 				Energy:   energy,
 				Soc:      batSoc,
 				Capacity: capacity,
+				Error:    errorString(err),
 			}
 		}
 
@@ -560,6 +789,7 @@ func (site *Site) updateMeters() error {
 	}
 
 	// grid phase currents (signed)
+	site.gridCurrents = nil
 	if phaseMeter, ok := site.gridMeter.(api.PhaseCurrents); err == nil && ok {
 		var i1, i2, i3 float64
 		i1, i2, i3, err = phaseMeter.Currents()
@@ -567,6 +797,7 @@ func (site *Site) updateMeters() error {
 			phases := []float64{util.SignFromPower(i1, p1), util.SignFromPower(i2, p2), util.SignFromPower(i3, p3)}
 			site.log.DEBUG.Printf("grid currents: %.3gA", phases)
 			site.publish("gridCurrents", phases)
+			site.gridCurrents = phases
 		} else {
 			err = fmt.Errorf("grid currents: %w", err)
 		}
@@ -583,9 +814,38 @@ func (site *Site) updateMeters() error {
 		}
 	}
 
+	// grid values are invalid if the grid meter failed
+	if site.gridMeter != nil {
+		site.publish("gridError", errorString(err))
+		site.trackDevice(site.gridMeterName(), err)
+	}
+
 	return err
 }
 
+// phaseCurrentLimit returns the max loadpoint current not exceeding any phase current cap.
+// The loadpoint's own phase currents are added back to the remaining grid phase headroom.
+func (site *Site) phaseCurrentLimit(own [3]float64) (float64, bool) {
+	if len(site.gridCurrents) != 3 {
+		return 0, false
+	}
+
+	limit := math.Inf(1)
+	for i, phaseMax := range site.MaxPhaseCurrents {
+		if phaseMax > 0 {
+			limit = min(limit, phaseMax-site.gridCurrents[i]+own[i])
+		}
+	}
+
+	if math.IsInf(limit, 1) {
+		return 0, false
+	}
+
+	site.log.DEBUG.Printf("phase current limit: %.3gA", limit)
+
+	return max(limit, 0), true
+}
+
 // sitePower returns
 //   - the net power exported by the site minus a residual margin
 //     (negative values mean grid: export, battery: charging
@@ -616,12 +876,21 @@ func (site *Site) sitePower(totalChargePower, flexiblePower float64) (float64, b
 	// handed to loadpoint
 	var batteryBuffered, batteryStart bool
 
+	var price *float64
+	if site.BatteryArbitrage.Enabled || site.BatteryCycleCost.Enabled {
+		if p, err := site.tariffs.CurrentGridPrice(); err == nil {
+			price = &p
+		}
+	}
+
+	var arbitrage, cycleCheaper bool
+
 	if len(site.batteryMeters) > 0 {
 		site.Lock()
 		defer site.Unlock()
 
 		// if battery is charging below prioritySoc give it priority
-		if site.batterySoc < site.PrioritySoc && batteryPower < 0 {
+		if site.batteryPriority() && batteryPower < 0 {
 			site.log.DEBUG.Printf("battery has priority at soc %.0f%% (< %.0f%%)", site.batterySoc, site.PrioritySoc)
 			batteryPower = 0
 		} else {
@@ -629,10 +898,49 @@ func (site *Site) sitePower(totalChargePower, flexiblePower float64) (float64, b
 			batteryBuffered = site.BufferSoc > 0 && site.batterySoc > site.BufferSoc
 			batteryStart = site.BufferStartSoc > 0 && site.batterySoc > site.BufferStartSoc
 		}
+
+		// battery discharge is not available for charging below protection soc
+		if site.batteryProtected() && (batteryBuffered || batteryStart) {
+			site.log.DEBUG.Printf("battery protected at soc %.0f%% (<= %.0f%%)", site.batterySoc, site.BatteryProtection.Soc)
+			batteryBuffered, batteryStart = false, false
+		}
+
+		// grid is cheaper than battery discharge
+		if cycleCheaper = site.batteryCycleCheaper(price); cycleCheaper {
+			site.log.DEBUG.Printf("battery cycle cost %.3f above price %.3f", site.BatteryCycleCost.Cost, *price)
+			batteryBuffered, batteryStart = false, false
+		}
+
+		// battery discharge is available for charging during high prices
+		if arbitrage = site.batteryArbitrage(price); arbitrage {
+			site.log.DEBUG.Printf("battery arbitrage at soc %.0f%% (> %.0f%%) and price %.3f", site.batterySoc, site.BatteryArbitrage.Soc, *price)
+			batteryBuffered, batteryStart = true, true
+		}
+
+		// stale battery values are not used for charging
+		if site.batteryUnhealthy() {
+			site.log.DEBUG.Println("battery unhealthy")
+			batteryBuffered, batteryStart, arbitrage = false, false, false
+		}
+
+		if arbitrage != site.batteryArbitrageActive {
+			site.batteryArbitrageActive = arbitrage
+			site.publish("batteryArbitrage", arbitrage)
+		}
+
+		if cycleCheaper != site.batteryCycleCostActive {
+			site.batteryCycleCostActive = cycleCheaper
+			site.publish("batteryCycleCostActive", cycleCheaper)
+		}
 	}
 
 	sitePower := sitePower(site.log, site.MaxGridSupplyWhileBatteryCharging, site.gridPower, batteryPower, site.ResidualPower)
 
+	// offer battery discharge up to the limit
+	if arbitrage && site.BatteryArbitrage.MaxPower > 0 {
+		sitePower -= site.BatteryArbitrage.MaxPower
+	}
+
 	// deduct smart loads
 	if len(site.auxMeters) > 0 {
 		var auxPower float64
@@ -667,6 +975,19 @@ func (site *Site) sitePower(totalChargePower, flexiblePower float64) (float64, b
 	return sitePower, batteryBuffered, batteryStart, nil
 }
 
+// allocatedSitePower returns the site power as seen by the loadpoint such that it
+// adjusts its charge power to the surplus allocated by loadpoint priority
+func (site *Site) allocatedSitePower(lp loadpoint.API, sitePower float64) float64 {
+	budget, ok := prioritizer.Allocate(site.SurplusAllocation.MaxGridImport-sitePower, site.Loadpoints())[lp]
+	if !ok {
+		return sitePower
+	}
+
+	site.log.DEBUG.Printf("lp %s at prio %d allocated: %.0fW", lp.Title(), lp.GetPriority(), budget)
+
+	return lp.GetChargePower() - budget
+}
+
 // greenShare returns
 //   - the current green share, calculated for the part of the consumption between powerFrom and powerTo
 //     the consumption below powerFrom will get the available green power first
@@ -688,6 +1009,18 @@ func (site *Site) greenShare(powerFrom float64, powerTo float64) float64 {
 	return share
 }
 
+// greenPower returns the self-generated pv and battery power remaining after home consumption.
+// Home consumption is served by pv first.
+func (site *Site) greenPower(homePower float64) (float64, float64) {
+	pv := math.Max(0, site.pvPower)
+	battery := math.Max(0, site.batteryPower)
+
+	pvRemaining := math.Max(0, pv-homePower)
+	batteryRemaining := math.Max(0, battery-math.Max(0, homePower-pv))
+
+	return pvRemaining, batteryRemaining
+}
+
 // effectivePrice calculates the real energy price based on self-produced and grid-imported energy.
 func (site *Site) effectivePrice(greenShare float64) *float64 {
 	if grid, err := site.tariffs.CurrentGridPrice(); err == nil {
@@ -749,9 +1082,14 @@ func (site *Site) update(lp Updater) {
 		site.prioritizer.UpdateChargePowerFlexibility(lp)
 	}
 
+	// stop all loadpoints immediately
+	site.updateEmergencyStop()
+	site.updateGenerator()
+	site.updateDemandResponse(site.clock.Now(), totalChargePower)
+
 	// prioritize if possible
 	var flexiblePower float64
-	if lp.GetMode() == api.ModePV {
+	if lp.GetMode() == api.ModePV && !site.SurplusAllocation.Enabled {
 		flexiblePower = site.prioritizer.GetChargePowerFlexibility(lp)
 	}
 
@@ -761,7 +1099,7 @@ func (site *Site) update(lp Updater) {
 
 		var rate api.Rate
 		if err == nil {
-			rate, err = rates.Current(time.Now())
+			rate, err = rates.Current(site.clock.Now())
 		}
 
 		if err == nil {
@@ -782,6 +1120,60 @@ func (site *Site) update(lp Updater) {
 		greenShareHome := site.greenShare(0, homePower)
 		greenShareLoadpoints := site.greenShare(homePower, homePower+totalChargePower)
 
+		site.updateGridCost(site.clock.Now())
+
+		if gr, ok := lp.(greenPowerReceiver); ok {
+			gr.setGreenPower(site.greenPower(homePower))
+		}
+
+		if pr, ok := lp.(pvPowerReceiver); ok {
+			pr.setPVPower(max(0, site.pvPower))
+		}
+
+		if pr, ok := lp.(gridPriceReceiver); ok {
+			var price *float64
+			if p, err := site.tariffs.CurrentGridPrice(); err == nil {
+				price = &p
+			}
+			pr.setGridPrice(price)
+		}
+
+		// export credit is available for charging
+		site.updateNetMetering()
+		sitePower = site.netMeteringSitePower(sitePower)
+
+		// curtailed pv is available for charging before curtailment is updated
+		sitePower = site.zeroExportSitePower(sitePower)
+		site.updateZeroExport()
+
+		if site.SurplusAllocation.Enabled {
+			sitePower = site.allocatedSitePower(lp, sitePower)
+		}
+
+		// charge from grid while cheaper than cycling the battery
+		if site.batteryCycleCostActive {
+			autoCharge = true
+		}
+
+		site.updatePeakDemand(site.clock.Now())
+		if dl, ok := lp.(demandLimiter); ok {
+			dl.setDemandLimit(site.demandCurrentLimit(dl))
+		}
+
+		if pl, ok := lp.(phaseCurrentLimiter); ok && len(site.MaxPhaseCurrents) > 0 {
+			pl.setPhaseCurrentLimit(site.phaseCurrentLimit(pl.phaseCurrents()))
+		}
+
+		for _, lg := range site.loadGroups {
+			if m := lg.loadpoint(lp); m != nil {
+				m.setLoadGroupLimit(lg.currentLimit(m), true)
+			}
+		}
+
+		if fl, ok := lp.(fuseLimiter); ok {
+			site.updateFuseLimit(fl)
+		}
+
 		lp.Update(sitePower, autoCharge, batteryBuffered, batteryStart, greenShareLoadpoints, site.effectivePrice(greenShareLoadpoints), site.effectiveCo2(greenShareLoadpoints))
 
 		site.Health.Update()
@@ -793,6 +1185,11 @@ func (site *Site) update(lp Updater) {
 		}
 	} else {
 		site.log.ERROR.Println(err)
+
+		// stop charging as soon as the grid meter becomes unhealthy
+		if site.gridUnhealthy() {
+			site.updateEmergencyStop()
+		}
 	}
 
 	if site.BatteryDischargeControl {
@@ -823,12 +1220,14 @@ func (site *Site) prepare() {
 
 	site.publish("vehicles", vehicleTitles(site.GetVehicles()))
 	site.publish("batteryDischargeControl", site.BatteryDischargeControl)
+	site.publish("emergencyStop", site.emergencyStop)
 	site.publish("batteryMode", site.batteryMode)
 }
 
 // Prepare attaches communication channels to site and loadpoints
 func (site *Site) Prepare(uiChan chan<- util.Param, pushChan chan<- push.Event) {
 	site.uiChan = uiChan
+	site.pushChan = pushChan
 	site.lpUpdateChan = make(chan *Loadpoint, 1) // 1 capacity to avoid deadlock
 
 	site.prepare()
@@ -873,6 +1272,10 @@ func (site *Site) Run(stopC chan struct{}, interval time.Duration) {
 		site.log.WARN.Printf("interval <%.0fs can lead to unexpected behavior, see https://docs.evcc.io/docs/reference/configuration/interval", max.Seconds())
 	}
 
+	if site.AuthProbe.Enabled {
+		go site.probeAuth(config.Vehicles().Devices())
+	}
+
 	loadpointChan := make(chan Updater)
 	go site.loopLoadpoints(loadpointChan)
 
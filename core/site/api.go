@@ -8,6 +8,10 @@ import (
 // API is the external site API
 type API interface {
 	Healthy() bool
+	// LoginRequired returns the names of vehicles requiring login
+	LoginRequired() []string
+	// Unhealthy returns the names of devices that exhausted their error budget
+	Unhealthy() []string
 	Loadpoints() []loadpoint.API
 
 	//
@@ -28,6 +32,20 @@ type API interface {
 	GetResidualPower() float64
 	SetResidualPower(float64) error
 
+	// GetPowerFlows returns the current directional power flows
+	GetPowerFlows() api.PowerFlows
+
+	// GetEmergencyStop returns the latched emergency stop state
+	GetEmergencyStop() bool
+	// SetEmergencyStop engages or clears the emergency stop
+	SetEmergencyStop(bool) error
+
+	// ResetStatistics discards the lifetime charging statistics
+	ResetStatistics()
+
+	// SendTestNotification sends a test notification to all messaging services
+	SendTestNotification() error
+
 	//
 	// vehicles
 	//
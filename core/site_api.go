@@ -5,6 +5,7 @@ import (
 
 	"github.com/evcc-io/evcc/api"
 	"github.com/evcc-io/evcc/core/site"
+	"github.com/evcc-io/evcc/push"
 	"github.com/evcc-io/evcc/server/db/settings"
 )
 
@@ -186,3 +187,21 @@ func (site *Site) GetTariff(tariff string) api.Tariff {
 		return nil
 	}
 }
+
+// ResetStatistics discards the lifetime charging statistics
+func (site *Site) ResetStatistics() {
+	site.log.INFO.Println("reset statistics")
+	site.stats.Reset()
+}
+
+// SendTestNotification sends a test notification to all messaging services
+func (site *Site) SendTestNotification() error {
+	if site.pushChan == nil {
+		return errors.New("messaging not configured")
+	}
+
+	site.log.INFO.Println("send test notification")
+	site.pushChan <- push.Event{Event: push.TestEvent}
+
+	return nil
+}
@@ -0,0 +1,52 @@
+package core
+
+import (
+	"errors"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/util/config"
+)
+
+// AuthProbe verifies the persisted authorization of vehicles at startup instead of failing on the first poll
+type AuthProbe struct {
+	Enabled bool          `mapstructure:"enabled"`
+	Delay   time.Duration `mapstructure:"delay"` // delay between probes to respect api rate limits
+}
+
+// Validate validates the auth probe configuration
+func (c AuthProbe) Validate() error {
+	if c.Delay < 0 {
+		return errors.New("delay must not be negative")
+	}
+	return nil
+}
+
+// probeAuth probes the vehicles one by one and reports those requiring login to the health checker
+func (site *Site) probeAuth(devs []config.Device[api.Vehicle]) {
+	var probed bool
+
+	for _, dev := range devs {
+		ap, ok := dev.Instance().(api.AuthProber)
+		if !ok {
+			continue
+		}
+
+		if probed {
+			time.Sleep(site.AuthProbe.Delay)
+		}
+		probed = true
+
+		name := dev.Config().Name
+
+		err := ap.ProbeAuth()
+		if err != nil {
+			site.log.WARN.Printf("vehicle %s: auth probe: %v", name, err)
+		}
+
+		if !errors.Is(err, api.ErrLoginRequired) {
+			err = nil
+		}
+		site.Health.SetLoginRequired(name, err)
+	}
+}
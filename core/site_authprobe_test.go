@@ -0,0 +1,55 @@
+package core
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/util"
+	"github.com/evcc-io/evcc/util/config"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+type authProberVehicle struct {
+	api.Vehicle
+	err    error
+	probes int
+}
+
+func (v *authProberVehicle) ProbeAuth() error {
+	v.probes++
+	return v.err
+}
+
+func TestProbeAuth(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	revoked := &authProberVehicle{err: fmt.Errorf("%w: invalid_grant", api.ErrLoginRequired)}
+	valid := &authProberVehicle{}
+	unreachable := &authProberVehicle{err: fmt.Errorf("timeout")}
+
+	devs := []config.Device[api.Vehicle]{
+		config.NewStaticDevice(config.Named{Name: "revoked"}, api.Vehicle(revoked)),
+		config.NewStaticDevice(config.Named{Name: "valid"}, api.Vehicle(valid)),
+		config.NewStaticDevice(config.Named{Name: "unreachable"}, api.Vehicle(unreachable)),
+		config.NewStaticDevice(config.Named{Name: "plain"}, api.Vehicle(api.NewMockVehicle(ctrl))),
+	}
+
+	site := &Site{
+		log:    util.NewLogger("foo"),
+		Health: NewHealth(0),
+	}
+
+	site.probeAuth(devs)
+
+	assert.Equal(t, 1, revoked.probes)
+	assert.Equal(t, 1, valid.probes)
+	assert.Equal(t, 1, unreachable.probes)
+	assert.Equal(t, []string{"revoked"}, site.LoginRequired())
+
+	// mark is cleared once accepted
+	revoked.err = nil
+	site.probeAuth(devs)
+	assert.Empty(t, site.LoginRequired())
+}
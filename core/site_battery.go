@@ -1,10 +1,91 @@
 package core
 
 import (
+	"errors"
+
 	"github.com/evcc-io/evcc/api"
 	"github.com/evcc-io/evcc/core/loadpoint"
 )
 
+// batteryPriority returns true if the battery has priority over charging vehicles. The battery yields once it reaches
+// prioritySoc and keeps yielding until its soc drops below prioritySoc by the hysteresis to avoid oscillating
+// while vehicles draw from the surplus (no mutex).
+func (site *Site) batteryPriority() bool {
+	switch {
+	case site.batterySoc >= site.PrioritySoc && !site.batteryYielding:
+		site.log.DEBUG.Printf("battery yielding at soc %.0f%% (>= %.0f%%)", site.batterySoc, site.PrioritySoc)
+		site.batteryYielding = true
+	case site.batterySoc < site.PrioritySoc-site.PrioritySocHysteresis && site.batteryYielding:
+		site.log.DEBUG.Printf("battery stopped yielding at soc %.0f%% (< %.0f%%)", site.batterySoc, site.PrioritySoc-site.PrioritySocHysteresis)
+		site.batteryYielding = false
+	}
+
+	return !site.batteryYielding
+}
+
+// BatteryProtection prevents vehicles from being charged by home battery discharge
+type BatteryProtection struct {
+	Enabled bool    `mapstructure:"enabled"`
+	Soc     float64 `mapstructure:"soc"` // allow discharging to vehicles above this soc, 0 to never allow
+}
+
+// batteryProtected returns true if battery discharge must not be used for charging (no mutex)
+func (site *Site) batteryProtected() bool {
+	bp := site.BatteryProtection
+	return bp.Enabled && (bp.Soc == 0 || site.batterySoc <= bp.Soc)
+}
+
+// BatteryArbitrage permits charging vehicles from home battery discharge while grid prices are high
+type BatteryArbitrage struct {
+	Enabled  bool    `mapstructure:"enabled"`
+	Soc      float64 `mapstructure:"soc"`      // discharge to vehicles above this soc only
+	Price    float64 `mapstructure:"price"`    // discharge to vehicles at or above this grid price only
+	MaxPower float64 `mapstructure:"maxPower"` // limit battery discharge while charging from battery
+}
+
+// Validate validates the battery arbitrage configuration
+func (c BatteryArbitrage) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	if c.Soc <= 0 || c.Soc >= 100 {
+		return errors.New("soc must be between 0 and 100")
+	}
+	if c.Price <= 0 {
+		return errors.New("missing price")
+	}
+	if c.MaxPower < 0 {
+		return errors.New("maxPower must not be negative")
+	}
+	return nil
+}
+
+// batteryArbitrage returns true if battery discharge may be used for charging at the current grid price (no mutex)
+func (site *Site) batteryArbitrage(price *float64) bool {
+	ba := site.BatteryArbitrage
+	return ba.Enabled && price != nil && *price >= ba.Price && site.batterySoc > ba.Soc
+}
+
+// BatteryCycleCost charges vehicles from grid instead of home battery discharge while the grid price is below the battery's cycle cost
+type BatteryCycleCost struct {
+	Enabled bool    `mapstructure:"enabled"`
+	Cost    float64 `mapstructure:"cost"` // battery wear per cycled kWh in the grid tariff's currency
+}
+
+// Validate validates the battery cycle cost configuration
+func (c BatteryCycleCost) Validate() error {
+	if c.Enabled && c.Cost <= 0 {
+		return errors.New("cost must be positive")
+	}
+	return nil
+}
+
+// batteryCycleCheaper returns true if charging from grid is cheaper than cycling the battery at the current grid price (no mutex)
+func (site *Site) batteryCycleCheaper(price *float64) bool {
+	bc := site.BatteryCycleCost
+	return bc.Enabled && price != nil && *price < bc.Cost
+}
+
 // getBatteryMode returns the battery mode
 func (site *Site) getBatteryMode() api.BatteryMode {
 	site.Lock()
@@ -29,6 +110,26 @@ func (site *Site) updateBatteryMode(loadpoints []loadpoint.API) {
 		}
 	}
 
+	// vehicles are charged from grid instead of battery
+	if site.batteryCycleCostActive && batMode == api.BatteryNormal {
+		for _, lp := range loadpoints {
+			if lp.GetStatus() == api.StatusC {
+				batMode = api.BatteryLocked
+				break
+			}
+		}
+	}
+
+	// battery discharge is used for charging
+	if site.batteryArbitrageActive {
+		batMode = api.BatteryNormal
+	}
+
+	// surplus is stored instead of exported
+	if site.zeroExporting() {
+		batMode = api.BatteryNormal
+	}
+
 	if batMode == site.getBatteryMode() {
 		return
 	}
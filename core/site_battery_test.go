@@ -2,12 +2,15 @@ package core
 
 import (
 	"testing"
+	"time"
 
 	"github.com/evcc-io/evcc/api"
 	"github.com/evcc-io/evcc/core/loadpoint"
+	"github.com/evcc-io/evcc/tariff"
 	"github.com/evcc-io/evcc/util"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestBatteryDischarge(t *testing.T) {
@@ -94,3 +97,273 @@ func TestBatteryModeNoUpdate(t *testing.T) {
 
 	s.updateBatteryMode(loadpoints) // this one should have updated again
 }
+
+func TestBatteryProtection(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	tc := []struct {
+		protection        BatteryProtection
+		soc               float64
+		buffered, started bool
+	}{
+		{BatteryProtection{}, 50, true, true},                         // no protection
+		{BatteryProtection{Enabled: true}, 50, false, false},          // never allowed
+		{BatteryProtection{Enabled: true, Soc: 60}, 50, false, false}, // below threshold
+		{BatteryProtection{Enabled: true, Soc: 60}, 60, false, false}, // at threshold
+		{BatteryProtection{Enabled: true, Soc: 40}, 50, true, true},   // above threshold
+	}
+
+	for _, tc := range tc {
+		t.Log(tc)
+
+		grid := api.NewMockMeter(ctrl)
+		grid.EXPECT().CurrentPower().Return(0.0, nil)
+
+		bat := struct {
+			*api.MockMeter
+			*api.MockBattery
+		}{
+			api.NewMockMeter(ctrl),
+			api.NewMockBattery(ctrl),
+		}
+		bat.MockMeter.EXPECT().CurrentPower().Return(2000.0, nil) // discharging
+		bat.MockBattery.EXPECT().Soc().Return(tc.soc, nil)
+
+		s := &Site{
+			log:               util.NewLogger("foo"),
+			gridMeter:         grid,
+			batteryMeters:     []api.Meter{bat},
+			BufferSoc:         20,
+			BufferStartSoc:    30,
+			BatteryProtection: tc.protection,
+		}
+
+		power, buffered, started, err := s.sitePower(0, 0)
+		require.NoError(t, err)
+
+		// battery discharge is never surplus
+		assert.Equal(t, 2000.0, power)
+		assert.Equal(t, tc.buffered, buffered, "buffered")
+		assert.Equal(t, tc.started, started, "started")
+	}
+}
+
+func TestBatteryArbitrage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	arbitrage := BatteryArbitrage{Enabled: true, Soc: 40, Price: 0.4, MaxPower: 5000}
+
+	tc := []struct {
+		arbitrage         BatteryArbitrage
+		price             float64
+		soc               float64
+		power             float64
+		buffered, started bool
+	}{
+		{BatteryArbitrage{}, 0.5, 80, 2000, false, false},                                 // disabled
+		{arbitrage, 0.3, 80, 2000, false, false},                                          // below price
+		{arbitrage, 0.4, 80, 2000 - 5000, true, true},                                     // at price
+		{arbitrage, 0.5, 40, 2000, false, false},                                          // at soc limit
+		{arbitrage, 0.5, 41, 2000 - 5000, true, true},                                     // above soc limit
+		{BatteryArbitrage{Enabled: true, Soc: 40, Price: 0.4}, 0.5, 80, 2000, true, true}, // no power limit
+	}
+
+	for _, tc := range tc {
+		t.Log(tc)
+
+		grid := api.NewMockMeter(ctrl)
+		grid.EXPECT().CurrentPower().Return(0.0, nil)
+
+		bat := struct {
+			*api.MockMeter
+			*api.MockBattery
+		}{
+			api.NewMockMeter(ctrl),
+			api.NewMockBattery(ctrl),
+		}
+		bat.MockMeter.EXPECT().CurrentPower().Return(2000.0, nil) // discharging
+		bat.MockBattery.EXPECT().Soc().Return(tc.soc, nil)
+
+		rates := api.NewMockTariff(ctrl)
+		rates.EXPECT().Rates().Return(api.Rates{{
+			Start: time.Now().Add(-time.Hour),
+			End:   time.Now().Add(time.Hour),
+			Price: tc.price,
+		}}, nil).AnyTimes()
+
+		s := &Site{
+			log:           util.NewLogger("foo"),
+			gridMeter:     grid,
+			batteryMeters: []api.Meter{bat},
+			tariffs:       tariff.Tariffs{Grid: rates},
+			// never charge from battery otherwise
+			BatteryProtection: BatteryProtection{Enabled: true},
+			BatteryArbitrage:  tc.arbitrage,
+		}
+
+		power, buffered, started, err := s.sitePower(0, 0)
+		require.NoError(t, err)
+
+		assert.Equal(t, tc.power, power)
+		assert.Equal(t, tc.buffered, buffered, "buffered")
+		assert.Equal(t, tc.started, started, "started")
+		assert.Equal(t, tc.buffered, s.batteryArbitrageActive)
+	}
+
+	assert.Error(t, BatteryArbitrage{Enabled: true, Price: 0.4}.Validate())
+	assert.Error(t, BatteryArbitrage{Enabled: true, Soc: 40}.Validate())
+	assert.NoError(t, arbitrage.Validate())
+}
+
+func TestBatteryCycleCost(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	cycleCost := BatteryCycleCost{Enabled: true, Cost: 0.2}
+
+	tc := []struct {
+		cycleCost         BatteryCycleCost
+		price             float64
+		buffered, started bool
+		active            bool
+	}{
+		{BatteryCycleCost{}, 0.1, true, true, false}, // disabled
+		{cycleCost, 0.1, false, false, true},         // below cycle cost
+		{cycleCost, 0.2, true, true, false},          // at cycle cost
+		{cycleCost, 0.3, true, true, false},          // above cycle cost
+	}
+
+	for _, tc := range tc {
+		t.Log(tc)
+
+		grid := api.NewMockMeter(ctrl)
+		grid.EXPECT().CurrentPower().Return(0.0, nil)
+
+		bat := struct {
+			*api.MockMeter
+			*api.MockBattery
+		}{
+			api.NewMockMeter(ctrl),
+			api.NewMockBattery(ctrl),
+		}
+		bat.MockMeter.EXPECT().CurrentPower().Return(2000.0, nil) // discharging
+		bat.MockBattery.EXPECT().Soc().Return(80.0, nil)
+
+		rates := api.NewMockTariff(ctrl)
+		rates.EXPECT().Rates().Return(api.Rates{{
+			Start: time.Now().Add(-time.Hour),
+			End:   time.Now().Add(time.Hour),
+			Price: tc.price,
+		}}, nil).AnyTimes()
+
+		s := &Site{
+			log:              util.NewLogger("foo"),
+			gridMeter:        grid,
+			batteryMeters:    []api.Meter{bat},
+			tariffs:          tariff.Tariffs{Grid: rates},
+			BufferSoc:        20,
+			BufferStartSoc:   30,
+			BatteryCycleCost: tc.cycleCost,
+		}
+
+		_, buffered, started, err := s.sitePower(0, 0)
+		require.NoError(t, err)
+
+		assert.Equal(t, tc.buffered, buffered, "buffered")
+		assert.Equal(t, tc.started, started, "started")
+		assert.Equal(t, tc.active, s.batteryCycleCostActive, "active")
+	}
+
+	assert.Error(t, BatteryCycleCost{Enabled: true}.Validate())
+	assert.NoError(t, cycleCost.Validate())
+}
+
+func TestBatteryCycleCostMode(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	batCtrl := struct {
+		*api.MockBatteryController
+		*api.MockMeter
+	}{
+		api.NewMockBatteryController(ctrl),
+		api.NewMockMeter(ctrl),
+	}
+
+	lp := loadpoint.NewMockAPI(ctrl)
+	lp.EXPECT().GetStatus().Return(api.StatusC).AnyTimes()
+	lp.EXPECT().GetMode().Return(api.ModePV).AnyTimes()
+	lp.EXPECT().GetPlanActive().Return(false).AnyTimes()
+
+	s := &Site{
+		log:                     util.NewLogger("foo"),
+		BatteryDischargeControl: true,
+		batteryMeters:           []api.Meter{batCtrl},
+		batteryCycleCostActive:  true,
+	}
+
+	// battery is held while charging from grid
+	batCtrl.MockBatteryController.EXPECT().SetBatteryMode(api.BatteryLocked)
+	s.updateBatteryMode([]loadpoint.API{lp})
+	assert.Equal(t, api.BatteryLocked, s.getBatteryMode())
+
+	// battery is released once the price rises
+	s.batteryCycleCostActive = false
+	batCtrl.MockBatteryController.EXPECT().SetBatteryMode(api.BatteryNormal)
+	s.updateBatteryMode([]loadpoint.API{lp})
+	assert.Equal(t, api.BatteryNormal, s.getBatteryMode())
+}
+
+func TestBatteryPriorityHysteresis(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	grid := api.NewMockMeter(ctrl)
+	grid.EXPECT().CurrentPower().Return(0.0, nil).AnyTimes()
+
+	var soc float64
+
+	bat := struct {
+		*api.MockMeter
+		*api.MockBattery
+	}{
+		api.NewMockMeter(ctrl),
+		api.NewMockBattery(ctrl),
+	}
+	bat.MockMeter.EXPECT().CurrentPower().Return(-2000.0, nil).AnyTimes() // charging
+	bat.MockBattery.EXPECT().Soc().DoAndReturn(func() (float64, error) { return soc, nil }).AnyTimes()
+
+	s := &Site{
+		log:                   util.NewLogger("foo"),
+		gridMeter:             grid,
+		batteryMeters:         []api.Meter{bat},
+		PrioritySoc:           80,
+		PrioritySocHysteresis: 5,
+	}
+
+	// vehicle draws power, battery soc oscillates around prioritySoc
+	tc := []struct {
+		soc      float64
+		yielding bool
+	}{
+		{79, false}, // below prioritySoc
+		{80, true},  // start yielding
+		{79, true},  // soc dips, keep yielding
+		{76, true},
+		{75, true}, // at stop level
+		{74, false},
+		{78, false}, // keep priority until prioritySoc
+		{80, true},
+	}
+
+	for _, tc := range tc {
+		soc = tc.soc
+
+		power, _, _, err := s.sitePower(0, 0)
+		require.NoError(t, err)
+
+		if tc.yielding {
+			// battery charge power is surplus
+			assert.Equal(t, -2000.0, power, tc.soc)
+		} else {
+			assert.Equal(t, 0.0, power, tc.soc)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// ErrorBudget tolerates a number of consecutive failed update cycles per device before the device is considered unhealthy.
+// Charging stops while the grid meter is unhealthy and unhealthy batteries are not used for charging.
+// Devices recover as soon as they are read successfully again.
+type ErrorBudget struct {
+	Errors  int            `mapstructure:"errors"`  // failed update cycles tolerated by each meter, disabled if zero
+	Devices map[string]int `mapstructure:"devices"` // failed update cycles tolerated by individual meters by name
+}
+
+// Validate validates the error budget configuration
+func (c ErrorBudget) Validate() error {
+	if c.Errors < 0 {
+		return errors.New("errors must not be negative")
+	}
+	for name, budget := range c.Devices {
+		if budget < 0 {
+			return fmt.Errorf("%s: errors must not be negative", name)
+		}
+	}
+	return nil
+}
+
+// budget returns the device's error budget
+func (c ErrorBudget) budget(name string) int {
+	if budget, ok := c.Devices[name]; ok {
+		return budget
+	}
+	return c.Errors
+}
+
+// meterName returns the i-th meter reference, falling back to the meter's role and index
+func meterName(refs []string, i int, role string) string {
+	if i < len(refs) && refs[i] != "" {
+		return refs[i]
+	}
+	return fmt.Sprintf("%s%d", role, i+1)
+}
+
+// gridMeterName returns the grid meter reference
+func (site *Site) gridMeterName() string {
+	return meterName([]string{site.Meters.GridMeterRef}, 0, "grid")
+}
+
+// trackDevice tracks the device's read result against its error budget and returns false while the device is unhealthy
+func (site *Site) trackDevice(name string, err error) bool {
+	healthy := site.Health.Track(name, site.ErrorBudget.budget(name), err)
+
+	if healthy == !site.deviceUnhealthy[name] {
+		return healthy
+	}
+
+	if healthy {
+		site.log.INFO.Printf("%s: recovered", name)
+		delete(site.deviceUnhealthy, name)
+	} else {
+		site.log.WARN.Printf("%s: unhealthy, error budget exhausted: %v", name, err)
+		if site.deviceUnhealthy == nil {
+			site.deviceUnhealthy = make(map[string]bool)
+		}
+		site.deviceUnhealthy[name] = true
+	}
+
+	site.publish("unhealthy", site.Health.Unhealthy())
+
+	return healthy
+}
+
+// gridUnhealthy returns true if the grid meter exhausted its error budget
+func (site *Site) gridUnhealthy() bool {
+	return site.gridMeter != nil && site.deviceUnhealthy[site.gridMeterName()]
+}
+
+// batteryUnhealthy returns true if any battery meter exhausted its error budget
+func (site *Site) batteryUnhealthy() bool {
+	refs := append(slices.Clone(site.Meters.BatteryMetersRef), site.Meters.BatteryMetersRef_...)
+	for i := range site.batteryMeters {
+		if site.deviceUnhealthy[meterName(refs, i, "battery")] {
+			return true
+		}
+	}
+	return false
+}
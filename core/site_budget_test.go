@@ -0,0 +1,109 @@
+package core
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/util"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrorBudget(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	grid := api.NewMockMeter(ctrl)
+	lp := &Loadpoint{log: util.NewLogger("foo")}
+
+	site := &Site{
+		log:         util.NewLogger("foo"),
+		Health:      NewHealth(0),
+		Meters:      MetersConfig{GridMeterRef: "my_grid"},
+		ErrorBudget: ErrorBudget{Errors: 1, Devices: map[string]int{"my_grid": 2}},
+		gridMeter:   grid,
+		loadpoints:  []*Loadpoint{lp},
+	}
+
+	readErr := errors.New("timeout")
+
+	// healthy while within budget
+	for i := 0; i < 2; i++ {
+		grid.EXPECT().CurrentPower().Return(0.0, readErr).Times(3) // retries
+		require.Error(t, site.updateMeters())
+		assert.False(t, site.gridUnhealthy())
+		assert.Empty(t, site.Unhealthy())
+	}
+
+	// unhealthy once budget is exhausted, charging stops
+	grid.EXPECT().CurrentPower().Return(0.0, readErr).Times(3)
+	require.Error(t, site.updateMeters())
+	assert.True(t, site.gridUnhealthy())
+	assert.Equal(t, []string{"my_grid"}, site.Unhealthy())
+
+	site.updateEmergencyStop()
+	assert.True(t, lp.emergencyStopped())
+
+	// recovered on successful read
+	grid.EXPECT().CurrentPower().Return(1000.0, nil)
+	require.NoError(t, site.updateMeters())
+	assert.False(t, site.gridUnhealthy())
+	assert.Empty(t, site.Unhealthy())
+
+	site.updateEmergencyStop()
+	assert.False(t, lp.emergencyStopped())
+}
+
+func TestErrorBudgetBattery(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	grid := api.NewMockMeter(ctrl)
+	grid.EXPECT().CurrentPower().Return(0.0, nil).AnyTimes()
+
+	bat := struct {
+		*api.MockMeter
+		*api.MockBattery
+	}{
+		api.NewMockMeter(ctrl),
+		api.NewMockBattery(ctrl),
+	}
+	bat.MockMeter.EXPECT().CurrentPower().Return(2000.0, nil).AnyTimes()
+
+	site := &Site{
+		log:            util.NewLogger("foo"),
+		Health:         NewHealth(0),
+		ErrorBudget:    ErrorBudget{Errors: 1},
+		gridMeter:      grid,
+		batteryMeters:  []api.Meter{bat},
+		BufferSoc:      20,
+		BufferStartSoc: 30,
+	}
+
+	// soc failures within budget keep the battery usable
+	bat.MockBattery.EXPECT().Soc().Return(0.0, errors.New("timeout"))
+	_, _, _, err := site.sitePower(0, 0)
+	require.NoError(t, err)
+	assert.False(t, site.batteryUnhealthy())
+
+	// exhausted budget disables battery buffer
+	bat.MockBattery.EXPECT().Soc().Return(0.0, errors.New("timeout"))
+	_, buffered, _, err := site.sitePower(0, 0)
+	require.NoError(t, err)
+	assert.True(t, site.batteryUnhealthy())
+	assert.False(t, buffered)
+	assert.Equal(t, []string{"battery1"}, site.Unhealthy())
+
+	// recovered
+	bat.MockBattery.EXPECT().Soc().Return(80.0, nil)
+	_, buffered, _, err = site.sitePower(0, 0)
+	require.NoError(t, err)
+	assert.False(t, site.batteryUnhealthy())
+	assert.True(t, buffered)
+}
+
+func TestErrorBudgetValidate(t *testing.T) {
+	assert.NoError(t, ErrorBudget{}.Validate())
+	assert.Error(t, ErrorBudget{Errors: -1}.Validate())
+	assert.Error(t, ErrorBudget{Devices: map[string]int{"grid": -1}}.Validate())
+}
@@ -0,0 +1,53 @@
+package core
+
+import "time"
+
+// gridCost accumulates the daily grid import cost and feed-in revenue at the respective tariff prices
+type gridCost struct {
+	updated            time.Time
+	imported, exported float64 // kWh
+	cost, revenue      float64 // currency
+}
+
+// add accounts the grid power since the last update. Positive power is imported, negative power exported.
+func (c *gridCost) add(now time.Time, gridPower, importPrice, exportPrice float64) {
+	if c.updated.IsZero() || now.YearDay() != c.updated.YearDay() || now.Year() != c.updated.Year() {
+		*c = gridCost{updated: now}
+		return
+	}
+
+	kWh := gridPower * now.Sub(c.updated).Hours() / 1e3
+	c.updated = now
+
+	if kWh > 0 {
+		c.imported += kWh
+		c.cost += kWh * importPrice
+	} else {
+		c.exported -= kWh
+		c.revenue -= kWh * exportPrice
+	}
+}
+
+// net returns the import cost less the feed-in revenue
+func (c *gridCost) net() float64 {
+	return c.cost - c.revenue
+}
+
+// updateGridCost accounts the grid energy at the current import and feed-in prices
+func (site *Site) updateGridCost(now time.Time) {
+	importPrice, err := site.tariffs.CurrentGridPrice()
+	if err != nil {
+		importPrice = 0
+	}
+
+	exportPrice, err := site.tariffs.CurrentFeedInPrice()
+	if err != nil {
+		exportPrice = 0
+	}
+
+	site.gridCost.add(now, site.gridPower, importPrice, exportPrice)
+
+	site.publish("gridCostToday", site.gridCost.cost)
+	site.publish("feedInRevenueToday", site.gridCost.revenue)
+	site.publish("netCostToday", site.gridCost.net())
+}
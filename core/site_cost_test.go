@@ -0,0 +1,33 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGridCost(t *testing.T) {
+	now := time.Date(2024, 6, 1, 10, 0, 0, 0, time.Local)
+
+	var c gridCost
+	c.add(now, 0, 0.30, 0.08)
+
+	// import 2kW for 1h at 0.30
+	now = now.Add(time.Hour)
+	c.add(now, 2000, 0.30, 0.08)
+
+	// export 5kW for 2h at 0.08
+	now = now.Add(2 * time.Hour)
+	c.add(now, -5000, 0.30, 0.08)
+
+	assert.InDelta(t, 2.0, c.imported, 1e-6)
+	assert.InDelta(t, 10.0, c.exported, 1e-6)
+	assert.InDelta(t, 0.6, c.cost, 1e-6)
+	assert.InDelta(t, 0.8, c.revenue, 1e-6)
+	assert.InDelta(t, -0.2, c.net(), 1e-6)
+
+	// reset at day change
+	c.add(now.AddDate(0, 0, 1), 2000, 0.30, 0.08)
+	assert.Zero(t, c.net())
+}
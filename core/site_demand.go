@@ -0,0 +1,116 @@
+package core
+
+import (
+	"errors"
+	"time"
+)
+
+// PeakDemand keeps the billed monthly peak demand below the target by limiting charging.
+// Once the month's peak exceeds the target it becomes the new limit since the demand charge is incurred anyway.
+type PeakDemand struct {
+	Target   float64       `mapstructure:"target"`   // monthly peak demand target (W), disabled if zero
+	Interval time.Duration `mapstructure:"interval"` // averaging interval of the billed demand, instantaneous if zero
+	Timezone string        `mapstructure:"timezone"` // timezone of the billing month, local if empty
+}
+
+// Validate validates the peak demand configuration
+func (c PeakDemand) Validate() error {
+	if c.Target < 0 {
+		return errors.New("target must not be negative")
+	}
+	if c.Interval < 0 {
+		return errors.New("interval must not be negative")
+	}
+	if _, err := time.LoadLocation(c.Timezone); err != nil {
+		return err
+	}
+	return nil
+}
+
+// demandLimiter is a loadpoint that is limited by the site's peak demand target
+type demandLimiter interface {
+	GetChargePower() float64
+	activePhases() int
+	setDemandLimit(limit float64, limited bool)
+}
+
+// peakDemand tracks the running monthly peak of the averaged grid import
+type peakDemand struct {
+	loc      *time.Location // billing month timezone, local if nil
+	interval time.Duration
+	month    time.Time // start of the tracked month
+	peak     float64   // monthly peak (W)
+	updated  time.Time
+	energy   float64 // import energy in the current averaging interval (Wh)
+}
+
+// add accounts the grid power since the last update and returns the monthly peak
+func (d *peakDemand) add(now time.Time, gridPower float64) float64 {
+	loc := d.loc
+	if loc == nil {
+		loc = time.Local
+	}
+	now = now.In(loc)
+
+	if month := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, loc); !month.Equal(d.month) {
+		*d = peakDemand{loc: d.loc, interval: d.interval, month: month}
+	}
+
+	if d.interval == 0 {
+		d.peak = max(d.peak, gridPower)
+		return d.peak
+	}
+
+	// split the elapsed time at interval boundaries
+	for ts := d.updated; !ts.IsZero() && ts.Before(now); {
+		end := ts.Truncate(d.interval).Add(d.interval)
+		if end.After(now) {
+			end = now
+		}
+
+		d.energy += max(0, gridPower) * end.Sub(ts).Hours()
+
+		// the interval's average grows towards the billed demand as the interval elapses
+		d.peak = max(d.peak, d.energy/d.interval.Hours())
+
+		if end.Equal(end.Truncate(d.interval)) {
+			d.energy = 0
+		}
+
+		ts = end
+	}
+
+	d.updated = now
+
+	return d.peak
+}
+
+// demandLimit returns the site's grid import limit
+func (site *Site) demandLimit() float64 {
+	return max(site.PeakDemand.Target, site.demand.peak)
+}
+
+// updatePeakDemand tracks the monthly peak demand
+func (site *Site) updatePeakDemand(now time.Time) {
+	if site.PeakDemand.Target <= 0 {
+		return
+	}
+
+	peak := site.demand.add(now, site.gridPower)
+	site.publish("peakDemand", peak)
+	site.publish("peakDemandLimit", site.demandLimit())
+}
+
+// demandCurrentLimit returns the loadpoint's charge current limit keeping the grid import below the demand limit
+func (site *Site) demandCurrentLimit(lp demandLimiter) (float64, bool) {
+	if site.PeakDemand.Target <= 0 {
+		return 0, false
+	}
+
+	available := site.demandLimit() - site.gridPower + lp.GetChargePower()
+	limit := max(0, available/(float64(max(lp.activePhases(), 1))*Voltage))
+
+	site.log.DEBUG.Printf("peak demand current limit: %.3gA", limit)
+
+	return limit, true
+}
@@ -0,0 +1,120 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evcc-io/evcc/util"
+	"github.com/stretchr/testify/assert"
+)
+
+type demandLoadpoint struct {
+	power   float64
+	phases  int
+	limit   float64
+	limited bool
+}
+
+func (lp *demandLoadpoint) GetChargePower() float64 { return lp.power }
+func (lp *demandLoadpoint) activePhases() int       { return lp.phases }
+
+func (lp *demandLoadpoint) setDemandLimit(limit float64, limited bool) {
+	lp.limit, lp.limited = limit, limited
+}
+
+func TestPeakDemandTracking(t *testing.T) {
+	loc := time.FixedZone("billing", 2*3600)
+	d := peakDemand{loc: loc, interval: 15 * time.Minute}
+
+	start := time.Date(2024, 1, 15, 12, 0, 0, 0, loc)
+
+	// samples account the power since the previous sample
+	sample := func(minute int, power float64) {
+		d.add(start.Add(time.Duration(minute)*time.Minute), power)
+	}
+
+	// 8kW for the first interval
+	sample(0, 0)
+	for i := 1; i <= 15; i++ {
+		sample(i, 8000)
+	}
+	assert.InDelta(t, 8000, d.peak, 1e-6)
+
+	// short spike does not exceed interval average
+	sample(16, 20000)
+	for i := 17; i <= 30; i++ {
+		sample(i, 0)
+	}
+	assert.InDelta(t, 8000, d.peak, 1e-6)
+
+	// spike lasting half the interval
+	for i := 31; i <= 45; i++ {
+		power := 0.0
+		if i <= 38 {
+			power = 20000
+		}
+		sample(i, power)
+	}
+	assert.InDelta(t, 20000*8.0/15, d.peak, 1e-6)
+
+	// update spanning intervals is split at the boundary
+	sample(50, 6000)
+	sample(75, 6000)
+	assert.InDelta(t, 20000*8.0/15, d.peak, 1e-6)
+	sample(95, 12000)
+	assert.InDelta(t, 12000, d.peak, 1e-6)
+
+	// month boundary in billing timezone, still january in utc
+	next := time.Date(2024, 2, 1, 0, 30, 0, 0, loc)
+	assert.Equal(t, time.January, next.UTC().Month())
+	assert.Zero(t, d.add(next, 1000))
+	assert.Equal(t, time.Date(2024, 2, 1, 0, 0, 0, 0, loc), d.month)
+}
+
+func TestPeakDemandThrottle(t *testing.T) {
+	const (
+		target = 11000.0 // W
+		home   = 4000.0  // W
+	)
+
+	Voltage = 230
+
+	site := &Site{
+		log: util.NewLogger("foo"),
+		PeakDemand: PeakDemand{
+			Target: target,
+		},
+	}
+
+	lp := &demandLoadpoint{phases: 3}
+	now := time.Date(2024, 1, 15, 12, 0, 0, 0, time.Local)
+
+	// loadpoint charges at limit up to 16A
+	for i := 0; i < 10; i++ {
+		site.gridPower = home + lp.power
+		site.updatePeakDemand(now)
+		lp.setDemandLimit(site.demandCurrentLimit(lp))
+
+		assert.True(t, lp.limited)
+		lp.power = min(lp.limit, 16) * 3 * Voltage
+
+		now = now.Add(time.Minute)
+	}
+
+	assert.InDelta(t, target-home, lp.power, 1e-6)
+	assert.LessOrEqual(t, site.demand.peak, target)
+
+	// peak beyond target raises the limit
+	site.gridPower = home + 9000
+	site.updatePeakDemand(now)
+	assert.Equal(t, home+9000, site.demandLimit())
+
+	site.gridPower = home + lp.power
+	lp.setDemandLimit(site.demandCurrentLimit(lp))
+	assert.InDelta(t, 9000/(3*Voltage), lp.limit, 1e-6)
+
+	// disabled
+	site.PeakDemand.Target = 0
+	lp.setDemandLimit(site.demandCurrentLimit(lp))
+	assert.False(t, lp.limited)
+}
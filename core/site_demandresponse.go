@@ -0,0 +1,83 @@
+package core
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/evcc-io/evcc/provider"
+)
+
+const (
+	demandResponseOff = "off" // stop charging during the event
+	demandResponseMin = "min" // limit charging to min current during the event
+)
+
+// DemandResponse reduces charging while the grid operator signals a demand response event
+type DemandResponse struct {
+	Active *provider.Config `mapstructure:"active"` // bool getter of the event state, e.g. webhook or mqtt flag
+	Limit  string           `mapstructure:"limit"`  // loadpoint state during the event: off or min
+}
+
+// Validate validates the demand response configuration
+func (c DemandResponse) Validate() error {
+	switch c.Limit {
+	case demandResponseOff, demandResponseMin:
+		return nil
+	default:
+		return fmt.Errorf("invalid limit: %s", c.Limit)
+	}
+}
+
+// DemandResponseEvent records the participation in a demand response event for accounting
+type DemandResponseEvent struct {
+	Start       time.Time `json:"start"`
+	End         time.Time `json:"end"`         // zero while the event is active
+	ChargePower float64   `json:"chargePower"` // total charge power at the start of the event (W)
+}
+
+// updateDemandResponse reads the demand response state and applies the limit to all loadpoints.
+// The last state is kept if the input cannot be read.
+func (site *Site) updateDemandResponse(now time.Time, chargePower float64) {
+	if site.demandResponseG == nil {
+		return
+	}
+
+	if active, err := site.demandResponseG(); err != nil {
+		site.log.ERROR.Printf("demand response: %v", err)
+	} else if active != site.demandResponseActive() {
+		site.setDemandResponse(now, active, chargePower)
+	}
+
+	var limit string
+	if site.demandResponseActive() {
+		limit = site.DemandResponse.Limit
+	}
+
+	for _, lp := range site.loadpoints {
+		lp.setDemandResponseLimit(limit)
+	}
+}
+
+// demandResponseActive returns true while a demand response event is active
+func (site *Site) demandResponseActive() bool {
+	n := len(site.demandResponseEvents)
+	return n > 0 && site.demandResponseEvents[n-1].End.IsZero()
+}
+
+// setDemandResponse starts or ends the participation in a demand response event
+func (site *Site) setDemandResponse(now time.Time, active bool, chargePower float64) {
+	if active {
+		site.log.INFO.Printf("demand response event started, reducing charge power of %.0fW", chargePower)
+		site.demandResponseEvents = append(site.demandResponseEvents, DemandResponseEvent{
+			Start:       now,
+			ChargePower: chargePower,
+		})
+	} else {
+		ev := &site.demandResponseEvents[len(site.demandResponseEvents)-1]
+		ev.End = now
+		site.log.INFO.Printf("demand response event ended after %v, start: %s", ev.End.Sub(ev.Start).Round(time.Second), ev.Start.Round(time.Second))
+	}
+
+	site.publish("demandResponseActive", active)
+	site.publish("demandResponseEvents", site.demandResponseEvents)
+}
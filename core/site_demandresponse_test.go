@@ -0,0 +1,100 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	evbus "github.com/asaskevich/EventBus"
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/util"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDemandResponse(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	charger := api.NewMockCharger(ctrl)
+
+	lp := &Loadpoint{
+		log:            util.NewLogger("foo"),
+		bus:            evbus.New(),
+		clock:          clock.NewMock(),
+		charger:        charger,
+		wakeUpTimer:    NewTimer(),
+		MinCurrent:     minA,
+		MaxCurrent:     maxA,
+		phases:         3,
+		measuredPhases: 3,
+		status:         api.StatusC,
+		Mode:           api.ModeNow,
+		enabled:        true,
+		chargeCurrent:  maxA,
+	}
+
+	var event bool
+	site := &Site{
+		log:            util.NewLogger("foo"),
+		loadpoints:     []*Loadpoint{lp},
+		DemandResponse: DemandResponse{Limit: demandResponseOff},
+		demandResponseG: func() (bool, error) {
+			return event, nil
+		},
+	}
+
+	start := time.Date(2024, 1, 15, 17, 0, 0, 0, time.UTC)
+
+	// no event
+	site.updateDemandResponse(start, 11000)
+	require.NoError(t, lp.fastCharging())
+	assert.True(t, lp.enabled)
+	assert.Empty(t, site.demandResponseEvents)
+
+	// charging pauses during the event
+	event = true
+	site.updateDemandResponse(start, 11000)
+	assert.True(t, site.demandResponseActive())
+
+	charger.EXPECT().Enable(false).Return(nil)
+	require.NoError(t, lp.fastCharging())
+	assert.False(t, lp.enabled)
+
+	// event continues
+	site.updateDemandResponse(start.Add(time.Hour), 0)
+	require.NoError(t, lp.fastCharging())
+	assert.False(t, lp.enabled)
+
+	// charging resumes after the event
+	event = false
+	site.updateDemandResponse(start.Add(2*time.Hour), 0)
+	assert.False(t, site.demandResponseActive())
+
+	charger.EXPECT().Enable(true).Return(nil)
+	require.NoError(t, lp.fastCharging())
+	assert.True(t, lp.enabled)
+
+	// participation is recorded
+	assert.Equal(t, []DemandResponseEvent{{
+		Start:       start,
+		End:         start.Add(2 * time.Hour),
+		ChargePower: 11000,
+	}}, site.demandResponseEvents)
+
+	// min limit reduces charging to min current
+	site.DemandResponse.Limit = demandResponseMin
+	event = true
+	site.updateDemandResponse(start.Add(3*time.Hour), 11000)
+
+	charger.EXPECT().MaxCurrent(int64(minA)).Return(nil)
+	require.NoError(t, lp.fastCharging())
+	assert.True(t, lp.enabled)
+	assert.Equal(t, float64(minA), lp.chargeCurrent)
+	assert.Len(t, site.demandResponseEvents, 2)
+}
+
+func TestDemandResponseValidate(t *testing.T) {
+	assert.NoError(t, DemandResponse{Limit: demandResponseOff}.Validate())
+	assert.NoError(t, DemandResponse{Limit: demandResponseMin}.Validate())
+	assert.Error(t, DemandResponse{Limit: "foo"}.Validate())
+}
@@ -0,0 +1,61 @@
+package core
+
+import (
+	"github.com/evcc-io/evcc/server/db/settings"
+)
+
+// GetEmergencyStop returns the latched emergency stop state
+func (site *Site) GetEmergencyStop() bool {
+	site.Lock()
+	defer site.Unlock()
+	return site.emergencyStop
+}
+
+// SetEmergencyStop engages or clears the emergency stop. Once engaged, the emergency stop remains latched until cleared.
+func (site *Site) SetEmergencyStop(active bool) error {
+	site.Lock()
+	site.setEmergencyStop(active)
+	site.Unlock()
+
+	// stop charging immediately
+	if len(site.loadpoints) > 0 {
+		site.loadpoints[0].requestUpdate()
+	}
+
+	return nil
+}
+
+// setEmergencyStop engages or clears the emergency stop (no mutex)
+func (site *Site) setEmergencyStop(active bool) {
+	if site.emergencyStop == active {
+		return
+	}
+
+	if active {
+		site.log.WARN.Println("emergency stop engaged")
+	} else {
+		site.log.WARN.Println("emergency stop cleared")
+	}
+
+	site.emergencyStop = active
+	settings.SetBool("site.emergencyStop", active)
+	site.publish("emergencyStop", active)
+}
+
+// updateEmergencyStop latches the emergency stop input and stops all loadpoints while engaged or while the grid meter is unhealthy
+func (site *Site) updateEmergencyStop() {
+	if site.emergencyStopG != nil {
+		if active, err := site.emergencyStopG(); err != nil {
+			site.log.ERROR.Printf("emergency stop: %v", err)
+		} else if active {
+			site.Lock()
+			site.setEmergencyStop(true)
+			site.Unlock()
+		}
+	}
+
+	active := site.GetEmergencyStop() || site.gridUnhealthy()
+	for _, lp := range site.loadpoints {
+		lp.setEmergencyStop(active)
+	}
+}
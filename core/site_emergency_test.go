@@ -0,0 +1,98 @@
+package core
+
+import (
+	"testing"
+
+	evbus "github.com/asaskevich/EventBus"
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/util"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmergencyStop(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	var chargers []*api.MockCharger
+	var loadpoints []*Loadpoint
+
+	for i := 0; i < 2; i++ {
+		charger := api.NewMockCharger(ctrl)
+		chargers = append(chargers, charger)
+
+		loadpoints = append(loadpoints, &Loadpoint{
+			log:            util.NewLogger("foo"),
+			bus:            evbus.New(),
+			clock:          clock.NewMock(),
+			charger:        charger,
+			wakeUpTimer:    NewTimer(),
+			MinCurrent:     minA,
+			MaxCurrent:     maxA,
+			phases:         3,
+			measuredPhases: 3,
+			status:         api.StatusC,
+			enabled:        true,
+			chargeCurrent:  maxA,
+		})
+	}
+
+	var input bool
+	site := &Site{
+		log:        util.NewLogger("foo"),
+		loadpoints: loadpoints,
+		emergencyStopG: func() (bool, error) {
+			return input, nil
+		},
+	}
+
+	// input engages emergency stop and stops all loadpoints
+	input = true
+	for _, charger := range chargers {
+		charger.EXPECT().Enable(false).Return(nil)
+	}
+	site.updateEmergencyStop()
+	ctrl.Finish()
+
+	for _, lp := range loadpoints {
+		assert.False(t, lp.enabled)
+	}
+	assert.True(t, site.GetEmergencyStop())
+
+	// emergency stop remains latched after input is released, overriding all modes
+	input = false
+	site.updateEmergencyStop()
+	assert.True(t, site.GetEmergencyStop())
+
+	for _, lp := range loadpoints {
+		require.NoError(t, lp.fastCharging())
+		assert.False(t, lp.enabled)
+	}
+	ctrl.Finish()
+
+	// explicitly cleared
+	require.NoError(t, site.SetEmergencyStop(false))
+	site.updateEmergencyStop()
+	assert.False(t, site.GetEmergencyStop())
+
+	for i, lp := range loadpoints {
+		chargers[i].EXPECT().Enable(true).Return(nil)
+		require.NoError(t, lp.fastCharging())
+		assert.True(t, lp.enabled)
+	}
+	ctrl.Finish()
+
+	// cannot be cleared while input is active
+	input = true
+	for _, charger := range chargers {
+		charger.EXPECT().Enable(false).Return(nil)
+	}
+	site.updateEmergencyStop()
+	require.NoError(t, site.SetEmergencyStop(false))
+	site.updateEmergencyStop()
+	assert.True(t, site.GetEmergencyStop())
+
+	input = false
+	require.NoError(t, site.SetEmergencyStop(false))
+}
@@ -0,0 +1,85 @@
+package core
+
+import (
+	"github.com/evcc-io/evcc/api"
+)
+
+// power flow nodes
+const (
+	flowPv         = "pv"
+	flowGrid       = "grid"
+	flowBattery    = "battery"
+	flowHome       = "home"
+	flowLoadpoints = "loadpoints"
+)
+
+// flowNode is a source or sink of power
+type flowNode struct {
+	name  string
+	power float64
+}
+
+// powerFlows distributes the power sources onto the consumers.
+// Sources supply consumers in order pv, battery, grid. Consumers are supplied in order home, loadpoints, battery, grid.
+func powerFlows(pv, grid, battery, charge float64) api.PowerFlows {
+	pv = max(0, pv)
+	charge = max(0, charge)
+	home := max(0, grid+pv+battery-charge)
+
+	res := api.PowerFlows{
+		Pv:         pv,
+		Grid:       grid,
+		Battery:    battery,
+		Home:       home,
+		Loadpoints: charge,
+		Flows:      make([]api.PowerFlow, 0),
+	}
+
+	sources := []flowNode{
+		{flowPv, pv},
+		{flowBattery, max(0, battery)},
+		{flowGrid, max(0, grid)},
+	}
+
+	sinks := []flowNode{
+		{flowHome, home},
+		{flowLoadpoints, charge},
+		{flowBattery, max(0, -battery)},
+		{flowGrid, max(0, -grid)},
+	}
+
+	for i := range sources {
+		src := &sources[i]
+
+		for j := range sinks {
+			sink := &sinks[j]
+			if src.power <= 0 {
+				break
+			}
+			if sink.power <= 0 || sink.name == src.name {
+				continue
+			}
+
+			power := min(src.power, sink.power)
+			src.power -= power
+			sink.power -= power
+
+			res.Flows = append(res.Flows, api.PowerFlow{From: src.name, To: sink.name, Power: power})
+		}
+	}
+
+	return res
+}
+
+// GetPowerFlows returns the current power flows of the site
+func (site *Site) GetPowerFlows() api.PowerFlows {
+	var charge float64
+	for _, lp := range site.loadpoints {
+		charge += lp.GetChargePower()
+	}
+
+	site.Lock()
+	defer site.Unlock()
+
+	return powerFlows(site.pvPower, site.gridPower, site.batteryPower, charge)
+}
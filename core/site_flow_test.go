@@ -0,0 +1,73 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func flow(from, to string, power float64) api.PowerFlow {
+	return api.PowerFlow{From: from, To: to, Power: power}
+}
+
+func TestPowerFlows(t *testing.T) {
+	tc := []struct {
+		title                     string
+		pv, grid, battery, charge float64
+		expected                  []api.PowerFlow
+	}{
+		{"pv only, export", 5000, -3000, 0, 0, []api.PowerFlow{
+			flow(flowPv, flowHome, 2000),
+			flow(flowPv, flowGrid, 3000),
+		}},
+		{"pv to car and battery", 8000, -500, -2000, 4000, []api.PowerFlow{
+			flow(flowPv, flowHome, 1500),
+			flow(flowPv, flowLoadpoints, 4000),
+			flow(flowPv, flowBattery, 2000),
+			flow(flowPv, flowGrid, 500),
+		}},
+		{"battery and grid supply", 1000, 2000, 1500, 3000, []api.PowerFlow{
+			flow(flowPv, flowHome, 1000),
+			flow(flowBattery, flowHome, 500),
+			flow(flowBattery, flowLoadpoints, 1000),
+			flow(flowGrid, flowLoadpoints, 2000),
+		}},
+		{"grid charges battery", 0, 4000, -3000, 0, []api.PowerFlow{
+			flow(flowGrid, flowHome, 1000),
+			flow(flowGrid, flowBattery, 3000),
+		}},
+		{"no pv, no battery", 0, 1000, 0, 0, []api.PowerFlow{
+			flow(flowGrid, flowHome, 1000),
+		}},
+		{"idle", 0, 0, 0, 0, []api.PowerFlow{}},
+	}
+
+	for _, tc := range tc {
+		t.Log(tc.title)
+
+		res := powerFlows(tc.pv, tc.grid, tc.battery, tc.charge)
+		assert.Equal(t, tc.expected, res.Flows, tc.title)
+
+		// flows are conserved at every node
+		balance := map[string]float64{
+			flowPv:         tc.pv,
+			flowGrid:       tc.grid,
+			flowBattery:    tc.battery,
+			flowHome:       -res.Home,
+			flowLoadpoints: -tc.charge,
+		}
+
+		for _, f := range res.Flows {
+			assert.Greater(t, f.Power, 0.0, tc.title)
+			assert.NotEqual(t, f.From, f.To, tc.title)
+
+			balance[f.From] -= f.Power
+			balance[f.To] += f.Power
+		}
+
+		for node, power := range balance {
+			assert.InDelta(t, 0, power, 1e-6, "%s: %s", tc.title, node)
+		}
+	}
+}
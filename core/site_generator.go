@@ -0,0 +1,53 @@
+package core
+
+import (
+	"fmt"
+
+	"github.com/evcc-io/evcc/provider"
+)
+
+const (
+	generatorOff = "off" // stop charging while the generator is running
+	generatorMin = "min" // limit charging to min current while the generator is running
+)
+
+// Generator limits charging while the site is supplied by a generator or backup source
+type Generator struct {
+	Active *provider.Config `mapstructure:"active"` // bool getter of the generator running state
+	Limit  string           `mapstructure:"limit"`  // loadpoint state while the generator is running: off or min
+}
+
+// Validate validates the generator configuration
+func (c Generator) Validate() error {
+	switch c.Limit {
+	case generatorOff, generatorMin:
+		return nil
+	default:
+		return fmt.Errorf("invalid limit: %s", c.Limit)
+	}
+}
+
+// updateGenerator reads the generator state and applies the generator limit to all loadpoints.
+// The last state is kept if the input cannot be read.
+func (site *Site) updateGenerator() {
+	if site.generatorG == nil {
+		return
+	}
+
+	if active, err := site.generatorG(); err != nil {
+		site.log.ERROR.Printf("generator: %v", err)
+	} else if active != site.generatorActive {
+		site.log.INFO.Printf("generator running: %v", active)
+		site.generatorActive = active
+		site.publish("generatorActive", active)
+	}
+
+	var limit string
+	if site.generatorActive {
+		limit = site.Generator.Limit
+	}
+
+	for _, lp := range site.loadpoints {
+		lp.setGeneratorLimit(limit)
+	}
+}
@@ -0,0 +1,82 @@
+package core
+
+import (
+	"testing"
+
+	evbus "github.com/asaskevich/EventBus"
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/util"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerator(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	charger := api.NewMockCharger(ctrl)
+
+	lp := &Loadpoint{
+		log:            util.NewLogger("foo"),
+		bus:            evbus.New(),
+		clock:          clock.NewMock(),
+		charger:        charger,
+		wakeUpTimer:    NewTimer(),
+		MinCurrent:     minA,
+		MaxCurrent:     maxA,
+		phases:         3,
+		measuredPhases: 3,
+		status:         api.StatusC,
+		Mode:           api.ModeNow,
+		enabled:        true,
+		chargeCurrent:  maxA,
+	}
+
+	var running bool
+	site := &Site{
+		log:        util.NewLogger("foo"),
+		loadpoints: []*Loadpoint{lp},
+		Generator:  Generator{Limit: generatorOff},
+		generatorG: func() (bool, error) {
+			return running, nil
+		},
+	}
+
+	// generator stopped
+	site.updateGenerator()
+	require.NoError(t, lp.fastCharging())
+	assert.True(t, lp.enabled)
+
+	// charging halts while generator is running
+	running = true
+	site.updateGenerator()
+	assert.True(t, site.generatorActive)
+
+	charger.EXPECT().Enable(false).Return(nil)
+	require.NoError(t, lp.fastCharging())
+	assert.False(t, lp.enabled)
+
+	// charging resumes once generator stops
+	running = false
+	site.updateGenerator()
+
+	charger.EXPECT().Enable(true).Return(nil)
+	require.NoError(t, lp.fastCharging())
+	assert.True(t, lp.enabled)
+
+	// min limit reduces charging to min current
+	site.Generator.Limit = generatorMin
+	running = true
+	site.updateGenerator()
+
+	charger.EXPECT().MaxCurrent(int64(minA)).Return(nil)
+	require.NoError(t, lp.fastCharging())
+	assert.True(t, lp.enabled)
+	assert.Equal(t, float64(minA), lp.chargeCurrent)
+}
+
+func TestGeneratorValidate(t *testing.T) {
+	assert.NoError(t, Generator{Limit: generatorOff}.Validate())
+	assert.NoError(t, Generator{Limit: generatorMin}.Validate())
+	assert.Error(t, Generator{Limit: "pv"}.Validate())
+}
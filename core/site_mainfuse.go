@@ -0,0 +1,81 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// MainFuse is the hard ceiling of the commanded charge currents of all loadpoints, representing the main fuse rating.
+// It is applied after all other limits and cannot be overridden by modes, plans or manual settings.
+// The ceiling does not depend on measurements, it limits the sum of the commanded currents and powers.
+type MainFuse struct {
+	Current float64 `mapstructure:"current"` // max current per phase across all loadpoints (A), disabled if zero
+	Power   float64 `mapstructure:"power"`   // max power across all loadpoints (W), disabled if zero
+}
+
+// Validate validates the main fuse configuration
+func (c MainFuse) Validate() error {
+	if c.Current < 0 || c.Power < 0 {
+		return errors.New("current and power must not be negative")
+	}
+	return nil
+}
+
+// enabled returns true if a current or power ceiling is configured
+func (c MainFuse) enabled() bool {
+	return c.Current > 0 || c.Power > 0
+}
+
+func (c MainFuse) String() string {
+	var res []string
+	if c.Current > 0 {
+		res = append(res, fmt.Sprintf("%.0fA per phase", c.Current))
+	}
+	if c.Power > 0 {
+		res = append(res, fmt.Sprintf("%.0fW", c.Power))
+	}
+	return strings.Join(res, " and ")
+}
+
+// fuseLimiter is a loadpoint whose commanded current is clamped by the site's main fuse
+type fuseLimiter interface {
+	commandedCurrent() float64
+	activePhases() int
+	setFuseLimit(current, power float64, limited bool)
+}
+
+// fuseBudget returns the current and power left for the loadpoint after the commanded currents of all other loadpoints.
+// Unconfigured ceilings are infinite.
+func (site *Site) fuseBudget(lp fuseLimiter) (float64, float64) {
+	current, power := math.Inf(1), math.Inf(1)
+	if site.MainFuse.Current > 0 {
+		current = site.MainFuse.Current
+	}
+	if site.MainFuse.Power > 0 {
+		power = site.MainFuse.Power
+	}
+
+	for _, other := range site.loadpoints {
+		if fuseLimiter(other) == lp {
+			continue
+		}
+
+		c := other.commandedCurrent()
+		current -= c
+		power -= c * float64(other.activePhases()) * Voltage
+	}
+
+	return max(0, current), max(0, power)
+}
+
+// updateFuseLimit passes the main fuse budget to the loadpoint
+func (site *Site) updateFuseLimit(lp fuseLimiter) {
+	if !site.MainFuse.enabled() {
+		return
+	}
+
+	current, power := site.fuseBudget(lp)
+	lp.setFuseLimit(current, power, true)
+}
@@ -0,0 +1,104 @@
+package core
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/evcc-io/evcc/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMainFuseClamp(t *testing.T) {
+	Voltage = 100
+
+	fuse := MainFuse{Current: 25, Power: 6000}
+
+	lp1 := newLoadGroupLoadpoint(t, "lp1", 3, 32)
+	lp2 := newLoadGroupLoadpoint(t, "lp2", 1, 32)
+
+	site := &Site{
+		log:        util.NewLogger("foo"),
+		MainFuse:   fuse,
+		loadpoints: []*Loadpoint{lp1, lp2},
+	}
+
+	update := func(t *testing.T, lp *Loadpoint, requested float64, force bool) {
+		site.updateFuseLimit(lp)
+		require.NoError(t, lp.setLimit(requested, force))
+
+		var current, power float64
+		for _, lp := range site.loadpoints {
+			current += lp.commandedCurrent()
+			power += lp.commandedCurrent() * float64(lp.activePhases()) * Voltage
+		}
+
+		assert.LessOrEqual(t, current, fuse.Current, "current")
+		assert.LessOrEqual(t, power, fuse.Power, "power")
+	}
+
+	// any requested current, e.g. from now mode, plans, boost or manual settings, is clamped
+	for _, phases := range [][2]int{{3, 1}, {1, 3}, {3, 3}, {1, 1}} {
+		lp1.phases, lp2.phases = phases[0], phases[1]
+
+		for _, c1 := range []float64{0, 6, 16, 32, 63} {
+			for _, c2 := range []float64{0, 6, 16, 32, 63} {
+				for _, force := range []bool{false, true} {
+					t.Run(fmt.Sprintf("%v %.0fA %.0fA %t", phases, c1, c2, force), func(t *testing.T) {
+						update(t, lp1, c1, force)
+						update(t, lp2, c2, force)
+						update(t, lp1, c1, force)
+					})
+				}
+			}
+		}
+	}
+}
+
+func TestMainFuseBudget(t *testing.T) {
+	Voltage = 100
+
+	lp1 := newLoadGroupLoadpoint(t, "lp1", 3, 32)
+	lp2 := newLoadGroupLoadpoint(t, "lp2", 3, 32)
+
+	site := &Site{
+		log:        util.NewLogger("foo"),
+		MainFuse:   MainFuse{Current: 25},
+		loadpoints: []*Loadpoint{lp1, lp2},
+	}
+
+	// single loadpoint is clamped to the fuse
+	site.updateFuseLimit(lp1)
+	require.NoError(t, lp1.setLimit(32, false))
+	assert.Equal(t, 25.0, lp1.chargeCurrent)
+	assert.True(t, lp1.fuseClamped)
+
+	// second loadpoint gets the remainder, disabled below min current
+	site.updateFuseLimit(lp2)
+	require.NoError(t, lp2.setLimit(32, false))
+	assert.False(t, lp2.enabled)
+
+	// released budget becomes available
+	site.updateFuseLimit(lp1)
+	require.NoError(t, lp1.setLimit(16, false))
+	assert.False(t, lp1.fuseClamped)
+
+	site.updateFuseLimit(lp2)
+	require.NoError(t, lp2.setLimit(32, false))
+	assert.True(t, lp2.enabled)
+	assert.Equal(t, 9.0, lp2.chargeCurrent)
+
+	// power ceiling depends on the active phases
+	site.MainFuse = MainFuse{Power: 3000}
+	lp2.enabled = false
+	lp1.phases = 1
+	site.updateFuseLimit(lp1)
+	require.NoError(t, lp1.setLimit(32, false))
+	assert.Equal(t, 30.0, lp1.chargeCurrent)
+
+	// disabled fuse does not limit
+	site.MainFuse = MainFuse{}
+	lp1.setFuseLimit(0, 0, false)
+	require.NoError(t, lp1.setLimit(32, false))
+	assert.Equal(t, 32.0, lp1.chargeCurrent)
+}
@@ -0,0 +1,66 @@
+package core
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/util"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMeterErrorsSurface(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	// valid zero reading
+	pv1 := api.NewMockMeter(ctrl)
+	pv1.EXPECT().CurrentPower().Return(0.0, nil)
+
+	// failing meter
+	pv2 := api.NewMockMeter(ctrl)
+	pv2.EXPECT().CurrentPower().Return(0.0, errors.New("timeout")).Times(3)
+
+	battery := &struct {
+		*api.MockMeter
+		*api.MockBattery
+	}{
+		api.NewMockMeter(ctrl),
+		api.NewMockBattery(ctrl),
+	}
+	battery.MockMeter.EXPECT().CurrentPower().Return(0.0, nil)
+	battery.MockBattery.EXPECT().Soc().Return(0.0, errors.New("soc failed"))
+
+	grid := api.NewMockMeter(ctrl)
+	grid.EXPECT().CurrentPower().Return(0.0, errors.New("unreachable")).Times(3)
+
+	uiChan := make(chan util.Param, 32)
+
+	site := &Site{
+		log:           util.NewLogger("foo"),
+		uiChan:        uiChan,
+		pvMeters:      []api.Meter{pv1, pv2},
+		batteryMeters: []api.Meter{battery},
+		gridMeter:     grid,
+	}
+
+	require.Error(t, site.updateMeters())
+	close(uiChan)
+
+	res := make(map[string]any)
+	for p := range uiChan {
+		res[p.Key] = p.Val
+	}
+
+	pv := res["pv"].([]meterMeasurement)
+	assert.Equal(t, meterMeasurement{}, pv[0])
+	assert.Equal(t, 0.0, pv[1].Power)
+	assert.Contains(t, pv[1].Error, "timeout")
+
+	bat := res["battery"].([]batteryMeasurement)
+	assert.Contains(t, bat[0].Error, "soc failed")
+
+	assert.Contains(t, res["gridError"], "unreachable")
+	assert.NotContains(t, res, "gridPower")
+}
@@ -0,0 +1,110 @@
+package core
+
+import (
+	"errors"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/provider"
+)
+
+// NetMetering charges from accumulated export credit where exported energy is netted against imported energy.
+// The balance is either read directly or calculated from the total grid export and import energy.
+type NetMetering struct {
+	Enabled  bool             `mapstructure:"enabled"`
+	Balance  *provider.Config `mapstructure:"balance"`  // float getter of the credit balance (kWh), overrides import and export
+	Import   *provider.Config `mapstructure:"import"`   // float getter of the total import energy (kWh), defaults to the grid meter
+	Export   *provider.Config `mapstructure:"export"`   // float getter of the total export energy (kWh)
+	Offset   float64          `mapstructure:"offset"`   // added to the calculated balance, e.g. to start the billing period at zero (kWh)
+	MaxPower float64          `mapstructure:"maxPower"` // grid power offered for charging while the balance is positive (W)
+}
+
+// Validate validates the net metering configuration
+func (c NetMetering) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	if c.Balance == nil && c.Export == nil {
+		return errors.New("missing balance or export energy")
+	}
+	if c.MaxPower <= 0 {
+		return errors.New("maxPower must be positive")
+	}
+	return nil
+}
+
+// configureNetMetering creates the balance sources
+func (site *Site) configureNetMetering() error {
+	var err error
+
+	if c := site.NetMetering; c.Balance != nil {
+		site.netBalanceG, err = provider.NewFloatGetterFromConfig(*c.Balance)
+		return err
+	}
+
+	if c := site.NetMetering.Import; c != nil {
+		if site.netImportG, err = provider.NewFloatGetterFromConfig(*c); err != nil {
+			return err
+		}
+	}
+
+	site.netExportG, err = provider.NewFloatGetterFromConfig(*site.NetMetering.Export)
+	return err
+}
+
+// netMeteringBalance returns the current credit balance
+func (site *Site) netMeteringBalance() (float64, error) {
+	if site.netBalanceG != nil {
+		return site.netBalanceG()
+	}
+
+	importG := site.netImportG
+	if importG == nil {
+		m, ok := site.gridMeter.(api.MeterEnergy)
+		if !ok {
+			return 0, errors.New("grid meter does not provide import energy")
+		}
+		importG = m.TotalEnergy
+	}
+
+	imported, err := importG()
+	if err != nil {
+		return 0, err
+	}
+
+	exported, err := site.netExportG()
+	if err != nil {
+		return 0, err
+	}
+
+	return exported - imported + site.NetMetering.Offset, nil
+}
+
+// updateNetMetering updates the credit balance. The last balance is kept if the sources cannot be read.
+func (site *Site) updateNetMetering() {
+	if !site.NetMetering.Enabled {
+		return
+	}
+
+	balance, err := site.netMeteringBalance()
+	if err != nil {
+		site.log.ERROR.Printf("net metering: %v", err)
+		return
+	}
+
+	site.netBalance = balance
+	site.publish("netMeteringBalance", balance)
+	site.publish("netMeteringActive", site.netMeteringActive())
+}
+
+// netMeteringActive returns true if export credit is available for charging
+func (site *Site) netMeteringActive() bool {
+	return site.NetMetering.Enabled && site.netBalance > 0
+}
+
+// netMeteringSitePower offers grid power for charging while export credit is available
+func (site *Site) netMeteringSitePower(sitePower float64) float64 {
+	if !site.netMeteringActive() {
+		return sitePower
+	}
+	return sitePower - site.NetMetering.MaxPower
+}
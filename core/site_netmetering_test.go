@@ -0,0 +1,77 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/evcc-io/evcc/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNetMetering(t *testing.T) {
+	const maxPower = 3600.0 // W
+
+	var imported, exported float64 // kWh
+
+	site := &Site{
+		log: util.NewLogger("foo"),
+		NetMetering: NetMetering{
+			Enabled:  true,
+			Offset:   -1, // kWh
+			MaxPower: maxPower,
+		},
+		netImportG: func() (float64, error) { return imported, nil },
+		netExportG: func() (float64, error) { return exported, nil },
+	}
+
+	// no credit
+	site.updateNetMetering()
+	assert.Equal(t, -1.0, site.netBalance)
+	assert.Equal(t, 0.0, site.netMeteringSitePower(0))
+
+	// export accumulates credit
+	exported = 3
+	site.updateNetMetering()
+	assert.Equal(t, 2.0, site.netBalance)
+
+	// charging consumes credit in 15 minute steps
+	var charged float64
+	for i := 0; i < 8; i++ {
+		site.updateNetMetering()
+
+		// loadpoint charges the offered grid power
+		if power := -site.netMeteringSitePower(0); power > 0 {
+			assert.Equal(t, maxPower, power)
+			imported += power / 1e3 / 4
+			charged += power / 1e3 / 4
+		}
+	}
+
+	assert.InDelta(t, 2.7, charged, 1e-9)
+	assert.False(t, site.netMeteringActive())
+	assert.InDelta(t, -0.7, site.netBalance, 1e-9)
+
+	// further export restores credit
+	exported += 1
+	site.updateNetMetering()
+	assert.True(t, site.netMeteringActive())
+}
+
+func TestNetMeteringBalance(t *testing.T) {
+	site := &Site{
+		log: util.NewLogger("foo"),
+		NetMetering: NetMetering{
+			Enabled:  true,
+			Offset:   10, // ignored
+			MaxPower: 1000,
+		},
+		netBalanceG: func() (float64, error) { return 5, nil },
+	}
+
+	site.updateNetMetering()
+	assert.Equal(t, 5.0, site.netBalance)
+	assert.Equal(t, -1000.0, site.netMeteringSitePower(0))
+
+	// disabled
+	site.NetMetering.Enabled = false
+	assert.Equal(t, 0.0, site.netMeteringSitePower(0))
+}
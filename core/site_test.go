@@ -107,3 +107,65 @@ func TestGreenShare(t *testing.T) {
 		}
 	}
 }
+
+func TestPhaseCurrentLimit(t *testing.T) {
+	tc := []struct {
+		title   string
+		caps    []float64
+		grid    []float64
+		own     [3]float64
+		limit   float64
+		limited bool
+	}{
+		{"no caps", []float64{0, 0, 0}, []float64{30, 30, 30}, [3]float64{16, 16, 16}, 0, false},
+		{"plenty of headroom", []float64{32, 32, 32}, []float64{20, 18, 18}, [3]float64{16, 16, 16}, 28, true},
+		{"one phase near cap", []float64{32, 32, 32}, []float64{31, 18, 18}, [3]float64{16, 16, 16}, 17, true},
+		{"loaded phase not charging", []float64{25, 32, 32}, []float64{22, 16, 16}, [3]float64{0, 16, 16}, 3, true},
+		{"overloaded", []float64{25, 32, 32}, []float64{30, 0, 0}, [3]float64{0, 0, 0}, 0, true},
+		{"export adds headroom", []float64{32, 32, 32}, []float64{-5, -5, -5}, [3]float64{0, 0, 0}, 37, true},
+	}
+
+	for _, tc := range tc {
+		t.Log(tc.title)
+
+		site := &Site{
+			log:              util.NewLogger("foo"),
+			MaxPhaseCurrents: tc.caps,
+			gridCurrents:     tc.grid,
+		}
+
+		limit, limited := site.phaseCurrentLimit(tc.own)
+		if limited != tc.limited || limit != tc.limit {
+			t.Errorf("%s: wanted %.1fA (%v), got %.1fA (%v)", tc.title, tc.limit, tc.limited, limit, limited)
+		}
+	}
+}
+
+func TestGreenPower(t *testing.T) {
+	tc := []struct {
+		title             string
+		pv, battery, home float64
+		expPv, expBattery float64
+	}{
+		{"pv only", 5000, 0, 1000, 4000, 0},
+		{"pv below home", 500, 0, 1000, 0, 0},
+		{"pv and battery", 5000, 2000, 1000, 4000, 2000},
+		{"battery covers home", 500, 2000, 1000, 0, 1500},
+		{"battery charging", 5000, -2000, 1000, 4000, 0},
+		{"negative pv", -10, 0, 1000, 0, 0},
+	}
+
+	for _, tc := range tc {
+		t.Log(tc.title)
+
+		s := &Site{
+			pvPower:      tc.pv,
+			batteryPower: tc.battery,
+		}
+
+		pv, battery := s.greenPower(tc.home)
+		if pv != tc.expPv || battery != tc.expBattery {
+			t.Errorf("%s: wanted %.0f/%.0f, got %.0f/%.0f", tc.title, tc.expPv, tc.expBattery, pv, battery)
+		}
+	}
+}
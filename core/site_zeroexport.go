@@ -0,0 +1,105 @@
+package core
+
+import (
+	"errors"
+	"time"
+
+	"github.com/evcc-io/evcc/provider"
+)
+
+// ZeroExport keeps grid export near zero where feed-in is not permitted.
+// Surplus is consumed by loadpoints and battery first, remaining surplus is curtailed.
+type ZeroExport struct {
+	Enabled   bool             `mapstructure:"enabled"`
+	Tolerance float64          `mapstructure:"tolerance"` // export power considered zero
+	Delay     time.Duration    `mapstructure:"delay"`     // report export that could not be absorbed after this duration
+	Curtail   *provider.Config `mapstructure:"curtail"`   // float setter receiving the pv power to curtail, 0 to release
+}
+
+// Validate validates the zero export configuration
+func (c ZeroExport) Validate() error {
+	if c.Tolerance < 0 {
+		return errors.New("tolerance must not be negative")
+	}
+	if c.Delay < 0 {
+		return errors.New("delay must not be negative")
+	}
+	return nil
+}
+
+// zeroExportSitePower adds the curtailed pv power to the site power available for charging
+func (site *Site) zeroExportSitePower(sitePower float64) float64 {
+	if !site.ZeroExport.Enabled {
+		return sitePower
+	}
+	return sitePower - site.curtailPower
+}
+
+// zeroExporting returns true if the site exports more than the permitted tolerance
+func (site *Site) zeroExporting() bool {
+	return site.ZeroExport.Enabled && site.exportPower > site.ZeroExport.Tolerance
+}
+
+// updateZeroExport curtails pv surplus that is not consumed and reports export that could not be absorbed
+func (site *Site) updateZeroExport() {
+	if !site.ZeroExport.Enabled {
+		return
+	}
+
+	tolerance := site.ZeroExport.Tolerance
+	site.exportPower = max(0, -site.gridPower)
+	excess := max(0, site.exportPower-tolerance)
+
+	if site.curtailS != nil {
+		curtail := site.curtailPower
+		if excess > 0 {
+			curtail += excess
+		} else if site.gridPower > 0 {
+			// release curtailment once consumption exceeds generation
+			curtail = max(0, curtail-site.gridPower-tolerance)
+		}
+
+		if curtail != site.curtailPower {
+			if err := site.curtailS(curtail); err != nil {
+				site.log.ERROR.Printf("zero export: curtail: %v", err)
+			} else {
+				site.log.DEBUG.Printf("zero export: curtail %.0fW", curtail)
+				site.curtailPower = curtail
+			}
+		}
+	}
+
+	site.publish("zeroExportCurtailment", site.curtailPower)
+	site.publish("zeroExportPower", excess)
+
+	if excess == 0 {
+		site.exportTimer = time.Time{}
+		site.setExportExceeded(false)
+		return
+	}
+
+	now := site.zeroExportClock.Now()
+	if site.exportTimer.IsZero() {
+		site.exportTimer = now
+	}
+
+	if now.Sub(site.exportTimer) >= site.ZeroExport.Delay {
+		site.setExportExceeded(true)
+	}
+}
+
+// setExportExceeded updates and publishes the export exceeded state
+func (site *Site) setExportExceeded(exceeded bool) {
+	if site.exportExceeded == exceeded {
+		return
+	}
+
+	if exceeded {
+		site.log.WARN.Printf("zero export: cannot absorb %.0fW export", site.exportPower)
+	} else {
+		site.log.INFO.Println("zero export: export absorbed")
+	}
+
+	site.exportExceeded = exceeded
+	site.publish("zeroExportExceeded", exceeded)
+}
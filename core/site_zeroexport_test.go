@@ -0,0 +1,79 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestZeroExport(t *testing.T) {
+	const (
+		potential = 5000.0 // W
+		home      = 1000.0 // W
+		tolerance = 50.0   // W
+	)
+
+	tc := []struct {
+		name      string
+		curtail   bool
+		consumer  float64 // max consumer power
+		curtailed float64 // expected curtailment
+		exceeded  bool
+	}{
+		{"curtail surplus", true, 0, potential - home - tolerance, false},
+		{"consume before curtailing", true, 3000, potential - home - 3000 - tolerance, false},
+		{"consume all surplus", true, 6000, 0, false},
+		{"cannot curtail", false, 0, 0, true},
+	}
+
+	for _, tc := range tc {
+		t.Run(tc.name, func(t *testing.T) {
+			clock := clock.NewMock()
+
+			site := &Site{
+				log: util.NewLogger("foo"),
+				ZeroExport: ZeroExport{
+					Enabled:   true,
+					Tolerance: tolerance,
+					Delay:     time.Minute,
+				},
+				zeroExportClock: clock,
+			}
+
+			var curtailed float64
+			if tc.curtail {
+				site.curtailS = func(power float64) error {
+					curtailed = power
+					return nil
+				}
+			}
+
+			var consumer float64
+			for i := 0; i < 10; i++ {
+				site.pvPower = max(0, potential-curtailed)
+				site.gridPower = home + consumer - site.pvPower
+
+				// consumer follows available surplus including curtailed pv
+				consumer = min(tc.consumer, max(0, consumer-site.zeroExportSitePower(site.gridPower)-tolerance))
+				site.updateZeroExport()
+				clock.Add(30 * time.Second)
+			}
+
+			site.pvPower = max(0, potential-curtailed)
+			site.gridPower = home + consumer - site.pvPower
+
+			assert.Equal(t, tc.curtailed, curtailed)
+			assert.Equal(t, tc.exceeded, site.exportExceeded)
+
+			if tc.curtail {
+				assert.LessOrEqual(t, -site.gridPower, tolerance)
+				assert.False(t, site.zeroExporting())
+			} else {
+				assert.True(t, site.zeroExporting())
+			}
+		})
+	}
+}
@@ -8,15 +8,19 @@ import (
 	"github.com/evcc-io/evcc/util"
 )
 
-const ChargeEfficiency = 0.9 // assume charge 90% efficiency
+const ChargeEfficiency = 0.9 // assume charge 90% efficiency unless configured
 
 // Estimator provides vehicle soc and charge duration
 // Vehicle Soc can be estimated to provide more granularity
 type Estimator struct {
-	log      *util.Logger
-	charger  api.Charger
-	vehicle  api.Vehicle
-	estimate bool
+	log        *util.Logger
+	charger    api.Charger
+	vehicle    api.Vehicle
+	estimate   bool
+	efficiency float64   // charge efficiency between grid and battery
+	smoother   *Smoother // optional smoothing of jittery vehicle soc
+	validSoc   float64   // last valid fetched soc
+	stale      bool      // soc recovered from api errors
 
 	capacity          float64 // vehicle capacity in Wh cached to simplify testing
 	virtualCapacity   float64 // estimated virtual vehicle capacity in Wh
@@ -31,13 +35,24 @@ type Estimator struct {
 	maxChargeSoc      float64 // SoC at/after which maxChargePower is degressive
 }
 
-// NewEstimator creates new estimator
-func NewEstimator(log *util.Logger, charger api.Charger, vehicle api.Vehicle, estimate bool) *Estimator {
+// NewEstimator creates new estimator. Efficiency outside (0,1] defaults to ChargeEfficiency.
+func NewEstimator(log *util.Logger, charger api.Charger, vehicle api.Vehicle, estimate bool, efficiency float64) *Estimator {
+	if efficiency <= 0 || efficiency > 1 {
+		efficiency = ChargeEfficiency
+	}
+
 	s := &Estimator{
-		log:      log,
-		charger:  charger,
-		vehicle:  vehicle,
-		estimate: estimate,
+		log:        log,
+		charger:    charger,
+		vehicle:    vehicle,
+		estimate:   estimate,
+		efficiency: efficiency,
+	}
+
+	if vs, ok := vehicle.(api.SocSmoother); ok {
+		if alpha := vs.SocSmoothing(); alpha > 0 && alpha < 1 {
+			s.smoother = NewSmoother(alpha)
+		}
 	}
 
 	s.Reset()
@@ -45,17 +60,28 @@ func NewEstimator(log *util.Logger, charger api.Charger, vehicle api.Vehicle, es
 	return s
 }
 
+// Stale returns true if the soc is the last valid soc recovered from api errors
+func (s *Estimator) Stale() bool {
+	return s.stale
+}
+
 // Reset resets the estimation process to default values
 func (s *Estimator) Reset() {
 	s.prevSoc = 0
+	s.validSoc = 0
+	s.stale = false
 	s.prevChargedEnergy = 0
 	s.initialSoc = 0
-	s.capacity = float64(s.vehicle.Capacity()) * 1e3  // cache to simplify debugging
-	s.virtualCapacity = s.capacity / ChargeEfficiency // initial capacity taking efficiency into account
+	s.capacity = float64(s.vehicle.Capacity()) * 1e3 // cache to simplify debugging
+	s.virtualCapacity = s.capacity / s.efficiency    // initial capacity taking efficiency into account
 	s.energyPerSocStep = s.virtualCapacity / 100
 	s.minChargePower = 1000  // default 1 kW
 	s.maxChargePower = 50000 // default 50 kW
 	s.maxChargeSoc = 50      // default 50%
+
+	if s.smoother != nil {
+		s.smoother.Reset()
+	}
 }
 
 // RemainingChargeDuration returns the estimated remaining duration
@@ -102,6 +128,18 @@ func (s *Estimator) RemainingChargeEnergy(targetSoc int) float64 {
 	return whRemaining / 1e3
 }
 
+// recover returns the last valid soc on api errors
+func (s *Estimator) recover(err error) (float64, error) {
+	// never received a soc value
+	if s.validSoc == 0 {
+		return 0, err
+	}
+
+	s.stale = true
+
+	return s.validSoc, nil
+}
+
 // Soc replaces the api.Vehicle.Soc interface to take charged energy into account
 func (s *Estimator) Soc(chargedEnergy float64) (float64, error) {
 	var fetchedSoc *float64
@@ -112,14 +150,16 @@ func (s *Estimator) Soc(chargedEnergy float64) (float64, error) {
 		// if the charger does or could provide Soc, we always use it instead of using the vehicle API
 		if err == nil || !errors.Is(err, api.ErrNotAvailable) {
 			if err != nil {
-				// never received a soc value
-				if s.prevSoc == 0 {
-					return 0, err
+				// recover from temporary api errors
+				var rerr error
+				if f, rerr = s.recover(err); rerr != nil {
+					return 0, rerr
 				}
 
-				// recover from temporary api errors
-				f = s.prevSoc
 				s.log.WARN.Printf("vehicle soc (charger): %v (ignored by estimator)", err)
+			} else {
+				s.validSoc = f
+				s.stale = false
 			}
 
 			fetchedSoc = &f
@@ -135,14 +175,22 @@ func (s *Estimator) Soc(chargedEnergy float64) (float64, error) {
 				return 0, err
 			}
 
-			// never received a soc value
-			if s.prevSoc == 0 {
-				return 0, err
+			// recover from temporary api errors
+			var rerr error
+			if f, rerr = s.recover(err); rerr != nil {
+				return 0, rerr
 			}
 
-			// recover from temporary api errors
-			f = s.prevSoc
 			s.log.WARN.Printf("vehicle soc: %v (ignored by estimator)", err)
+		} else {
+			if s.smoother != nil {
+				raw := f
+				f = s.smoother.Add(raw)
+				s.log.DEBUG.Printf("soc smoothed: %.2f%% (vehicle: %.2f%%)", f, raw)
+			}
+
+			s.validSoc = f
+			s.stale = false
 		}
 
 		fetchedSoc = &f
@@ -18,7 +18,7 @@ func TestRemainingChargeDuration(t *testing.T) {
 	// 9 kWh userBatCap => 10 kWh virtualBatCap
 	vehicle.EXPECT().Capacity().Return(float64(9))
 
-	ce := NewEstimator(util.NewLogger("foo"), charger, vehicle, false)
+	ce := NewEstimator(util.NewLogger("foo"), charger, vehicle, false, ChargeEfficiency)
 	ce.vehicleSoc = 20.0
 
 	chargePower := 1000.0
@@ -43,7 +43,7 @@ func TestSocEstimation(t *testing.T) {
 	var capacity float64 = 9
 	vehicle.EXPECT().Capacity().Return(capacity)
 
-	ce := NewEstimator(util.NewLogger("foo"), charger, vehicle, true)
+	ce := NewEstimator(util.NewLogger("foo"), charger, vehicle, true, ChargeEfficiency)
 	ce.vehicleSoc = 0.0
 
 	tc := []struct {
@@ -127,7 +127,7 @@ func TestSocFromChargerAndVehicleWithErrors(t *testing.T) {
 	var capacity float64 = 9
 	vehicle.EXPECT().Capacity().Return(capacity)
 
-	ce := NewEstimator(util.NewLogger("foo"), charger, vehicle, true)
+	ce := NewEstimator(util.NewLogger("foo"), charger, vehicle, true, ChargeEfficiency)
 	ce.vehicleSoc = 20.0
 
 	tc := []struct {
@@ -236,9 +236,97 @@ func TestImprovedEstimatorRemainingChargeDuration(t *testing.T) {
 
 		vehicle.EXPECT().Capacity().Return(tc.capacity)
 
-		ce := NewEstimator(util.NewLogger("foo"), charger, vehicle, false)
+		ce := NewEstimator(util.NewLogger("foo"), charger, vehicle, false, ChargeEfficiency)
 		ce.vehicleSoc = tc.soc
 
 		assert.Equal(t, tc.duration, ce.RemainingChargeDuration(tc.targetsoc, tc.chargePower))
 	}
 }
+
+type smoothingVehicle struct {
+	*api.MockVehicle
+	alpha float64
+}
+
+func (v *smoothingVehicle) SocSmoothing() float64 {
+	return v.alpha
+}
+
+func TestSocSmoothing(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	charger := api.NewMockCharger(ctrl)
+	vehicle := &smoothingVehicle{api.NewMockVehicle(ctrl), 0.3}
+	vehicle.MockVehicle.EXPECT().Capacity().Return(float64(50)).AnyTimes()
+
+	ce := NewEstimator(util.NewLogger("foo"), charger, vehicle, false, ChargeEfficiency)
+
+	const targetSoc = 80
+
+	// charging towards target, then jittering cloud readings above target
+	raw := []float64{70, 72, 74, 76, 78, 80, 82, 80, 83, 79, 82, 81, 83, 79, 82, 80, 81, 83, 79, 82}
+
+	var reached, rawFlapped bool
+
+	for _, f := range raw {
+		vehicle.MockVehicle.EXPECT().Soc().Return(f, nil)
+
+		soc, err := ce.Soc(0)
+		assert.NoError(t, err)
+
+		// charging progress is not delayed excessively
+		assert.LessOrEqual(t, f-soc, maxSmoothingLag, "raw %.1f smoothed %.1f", f, soc)
+
+		if reached {
+			assert.GreaterOrEqual(t, soc, float64(targetSoc), "target flapped at raw %.1f", f)
+			rawFlapped = rawFlapped || f < targetSoc
+		}
+
+		reached = reached || soc >= targetSoc
+	}
+
+	assert.True(t, reached, "target not reached")
+	assert.True(t, rawFlapped, "raw soc should flap around target")
+
+	// smoothing is opt-in
+	vehicle = &smoothingVehicle{api.NewMockVehicle(ctrl), 0}
+	vehicle.MockVehicle.EXPECT().Capacity().Return(float64(50)).AnyTimes()
+
+	ce = NewEstimator(util.NewLogger("foo"), charger, vehicle, false, ChargeEfficiency)
+
+	for _, f := range []float64{82, 79} {
+		vehicle.MockVehicle.EXPECT().Soc().Return(f, nil)
+
+		soc, err := ce.Soc(0)
+		assert.NoError(t, err)
+		assert.Equal(t, f, soc)
+	}
+}
+
+func TestChargeEfficiency(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	charger := api.NewMockCharger(ctrl)
+	vehicle := api.NewMockVehicle(ctrl)
+	// 8 kWh userBatCap at 80% efficiency => 10 kWh virtualBatCap
+	vehicle.EXPECT().Capacity().Return(float64(8)).AnyTimes()
+
+	ce := NewEstimator(util.NewLogger("foo"), charger, vehicle, true, 0.8)
+	assert.Equal(t, 10000.0, ce.virtualCapacity)
+
+	vehicle.EXPECT().Soc().Return(20.0, nil).Times(2)
+
+	soc, err := ce.Soc(0)
+	assert.NoError(t, err)
+	assert.Equal(t, 20.0, soc)
+
+	// 1 kWh from the grid charges 0.8 kWh into the battery
+	soc, err = ce.Soc(1000)
+	assert.NoError(t, err)
+	assert.Equal(t, 30.0, soc)
+
+	// remaining 70% require 7 kWh grid energy
+	assert.Equal(t, 7.0, ce.RemainingChargeEnergy(100))
+
+	// invalid efficiency uses default
+	ce = NewEstimator(util.NewLogger("foo"), charger, vehicle, true, 0)
+	assert.InDelta(t, 8000/ChargeEfficiency, ce.virtualCapacity, 1e-6)
+}
@@ -0,0 +1,38 @@
+package soc
+
+import "math"
+
+// maxSmoothingLag limits the deviation of the smoothed from the raw soc in %
+// to not delay real charging progress excessively
+const maxSmoothingLag = 2.0
+
+// Smoother applies exponential smoothing to jittery soc readings
+type Smoother struct {
+	alpha float64
+	value float64
+	valid bool
+}
+
+// NewSmoother creates a soc smoother. Alpha is the weight of each new reading.
+func NewSmoother(alpha float64) *Smoother {
+	return &Smoother{alpha: alpha}
+}
+
+// Reset discards the smoothing history
+func (s *Smoother) Reset() {
+	s.valid = false
+}
+
+// Add adds a raw soc reading and returns the smoothed soc
+func (s *Smoother) Add(soc float64) float64 {
+	if !s.valid {
+		s.value = soc
+		s.valid = true
+		return s.value
+	}
+
+	s.value = s.alpha*soc + (1-s.alpha)*s.value
+	s.value = math.Max(soc-maxSmoothingLag, math.Min(soc+maxSmoothingLag, s.value))
+
+	return s.value
+}
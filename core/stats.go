@@ -1,12 +1,16 @@
 package core
 
 import (
+	"sync"
 	"time"
 
 	"github.com/evcc-io/evcc/server/db"
+	"github.com/evcc-io/evcc/server/db/settings"
 	"github.com/evcc-io/evcc/util"
 )
 
+const statisticsResetKey = "site.statisticsReset"
+
 // publisher gives access to the site's publish function
 type publisher interface {
 	publish(key string, val interface{})
@@ -14,18 +18,41 @@ type publisher interface {
 
 // Publishes long term charging statistics
 type Stats struct {
+	mu      sync.Mutex
 	updated time.Time // Time of last charged value update
+	reset   time.Time // Sessions before reset are excluded
 	log     *util.Logger
 }
 
 func NewStats() *Stats {
-	return &Stats{
+	s := &Stats{
 		log: util.NewLogger("stats"),
 	}
+
+	if t, err := settings.Time(statisticsResetKey); err == nil {
+		s.reset = t
+	}
+
+	return s
+}
+
+// Reset excludes all sessions finished until now from the statistics
+func (s *Stats) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.reset = time.Now().Truncate(time.Second) // persisted with second resolution
+	settings.SetTime(statisticsResetKey, s.reset)
+
+	// force re-calculation
+	s.updated = time.Time{}
 }
 
 // Update publishes stats based on charging sessions
 func (s *Stats) Update(p publisher) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
 	if time.Since(s.updated) < time.Hour {
 		return
 	}
@@ -46,6 +73,9 @@ func (s *Stats) calculate(days int) map[string]float64 {
 
 	// Calculate the date from numberOfDays ago
 	fromDate := time.Now().AddDate(0, 0, -days)
+	if fromDate.Before(s.reset) {
+		fromDate = s.reset
+	}
 
 	// Struct to hold the results
 	var dbResult struct {
@@ -0,0 +1,26 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evcc-io/evcc/server/db/settings"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatsReset(t *testing.T) {
+	s := NewStats()
+	s.updated = time.Now()
+
+	s.Reset()
+	assert.True(t, s.updated.IsZero(), "reset must force re-calculation")
+
+	// reset is persisted
+	ts, err := settings.Time(statisticsResetKey)
+	require.NoError(t, err)
+	assert.True(t, s.reset.Equal(ts))
+
+	// and restored
+	assert.True(t, ts.Equal(NewStats().reset))
+}
@@ -0,0 +1,194 @@
+package meter
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/provider"
+	"github.com/evcc-io/evcc/util"
+	"github.com/evcc-io/evcc/util/config"
+)
+
+func init() {
+	registry.Add("virtual", NewVirtualFromConfig)
+}
+
+// virtualOperand is a weighted reference to another configured meter
+type virtualOperand struct {
+	Meter   string   // meter reference
+	Scale   *float64 // weight, default 1, -1 to subtract
+	Default *float64 // power used if the meter cannot be read, error if nil
+}
+
+// maxVirtualDepth limits nested reads of a virtual meter, exceeding it indicates a meter referencing itself
+const maxVirtualDepth = 16
+
+// errVirtualCycle is returned if a virtual meter directly or indirectly references itself
+var errVirtualCycle = errors.New("virtual meter references itself")
+
+// Virtual meter computes power and energy from other configured meters
+type Virtual struct {
+	log       *util.Logger
+	operands  []virtualOperand
+	transform *provider.TransformConfig
+	depth     atomic.Int32 // nested reads
+}
+
+// NewVirtualFromConfig creates api.Meter from config
+func NewVirtualFromConfig(other map[string]interface{}) (api.Meter, error) {
+	var cc struct {
+		Operands  []virtualOperand
+		Transform *provider.TransformConfig // optional
+		Energy    bool                      // sum operand energies
+	}
+
+	if err := util.DecodeOther(other, &cc); err != nil {
+		return nil, err
+	}
+
+	if len(cc.Operands) == 0 {
+		return nil, errors.New("missing operands")
+	}
+
+	for i, op := range cc.Operands {
+		if op.Meter == "" {
+			return nil, fmt.Errorf("operands[%d]: missing meter", i)
+		}
+	}
+
+	if cc.Transform != nil {
+		if err := cc.Transform.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	v := &Virtual{
+		log:       util.NewLogger("virtual"),
+		operands:  cc.Operands,
+		transform: cc.Transform,
+	}
+
+	m, _ := NewConfigurable(v.currentPower)
+
+	var totalEnergy func() (float64, error)
+	if cc.Energy {
+		totalEnergy = v.totalEnergy
+	}
+
+	return m.Decorate(totalEnergy, nil, nil, nil, nil, nil), nil
+}
+
+// meter resolves the operand's meter. Meters are resolved on each read to not depend on configuration order.
+func (op *virtualOperand) meter() (api.Meter, error) {
+	dev, err := config.Meters().ByName(op.Meter)
+	if err != nil {
+		return nil, err
+	}
+	return dev.Instance(), nil
+}
+
+// scale returns the operand's weight
+func (op *virtualOperand) scale() float64 {
+	if op.Scale == nil {
+		return 1
+	}
+	return *op.Scale
+}
+
+// power returns the operand's weighted power, falling back to the default if configured
+func (v *Virtual) power(op *virtualOperand) (float64, error) {
+	m, err := op.meter()
+
+	var f float64
+	if err == nil {
+		f, err = m.CurrentPower()
+	}
+
+	if errors.Is(err, errVirtualCycle) {
+		return 0, err
+	}
+
+	if err != nil {
+		if op.Default == nil {
+			return 0, fmt.Errorf("%s: %w", op.Meter, err)
+		}
+
+		v.log.DEBUG.Printf("%s: %v, using default %.0fW", op.Meter, err, *op.Default)
+		f = *op.Default
+	}
+
+	return op.scale() * f, nil
+}
+
+// enter guards against unbounded recursion of virtual meters referencing each other.
+// The returned function must be called when the read has completed.
+func (v *Virtual) enter() (func(), error) {
+	leave := func() { v.depth.Add(-1) }
+	if v.depth.Add(1) > maxVirtualDepth {
+		leave()
+		return nil, errVirtualCycle
+	}
+	return leave, nil
+}
+
+func (v *Virtual) currentPower() (float64, error) {
+	leave, err := v.enter()
+	if err != nil {
+		return 0, err
+	}
+	defer leave()
+
+	var res float64
+
+	for i := range v.operands {
+		f, err := v.power(&v.operands[i])
+		if err != nil {
+			return 0, err
+		}
+		res += f
+	}
+
+	if v.transform != nil {
+		res = v.transform.Apply(res)
+	}
+
+	return res, nil
+}
+
+func (v *Virtual) totalEnergy() (float64, error) {
+	leave, err := v.enter()
+	if err != nil {
+		return 0, err
+	}
+	defer leave()
+
+	var res float64
+
+	for i := range v.operands {
+		op := &v.operands[i]
+
+		m, err := op.meter()
+		if err != nil {
+			return 0, fmt.Errorf("%s: %w", op.Meter, err)
+		}
+
+		me, ok := m.(api.MeterEnergy)
+		if !ok {
+			return 0, fmt.Errorf("%s: %w", op.Meter, api.ErrNotAvailable)
+		}
+
+		f, err := me.TotalEnergy()
+		if errors.Is(err, errVirtualCycle) {
+			return 0, err
+		}
+		if err != nil {
+			return 0, fmt.Errorf("%s: %w", op.Meter, err)
+		}
+
+		res += op.scale() * f
+	}
+
+	return res, nil
+}
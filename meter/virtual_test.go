@@ -0,0 +1,156 @@
+package meter
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/util/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func addVirtualTestMeter(t *testing.T, name string, power, energy float64, err error) {
+	m, _ := NewConfigurable(func() (float64, error) {
+		return power, err
+	})
+
+	dev := config.NewStaticDevice(config.Named{Name: name}, m.Decorate(func() (float64, error) {
+		return energy, err
+	}, nil, nil, nil, nil, nil))
+
+	require.NoError(t, config.Meters().Add(dev))
+	t.Cleanup(func() {
+		_ = config.Meters().Delete(name)
+	})
+}
+
+func TestVirtualMeter(t *testing.T) {
+	addVirtualTestMeter(t, "inverter", 5000, 100, nil)
+	addVirtualTestMeter(t, "loads", 1500, 30, nil)
+	addVirtualTestMeter(t, "broken", 0, 0, errors.New("broken"))
+
+	tc := []struct {
+		config        map[string]interface{}
+		power, energy float64
+		err           bool
+	}{
+		{
+			// grid = inverter - loads
+			map[string]interface{}{
+				"operands": []map[string]interface{}{
+					{"meter": "inverter"},
+					{"meter": "loads", "scale": -1},
+				},
+				"energy": true,
+			},
+			3500, 70, false,
+		},
+		{
+			// transformed result
+			map[string]interface{}{
+				"operands": []map[string]interface{}{
+					{"meter": "inverter"},
+					{"meter": "loads", "scale": -1},
+				},
+				"transform": map[string]interface{}{"invert": true},
+			},
+			-3500, 0, false,
+		},
+		{
+			// failed operand
+			map[string]interface{}{
+				"operands": []map[string]interface{}{
+					{"meter": "inverter"},
+					{"meter": "broken"},
+				},
+			},
+			0, 0, true,
+		},
+		{
+			// failed operand with default
+			map[string]interface{}{
+				"operands": []map[string]interface{}{
+					{"meter": "inverter"},
+					{"meter": "broken", "scale": -1, "default": 200},
+				},
+			},
+			4800, 0, false,
+		},
+		{
+			// unknown meter
+			map[string]interface{}{
+				"operands": []map[string]interface{}{
+					{"meter": "foo"},
+				},
+			},
+			0, 0, true,
+		},
+	}
+
+	for _, tc := range tc {
+		m, err := NewVirtualFromConfig(tc.config)
+		require.NoError(t, err)
+
+		power, err := m.CurrentPower()
+		if tc.err {
+			assert.Error(t, err, tc.config)
+			continue
+		}
+
+		require.NoError(t, err, tc.config)
+		assert.Equal(t, tc.power, power, tc.config)
+
+		me, ok := m.(api.MeterEnergy)
+		assert.Equal(t, tc.energy != 0, ok, tc.config)
+
+		if ok {
+			energy, err := me.TotalEnergy()
+			require.NoError(t, err)
+			assert.Equal(t, tc.energy, energy)
+		}
+	}
+}
+
+func TestVirtualMeterConfig(t *testing.T) {
+	_, err := NewVirtualFromConfig(map[string]interface{}{})
+	assert.Error(t, err)
+
+	_, err = NewVirtualFromConfig(map[string]interface{}{
+		"operands": []map[string]interface{}{{"scale": 2}},
+	})
+	assert.Error(t, err)
+}
+
+func TestVirtualMeterCycle(t *testing.T) {
+	add := func(name, operand string) {
+		m, err := NewVirtualFromConfig(map[string]interface{}{
+			"operands": []map[string]interface{}{
+				{"meter": operand, "default": 0},
+			},
+			"energy": true,
+		})
+		require.NoError(t, err)
+
+		require.NoError(t, config.Meters().Add(config.NewStaticDevice(config.Named{Name: name}, m)))
+		t.Cleanup(func() {
+			_ = config.Meters().Delete(name)
+		})
+	}
+
+	// self reference and cycle of virtual meters
+	add("self", "self")
+	add("a", "b")
+	add("b", "a")
+
+	for _, name := range []string{"self", "a"} {
+		dev, err := config.Meters().ByName(name)
+		require.NoError(t, err)
+
+		_, err = dev.Instance().CurrentPower()
+		assert.ErrorIs(t, err, errVirtualCycle, name)
+
+		_, err = dev.Instance().(api.MeterEnergy).TotalEnergy()
+		assert.ErrorIs(t, err, errVirtualCycle, name)
+	}
+}
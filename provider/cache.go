@@ -2,28 +2,73 @@ package provider
 
 import (
 	"errors"
+	"fmt"
 	"math"
+	"math/rand"
 	"sync"
+	"sync/atomic"
 	"time"
 
-	"github.com/asaskevich/EventBus"
 	"github.com/benbjohnson/clock"
 	"github.com/evcc-io/evcc/api"
 	"github.com/evcc-io/evcc/util"
 )
 
+var log = util.NewLogger("cache")
+
+const backoffDuration = 5 * time.Second
+
+func ResetCached() {
+	for _, c := range registeredCaches() {
+		c.Reset()
+	}
+}
+
+// ResetCachedBy resets the caches created by code of the given package or its sub-packages,
+// e.g. the caches of all devices of an implementation package
+func ResetCachedBy(pkg string) {
+	for _, c := range registeredCaches() {
+		if c.createdBy(pkg) {
+			c.Reset()
+		}
+	}
+}
+
 var (
-	bus = EventBus.New()
-	log = util.NewLogger("cache")
+	jitterMux      sync.Mutex
+	jitterFraction float64
+	jitterRand     = rand.New(rand.NewSource(time.Now().UnixNano()))
 )
 
-const (
-	reset           = "reset"
-	backoffDuration = 5 * time.Second
-)
+// SetJitter extends the cache duration of each subsequently created cache by a random offset
+// of up to the given fraction to spread requests of many instances polling the same api.
+// A non-zero seed makes the offsets reproducible.
+func SetJitter(fraction float64, seed int64) error {
+	if fraction < 0 || fraction > 1 {
+		return fmt.Errorf("invalid jitter: %v", fraction)
+	}
 
-func ResetCached() {
-	bus.Publish(reset)
+	jitterMux.Lock()
+	defer jitterMux.Unlock()
+
+	jitterFraction = fraction
+	if seed != 0 {
+		jitterRand = rand.New(rand.NewSource(seed))
+	}
+
+	return nil
+}
+
+// jittered returns the duration extended by a random offset within the jitter fraction
+func jittered(d time.Duration) time.Duration {
+	jitterMux.Lock()
+	defer jitterMux.Unlock()
+
+	if jitterFraction == 0 || d <= 0 {
+		return d
+	}
+
+	return d + time.Duration(jitterRand.Float64()*jitterFraction*float64(d))
 }
 
 // cached wraps a getter with a cache
@@ -37,6 +82,17 @@ type cached[T any] struct {
 	g              func() (T, error)
 	val            T
 	err            error
+
+	// registration
+	id         int
+	read       atomic.Int64 // last read in unix nanoseconds
+	registered atomic.Bool
+	packages   []string // packages creating the cache
+
+	// diagnostics
+	source  string
+	lastErr error
+	errored time.Time
 }
 
 // Cached wraps a getter with a cache
@@ -57,16 +113,26 @@ var _ Cacheable[int64] = (*cached[int64])(nil)
 // Instead of the cached getter, the `Get()` and `Reset()` methods are exposed.
 func ResettableCached[T any](g func() (T, error), cache time.Duration) *cached[T] {
 	clock := clock.New()
+	source, packages := cacheOrigin()
 	c := &cached[T]{
-		clock: clock,
-		cache: cache,
-		g:     g,
+		clock:    clock,
+		cache:    jittered(cache),
+		g:        g,
+		source:   source,
+		packages: packages,
 	}
-	_ = bus.Subscribe(reset, c.Reset)
+	c.read.Store(clock.Now().UnixNano())
+	c.registered.Store(true)
+	c.id = registerCache(0, c)
 	return c
 }
 
 func (c *cached[T]) Get() (T, error) {
+	c.read.Store(c.clock.Now().UnixNano())
+	if !c.registered.Swap(true) {
+		registerCache(c.id, c)
+	}
+
 	c.mux.Lock()
 	defer c.mux.Unlock()
 
@@ -77,6 +143,9 @@ func (c *cached[T]) Get() (T, error) {
 
 		if c.err == nil {
 			c.backoffCounter = 0
+		} else {
+			c.lastErr = c.err
+			c.errored = c.updated
 		}
 	}
 
@@ -0,0 +1,204 @@
+package provider
+
+import (
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CacheState is the diagnostic state of a cached getter
+type CacheState struct {
+	ID          int           `json:"id"`
+	Source      string        `json:"source"`   // code location creating the cache
+	Type        string        `json:"type"`     // value type
+	Value       string        `json:"value"`    // cached value
+	Updated     time.Time     `json:"updated"`  // last update, zero if never updated
+	Age         time.Duration `json:"age"`      // age of the cached value
+	Interval    time.Duration `json:"interval"` // effective cache duration including jitter
+	Error       string        `json:"error,omitempty"`
+	LastError   string        `json:"lastError,omitempty"` // last error, kept after recovery
+	LastErrorAt time.Time     `json:"lastErrorAt,omitempty"`
+}
+
+// maxValueLength truncates formatted cache values
+const maxValueLength = 256
+
+// cacheExpiry is the time in addition to the cache duration after which an unread cache is released
+const cacheExpiry = time.Hour
+
+// stater is a registered cache
+type stater interface {
+	state() CacheState
+	expired() bool
+	unregister()
+	createdBy(pkg string) bool
+	Reset()
+}
+
+// caches is the only global reference to the caches, such that released caches
+// of deleted or tested devices can be garbage collected
+var caches = struct {
+	sync.Mutex
+	id   int
+	list map[int]stater
+}{
+	list: make(map[int]stater),
+}
+
+// registerCache adds the cache to the list and returns its id. A zero id assigns a new id.
+func registerCache(id int, c stater) int {
+	caches.Lock()
+	defer caches.Unlock()
+
+	pruneCaches()
+
+	if id == 0 {
+		caches.id++
+		id = caches.id
+	}
+
+	caches.list[id] = c
+	return id
+}
+
+// pruneCaches releases caches that have not been read within their cache duration plus expiry,
+// i.e. caches of devices that are no longer in use. Released caches register again when read.
+// The caller must hold the lock.
+func pruneCaches() {
+	for id, c := range caches.list {
+		if c.expired() {
+			c.unregister()
+			delete(caches.list, id)
+		}
+	}
+}
+
+// registeredCaches returns the registered caches ordered by id
+func registeredCaches() []stater {
+	caches.Lock()
+	defer caches.Unlock()
+
+	pruneCaches()
+
+	ids := make([]int, 0, len(caches.list))
+	for id := range caches.list {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+
+	res := make([]stater, 0, len(ids))
+	for _, id := range ids {
+		res = append(res, caches.list[id])
+	}
+
+	return res
+}
+
+// CacheStates returns the diagnostic states of all caches in use
+func CacheStates() []CacheState {
+	list := registeredCaches()
+
+	res := make([]CacheState, 0, len(list))
+	for _, c := range list {
+		res = append(res, c.state())
+	}
+
+	return res
+}
+
+// providerDir is the directory of this package
+var providerDir = func() string {
+	_, file, _, _ := runtime.Caller(0)
+	return filepath.Dir(file)
+}()
+
+// cacheOrigin returns the code location creating the cache outside this package,
+// attributing caches created by provider helpers to the device using them,
+// and the packages on the call stack creating the cache
+func cacheOrigin() (string, []string) {
+	pc := make([]uintptr, 32)
+	n := runtime.Callers(2, pc)
+
+	var source string
+	var packages []string
+
+	frames := runtime.CallersFrames(pc[:n])
+	for {
+		frame, more := frames.Next()
+		if source == "" && (filepath.Dir(frame.File) != providerDir || strings.HasSuffix(frame.File, "_test.go")) {
+			source = fmt.Sprintf("%s:%d", filepath.Join(filepath.Base(filepath.Dir(frame.File)), filepath.Base(frame.File)), frame.Line)
+		}
+		if pkg := funcPackage(frame.Function); pkg != "" && !slices.Contains(packages, pkg) {
+			packages = append(packages, pkg)
+		}
+		if !more {
+			return source, packages
+		}
+	}
+}
+
+// funcPackage returns the package path of a fully qualified function name
+func funcPackage(name string) string {
+	slash := strings.LastIndex(name, "/") + 1
+	if dot := strings.Index(name[slash:], "."); dot >= 0 {
+		return name[:slash+dot]
+	}
+	return ""
+}
+
+// format returns the formatted value truncated to the max length
+func format(v any) string {
+	s := fmt.Sprintf("%v", v)
+	if len(s) > maxValueLength {
+		s = s[:maxValueLength] + "…"
+	}
+	return s
+}
+
+func (c *cached[T]) state() CacheState {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	res := CacheState{
+		ID:          c.id,
+		Source:      c.source,
+		Type:        fmt.Sprintf("%T", c.val),
+		Updated:     c.updated,
+		Interval:    c.cache,
+		LastErrorAt: c.errored,
+	}
+
+	if !c.updated.IsZero() {
+		res.Value = format(c.val)
+		res.Age = c.clock.Since(c.updated)
+	}
+
+	if c.err != nil {
+		res.Error = c.err.Error()
+	}
+	if c.lastErr != nil {
+		res.LastError = c.lastErr.Error()
+	}
+
+	return res
+}
+
+// expired returns true if the cache has not been read within its cache duration plus expiry
+func (c *cached[T]) expired() bool {
+	return c.clock.Since(time.Unix(0, c.read.Load())) > c.cache+cacheExpiry
+}
+
+// createdBy returns true if the cache has been created by code of the package or its sub-packages
+func (c *cached[T]) createdBy(pkg string) bool {
+	return slices.ContainsFunc(c.packages, func(p string) bool {
+		return p == pkg || strings.HasPrefix(p, pkg+"/")
+	})
+}
+
+func (c *cached[T]) unregister() {
+	c.registered.Store(false)
+}
@@ -0,0 +1,106 @@
+package provider
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCacheState(t *testing.T) {
+	var err error
+	val := 1.0
+
+	c := ResettableCached(func() (float64, error) { return val, err }, time.Minute)
+	clck := clock.NewMock()
+	c.clock = clck
+
+	state := func() CacheState {
+		for _, s := range CacheStates() {
+			if s.ID == c.id {
+				return s
+			}
+		}
+		require.FailNow(t, "cache not found")
+		return CacheState{}
+	}
+
+	// not yet updated
+	s := state()
+	assert.Regexp(t, `^provider/cache_state_test.go:\d+$`, s.Source)
+	assert.Equal(t, "float64", s.Type)
+	assert.Equal(t, time.Minute, s.Interval)
+	assert.Empty(t, s.Value)
+	assert.True(t, s.Updated.IsZero())
+
+	// cached value ages
+	_, _ = c.Get()
+	clck.Add(30 * time.Second)
+
+	s = state()
+	assert.Equal(t, "1", s.Value)
+	assert.Equal(t, 30*time.Second, s.Age)
+
+	// error is reported and kept after recovery
+	err = errors.New("timeout")
+	clck.Add(time.Minute)
+	_, _ = c.Get()
+
+	s = state()
+	assert.Equal(t, "timeout", s.Error)
+	assert.Equal(t, "timeout", s.LastError)
+	assert.Equal(t, clck.Now(), s.LastErrorAt)
+
+	err = nil
+	val = 2
+	clck.Add(time.Minute)
+	_, _ = c.Get()
+
+	s = state()
+	assert.Equal(t, "2", s.Value)
+	assert.Zero(t, s.Age)
+	assert.Empty(t, s.Error)
+	assert.Equal(t, "timeout", s.LastError)
+}
+
+func TestCacheStateSource(t *testing.T) {
+	before := len(CacheStates())
+
+	// cache created by provider helper is attributed to the caller
+	_, err := NewExecProviderFromConfig(map[string]any{"cmd": "echo 1"})
+	require.NoError(t, err)
+
+	states := CacheStates()
+	require.Len(t, states, before+1)
+	assert.Regexp(t, `^provider/cache_state_test.go:\d+$`, states[len(states)-1].Source)
+}
+
+func TestCacheStateExpiry(t *testing.T) {
+	c := ResettableCached(func() (float64, error) { return 1, nil }, time.Minute)
+	clck := clock.NewMock()
+	c.clock = clck
+
+	registered := func() bool {
+		for _, s := range CacheStates() {
+			if s.ID == c.id {
+				return true
+			}
+		}
+		return false
+	}
+
+	_, _ = c.Get()
+	clck.Add(time.Minute + cacheExpiry)
+	assert.True(t, registered())
+
+	// unread cache is released
+	clck.Add(time.Second)
+	assert.False(t, registered())
+
+	// and registered again with same id when read
+	_, _ = c.Get()
+	assert.True(t, registered())
+}
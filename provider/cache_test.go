@@ -8,6 +8,7 @@ import (
 	"github.com/benbjohnson/clock"
 	"github.com/evcc-io/evcc/api"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestCachedGetter(t *testing.T) {
@@ -80,6 +81,33 @@ func TestCacheReset(t *testing.T) {
 	test(3)
 }
 
+func TestCacheResetBy(t *testing.T) {
+	var reads int
+	g := func() (int, error) {
+		reads++
+		return reads, nil
+	}
+
+	c := ResettableCached(g, time.Minute)
+	other := ResettableCached(g, time.Minute)
+	other.packages = []string{"github.com/evcc-io/evcc/meter"}
+
+	_, _ = c.Get()
+	_, _ = other.Get()
+	require.Equal(t, 2, reads)
+
+	// only caches created by the package or its sub-packages are reset
+	ResetCachedBy("github.com/evcc-io/evcc/provider")
+	_, _ = c.Get()
+	_, _ = other.Get()
+	assert.Equal(t, 3, reads)
+
+	ResetCachedBy("github.com/evcc-io/evcc")
+	_, _ = c.Get()
+	_, _ = other.Get()
+	assert.Equal(t, 5, reads)
+}
+
 func TestRetryWithBackoff(t *testing.T) {
 	tests := []struct {
 		deltaTime      time.Duration
@@ -127,3 +155,59 @@ func TestRetryWithBackoff(t *testing.T) {
 		assert.Equal(t, tt.functionCalled, functionCalled)
 	}
 }
+
+func TestCacheJitter(t *testing.T) {
+	t.Cleanup(func() {
+		_ = SetJitter(0, 0)
+	})
+
+	assert.Error(t, SetJitter(-0.1, 0))
+	assert.Error(t, SetJitter(1.5, 0))
+
+	const (
+		duration = time.Minute
+		fraction = 0.2
+	)
+
+	durations := func(seed int64) []time.Duration {
+		assert.NoError(t, SetJitter(fraction, seed))
+
+		var res []time.Duration
+		for i := 0; i < 10; i++ {
+			c := ResettableCached(func() (int, error) { return 0, nil }, duration)
+			res = append(res, c.cache)
+		}
+		return res
+	}
+
+	res := durations(42)
+	for _, d := range res {
+		assert.GreaterOrEqual(t, d, duration)
+		assert.LessOrEqual(t, d, duration+time.Duration(fraction*float64(duration)))
+	}
+	assert.NotEqual(t, res[0], res[1], "instances are spread")
+
+	// seeded jitter is reproducible
+	assert.Equal(t, res, durations(42))
+
+	// poll times follow the jittered duration
+	var i int
+	c := ResettableCached(func() (int, error) { i++; return i, nil }, duration)
+	clock := clock.NewMock()
+	c.clock = clock
+
+	assert.Greater(t, c.cache, duration+time.Second)
+
+	_, _ = c.Get()
+	clock.Add(duration + time.Second)
+	v, _ := c.Get()
+	assert.Equal(t, 1, v, "not updated within jittered duration")
+
+	clock.Add(c.cache - duration)
+	v, _ = c.Get()
+	assert.Equal(t, 2, v)
+
+	// no jitter by default
+	assert.NoError(t, SetJitter(0, 0))
+	assert.Equal(t, duration, ResettableCached(func() (int, error) { return 0, nil }, duration).cache)
+}
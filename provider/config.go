@@ -54,8 +54,9 @@ var registry providerRegistry = make(map[string]func(map[string]interface{}) (Pr
 
 // Config is the general provider config
 type Config struct {
-	Source string
-	Other  map[string]interface{} `mapstructure:",remain"`
+	Source    string
+	Transform *TransformConfig       // numeric value transformations
+	Other     map[string]interface{} `mapstructure:",remain"`
 }
 
 // NewIntGetterFromConfig creates a IntGetter from config
@@ -75,6 +76,14 @@ func NewIntGetterFromConfig(config Config) (func() (int64, error), error) {
 		return nil, fmt.Errorf("invalid plugin source for type int: %s", config.Source)
 	}
 
+	if t := config.Transform; t != nil {
+		if err := t.Validate(); err != nil {
+			return nil, err
+		}
+
+		return transformIntGetter(t, prov.IntGetter()), nil
+	}
+
 	return prov.IntGetter(), nil
 }
 
@@ -95,6 +104,14 @@ func NewFloatGetterFromConfig(config Config) (func() (float64, error), error) {
 		return nil, fmt.Errorf("invalid plugin source for type float: %s", config.Source)
 	}
 
+	if t := config.Transform; t != nil {
+		if err := t.Validate(); err != nil {
+			return nil, err
+		}
+
+		return transformFloatGetter(t, prov.FloatGetter()), nil
+	}
+
 	return prov.FloatGetter(), nil
 }
 
@@ -0,0 +1,276 @@
+package demo
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/benbjohnson/clock"
+)
+
+// Scenario selects the weather and daylight conditions of the simulation
+type Scenario string
+
+const (
+	Sunny  Scenario = "sunny"
+	Cloudy Scenario = "cloudy"
+	Night  Scenario = "night"
+)
+
+// ScenarioString parses a scenario name
+func ScenarioString(s string) (Scenario, error) {
+	switch sc := Scenario(strings.ToLower(s)); sc {
+	case Sunny, Cloudy, Night:
+		return sc, nil
+	case "":
+		return Sunny, nil
+	default:
+		return "", fmt.Errorf("invalid scenario: %s", s)
+	}
+}
+
+const (
+	Voltage = 230 // V
+
+	pvPeak = 8000.0 // W
+
+	homeMin = 250.0  // W
+	homeMax = 1200.0 // W
+
+	batteryCapacity = 10.0   // kWh
+	batteryMaxPower = 5000.0 // W
+	batteryMinSoc   = 10.0   // %
+	batteryTracking = 0.9    // share of residual power handled by the battery
+
+	// period of slow pv variation
+	period = 15 * time.Minute
+)
+
+// Loadpoint is the simulated state of a charger and its connected vehicle
+type Loadpoint struct {
+	Enabled  bool
+	Current  float64 // A
+	Phases   int
+	Soc      float64 // %
+	Capacity float64 // kWh
+	Power    float64 // W
+}
+
+// Simulation synthesizes plausible site behaviour over time
+type Simulation struct {
+	mu       sync.Mutex
+	clock    clock.Clock
+	rnd      *rand.Rand
+	scenario Scenario
+	updated  time.Time
+	started  time.Time
+
+	pvPower      float64
+	homePower    float64
+	batteryPower float64 // positive when discharging
+	batterySoc   float64
+	gridPower    float64 // positive when importing
+
+	loadpoints []*Loadpoint
+}
+
+var (
+	mu        sync.Mutex
+	instances = make(map[Scenario]*Simulation)
+)
+
+// Instance returns the shared simulation for the given scenario
+func Instance(scenario Scenario) *Simulation {
+	mu.Lock()
+	defer mu.Unlock()
+
+	s, ok := instances[scenario]
+	if !ok {
+		s = New(scenario, clock.New())
+		instances[scenario] = s
+	}
+
+	return s
+}
+
+// New creates a simulation
+func New(scenario Scenario, clock clock.Clock) *Simulation {
+	s := &Simulation{
+		clock:      clock,
+		rnd:        rand.New(rand.NewSource(clock.Now().UnixNano())),
+		scenario:   scenario,
+		started:    clock.Now(),
+		homePower:  500,
+		batterySoc: 55,
+		loadpoints: []*Loadpoint{
+			{Phases: 1, Soc: 62, Capacity: 44},
+			{Phases: 3, Soc: 22, Capacity: 80},
+		},
+	}
+
+	s.updated = s.started
+	s.pvPower = s.pv(s.started)
+	s.balance()
+
+	return s
+}
+
+// loadpoint returns the loadpoint state, creating it if required. Must be called while holding lock.
+func (s *Simulation) loadpoint(id int) *Loadpoint {
+	for len(s.loadpoints) <= id {
+		s.loadpoints = append(s.loadpoints, &Loadpoint{Phases: 3, Soc: 50, Capacity: 50})
+	}
+	return s.loadpoints[id]
+}
+
+func clamp(v, lo, hi float64) float64 {
+	return math.Max(lo, math.Min(hi, v))
+}
+
+// pv returns the scenario's pv production at given time
+func (s *Simulation) pv(t time.Time) float64 {
+	// slow variation between 0.8 and 1
+	phase := 2 * math.Pi * float64(t.Sub(s.started)) / float64(period)
+	slow := 0.9 + 0.1*math.Sin(phase)
+
+	switch s.scenario {
+	case Sunny:
+		return pvPeak * slow * (0.97 + 0.03*s.rnd.Float64())
+	case Cloudy:
+		// passing clouds
+		return pvPeak * slow * (0.1 + 0.4*s.rnd.Float64())
+	default:
+		return 0
+	}
+}
+
+// update advances the simulation to the current time. Must be called while holding lock.
+func (s *Simulation) update() {
+	now := s.clock.Now()
+
+	// random values only change once per second, independent of the number of readings
+	if elapsed := now.Sub(s.updated); elapsed >= time.Second {
+		dt := elapsed.Hours()
+		s.updated = now
+
+		for _, lp := range s.loadpoints {
+			lp.Soc = clamp(lp.Soc+100*lp.Power*dt/1e3/lp.Capacity, 0, 100)
+		}
+
+		s.batterySoc = clamp(s.batterySoc-100*s.batteryPower*dt/1e3/batteryCapacity, 0, 100)
+
+		s.pvPower = s.pv(now)
+		s.homePower = clamp(s.homePower+200*(s.rnd.Float64()-0.5), homeMin, homeMax)
+	}
+
+	s.balance()
+}
+
+// balance distributes power between loadpoints, battery and grid. Must be called while holding lock.
+func (s *Simulation) balance() {
+	var chargePower float64
+	for _, lp := range s.loadpoints {
+		lp.Power = 0
+		if lp.Enabled && lp.Soc < 100 {
+			lp.Power = lp.Current * Voltage * float64(lp.Phases)
+		}
+
+		chargePower += lp.Power
+	}
+
+	// battery covers most of the deficit or absorbs most of the surplus within limits
+	residual := s.homePower + chargePower - s.pvPower
+	switch {
+	case residual > 0 && s.batterySoc > batteryMinSoc:
+		s.batteryPower = math.Min(batteryTracking*residual, batteryMaxPower)
+	case residual < 0 && s.batterySoc < 100:
+		s.batteryPower = math.Max(batteryTracking*residual, -batteryMaxPower)
+	default:
+		s.batteryPower = 0
+	}
+
+	s.gridPower = residual - s.batteryPower
+}
+
+// Get returns the named value
+func (s *Simulation) Get(value string, loadpoint int) (float64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.update()
+
+	lp := s.loadpoint(loadpoint)
+
+	switch strings.ToLower(value) {
+	case "pvpower":
+		return s.pvPower, nil
+	case "homepower":
+		return s.homePower, nil
+	case "gridpower":
+		return s.gridPower, nil
+	case "batterypower":
+		return s.batteryPower, nil
+	case "batterysoc":
+		return s.batterySoc, nil
+	case "chargepower":
+		return lp.Power, nil
+	case "vehiclesoc":
+		return lp.Soc, nil
+	case "vehiclerange":
+		// assume 6 km/kWh
+		return lp.Soc / 100 * lp.Capacity * 6, nil
+	default:
+		return 0, fmt.Errorf("invalid value: %s", value)
+	}
+}
+
+// Status returns the loadpoint's charge status
+func (s *Simulation) Status(loadpoint int) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.loadpoint(loadpoint).Power > 0 {
+		return "C"
+	}
+
+	return "B"
+}
+
+// Enabled returns the loadpoint's enabled state
+func (s *Simulation) Enabled(loadpoint int) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.loadpoint(loadpoint).Enabled
+}
+
+// Set updates the named loadpoint setting
+func (s *Simulation) Set(param string, loadpoint int, value float64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	lp := s.loadpoint(loadpoint)
+
+	switch strings.ToLower(param) {
+	case "enable":
+		lp.Enabled = value != 0
+	case "maxcurrent", "maxcurrentmillis":
+		lp.Current = value
+	case "phases":
+		if value != 1 && value != 3 {
+			return fmt.Errorf("invalid phases: %v", value)
+		}
+		lp.Phases = int(value)
+	case "vehiclesoc":
+		lp.Soc = clamp(value, 0, 100)
+	default:
+		return fmt.Errorf("invalid parameter: %s", param)
+	}
+
+	s.update()
+
+	return nil
+}
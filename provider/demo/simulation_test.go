@@ -0,0 +1,108 @@
+package demo
+
+import (
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScenarioString(t *testing.T) {
+	for _, s := range []string{"sunny", "Cloudy", "NIGHT"} {
+		_, err := ScenarioString(s)
+		assert.NoError(t, err, s)
+	}
+
+	sc, err := ScenarioString("")
+	assert.NoError(t, err)
+	assert.Equal(t, Sunny, sc)
+
+	_, err = ScenarioString("foggy")
+	assert.Error(t, err)
+}
+
+func TestSimulationBounded(t *testing.T) {
+	for _, sc := range []Scenario{Sunny, Cloudy, Night} {
+		clock := clock.NewMock()
+		sim := New(sc, clock)
+
+		require.NoError(t, sim.Set("maxcurrent", 0, 16))
+		require.NoError(t, sim.Set("enable", 0, 1))
+
+		seen := make(map[string]map[float64]bool)
+
+		for i := 0; i < 100; i++ {
+			clock.Add(10 * time.Second)
+
+			values := make(map[string]float64)
+			for _, v := range []string{"pvpower", "homepower", "gridpower", "batterypower", "batterysoc", "chargepower", "vehiclesoc"} {
+				f, err := sim.Get(v, 0)
+				require.NoError(t, err)
+
+				values[v] = f
+				if seen[v] == nil {
+					seen[v] = make(map[float64]bool)
+				}
+				seen[v][f] = true
+			}
+
+			assert.GreaterOrEqual(t, values["pvpower"], 0.0, sc)
+			assert.LessOrEqual(t, values["pvpower"], pvPeak, sc)
+			assert.GreaterOrEqual(t, values["homepower"], homeMin, sc)
+			assert.LessOrEqual(t, values["homepower"], homeMax, sc)
+			assert.LessOrEqual(t, values["batterypower"], batteryMaxPower, sc)
+			assert.GreaterOrEqual(t, values["batterypower"], -batteryMaxPower, sc)
+			assert.GreaterOrEqual(t, values["batterysoc"], 0.0, sc)
+			assert.LessOrEqual(t, values["batterysoc"], 100.0, sc)
+			assert.GreaterOrEqual(t, values["vehiclesoc"], 0.0, sc)
+			assert.LessOrEqual(t, values["vehiclesoc"], 100.0, sc)
+
+			// site power balance
+			assert.InDelta(t, values["homepower"]+values["chargepower"], values["pvpower"]+values["batterypower"]+values["gridpower"], 1e-6, sc)
+
+			if sc == Night {
+				assert.Equal(t, 0.0, values["pvpower"])
+			}
+		}
+
+		// values are moving
+		for _, v := range []string{"homepower", "gridpower", "vehiclesoc"} {
+			assert.Greater(t, len(seen[v]), 1, "%s: %s not changing", sc, v)
+		}
+		if sc != Night {
+			assert.Greater(t, len(seen["pvpower"]), 1, "%s: pvpower not changing", sc)
+		}
+	}
+}
+
+func TestSimulationCharging(t *testing.T) {
+	clock := clock.NewMock()
+	sim := New(Sunny, clock)
+
+	soc, err := sim.Get("vehiclesoc", 0)
+	require.NoError(t, err)
+	assert.Equal(t, "B", sim.Status(0))
+
+	require.NoError(t, sim.Set("maxcurrent", 0, 16))
+	require.NoError(t, sim.Set("enable", 0, 1))
+	assert.True(t, sim.Enabled(0))
+	assert.Equal(t, "C", sim.Status(0))
+
+	power, err := sim.Get("chargepower", 0)
+	require.NoError(t, err)
+	assert.Equal(t, 16.0*Voltage, power)
+
+	clock.Add(time.Hour)
+
+	soc2, err := sim.Get("vehiclesoc", 0)
+	require.NoError(t, err)
+	assert.Greater(t, soc2, soc)
+
+	require.NoError(t, sim.Set("enable", 0, 0))
+	assert.Equal(t, "B", sim.Status(0))
+
+	assert.Error(t, sim.Set("phases", 0, 2))
+	assert.Error(t, sim.Set("foo", 0, 1))
+}
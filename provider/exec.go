@@ -0,0 +1,176 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/evcc-io/evcc/provider/pipeline"
+	"github.com/evcc-io/evcc/util"
+	"github.com/evcc-io/evcc/util/request"
+)
+
+// Exec implements providers executing a command without shell
+type Exec struct {
+	log      *util.Logger
+	cmd      string
+	args     []string
+	env      []string
+	timeout  time.Duration
+	pipeline *pipeline.Pipeline
+	scale    float64
+	valG     func() (string, error)
+}
+
+func init() {
+	registry.Add("exec", NewExecProviderFromConfig)
+}
+
+// NewExecProviderFromConfig creates an exec provider
+func NewExecProviderFromConfig(other map[string]interface{}) (Provider, error) {
+	cc := struct {
+		Cmd               string
+		Args              []string
+		Env               map[string]string
+		Timeout           time.Duration
+		Cache             time.Duration
+		pipeline.Settings `mapstructure:",squash"`
+		Scale             float64
+	}{
+		Timeout: request.Timeout,
+		Cache:   10 * time.Second, // default site interval
+		Scale:   1,
+	}
+
+	if err := util.DecodeOther(other, &cc); err != nil {
+		return nil, err
+	}
+
+	if cc.Cmd == "" {
+		return nil, errors.New("missing cmd")
+	}
+
+	log := util.NewLogger("exec")
+
+	pipe, err := pipeline.New(log, cc.Settings)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewExecProvider(log, cc.Cmd, cc.Args, cc.Env, cc.Timeout, cc.Cache).WithPipeline(pipe).WithScale(cc.Scale), nil
+}
+
+// NewExecProvider creates an exec provider.
+// The command's process group is killed after given timeout, results are cached for the cache duration.
+func NewExecProvider(log *util.Logger, cmd string, args []string, env map[string]string, timeout, cache time.Duration) *Exec {
+	p := &Exec{
+		log:     log,
+		cmd:     cmd,
+		args:    args,
+		timeout: timeout,
+		scale:   1,
+	}
+
+	for k, v := range env {
+		p.env = append(p.env, k+"="+v)
+	}
+
+	p.valG = Cached(p.exec, cache)
+
+	return p
+}
+
+// WithPipeline adds a processing pipeline
+func (p *Exec) WithPipeline(pipeline *pipeline.Pipeline) *Exec {
+	p.pipeline = pipeline
+	return p
+}
+
+// WithScale adds a scale factor for numeric values
+func (p *Exec) WithScale(scale float64) *Exec {
+	p.scale = scale
+	return p
+}
+
+func (p *Exec) exec() (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), p.timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, p.cmd, p.args...)
+	cmd.Env = append(os.Environ(), p.env...)
+	killProcessGroup(cmd)
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	b, err := cmd.Output()
+
+	if ctx.Err() != nil {
+		return "", fmt.Errorf("%s: timeout after %v", p.cmd, p.timeout)
+	}
+
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			err = fmt.Errorf("%w: %s", err, msg)
+		}
+		return "", fmt.Errorf("%s: %w", p.cmd, err)
+	}
+
+	if p.pipeline != nil {
+		if b, err = p.pipeline.Process(b); err != nil {
+			return "", err
+		}
+	}
+
+	s := strings.TrimSpace(string(b))
+	p.log.DEBUG.Printf("%s: %s", strings.Join(append([]string{p.cmd}, p.args...), " "), s)
+
+	return s, nil
+}
+
+// StringGetter returns string from exec result. Only STDOUT is considered.
+func (p *Exec) StringGetter() func() (string, error) {
+	return p.valG
+}
+
+// FloatGetter parses float from exec result
+func (p *Exec) FloatGetter() func() (float64, error) {
+	return func() (float64, error) {
+		s, err := p.valG()
+		if err != nil {
+			return 0, err
+		}
+
+		f, err := strconv.ParseFloat(s, 64)
+		return f * p.scale, err
+	}
+}
+
+// IntGetter parses int64 from exec result
+func (p *Exec) IntGetter() func() (int64, error) {
+	g := p.FloatGetter()
+
+	return func() (int64, error) {
+		f, err := g()
+		return int64(math.Round(f)), err
+	}
+}
+
+// BoolGetter parses bool from exec result. "on", "true" and 1 are considered truish.
+func (p *Exec) BoolGetter() func() (bool, error) {
+	return func() (bool, error) {
+		s, err := p.valG()
+		if err != nil {
+			return false, err
+		}
+
+		return util.Truish(s), nil
+	}
+}
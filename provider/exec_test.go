@@ -0,0 +1,67 @@
+//go:build !windows
+
+package provider
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evcc-io/evcc/provider/pipeline"
+	"github.com/evcc-io/evcc/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecProvider(t *testing.T) {
+	log := util.NewLogger("foo")
+
+	p, err := NewExecProviderFromConfig(map[string]interface{}{
+		"cmd":   "echo",
+		"args":  []string{`{"power":`, "1.5}"},
+		"jq":    ".power",
+		"scale": 1000,
+	})
+	require.NoError(t, err)
+
+	f, err := p.(FloatProvider).FloatGetter()()
+	require.NoError(t, err)
+	assert.Equal(t, 1500.0, f)
+
+	// environment
+	s, err := NewExecProvider(log, "sh", []string{"-c", "echo $FOO"}, map[string]string{"FOO": "bar"}, time.Second, 0).StringGetter()()
+	require.NoError(t, err)
+	assert.Equal(t, "bar", s)
+
+	// non-zero exit
+	_, err = NewExecProvider(log, "sh", []string{"-c", "echo foo; echo failed >&2; exit 1"}, nil, time.Second, 0).StringGetter()()
+	require.ErrorContains(t, err, "failed")
+
+	// cached
+	g := NewExecProvider(log, "date", []string{"+%s%N"}, nil, time.Second, time.Hour).StringGetter()
+	s, err = g()
+	require.NoError(t, err)
+	s2, err := g()
+	require.NoError(t, err)
+	assert.Equal(t, s, s2)
+}
+
+func TestExecProviderTimeout(t *testing.T) {
+	// child process keeps stdout open unless the process group is killed
+	p := NewExecProvider(util.NewLogger("foo"), "sh", []string{"-c", "sleep 10; echo 1"}, nil, 100*time.Millisecond, 0)
+
+	start := time.Now()
+	_, err := p.IntGetter()()
+	require.ErrorContains(t, err, "timeout")
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestExecProviderPipeline(t *testing.T) {
+	pipe, err := pipeline.New(nil, pipeline.Settings{Regex: `power=(\d+)`})
+	require.NoError(t, err)
+
+	p := NewExecProvider(util.NewLogger("foo"), "echo", []string{"power=42"}, nil, time.Second, 0).WithPipeline(pipe)
+
+	i, err := p.IntGetter()()
+	require.NoError(t, err)
+	assert.Equal(t, int64(42), i)
+}
@@ -0,0 +1,19 @@
+//go:build !windows
+
+package provider
+
+import (
+	"os/exec"
+	"syscall"
+	"time"
+)
+
+// killProcessGroup runs the command in its own process group which is killed once the command's context is done
+func killProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+	// don't wait for orphaned children holding stdout
+	cmd.WaitDelay = time.Second
+}
@@ -0,0 +1,14 @@
+//go:build windows
+
+package provider
+
+import (
+	"os/exec"
+	"time"
+)
+
+// killProcessGroup kills the command once the command's context is done. Child processes are not killed on windows.
+func killProcessGroup(cmd *exec.Cmd) {
+	// don't wait for orphaned children holding stdout
+	cmd.WaitDelay = time.Second
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"io"
 	"math"
+	"net/http"
 	"strconv"
 	"strings"
 	"time"
@@ -19,8 +20,11 @@ import (
 // HTTP implements HTTP request provider
 type HTTP struct {
 	*request.Helper
+	log         *util.Logger
 	url, method string
 	headers     map[string]string
+	sign        *HTTPSign
+	now         func() time.Time
 	body        string
 	scale       float64
 	cache       time.Duration
@@ -49,6 +53,7 @@ func NewHTTPProviderFromConfig(other map[string]interface{}) (Provider, error) {
 		Scale             float64
 		Insecure          bool
 		Auth              Auth
+		Sign              *HTTPSign
 		Timeout           time.Duration
 		Cache             time.Duration
 	}{
@@ -80,6 +85,10 @@ func NewHTTPProviderFromConfig(other map[string]interface{}) (Provider, error) {
 		_, err = http.WithAuth(cc.Auth.Type, cc.Auth.User, cc.Auth.Password)
 	}
 
+	if err == nil && cc.Sign != nil {
+		_, err = http.WithSign(*cc.Sign)
+	}
+
 	if err == nil {
 		var pipe *pipeline.Pipeline
 		pipe, err = pipeline.New(log, cc.Settings)
@@ -98,10 +107,12 @@ func NewHTTP(log *util.Logger, method, uri string, insecure bool, scale float64,
 
 	p := &HTTP{
 		Helper: request.NewHelper(log),
+		log:    log,
 		url:    url,
 		method: method,
 		scale:  scale,
 		cache:  cache,
+		now:    time.Now,
 	}
 
 	// http cache
@@ -158,12 +169,24 @@ func (p *HTTP) WithAuth(typ, user, password string) (*HTTP, error) {
 func (p *HTTP) request(url string, body ...string) ([]byte, error) {
 	if time.Since(p.updated) >= p.cache {
 		var b io.Reader
+		var s string
 		if len(body) == 1 {
-			b = strings.NewReader(body[0])
+			s = body[0]
+			b = strings.NewReader(s)
 		}
 
 		// empty method becomes GET
-		req, err := request.New(strings.ToUpper(p.method), url, b, p.headers)
+		method := strings.ToUpper(p.method)
+		if method == "" {
+			method = http.MethodGet
+		}
+
+		headers, err := p.requestHeaders(method, url, s)
+		if err != nil {
+			return []byte{}, err
+		}
+
+		req, err := request.New(method, url, b, headers)
 		if err != nil {
 			return []byte{}, err
 		}
@@ -175,6 +198,13 @@ func (p *HTTP) request(url string, body ...string) ([]byte, error) {
 	return p.val, p.err
 }
 
+var _ RawProvider = (*HTTP)(nil)
+
+// Raw returns the last response before processing
+func (p *HTTP) Raw() string {
+	return string(p.val)
+}
+
 var _ StringProvider = (*HTTP)(nil)
 
 // StringGetter sends string request
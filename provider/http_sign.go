@@ -0,0 +1,137 @@
+package provider
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/Masterminds/sprig/v3"
+)
+
+// HTTPSign is the request signing config. The signature is the HMAC-SHA256 of the canonical
+// payload template using the shared secret and can be referenced as {{.signature}} in headers.
+type HTTPSign struct {
+	Secret   string // shared secret
+	Header   string // signature header, optional if referenced by header templates
+	Payload  string // canonical string template
+	Encoding string // hex or base64
+}
+
+// defaultSignPayload is the canonical string signed unless configured otherwise
+const defaultSignPayload = "{{.method}}\n{{.path}}\n{{.timestamp}}\n{{.body}}"
+
+// WithSign adds HMAC-SHA256 request signing
+func (p *HTTP) WithSign(sign HTTPSign) (*HTTP, error) {
+	if sign.Secret == "" {
+		return nil, errors.New("missing secret")
+	}
+
+	if sign.Payload == "" {
+		sign.Payload = defaultSignPayload
+	}
+
+	switch strings.ToLower(sign.Encoding) {
+	case "":
+		sign.Encoding = "hex"
+	case "hex", "base64":
+	default:
+		return nil, fmt.Errorf("invalid encoding: %s", sign.Encoding)
+	}
+
+	p.log.Redact(sign.Secret)
+	p.sign = &sign
+
+	return p, nil
+}
+
+// requestHeaders renders the header templates and adds the request signature
+func (p *HTTP) requestHeaders(method, uri, body string) (map[string]string, error) {
+	if p.sign == nil && !templated(p.headers) {
+		return p.headers, nil
+	}
+
+	u, err := url.Parse(uri)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	now := p.now()
+	kv := map[string]interface{}{
+		"method":      method,
+		"uri":         uri,
+		"host":        u.Host,
+		"path":        u.EscapedPath(),
+		"query":       u.RawQuery,
+		"body":        body,
+		"timestamp":   strconv.FormatInt(now.Unix(), 10),
+		"timestampMs": strconv.FormatInt(now.UnixMilli(), 10),
+		"date":        now.UTC().Format(http.TimeFormat),
+		"nonce":       hex.EncodeToString(nonce),
+	}
+
+	if p.sign != nil {
+		payload, err := render(p.sign.Payload, kv)
+		if err != nil {
+			return nil, fmt.Errorf("sign payload: %w", err)
+		}
+
+		mac := hmac.New(sha256.New, []byte(p.sign.Secret))
+		mac.Write([]byte(payload))
+
+		if strings.EqualFold(p.sign.Encoding, "base64") {
+			kv["signature"] = base64.StdEncoding.EncodeToString(mac.Sum(nil))
+		} else {
+			kv["signature"] = hex.EncodeToString(mac.Sum(nil))
+		}
+	}
+
+	res := make(map[string]string, len(p.headers)+1)
+	for k, v := range p.headers {
+		if res[k], err = render(v, kv); err != nil {
+			return nil, fmt.Errorf("header %s: %w", k, err)
+		}
+	}
+
+	if p.sign != nil && p.sign.Header != "" {
+		res[p.sign.Header] = kv["signature"].(string)
+	}
+
+	return res, nil
+}
+
+// render executes the template without html escaping to keep signed values unmodified
+func render(s string, kv map[string]interface{}) (string, error) {
+	tpl, err := template.New("base").Funcs(sprig.TxtFuncMap()).Parse(s)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	err = tpl.Execute(&b, kv)
+
+	return b.String(), err
+}
+
+// templated returns true if any header value is a template
+func templated(headers map[string]string) bool {
+	for _, v := range headers {
+		if strings.Contains(v, "{{") {
+			return true
+		}
+	}
+	return false
+}
@@ -1,14 +1,20 @@
 package provider
 
 import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"testing"
+	"time"
 
 	"github.com/evcc-io/evcc/util"
 	"github.com/samber/lo"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 type httpHandler struct {
@@ -53,3 +59,69 @@ func TestHttpSet(t *testing.T) {
 	assert.Equal(t, uriUrl.Path, h.req.URL.Path)
 	assert.Equal(t, "baz=4711", h.req.URL.RawQuery)
 }
+
+func TestHttpSign(t *testing.T) {
+	h := new(httpHandler)
+	srv := httptest.NewServer(h)
+	defer srv.Close()
+
+	const secret = "s3cr3t"
+	now := time.Unix(1700000000, 0)
+
+	p, err := NewHTTP(util.NewLogger("foo"), http.MethodPost, srv.URL+"/api/power?id=1", false, 1, 0).
+		WithHeaders(map[string]string{
+			"X-Timestamp":   "{{.timestamp}}",
+			"X-Nonce":       "{{.nonce}}",
+			"Authorization": "HMAC key=foo, signature={{.signature}}",
+			"Content-Type":  "application/json",
+		}).
+		WithBody(`{"power":1}`).
+		WithSign(HTTPSign{
+			Secret:  secret,
+			Header:  "X-Signature",
+			Payload: "{{.method}}\n{{.path}}?{{.query}}\n{{.timestamp}}\n{{.nonce}}\n{{.body}}",
+		})
+	require.NoError(t, err)
+	p.now = func() time.Time { return now }
+
+	_, err = p.StringGetter()()
+	require.NoError(t, err)
+
+	nonce := h.req.Header.Get("X-Nonce")
+	assert.Len(t, nonce, 32)
+	assert.Equal(t, "1700000000", h.req.Header.Get("X-Timestamp"))
+	assert.Equal(t, "application/json", h.req.Header.Get("Content-Type"))
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("POST\n/api/power?id=1\n1700000000\n" + nonce + "\n" + `{"power":1}`))
+	signature := hex.EncodeToString(mac.Sum(nil))
+
+	assert.Equal(t, signature, h.req.Header.Get("X-Signature"))
+	assert.Equal(t, "HMAC key=foo, signature="+signature, h.req.Header.Get("Authorization"))
+
+	// nonce changes per request
+	_, err = p.StringGetter()()
+	require.NoError(t, err)
+	assert.NotEqual(t, nonce, h.req.Header.Get("X-Nonce"))
+}
+
+func TestHttpSignDefaults(t *testing.T) {
+	h := new(httpHandler)
+	srv := httptest.NewServer(h)
+	defer srv.Close()
+
+	p, err := NewHTTP(util.NewLogger("foo"), "", srv.URL+"/foo", false, 1, 0).
+		WithSign(HTTPSign{Secret: "secret", Header: "X-Signature", Encoding: "base64"})
+	require.NoError(t, err)
+	p.now = func() time.Time { return time.Unix(1700000000, 0) }
+
+	_, err = p.StringGetter()()
+	require.NoError(t, err)
+
+	mac := hmac.New(sha256.New, []byte("secret"))
+	mac.Write([]byte("GET\n/foo\n1700000000\n"))
+	assert.Equal(t, base64.StdEncoding.EncodeToString(mac.Sum(nil)), h.req.Header.Get("X-Signature"))
+
+	_, err = NewHTTP(util.NewLogger("foo"), "", srv.URL, false, 1, 0).WithSign(HTTPSign{})
+	assert.Error(t, err)
+}
@@ -25,6 +25,7 @@ type Script struct {
 	timeout time.Duration
 	cache   time.Duration
 	updated time.Time
+	raw     string // exec result before regex and jq processing
 	val     string
 	err     error
 	re      *regexp.Regexp
@@ -133,11 +134,17 @@ func (p *Script) exec(script string) (string, error) {
 	return s, nil
 }
 
+// Raw returns the last exec result before processing
+func (p *Script) Raw() string {
+	return p.raw
+}
+
 // StringGetter returns string from exec result. Only STDOUT is considered.
 func (p *Script) StringGetter() func() (string, error) {
 	return func() (string, error) {
 		if time.Since(p.updated) > p.cache {
 			p.val, p.err = p.exec(p.script)
+			p.raw = p.val
 			p.updated = time.Now()
 
 			if p.err == nil && p.re != nil {
@@ -5,6 +5,7 @@ import (
 	"math"
 	"net/http"
 	"strconv"
+	"sync"
 	"time"
 
 	"github.com/evcc-io/evcc/api"
@@ -26,6 +27,10 @@ type Socket struct {
 	scale    float64
 	pipeline *pipeline.Pipeline
 	val      *util.Monitor[[]byte]
+	watchdog *Watchdog
+
+	mu     sync.Mutex
+	cancel context.CancelFunc // cancels the current connection
 }
 
 func init() {
@@ -43,6 +48,7 @@ func NewSocketProviderFromConfig(other map[string]interface{}) (Provider, error)
 		Insecure          bool
 		Auth              Auth
 		Timeout           time.Duration
+		Watchdog          time.Duration
 	}{
 		Headers: make(map[string]string),
 		Scale:   1,
@@ -86,6 +92,11 @@ func NewSocketProviderFromConfig(other map[string]interface{}) (Provider, error)
 		return nil, err
 	}
 
+	// reconnect if the connection is silent
+	if cc.Watchdog > 0 {
+		p.watchdog = NewWatchdog(cc.Watchdog, p.restart)
+	}
+
 	go p.listen()
 
 	if cc.Timeout > 0 {
@@ -120,8 +131,13 @@ func (p *Socket) listen() {
 			continue
 		}
 
+		ctx, cancel = context.WithCancel(context.Background())
+		p.mu.Lock()
+		p.cancel = cancel
+		p.mu.Unlock()
+
 		for {
-			_, b, err := conn.Read(context.Background())
+			_, b, err := conn.Read(ctx)
 			if err != nil {
 				p.log.TRACE.Println("read:", err)
 				_ = conn.Close(websocket.StatusAbnormalClosure, "done")
@@ -130,10 +146,30 @@ func (p *Socket) listen() {
 
 			p.log.TRACE.Printf("recv: %s", b)
 
+			if p.watchdog != nil {
+				p.watchdog.Update()
+			}
+
 			if v, err := p.pipeline.Process(b); err == nil {
 				p.val.Set(v)
 			}
 		}
+
+		p.mu.Lock()
+		p.cancel = nil
+		p.mu.Unlock()
+		cancel()
+	}
+}
+
+// restart closes a silent connection to reconnect
+func (p *Socket) restart(silent time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.cancel != nil {
+		p.log.WARN.Printf("watchdog: no data for %v, reconnecting", silent.Round(time.Second))
+		p.cancel()
 	}
 }
 
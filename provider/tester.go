@@ -0,0 +1,158 @@
+package provider
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/evcc-io/evcc/util"
+)
+
+// RawProvider exposes the unprocessed response of the last read
+type RawProvider interface {
+	Raw() string
+}
+
+// TestResult is the result of a single provider read
+type TestResult struct {
+	Raw   string `json:"raw,omitempty"`   // response before extraction, if supported by the plugin
+	Value any    `json:"value,omitempty"` // extracted value
+	Error string `json:"error,omitempty"` // read error
+}
+
+// Test executes a single read of the provider config as int, float, string or bool value.
+// Secrets contained in the config are redacted from the result.
+func Test(typ string, config Config) (TestResult, error) {
+	factory, err := registry.Get(config.Source)
+	if err != nil {
+		return TestResult{}, err
+	}
+
+	provider, err := factory(config.Other)
+	if err != nil {
+		return TestResult{}, err
+	}
+
+	var val any
+	switch typ {
+	case "int":
+		prov, ok := provider.(IntProvider)
+		if !ok {
+			return TestResult{}, fmt.Errorf("invalid plugin source for type int: %s", config.Source)
+		}
+		g := prov.IntGetter()
+		if t := config.Transform; t != nil {
+			if err := t.Validate(); err != nil {
+				return TestResult{}, err
+			}
+			g = transformIntGetter(t, g)
+		}
+		val, err = g()
+
+	case "float", "":
+		prov, ok := provider.(FloatProvider)
+		if !ok {
+			return TestResult{}, fmt.Errorf("invalid plugin source for type float: %s", config.Source)
+		}
+		g := prov.FloatGetter()
+		if t := config.Transform; t != nil {
+			if err := t.Validate(); err != nil {
+				return TestResult{}, err
+			}
+			g = transformFloatGetter(t, g)
+		}
+		val, err = g()
+
+	case "string":
+		prov, ok := provider.(StringProvider)
+		if !ok {
+			return TestResult{}, fmt.Errorf("invalid plugin source for type string: %s", config.Source)
+		}
+		val, err = prov.StringGetter()()
+
+	case "bool":
+		prov, ok := provider.(BoolProvider)
+		if !ok {
+			return TestResult{}, fmt.Errorf("invalid plugin source for type bool: %s", config.Source)
+		}
+		val, err = prov.BoolGetter()()
+
+	default:
+		return TestResult{}, fmt.Errorf("invalid type: %s", typ)
+	}
+
+	var res TestResult
+	if err != nil {
+		res.Error = err.Error()
+	} else {
+		res.Value = val
+	}
+
+	if p, ok := provider.(RawProvider); ok {
+		res.Raw = p.Raw()
+	}
+
+	secrets := secretValues("", config.Other)
+	res.Raw = redactSecrets(res.Raw, secrets)
+	res.Error = redactSecrets(res.Error, secrets)
+	if s, ok := res.Value.(string); ok {
+		res.Value = redactSecrets(s, secrets)
+	}
+
+	return res, nil
+}
+
+// secretValues recursively collects the secret config values including credentials contained in uris
+func secretValues(key string, v any) []string {
+	var res []string
+
+	switch v := v.(type) {
+	case map[string]any:
+		for k, val := range v {
+			res = append(res, secretValues(k, val)...)
+		}
+
+	case []any:
+		for _, val := range v {
+			res = append(res, secretValues(key, val)...)
+		}
+
+	case string:
+		if v == "" {
+			break
+		}
+		if util.IsSecretKey(key) {
+			res = append(res, v)
+		}
+		if u, err := url.Parse(v); err == nil && u.User != nil {
+			if pass, ok := u.User.Password(); ok && pass != "" {
+				res = append(res, pass)
+			}
+		}
+
+	default:
+		if v != nil && util.IsSecretKey(key) {
+			res = append(res, fmt.Sprintf("%v", v))
+		}
+	}
+
+	return res
+}
+
+// redactSecrets replaces the secrets and their url encoding in s
+func redactSecrets(s string, secrets []string) string {
+	var items []string
+	for _, secret := range secrets {
+		items = append(items, util.RedactDefaultHook(secret)...)
+	}
+
+	// replace longer items first to not leave partial secrets
+	sort.Slice(items, func(i, j int) bool { return len(items[i]) > len(items[j]) })
+
+	for _, item := range items {
+		s = strings.ReplaceAll(s, item, util.RedactReplacement)
+	}
+
+	return s
+}
@@ -0,0 +1,83 @@
+package provider
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTestHTTP(t *testing.T) {
+	const password = "s3cr3t+pass"
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if _, pass, _ := req.BasicAuth(); pass != password {
+			http.Error(w, "invalid password "+pass, http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(`{"power":1200,"auth":"` + password + `"}`))
+	}))
+	defer srv.Close()
+
+	conf := Config{
+		Source: "http",
+		Other: map[string]any{
+			"uri": srv.URL,
+			"jq":  ".power",
+			"auth": map[string]any{
+				"type":     "basic",
+				"user":     "admin",
+				"password": password,
+			},
+		},
+	}
+
+	res, err := Test("float", conf)
+	require.NoError(t, err)
+	assert.Equal(t, 1200.0, res.Value)
+	assert.Empty(t, res.Error)
+	assert.Equal(t, `{"power":1200,"auth":"***"}`, res.Raw)
+
+	// read errors are reported as result
+	conf.Other["auth"].(map[string]any)["password"] = "wrong"
+	res, err = Test("float", conf)
+	require.NoError(t, err)
+	assert.Nil(t, res.Value)
+	assert.Contains(t, res.Error, "401")
+	assert.Equal(t, "invalid password ***\n", res.Raw)
+
+	// invalid configs are rejected
+	_, err = Test("float", Config{Source: "http", Other: map[string]any{"jq": "..."}})
+	assert.Error(t, err)
+
+	_, err = Test("foo", conf)
+	assert.Error(t, err)
+}
+
+func TestTestScript(t *testing.T) {
+	conf := Config{
+		Source: "script",
+		Other: map[string]any{
+			"cmd":   `echo "soc=75 token=abc123"`,
+			"regex": `soc=(\d+)`,
+		},
+	}
+
+	res, err := Test("int", conf)
+	require.NoError(t, err)
+	assert.Equal(t, int64(75), res.Value)
+	assert.Equal(t, "soc=75 token=abc123", res.Raw)
+
+	res, err = Test("string", conf)
+	require.NoError(t, err)
+	assert.Equal(t, "75", res.Value)
+
+	// transformations apply to numeric values
+	conf.Transform = &TransformConfig{Scale: lo.ToPtr(0.5)}
+	res, err = Test("float", conf)
+	require.NoError(t, err)
+	assert.Equal(t, 37.5, res.Value)
+}
@@ -0,0 +1,76 @@
+package provider
+
+import (
+	"errors"
+	"math"
+)
+
+// TransformConfig transforms the value of numeric providers.
+// Transformations are applied in order: scale, offset, abs, invert, min/max.
+type TransformConfig struct {
+	Scale    *float64 // multiply by factor
+	Offset   float64  // add constant
+	Abs      bool     // remove sign
+	Invert   bool     // flip sign
+	Min, Max *float64 // clamp to range
+}
+
+// Validate checks the transformation config
+func (t *TransformConfig) Validate() error {
+	if t.Min != nil && t.Max != nil && *t.Min > *t.Max {
+		return errors.New("transform: min must not exceed max")
+	}
+
+	return nil
+}
+
+// Apply applies the transformations to the value
+func (t *TransformConfig) Apply(f float64) float64 {
+	if t.Scale != nil {
+		f *= *t.Scale
+	}
+
+	f += t.Offset
+
+	if t.Abs {
+		f = math.Abs(f)
+	}
+
+	if t.Invert {
+		f = -f
+	}
+
+	if t.Min != nil {
+		f = math.Max(f, *t.Min)
+	}
+
+	if t.Max != nil {
+		f = math.Min(f, *t.Max)
+	}
+
+	return f
+}
+
+// transformFloatGetter wraps a getter with transformations
+func transformFloatGetter(t *TransformConfig, g func() (float64, error)) func() (float64, error) {
+	return func() (float64, error) {
+		f, err := g()
+		if err != nil {
+			return 0, err
+		}
+
+		return t.Apply(f), nil
+	}
+}
+
+// transformIntGetter wraps a getter with transformations, rounding the result
+func transformIntGetter(t *TransformConfig, g func() (int64, error)) func() (int64, error) {
+	return func() (int64, error) {
+		i, err := g()
+		if err != nil {
+			return 0, err
+		}
+
+		return int64(math.Round(t.Apply(float64(i)))), nil
+	}
+}
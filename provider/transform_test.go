@@ -0,0 +1,87 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/evcc-io/evcc/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ptr(f float64) *float64 {
+	return &f
+}
+
+func TestTransform(t *testing.T) {
+	tc := []struct {
+		name      string
+		transform TransformConfig
+		in, out   float64
+	}{
+		{"none", TransformConfig{}, -1500, -1500},
+		{"scale", TransformConfig{Scale: ptr(0.001)}, 1500, 1.5},
+		{"offset", TransformConfig{Offset: 100}, 1500, 1600},
+		{"abs", TransformConfig{Abs: true}, -1500, 1500},
+		{"invert", TransformConfig{Invert: true}, 1500, -1500},
+		{"min", TransformConfig{Min: ptr(0)}, -1500, 0},
+		{"max", TransformConfig{Max: ptr(1000)}, 1500, 1000},
+		// scale before offset
+		{"scale offset", TransformConfig{Scale: ptr(2), Offset: 10}, 5, 20},
+		// offset before abs
+		{"offset abs", TransformConfig{Offset: -10, Abs: true}, 5, 5},
+		// abs before invert
+		{"abs invert", TransformConfig{Abs: true, Invert: true}, 5, -5},
+		// invert before clamp
+		{"invert min", TransformConfig{Invert: true, Min: ptr(0)}, 1500, 0},
+		{"invert max", TransformConfig{Invert: true, Max: ptr(0)}, -1500, 0},
+		{"all", TransformConfig{Scale: ptr(0.001), Offset: -1, Abs: true, Invert: true, Min: ptr(-2), Max: ptr(0)}, -3000, -2},
+	}
+
+	for _, tc := range tc {
+		assert.InDelta(t, tc.out, tc.transform.Apply(tc.in), 1e-9, tc.name)
+	}
+
+	assert.Error(t, (&TransformConfig{Min: ptr(1), Max: ptr(0)}).Validate())
+	assert.NoError(t, (&TransformConfig{Min: ptr(0), Max: ptr(0)}).Validate())
+}
+
+func TestTransformConfig(t *testing.T) {
+	var cc struct {
+		Power Config
+	}
+
+	require.NoError(t, util.DecodeOther(map[string]interface{}{
+		"power": map[string]interface{}{
+			"source": "const",
+			"value":  "-1500",
+			"transform": map[string]interface{}{
+				"scale":  0.001,
+				"invert": true,
+				"min":    0,
+			},
+		},
+	}, &cc))
+
+	assert.NotContains(t, cc.Power.Other, "transform")
+
+	fg, err := NewFloatGetterFromConfig(cc.Power)
+	require.NoError(t, err)
+
+	f, err := fg()
+	require.NoError(t, err)
+	assert.Equal(t, 1.5, f)
+
+	cc.Power.Transform.Max = ptr(1)
+
+	ig, err := NewIntGetterFromConfig(cc.Power)
+	require.NoError(t, err)
+
+	i, err := ig()
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), i)
+
+	cc.Power.Transform.Min = ptr(2)
+
+	_, err = NewFloatGetterFromConfig(cc.Power)
+	assert.Error(t, err)
+}
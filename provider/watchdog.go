@@ -0,0 +1,74 @@
+package provider
+
+import (
+	"sync"
+	"time"
+
+	"github.com/benbjohnson/clock"
+)
+
+// Watchdog restarts a provider whose data has been silent for longer than the timeout.
+// It is meant for providers receiving data in background like websocket readers which
+// may hang without returning an error.
+type Watchdog struct {
+	mu      sync.Mutex
+	clock   clock.Clock
+	timeout time.Duration
+	timer   *clock.Timer
+	updated time.Time
+	restart func(silent time.Duration)
+	stopped bool
+}
+
+// NewWatchdog creates a watchdog calling restart once timeout elapses without update
+func NewWatchdog(timeout time.Duration, restart func(silent time.Duration)) *Watchdog {
+	return newWatchdog(clock.New(), timeout, restart)
+}
+
+func newWatchdog(clock clock.Clock, timeout time.Duration, restart func(silent time.Duration)) *Watchdog {
+	w := &Watchdog{
+		clock:   clock,
+		timeout: timeout,
+		restart: restart,
+		updated: clock.Now(),
+	}
+
+	w.timer = clock.AfterFunc(timeout, w.expire)
+
+	return w
+}
+
+// Update records a data update
+func (w *Watchdog) Update() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.updated = w.clock.Now()
+	w.timer.Reset(w.timeout)
+}
+
+// Stop stops the watchdog
+func (w *Watchdog) Stop() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.stopped = true
+	w.timer.Stop()
+}
+
+func (w *Watchdog) expire() {
+	w.mu.Lock()
+	if w.stopped {
+		w.mu.Unlock()
+		return
+	}
+
+	silent := w.clock.Since(w.updated)
+
+	// restarted provider gets another timeout to deliver data
+	w.updated = w.clock.Now()
+	w.timer.Reset(w.timeout)
+	w.mu.Unlock()
+
+	w.restart(silent)
+}
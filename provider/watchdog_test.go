@@ -0,0 +1,75 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"nhooyr.io/websocket"
+)
+
+func TestWatchdog(t *testing.T) {
+	clock := clock.NewMock()
+
+	var restarts int
+	w := newWatchdog(clock, time.Minute, func(time.Duration) {
+		restarts++
+	})
+
+	// updated provider is not restarted
+	for i := 0; i < 5; i++ {
+		clock.Add(30 * time.Second)
+		w.Update()
+	}
+	assert.Equal(t, 0, restarts)
+
+	// silent provider is restarted
+	clock.Add(time.Minute)
+	assert.Equal(t, 1, restarts)
+
+	// and restarted again if silent after restart
+	clock.Add(time.Minute)
+	assert.Equal(t, 2, restarts)
+
+	w.Stop()
+	clock.Add(time.Minute)
+	assert.Equal(t, 2, restarts)
+}
+
+func TestSocketWatchdog(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var connections atomic.Int32
+
+	// server sends a single value and hangs
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c, err := websocket.Accept(w, r, nil)
+		require.NoError(t, err)
+		defer c.Close(websocket.StatusNormalClosure, "")
+
+		connections.Add(1)
+		_ = c.Write(ctx, websocket.MessageText, []byte("1"))
+
+		// returns once the client closes the connection
+		_, _, _ = c.Read(ctx)
+	}))
+	defer srv.Close()
+
+	p, err := NewSocketProviderFromConfig(map[string]any{
+		"uri":      "ws://" + srv.Listener.Addr().String(),
+		"watchdog": "100ms",
+	})
+	require.NoError(t, err)
+	defer p.(*Socket).watchdog.Stop()
+
+	require.Eventually(t, func() bool {
+		return connections.Load() >= 2
+	}, 5*time.Second, 10*time.Millisecond, "silent connection not restarted")
+}
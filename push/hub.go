@@ -9,6 +9,15 @@ import (
 	"github.com/evcc-io/evcc/util"
 )
 
+// TestEvent is the event sent to verify the notification setup
+const TestEvent = "test"
+
+// testTemplate is the default test event template
+var testTemplate = EventTemplateConfig{
+	Title: "evcc test notification",
+	Msg:   "Notifications are working.",
+}
+
 // Event is a notification event
 type Event struct {
 	Loadpoint *int // optional loadpoint id
@@ -39,8 +48,14 @@ func NewHub(cc map[string]EventTemplateConfig, cache *util.Cache) (*Hub, error)
 		}
 	}
 
+	definitions := make(map[string]EventTemplateConfig, len(cc)+1)
+	definitions[TestEvent] = testTemplate
+	for k, v := range cc {
+		definitions[k] = v
+	}
+
 	h := &Hub{
-		definitions: cc,
+		definitions: definitions,
 		cache:       cache,
 	}
 
@@ -77,6 +92,9 @@ func (h *Hub) Run(events <-chan Event, valueChan chan util.Param) {
 
 	for ev := range events {
 		if len(h.sender) == 0 {
+			if ev.Event == TestEvent {
+				log.WARN.Println("test notification: no messaging services configured")
+			}
 			continue
 		}
 
@@ -0,0 +1,104 @@
+package push
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/evcc-io/evcc/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type message struct {
+	Title   string `json:"title"`
+	Message string `json:"message"`
+}
+
+type testMessenger chan message
+
+func (m testMessenger) Send(title, msg string) {
+	m <- message{title, msg}
+}
+
+func runHub(t *testing.T, hub *Hub) chan<- Event {
+	events := make(chan Event)
+	valueChan := make(chan util.Param)
+
+	cache := util.NewCache()
+	go cache.Run(valueChan)
+	go hub.Run(events, valueChan)
+
+	t.Cleanup(func() { close(events) })
+
+	return events
+}
+
+func receive(t *testing.T, c <-chan message) message {
+	select {
+	case msg := <-c:
+		return msg
+	case <-time.After(time.Second):
+		require.Fail(t, "message not received")
+	}
+	return message{}
+}
+
+func TestHubTestNotification(t *testing.T) {
+	hub, err := NewHub(map[string]EventTemplateConfig{
+		"start": {Title: "Start", Msg: "Charging started"},
+	}, util.NewCache())
+	require.NoError(t, err)
+
+	m := make(testMessenger, 1)
+	hub.Add(m)
+
+	events := runHub(t, hub)
+
+	events <- Event{Event: TestEvent}
+	assert.Equal(t, message{testTemplate.Title, testTemplate.Msg}, receive(t, m))
+
+	events <- Event{Event: "start"}
+	assert.Equal(t, message{"Start", "Charging started"}, receive(t, m))
+}
+
+func TestHubTestNotificationOverride(t *testing.T) {
+	hub, err := NewHub(map[string]EventTemplateConfig{
+		TestEvent: {Title: "Hello", Msg: "World"},
+	}, util.NewCache())
+	require.NoError(t, err)
+
+	m := make(testMessenger, 1)
+	hub.Add(m)
+
+	runHub(t, hub) <- Event{Event: TestEvent}
+	assert.Equal(t, message{"Hello", "World"}, receive(t, m))
+}
+
+func TestWebhook(t *testing.T) {
+	received := make(chan message, 1)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "secret", r.Header.Get("Authorization"))
+
+		var msg message
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&msg))
+		received <- msg
+	}))
+	defer srv.Close()
+
+	_, err := NewFromConfig("webhook", nil)
+	assert.Error(t, err)
+
+	m, err := NewFromConfig("webhook", map[string]interface{}{
+		"uri":     srv.URL,
+		"headers": map[string]string{"Authorization": "secret"},
+	})
+	require.NoError(t, err)
+
+	m.Send("title", "msg")
+	assert.Equal(t, message{"title", "msg"}, receive(t, received))
+}
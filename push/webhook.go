@@ -0,0 +1,76 @@
+package push
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/evcc-io/evcc/util"
+	"github.com/evcc-io/evcc/util/request"
+)
+
+func init() {
+	registry.Add("webhook", NewWebhookFromConfig)
+}
+
+// Webhook implements a generic http webhook messenger sending a json payload
+type Webhook struct {
+	*request.Helper
+	log     *util.Logger
+	uri     string
+	headers map[string]string
+}
+
+// NewWebhookFromConfig creates new webhook messenger
+func NewWebhookFromConfig(other map[string]interface{}) (Messenger, error) {
+	var cc struct {
+		URI     string
+		Headers map[string]string
+	}
+
+	if err := util.DecodeOther(other, &cc); err != nil {
+		return nil, err
+	}
+
+	if cc.URI == "" {
+		return nil, errors.New("missing uri")
+	}
+
+	log := util.NewLogger("webhook")
+
+	headers := map[string]string{
+		"Content-Type": request.JSONContent,
+	}
+	for k, v := range cc.Headers {
+		headers[k] = v
+		log.Redact(v)
+	}
+
+	m := &Webhook{
+		Helper:  request.NewHelper(log),
+		log:     log,
+		uri:     cc.URI,
+		headers: headers,
+	}
+
+	return m, nil
+}
+
+// Send sends to all receivers
+func (m *Webhook) Send(title, msg string) {
+	data := struct {
+		Title   string `json:"title"`
+		Message string `json:"message"`
+	}{
+		Title:   title,
+		Message: msg,
+	}
+
+	req, err := request.New(http.MethodPost, m.uri, request.MarshalJSON(data), m.headers)
+	if err == nil {
+		_, err = m.DoBody(req)
+	}
+
+	if err != nil {
+		m.log.ERROR.Printf("webhook: %v", err)
+	}
+}
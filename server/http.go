@@ -81,8 +81,10 @@ func (s *HTTPd) Router() *mux.Router {
 	return s.Handler.(*mux.Router)
 }
 
-// RegisterSiteHandlers connects the http handlers to the site
-func (s *HTTPd) RegisterSiteHandlers(site site.API, cache *util.Cache) {
+// RegisterSiteHandlers connects the http handlers to the site.
+// Destructive endpoints, the vehicle account listing and the provider test, which executes scripts and commands
+// from the request, require the token as bearer authorization and are disabled if the token is empty.
+func (s *HTTPd) RegisterSiteHandlers(site site.API, cache *util.Cache, token string) {
 	router := s.Server.Handler.(*mux.Router)
 
 	// api
@@ -90,33 +92,41 @@ func (s *HTTPd) RegisterSiteHandlers(site site.API, cache *util.Cache) {
 	api.Use(jsonHandler)
 	api.Use(handlers.CompressHandler)
 	api.Use(handlers.CORS(
-		handlers.AllowedHeaders([]string{"Content-Type"}),
+		handlers.AllowedHeaders([]string{"Content-Type", "Authorization"}),
 	))
 
 	// site api
 	routes := map[string]route{
-		"health":         {[]string{"GET"}, "/health", healthHandler(site)},
-		"state":          {[]string{"GET"}, "/state", stateHandler(cache)},
-		"config":         {[]string{"GET"}, "/config/templates/{class:[a-z]+}", templatesHandler},
-		"products":       {[]string{"GET"}, "/config/products/{class:[a-z]+}", productsHandler},
-		"device":         {[]string{"GET"}, "/config/devices/{class:[a-z]+}/{id:[0-9.]+}", deviceHandler},
-		"devices":        {[]string{"GET"}, "/config/devices/{class:[a-z]+}", devicesHandler},
-		"newdevice":      {[]string{"POST", "OPTIONS"}, "/config/devices/{class:[a-z]+}", newDeviceHandler},
-		"updatedevice":   {[]string{"PUT", "OPTIONS"}, "/config/devices/{class:[a-z]+}/{id:[0-9.]+}", updateDeviceHandler},
-		"deletedevice":   {[]string{"DELETE", "OPTIONS"}, "/config/devices/{class:[a-z]+}/{id:[0-9.]+}", deleteDeviceHandler},
-		"testconfig":     {[]string{"POST", "OPTIONS"}, "/config/test/{class:[a-z]+}", testHandler},
-		"testdevice":     {[]string{"POST", "OPTIONS"}, "/config/test/{class:[a-z]+}/{id:[0-9.]+}", testHandler},
-		"buffersoc":      {[]string{"POST", "OPTIONS"}, "/buffersoc/{value:[0-9.]+}", floatHandler(site.SetBufferSoc, site.GetBufferSoc)},
-		"bufferstartsoc": {[]string{"POST", "OPTIONS"}, "/bufferstartsoc/{value:[0-9.]+}", floatHandler(site.SetBufferStartSoc, site.GetBufferStartSoc)},
-		"prioritysoc":    {[]string{"POST", "OPTIONS"}, "/prioritysoc/{value:[0-9.]+}", floatHandler(site.SetPrioritySoc, site.GetPrioritySoc)},
-		"residualpower":  {[]string{"POST", "OPTIONS"}, "/residualpower/{value:[-0-9.]+}", floatHandler(site.SetResidualPower, site.GetResidualPower)},
-		"smartcost":      {[]string{"POST", "OPTIONS"}, "/smartcostlimit/{value:[-0-9.]+}", floatHandler(site.SetSmartCostLimit, site.GetSmartCostLimit)},
-		"tariff":         {[]string{"GET"}, "/tariff/{tariff:[a-z]+}", tariffHandler(site)},
-		"sessions":       {[]string{"GET"}, "/sessions", sessionHandler},
-		"session1":       {[]string{"PUT", "OPTIONS"}, "/session/{id:[0-9]+}", updateSessionHandler},
-		"session2":       {[]string{"DELETE", "OPTIONS"}, "/session/{id:[0-9]+}", deleteSessionHandler},
-		"telemetry":      {[]string{"GET"}, "/settings/telemetry", boolGetHandler(telemetry.Enabled)},
-		"telemetry2":     {[]string{"POST", "OPTIONS"}, "/settings/telemetry/{value:[a-z]+}", boolHandler(telemetry.Enable, telemetry.Enabled)},
+		"health":           {[]string{"GET"}, "/health", healthHandler(site)},
+		"state":            {[]string{"GET"}, "/state", stateHandler(cache)},
+		"config":           {[]string{"GET"}, "/config/templates/{class:[a-z]+}", templatesHandler},
+		"products":         {[]string{"GET"}, "/config/products/{class:[a-z]+}", productsHandler},
+		"device":           {[]string{"GET"}, "/config/devices/{class:[a-z]+}/{id:[0-9.]+}", deviceHandler},
+		"devices":          {[]string{"GET"}, "/config/devices/{class:[a-z]+}", devicesHandler},
+		"accounts":         {[]string{"GET"}, "/config/accounts", tokenAuth(token, accountsHandler)},
+		"newdevice":        {[]string{"POST", "OPTIONS"}, "/config/devices/{class:[a-z]+}", newDeviceHandler},
+		"updatedevice":     {[]string{"PUT", "OPTIONS"}, "/config/devices/{class:[a-z]+}/{id:[0-9.]+}", updateDeviceHandler},
+		"deletedevice":     {[]string{"DELETE", "OPTIONS"}, "/config/devices/{class:[a-z]+}/{id:[0-9.]+}", deleteDeviceHandler},
+		"testconfig":       {[]string{"POST", "OPTIONS"}, "/config/test/{class:[a-z]+}", testHandler},
+		"testdevice":       {[]string{"POST", "OPTIONS"}, "/config/test/{class:[a-z]+}/{id:[0-9.]+}", testHandler},
+		"testprovider":     {[]string{"POST", "OPTIONS"}, "/config/test/provider/{type:[a-z]+}", tokenAuth(token, testProviderHandler)},
+		"buffersoc":        {[]string{"POST", "OPTIONS"}, "/buffersoc/{value:[0-9.]+}", floatHandler(site.SetBufferSoc, site.GetBufferSoc)},
+		"bufferstartsoc":   {[]string{"POST", "OPTIONS"}, "/bufferstartsoc/{value:[0-9.]+}", floatHandler(site.SetBufferStartSoc, site.GetBufferStartSoc)},
+		"prioritysoc":      {[]string{"POST", "OPTIONS"}, "/prioritysoc/{value:[0-9.]+}", floatHandler(site.SetPrioritySoc, site.GetPrioritySoc)},
+		"residualpower":    {[]string{"POST", "OPTIONS"}, "/residualpower/{value:[-0-9.]+}", floatHandler(site.SetResidualPower, site.GetResidualPower)},
+		"smartcost":        {[]string{"POST", "OPTIONS"}, "/smartcostlimit/{value:[-0-9.]+}", floatHandler(site.SetSmartCostLimit, site.GetSmartCostLimit)},
+		"tariff":           {[]string{"GET"}, "/tariff/{tariff:[a-z]+}", tariffHandler(site)},
+		"powerflow":        {[]string{"GET"}, "/powerflow", powerFlowHandler(site)},
+		"plansimulate":     {[]string{"POST", "OPTIONS"}, "/plan/simulate", planSimulationHandler},
+		"emergencystop":    {[]string{"POST", "OPTIONS"}, "/emergencystop/{value:[a-z]+}", boolHandler(site.SetEmergencyStop, site.GetEmergencyStop)},
+		"notificationtest": {[]string{"POST", "OPTIONS"}, "/notification/test", notificationTestHandler(site)},
+		"sessions":         {[]string{"GET"}, "/sessions", sessionHandler},
+		"sessiontags":      {[]string{"GET"}, "/sessions/tags", sessionTagsHandler},
+		"session1":         {[]string{"PUT", "OPTIONS"}, "/session/{id:[0-9]+}", updateSessionHandler},
+		"session2":         {[]string{"DELETE", "OPTIONS"}, "/session/{id:[0-9]+}", deleteSessionHandler},
+		"statisticsreset":  {[]string{"POST", "OPTIONS"}, "/statistics/reset", tokenAuth(token, statisticsResetHandler(site))},
+		"telemetry":        {[]string{"GET"}, "/settings/telemetry", boolGetHandler(telemetry.Enabled)},
+		"telemetry2":       {[]string{"POST", "OPTIONS"}, "/settings/telemetry/{value:[a-z]+}", boolHandler(telemetry.Enable, telemetry.Enabled)},
 	}
 
 	for _, r := range routes {
@@ -137,10 +147,22 @@ func (s *HTTPd) RegisterSiteHandlers(site site.API, cache *util.Cache) {
 			"targetsoc":        {[]string{"POST", "OPTIONS"}, "/target/soc/{value:[0-9]+}", intHandler(pass(lp.SetTargetSoc), lp.GetTargetSoc)},
 			"targettime":       {[]string{"POST", "OPTIONS"}, "/target/time/{time:[0-9TZ:.-]+}", targetTimeHandler(lp)},
 			"targettime2":      {[]string{"DELETE", "OPTIONS"}, "/target/time", targetTimeRemoveHandler(lp)},
+			"target":           {[]string{"POST", "OPTIONS"}, "/target/{soc:[0-9]+}/{time:[0-9TZ:.-]+}", targetHandler(lp)},
 			"plan":             {[]string{"GET"}, "/target/plan", planHandler(lp)},
 			"vehicle":          {[]string{"POST", "OPTIONS"}, "/vehicle/{vehicle:[1-9][0-9]*}", vehicleHandler(site, lp)},
 			"vehicle2":         {[]string{"DELETE", "OPTIONS"}, "/vehicle", vehicleRemoveHandler(lp)},
+			"vehicle3":         {[]string{"GET"}, "/vehicle", vehicleGetHandler(site, lp)},
 			"vehicleDetect":    {[]string{"PATCH", "OPTIONS"}, "/vehicle", vehicleDetectHandler(lp)},
+			"vehiclerefresh":   {[]string{"POST", "OPTIONS"}, "/vehicle/refresh", vehicleRefreshHandler(lp)},
+			"vehiclehealth":    {[]string{"GET"}, "/vehicle/health", vehicleHealthHandler(lp)},
+			"vehiclefuel":      {[]string{"GET"}, "/vehicle/fuel", vehicleFuelHandler(lp)},
+			"vehiclecable":     {[]string{"GET"}, "/vehicle/cable", vehicleCableHandler(lp)},
+			"vehicletemps":     {[]string{"GET"}, "/vehicle/temperatures", vehicleTemperaturesHandler(lp)},
+			"vehicledeparture": {[]string{"GET"}, "/vehicle/departure", vehicleDepartureHandler(lp)},
+			"chargerinfo":      {[]string{"GET"}, "/charger/info", chargerInfoHandler(lp)},
+			"sessionreset":     {[]string{"POST", "OPTIONS"}, "/session/reset", tokenAuth(token, sessionResetHandler(lp))},
+			"boost":            {[]string{"POST", "OPTIONS"}, "/boost/{value:[0-9]+}", boostHandler(lp)},
+			"boost2":           {[]string{"DELETE", "OPTIONS"}, "/boost", boostRemoveHandler(lp)},
 			"remotedemand":     {[]string{"POST", "OPTIONS"}, "/remotedemand/{demand:[a-z]+}/{source::[0-9a-zA-Z_-]+}", remoteDemandHandler(lp)},
 			"enableThreshold":  {[]string{"POST", "OPTIONS"}, "/enable/threshold/{value:-?[0-9.]+}", floatHandler(pass(lp.SetEnableThreshold), lp.GetEnableThreshold)},
 			"disableThreshold": {[]string{"POST", "OPTIONS"}, "/disable/threshold/{value:-?[0-9.]+}", floatHandler(pass(lp.SetDisableThreshold), lp.GetDisableThreshold)},
@@ -176,3 +198,68 @@ func (s *HTTPd) RegisterShutdownHandler(callback func()) {
 		api.Methods(r.Methods...).Path(r.Pattern).Handler(r.HandlerFunc)
 	}
 }
+
+// RegisterReloadHandler connects the config reload handler. Requests must provide the token as bearer authorization.
+func (s *HTTPd) RegisterReloadHandler(token string, callback func() (any, error)) {
+	router := s.Server.Handler.(*mux.Router)
+
+	// api
+	api := router.PathPrefix("/api").Subrouter()
+	api.Use(jsonHandler)
+	api.Use(handlers.CompressHandler)
+	api.Use(handlers.CORS(
+		handlers.AllowedHeaders([]string{"Content-Type", "Authorization"}),
+	))
+
+	routes := map[string]route{
+		"reload": {[]string{"POST", "OPTIONS"}, "/config/reload", tokenAuth(token, reloadHandler(callback))},
+	}
+
+	for _, r := range routes {
+		api.Methods(r.Methods...).Path(r.Pattern).Handler(r.HandlerFunc)
+	}
+}
+
+// RegisterEffectiveConfigHandler connects the handler returning the running configuration
+func (s *HTTPd) RegisterEffectiveConfigHandler(callback func() any) {
+	router := s.Server.Handler.(*mux.Router)
+
+	// api
+	api := router.PathPrefix("/api").Subrouter()
+	api.Use(jsonHandler)
+	api.Use(handlers.CompressHandler)
+	api.Use(handlers.CORS(
+		handlers.AllowedHeaders([]string{"Content-Type"}),
+	))
+
+	routes := map[string]route{
+		"effectiveconfig": {[]string{"GET"}, "/config/effective", func(w http.ResponseWriter, r *http.Request) {
+			jsonResult(w, callback())
+		}},
+	}
+
+	for _, r := range routes {
+		api.Methods(r.Methods...).Path(r.Pattern).Handler(r.HandlerFunc)
+	}
+}
+
+// RegisterDiagnosticsHandler connects the diagnostic handlers. Requests must provide the token as bearer authorization.
+func (s *HTTPd) RegisterDiagnosticsHandler(token string) {
+	router := s.Server.Handler.(*mux.Router)
+
+	// api
+	api := router.PathPrefix("/api").Subrouter()
+	api.Use(jsonHandler)
+	api.Use(handlers.CompressHandler)
+	api.Use(handlers.CORS(
+		handlers.AllowedHeaders([]string{"Content-Type", "Authorization"}),
+	))
+
+	routes := map[string]route{
+		"diagnosecache": {[]string{"GET"}, "/diagnose/cache", tokenAuth(token, cacheStateHandler)},
+	}
+
+	for _, r := range routes {
+		api.Methods(r.Methods...).Path(r.Pattern).Handler(r.HandlerFunc)
+	}
+}
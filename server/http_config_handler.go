@@ -11,9 +11,12 @@ import (
 	"github.com/evcc-io/evcc/api"
 	"github.com/evcc-io/evcc/charger"
 	"github.com/evcc-io/evcc/meter"
+	"github.com/evcc-io/evcc/provider"
+	"github.com/evcc-io/evcc/util"
 	"github.com/evcc-io/evcc/util/config"
 	"github.com/evcc-io/evcc/util/templates"
 	"github.com/evcc-io/evcc/vehicle"
+	"github.com/evcc-io/evcc/vehicle/mb"
 	"github.com/gorilla/mux"
 )
 
@@ -462,3 +465,45 @@ func testHandler(w http.ResponseWriter, r *http.Request) {
 
 	jsonResult(w, res)
 }
+
+// testProviderHandler executes a single read of a provider plugin config
+func testProviderHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	var req map[string]any
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		jsonError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	var cc provider.Config
+	if err := util.DecodeOther(req, &cc); err != nil {
+		jsonError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	res, err := provider.Test(vars["type"], cc)
+	if err != nil {
+		jsonError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	jsonResult(w, res)
+}
+
+// reloadHandler applies the configuration file to the running configuration
+func reloadHandler(callback func() (any, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		res, err := callback()
+		if err != nil {
+			jsonError(w, http.StatusBadRequest, err)
+			return
+		}
+		jsonResult(w, res)
+	}
+}
+
+// accountsHandler returns the logged in vehicle accounts, listing multiple accounts of a brand distinctly
+func accountsHandler(w http.ResponseWriter, r *http.Request) {
+	jsonResult(w, mb.Accounts())
+}
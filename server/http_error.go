@@ -0,0 +1,96 @@
+package server
+
+import (
+	"errors"
+	"net"
+	"net/http"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/mitchellh/mapstructure"
+	"golang.org/x/oauth2"
+)
+
+// ErrorCode is the stable machine-readable code of an api error
+type ErrorCode string
+
+// api error codes
+const (
+	CodeInvalidRequest    ErrorCode = "invalid-request"
+	CodeNotFound          ErrorCode = "not-found"
+	CodeNotAvailable      ErrorCode = "not-available"
+	CodeAuthRequired      ErrorCode = "auth-required"
+	CodeSponsorRequired   ErrorCode = "sponsor-required"
+	CodeDeviceUnreachable ErrorCode = "device-unreachable"
+	CodeRateLimited       ErrorCode = "rate-limited"
+	CodeInvalidConfig     ErrorCode = "invalid-config"
+	CodeInternal          ErrorCode = "internal"
+)
+
+// errorResponse is the api error envelope
+type errorResponse struct {
+	Error   string    `json:"error"` // human readable message
+	Code    ErrorCode `json:"code"`
+	Details []string  `json:"details,omitempty"`
+}
+
+// newErrorResponse creates the error envelope for the error and response status
+func newErrorResponse(status int, err error) errorResponse {
+	return errorResponse{
+		Error:   err.Error(),
+		Code:    errorCode(status, err),
+		Details: errorDetails(err),
+	}
+}
+
+// errorCode maps the error to its code, falling back to the response status
+func errorCode(status int, err error) ErrorCode {
+	var (
+		mse *mapstructure.Error
+		re  *oauth2.RetrieveError
+		ne  net.Error
+	)
+
+	switch {
+	case errors.Is(err, api.ErrSponsorRequired):
+		return CodeSponsorRequired
+	case errors.Is(err, api.ErrMissingCredentials), errors.Is(err, api.ErrLoginRequired), errors.As(err, &re):
+		return CodeAuthRequired
+	case errors.Is(err, api.ErrNotAvailable):
+		return CodeNotAvailable
+	case errors.Is(err, api.ErrMustRetry):
+		return CodeRateLimited
+	case errors.As(err, &mse):
+		return CodeInvalidConfig
+	case errors.As(err, &ne):
+		return CodeDeviceUnreachable
+	}
+
+	switch status {
+	case http.StatusNotFound:
+		return CodeNotFound
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return CodeAuthRequired
+	case http.StatusBadRequest:
+		return CodeInvalidRequest
+	default:
+		return CodeInternal
+	}
+}
+
+// errorDetails returns the individual messages of multiple errors
+func errorDetails(err error) []string {
+	var mse *mapstructure.Error
+	if errors.As(err, &mse) && len(mse.Errors) > 1 {
+		return mse.Errors
+	}
+
+	if je, ok := err.(interface{ Unwrap() []error }); ok {
+		var res []string
+		for _, err := range je.Unwrap() {
+			res = append(res, err.Error())
+		}
+		return res
+	}
+
+	return nil
+}
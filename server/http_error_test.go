@@ -0,0 +1,58 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/mitchellh/mapstructure"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+)
+
+func TestErrorEnvelope(t *testing.T) {
+	var decodeErr error
+	{
+		var res struct{ Power int }
+		decodeErr = mapstructure.Decode(map[string]any{"power": "foo"}, &res)
+		require.Error(t, decodeErr)
+	}
+
+	tc := []struct {
+		status  int
+		err     error
+		code    ErrorCode
+		details []string
+	}{
+		{http.StatusBadRequest, errors.New("invalid"), CodeInvalidRequest, nil},
+		{http.StatusNotFound, errors.New("missing"), CodeNotFound, nil},
+		{http.StatusInternalServerError, errors.New("failed"), CodeInternal, nil},
+		{http.StatusBadRequest, fmt.Errorf("charger: %w", api.ErrMissingCredentials), CodeAuthRequired, nil},
+		{http.StatusBadRequest, &oauth2.RetrieveError{Response: &http.Response{StatusCode: http.StatusUnauthorized}}, CodeAuthRequired, nil},
+		{http.StatusBadRequest, api.ErrSponsorRequired, CodeSponsorRequired, nil},
+		{http.StatusBadRequest, api.ErrNotAvailable, CodeNotAvailable, nil},
+		{http.StatusBadRequest, fmt.Errorf("meter: %w", &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}), CodeDeviceUnreachable, nil},
+		{http.StatusBadRequest, fmt.Errorf("vehicle: %w", api.ErrTimeout), CodeDeviceUnreachable, nil},
+		{http.StatusBadRequest, fmt.Errorf("charger: %w", decodeErr), CodeInvalidConfig, nil},
+		{http.StatusBadRequest, errors.Join(errors.New("foo"), errors.New("bar")), CodeInvalidRequest, []string{"foo", "bar"}},
+	}
+
+	for _, tc := range tc {
+		w := httptest.NewRecorder()
+		jsonError(w, tc.status, tc.err)
+
+		assert.Equal(t, tc.status, w.Code)
+
+		var res errorResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+		assert.Equal(t, tc.err.Error(), res.Error, "human readable message")
+		assert.Equal(t, tc.code, res.Code, tc.err)
+		assert.Equal(t, tc.details, res.Details, tc.err)
+	}
+}
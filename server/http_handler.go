@@ -1,12 +1,14 @@
 package server
 
 import (
+	"crypto/subtle"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io/fs"
 	"math"
 	"net/http"
+	"slices"
 	"strconv"
 	"strings"
 	"text/template"
@@ -14,7 +16,9 @@ import (
 
 	"github.com/evcc-io/evcc/api"
 	"github.com/evcc-io/evcc/core/loadpoint"
+	"github.com/evcc-io/evcc/core/planner"
 	"github.com/evcc-io/evcc/core/site"
+	"github.com/evcc-io/evcc/provider"
 	"github.com/evcc-io/evcc/server/assets"
 	"github.com/evcc-io/evcc/util"
 	"github.com/evcc-io/evcc/util/jq"
@@ -70,7 +74,7 @@ func jsonResult(w http.ResponseWriter, res interface{}) {
 
 func jsonError(w http.ResponseWriter, status int, err error) {
 	w.WriteHeader(status)
-	jsonWrite(w, map[string]interface{}{"error": err.Error()})
+	jsonWrite(w, newErrorResponse(status, err))
 }
 
 // pass converts a simple api without return value to api with nil error return value
@@ -199,7 +203,7 @@ func stateHandler(cache *util.Cache) http.HandlerFunc {
 	}
 }
 
-// healthHandler returns current charge mode
+// healthHandler returns the health status, vehicles requiring login and unhealthy devices
 func healthHandler(site site.API) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		if site == nil || !site.Healthy() {
@@ -209,6 +213,21 @@ func healthHandler(site site.API) http.HandlerFunc {
 
 		w.WriteHeader(http.StatusOK)
 		fmt.Fprintln(w, "OK")
+
+		for _, name := range site.LoginRequired() {
+			fmt.Fprintf(w, "%s: login required\n", name)
+		}
+
+		for _, name := range site.Unhealthy() {
+			fmt.Fprintf(w, "%s: unhealthy\n", name)
+		}
+	}
+}
+
+// powerFlowHandler returns the current power flows
+func powerFlowHandler(site site.API) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		jsonResult(w, site.GetPowerFlows())
 	}
 }
 
@@ -257,6 +276,38 @@ func chargeModeHandler(lp loadpoint.API) http.HandlerFunc {
 	}
 }
 
+// boostHandler starts boost for the given number of minutes
+func boostHandler(lp loadpoint.API) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+
+		minutes, err := strconv.Atoi(vars["value"])
+		if err == nil {
+			err = lp.Boost(time.Duration(minutes) * time.Minute)
+		}
+
+		if err != nil {
+			jsonError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		jsonResult(w, lp.GetBoostRemaining())
+	}
+}
+
+// boostRemoveHandler ends boost
+func boostRemoveHandler(lp loadpoint.API) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := lp.Boost(0); err != nil {
+			jsonError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		res := struct{}{}
+		jsonResult(w, res)
+	}
+}
+
 // phasesHandler updates minimum soc
 func phasesHandler(lp loadpoint.API) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -334,6 +385,45 @@ func targetTimeHandler(lp loadpoint.API) http.HandlerFunc {
 	}
 }
 
+// targetHandler sets target soc and time atomically
+func targetHandler(lp loadpoint.API) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+
+		soc, err := strconv.Atoi(vars["soc"])
+		if err != nil {
+			jsonError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		timeV, err := time.Parse(time.RFC3339, vars["time"])
+		if err != nil {
+			jsonError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		requiredDuration, plan, err := lp.SetTarget(soc, timeV)
+		if err != nil {
+			jsonError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		res := struct {
+			Soc      int       `json:"soc"`
+			Time     time.Time `json:"time"`
+			Duration int64     `json:"duration"`
+			Plan     api.Rates `json:"plan"`
+		}{
+			Soc:      lp.GetTargetSoc(),
+			Time:     lp.GetTargetTime(),
+			Duration: int64(requiredDuration.Seconds()),
+			Plan:     plan,
+		}
+
+		jsonResult(w, res)
+	}
+}
+
 // targetTimeRemoveHandler removes target soc
 func targetTimeRemoveHandler(lp loadpoint.API) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -357,7 +447,7 @@ func vehicleHandler(site site.API, lp loadpoint.API) http.HandlerFunc {
 
 		vehicles := site.GetVehicles()
 		if !ok || val < 1 || val > len(vehicles) || err != nil {
-			w.WriteHeader(http.StatusBadRequest)
+			jsonError(w, http.StatusBadRequest, fmt.Errorf("invalid vehicle: %s", valS))
 			return
 		}
 
@@ -374,6 +464,28 @@ func vehicleHandler(site site.API, lp loadpoint.API) http.HandlerFunc {
 	}
 }
 
+// vehicleGetHandler returns the active vehicle and how it has been assigned
+func vehicleGetHandler(site site.API, lp loadpoint.API) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		res := struct {
+			Vehicle string `json:"vehicle,omitempty"`
+			Index   int    `json:"index,omitempty"`
+			Manual  bool   `json:"manual"`
+		}{
+			Manual: lp.GetVehicleManual(),
+		}
+
+		if v := lp.GetVehicle(); v != nil {
+			res.Vehicle = v.Title()
+			if idx := slices.Index(site.GetVehicles(), v); idx >= 0 {
+				res.Index = idx + 1
+			}
+		}
+
+		jsonResult(w, res)
+	}
+}
+
 // vehicleRemoveHandler removes vehicle
 func vehicleRemoveHandler(lp loadpoint.API) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -383,6 +495,42 @@ func vehicleRemoveHandler(lp loadpoint.API) http.HandlerFunc {
 	}
 }
 
+// sessionResetHandler resets the session energy counters
+func sessionResetHandler(lp loadpoint.API) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		lp.ResetSessionEnergy()
+		res := struct{}{}
+		jsonResult(w, res)
+	}
+}
+
+// notificationTestHandler sends a test notification
+func notificationTestHandler(site site.API) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := site.SendTestNotification(); err != nil {
+			jsonError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		res := struct{}{}
+		jsonResult(w, res)
+	}
+}
+
+// statisticsResetHandler resets the lifetime statistics if confirmed
+func statisticsResetHandler(site site.API) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if confirm, _ := strconv.ParseBool(r.URL.Query().Get("confirm")); !confirm {
+			jsonError(w, http.StatusBadRequest, errors.New("reset requires confirm=true"))
+			return
+		}
+
+		site.ResetStatistics()
+		res := struct{}{}
+		jsonResult(w, res)
+	}
+}
+
 // vehicleDetectHandler starts vehicle detection
 func vehicleDetectHandler(lp loadpoint.API) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -392,6 +540,24 @@ func vehicleDetectHandler(lp loadpoint.API) http.HandlerFunc {
 	}
 }
 
+// vehicleRefreshHandler forces an immediate vehicle refresh
+func vehicleRefreshHandler(lp loadpoint.API) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := lp.RefreshVehicle(); err != nil {
+			status := http.StatusBadRequest
+			if errors.Is(err, api.ErrMustRetry) {
+				status = http.StatusTooManyRequests
+			}
+
+			jsonError(w, status, err)
+			return
+		}
+
+		res := struct{}{}
+		jsonResult(w, res)
+	}
+}
+
 // planHandler starts vehicle detection
 func planHandler(lp loadpoint.API) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -403,14 +569,14 @@ func planHandler(lp loadpoint.API) http.HandlerFunc {
 		}
 
 		if err != nil {
-			w.WriteHeader(http.StatusBadRequest)
+			jsonError(w, http.StatusBadRequest, err)
 			return
 		}
 
 		power := lp.GetMaxPower()
 		requiredDuration, plan, err := lp.GetPlan(targetTime, power)
 		if err != nil {
-			w.WriteHeader(http.StatusBadRequest)
+			jsonError(w, http.StatusBadRequest, err)
 			return
 		}
 
@@ -428,9 +594,151 @@ func planHandler(lp loadpoint.API) http.HandlerFunc {
 	}
 }
 
+// planSimulationHandler computes a plan for hypothetical inputs without affecting live state
+func planSimulationHandler(w http.ResponseWriter, r *http.Request) {
+	var req planner.Simulation
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		jsonError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	sim, err := planner.Simulate(req)
+	if err != nil {
+		jsonError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	res := struct {
+		Duration int64     `json:"duration"`
+		Plan     api.Rates `json:"plan"`
+		Energy   float64   `json:"energy"`
+		Cost     float64   `json:"cost"`
+	}{
+		Duration: int64(sim.Duration.Seconds()),
+		Plan:     sim.Plan,
+		Energy:   sim.Energy,
+		Cost:     sim.Cost,
+	}
+	jsonResult(w, res)
+}
+
+// chargerInfoHandler returns the charger's identity and diagnostic data
+func chargerInfoHandler(lp loadpoint.API) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		res, err := lp.GetChargerInfo()
+		if err != nil {
+			jsonError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		jsonResult(w, res)
+	}
+}
+
+// vehicleHealthHandler returns the active vehicle's auxiliary diagnostic data
+func vehicleHealthHandler(lp loadpoint.API) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		res, err := lp.GetVehicleHealth()
+		if err != nil {
+			jsonError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		jsonResult(w, res)
+	}
+}
+
+// vehicleFuelHandler returns the active vehicle's fuel data
+func vehicleFuelHandler(lp loadpoint.API) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		res, err := lp.GetVehicleFuel()
+		if err != nil {
+			jsonError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		jsonResult(w, res)
+	}
+}
+
+// vehicleCableHandler returns the active vehicle's charge cable status
+func vehicleCableHandler(lp loadpoint.API) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		res, err := lp.GetVehicleCable()
+		if err != nil {
+			jsonError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		jsonResult(w, res)
+	}
+}
+
+// vehicleTemperaturesHandler returns the active vehicle's temperatures and the climate lead time
+func vehicleTemperaturesHandler(lp loadpoint.API) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		temps, lead, err := lp.GetVehicleTemperatures()
+		if err != nil {
+			jsonError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		res := struct {
+			api.VehicleTemperatures
+			LeadTime int64 `json:"leadTime"` // seconds
+		}{
+			VehicleTemperatures: temps,
+			LeadTime:            int64(lead.Seconds()),
+		}
+
+		jsonResult(w, res)
+	}
+}
+
+// vehicleDepartureHandler returns the departure scheduled in the active vehicle compared to the plan
+func vehicleDepartureHandler(lp loadpoint.API) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		departure, conflict, err := lp.GetVehicleDeparture()
+		if err != nil {
+			jsonError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		res := struct {
+			Departure  time.Time `json:"departure"`
+			TargetTime time.Time `json:"targetTime"`
+			Conflict   bool      `json:"conflict"`
+		}{
+			Departure:  departure,
+			TargetTime: lp.GetTargetTime(),
+			Conflict:   conflict,
+		}
+
+		jsonResult(w, res)
+	}
+}
+
 // socketHandler attaches websocket handler to uri
 func socketHandler(hub *SocketHub) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		hub.ServeWebsocket(w, r)
 	}
 }
+
+// tokenAuth rejects requests not providing the token as bearer authorization
+func tokenAuth(token string, handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		auth, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || token == "" || subtle.ConstantTimeCompare([]byte(auth), []byte(token)) != 1 {
+			jsonError(w, http.StatusUnauthorized, errors.New("unauthorized"))
+			return
+		}
+
+		handler(w, r)
+	}
+}
+
+// cacheStateHandler returns the state of the provider caches
+func cacheStateHandler(w http.ResponseWriter, r *http.Request) {
+	jsonResult(w, provider.CacheStates())
+}
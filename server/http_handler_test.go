@@ -1,10 +1,21 @@
 package server
 
 import (
+	"encoding/json"
 	"math"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/evcc-io/evcc/core/loadpoint"
+	"github.com/evcc-io/evcc/core/site"
+	"github.com/evcc-io/evcc/provider"
+	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNaNInf(t *testing.T) {
@@ -15,3 +26,154 @@ func TestNaNInf(t *testing.T) {
 	encodeFloats(c)
 	assert.Equal(t, map[string]any{"foo": nil, "bar": nil}, c, "NaN not encoded as nil")
 }
+
+func TestStatisticsResetRequiresConfirm(t *testing.T) {
+	for _, q := range []string{"", "?confirm=false", "?confirm=foo"} {
+		w := httptest.NewRecorder()
+		statisticsResetHandler(nil).ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/statistics/reset"+q, nil))
+		assert.Equal(t, http.StatusBadRequest, w.Code, q)
+	}
+}
+
+type resetSite struct {
+	site.API
+	resets int
+}
+
+func (s *resetSite) ResetStatistics() {
+	s.resets++
+}
+
+func TestResetRequiresToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	lp := loadpoint.NewMockAPI(ctrl)
+	site := new(resetSite)
+
+	handlers := map[string]http.HandlerFunc{
+		"/session/reset":                 tokenAuth("secret", sessionResetHandler(lp)),
+		"/statistics/reset?confirm=true": tokenAuth("secret", statisticsResetHandler(site)),
+	}
+
+	for uri, handler := range handlers {
+		// rejected without matching credentials
+		for _, auth := range []string{"", "Bearer foo"} {
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, uri, nil)
+			req.Header.Set("Authorization", auth)
+			handler.ServeHTTP(w, req)
+			assert.Equal(t, http.StatusUnauthorized, w.Code, uri)
+		}
+	}
+
+	// disabled without token
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/session/reset", nil)
+	req.Header.Set("Authorization", "Bearer ")
+	tokenAuth("", sessionResetHandler(lp)).ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	// accepted with token
+	lp.EXPECT().ResetSessionEnergy()
+
+	for uri, handler := range handlers {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, uri, nil)
+		req.Header.Set("Authorization", "Bearer secret")
+		handler.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code, uri)
+	}
+
+	assert.Equal(t, 1, site.resets)
+}
+
+func TestProviderTestRequiresToken(t *testing.T) {
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/config/test/provider/float", strings.NewReader(`{"source":"script","cmd":"echo 1"}`))
+	tokenAuth("secret", testProviderHandler).ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestReloadRequiresToken(t *testing.T) {
+	var reloaded int
+	handler := tokenAuth("secret", reloadHandler(func() (any, error) {
+		reloaded++
+		return nil, nil
+	}))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/config/reload", nil))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Zero(t, reloaded)
+
+	w = httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/config/reload", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	handler.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, 1, reloaded)
+}
+
+func TestPlanSimulation(t *testing.T) {
+	body := `{
+		"rates": [
+			{"start": "2024-01-01T00:00:00Z", "end": "2024-01-01T01:00:00Z", "price": 0.3},
+			{"start": "2024-01-01T01:00:00Z", "end": "2024-01-01T02:00:00Z", "price": 0.1}
+		],
+		"soc": 50, "targetSoc": 60, "targetTime": "2024-01-01T02:00:00Z",
+		"capacity": 9, "power": 1000, "efficiency": 0.9
+	}`
+
+	w := httptest.NewRecorder()
+	planSimulationHandler(w, httptest.NewRequest(http.MethodPost, "/plan/simulate", strings.NewReader(body)))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"result": {
+		"duration": 3600,
+		"plan": [{"start": "2024-01-01T01:00:00Z", "end": "2024-01-01T02:00:00Z", "price": 0.1}],
+		"energy": 1, "cost": 0.1
+	}}`, w.Body.String())
+
+	// invalid inputs
+	w = httptest.NewRecorder()
+	planSimulationHandler(w, httptest.NewRequest(http.MethodPost, "/plan/simulate", strings.NewReader(`{}`)))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestCacheStateRequiresToken(t *testing.T) {
+	c := provider.Cached(func() (int64, error) { return 42, nil }, time.Hour)
+	_, _ = c()
+
+	handler := tokenAuth("secret", cacheStateHandler)
+
+	for _, auth := range []string{"", "secret", "Bearer", "Bearer foo"} {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/diagnose/cache", nil)
+		req.Header.Set("Authorization", auth)
+		handler.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusUnauthorized, w.Code, auth)
+	}
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/diagnose/cache", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	handler.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var res struct {
+		Result []provider.CacheState
+	}
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&res))
+
+	idx := slices.IndexFunc(res.Result, func(s provider.CacheState) bool {
+		return strings.HasPrefix(s.Source, "server/http_handler_test.go:")
+	})
+	require.GreaterOrEqual(t, idx, 0)
+
+	s := res.Result[idx]
+	assert.Equal(t, "int64", s.Type)
+	assert.Equal(t, "42", s.Value)
+	assert.Equal(t, time.Hour, s.Interval.Truncate(time.Hour))
+	assert.False(t, s.Updated.IsZero())
+	assert.GreaterOrEqual(t, s.Age, time.Duration(0))
+	assert.Less(t, s.Age, time.Minute)
+}
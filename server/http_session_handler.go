@@ -7,7 +7,9 @@ import (
 	"fmt"
 	"math"
 	"net/http"
-	"strings"
+	"net/url"
+	"strconv"
+	"time"
 
 	"github.com/evcc-io/evcc/api"
 	"github.com/evcc-io/evcc/core/session"
@@ -15,6 +17,7 @@ import (
 	"github.com/evcc-io/evcc/util/locale"
 	"github.com/gorilla/mux"
 	"golang.org/x/text/language"
+	"gorm.io/gorm"
 )
 
 func csvResult(ctx context.Context, w http.ResponseWriter, res any, filename string) {
@@ -28,38 +31,117 @@ func csvResult(ctx context.Context, w http.ResponseWriter, res any, filename str
 	}
 }
 
-// sessionHandler returns the list of charging sessions
+const (
+	// sessionsMaxLimit caps the page size of paginated session requests
+	sessionsMaxLimit   = 1000
+	sessionsDateFormat = "2006-01-02"
+)
+
+// parseSessionTime parses a date or RFC3339 timestamp
+func parseSessionTime(s string) (time.Time, error) {
+	if t, err := time.ParseInLocation(sessionsDateFormat, s, time.Local); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, s)
+}
+
+// sessionQuery applies the request's filters to the sessions query and returns the export file name.
+// Sessions can be filtered by year and month, date range (from inclusive, to exclusive), loadpoint, vehicle and tag.
+func sessionQuery(tx *gorm.DB, q url.Values) (*gorm.DB, string, error) {
+	tx = tx.Where("charged_kwh>=0.05")
+
+	filename := "session"
+	if year := q.Get("year"); year != "" {
+		filename += "-" + year
+		tx = tx.Where("STRFTIME('%Y', created) LIKE ?", year)
+
+		if month := fmt.Sprintf("%02s", q.Get("month")); month != "00" {
+			filename += "-" + month
+			tx = tx.Where("STRFTIME('%m', created) LIKE ?", month)
+		}
+	}
+
+	for key, cond := range map[string]string{"from": "created >= ?", "to": "created < ?"} {
+		if val := q.Get(key); val != "" {
+			ts, err := parseSessionTime(val)
+			if err != nil {
+				return nil, "", fmt.Errorf("invalid %s: %s", key, val)
+			}
+			tx = tx.Where(cond, ts)
+		}
+	}
+
+	if lp := q.Get("loadpoint"); lp != "" {
+		tx = tx.Where("loadpoint = ?", lp)
+	}
+	if vehicle := q.Get("vehicle"); vehicle != "" {
+		tx = tx.Where("vehicle = ?", vehicle)
+	}
+	if tag := q.Get("tag"); tag != "" {
+		tx = tx.Where("tag = ?", tag)
+	}
+
+	return tx, filename, nil
+}
+
+// sessionPage returns the requested page limit and offset. Without limit all sessions are returned.
+func sessionPage(q url.Values) (int, int, error) {
+	limit, offset := -1, 0
+
+	if val := q.Get("limit"); val != "" {
+		i, err := strconv.Atoi(val)
+		if err != nil || i <= 0 {
+			return 0, 0, fmt.Errorf("invalid limit: %s", val)
+		}
+		limit = min(i, sessionsMaxLimit)
+	}
+
+	if val := q.Get("offset"); val != "" {
+		i, err := strconv.Atoi(val)
+		if err != nil || i < 0 {
+			return 0, 0, fmt.Errorf("invalid offset: %s", val)
+		}
+		if limit < 0 {
+			limit = sessionsMaxLimit
+		}
+		offset = i
+	}
+
+	return limit, offset, nil
+}
+
+// sessionHandler returns the list of charging sessions.
+// Paginated requests return the total number of matching sessions in the X-Total-Count header.
 func sessionHandler(w http.ResponseWriter, r *http.Request) {
 	if db.Instance == nil {
 		jsonError(w, http.StatusBadRequest, errors.New("database offline"))
 		return
 	}
 
-	var (
-		res  session.Sessions
-		cond []string
-		args []any
-	)
-
-	push := func(field, val string) {
-		cond = append(cond, field)
-		args = append(args, val)
+	tx, filename, err := sessionQuery(db.Instance.Model(new(session.Session)), r.URL.Query())
+	if err != nil {
+		jsonError(w, http.StatusBadRequest, err)
+		return
 	}
 
-	filename := "session"
-	if year := r.URL.Query().Get("year"); year != "" {
-		filename += "-" + year
-		push("STRFTIME('%Y', created) LIKE ?", year)
+	limit, offset, err := sessionPage(r.URL.Query())
+	if err != nil {
+		jsonError(w, http.StatusBadRequest, err)
+		return
+	}
 
-		if month := fmt.Sprintf("%02s", r.URL.Query().Get("month")); month != "00" {
-			filename += "-" + month
-			push("STRFTIME('%m', created) LIKE ?", month)
+	if limit > 0 {
+		var total int64
+		if txn := tx.Session(&gorm.Session{}).Count(&total); txn.Error != nil {
+			jsonError(w, http.StatusInternalServerError, txn.Error)
+			return
 		}
+		w.Header().Set("X-Total-Count", strconv.FormatInt(total, 10))
 	}
 
 	// TODO support other databases than Sqlite
-	query := strings.Join(append([]string{"charged_kwh>=0.05"}, cond...), " AND ")
-	if txn := db.Instance.Where(query, args...).Order("created DESC").Find(&res); txn.Error != nil {
+	var res session.Sessions
+	if txn := tx.Order("created DESC").Order("id DESC").Limit(limit).Offset(offset).Find(&res); txn.Error != nil {
 		jsonError(w, http.StatusInternalServerError, txn.Error)
 		return
 	}
@@ -90,6 +172,28 @@ func sessionHandler(w http.ResponseWriter, r *http.Request) {
 	jsonResult(w, res)
 }
 
+// sessionTagsHandler returns the filtered charging sessions aggregated per tag
+func sessionTagsHandler(w http.ResponseWriter, r *http.Request) {
+	if db.Instance == nil {
+		jsonError(w, http.StatusBadRequest, errors.New("database offline"))
+		return
+	}
+
+	tx, _, err := sessionQuery(db.Instance.Model(new(session.Session)), r.URL.Query())
+	if err != nil {
+		jsonError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	var res session.Sessions
+	if txn := tx.Find(&res); txn.Error != nil {
+		jsonError(w, http.StatusInternalServerError, txn.Error)
+		return
+	}
+
+	jsonResult(w, res.ByTag())
+}
+
 // deleteSessionHandler removes session in sessions table with given id
 func deleteSessionHandler(w http.ResponseWriter, r *http.Request) {
 	if db.Instance == nil {
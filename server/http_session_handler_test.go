@@ -0,0 +1,142 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/evcc-io/evcc/core/session"
+	"github.com/evcc-io/evcc/server/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSessionPagination(t *testing.T) {
+	var err error
+	db.Instance, err = db.New("sqlite", ":memory:")
+	require.NoError(t, err)
+	defer func() { db.Instance = nil }()
+
+	sqlDB, err := db.Instance.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+
+	_, err = session.NewStore("garage", db.Instance)
+	require.NoError(t, err)
+
+	// one session per day, alternating loadpoints and vehicles
+	start := time.Date(2023, 12, 28, 12, 0, 0, 0, time.UTC)
+	for i := 0; i < 10; i++ {
+		s := session.Session{
+			Created:       start.AddDate(0, 0, i),
+			Loadpoint:     []string{"garage", "carport"}[i%2],
+			Vehicle:       []string{"blue", "red"}[i%2],
+			ChargedEnergy: float64(i + 1),
+		}
+		require.NoError(t, db.Instance.Create(&s).Error)
+	}
+
+	// below minimum energy
+	require.NoError(t, db.Instance.Create(&session.Session{Created: start, Loadpoint: "garage"}).Error)
+
+	get := func(query string) (session.Sessions, *httptest.ResponseRecorder) {
+		w := httptest.NewRecorder()
+		sessionHandler(w, httptest.NewRequest(http.MethodGet, "/sessions"+query, nil))
+
+		var res struct{ Result session.Sessions }
+		if w.Code == http.StatusOK {
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res), query)
+		}
+		return res.Result, w
+	}
+
+	energies := func(res session.Sessions) []float64 {
+		var e []float64
+		for _, s := range res {
+			e = append(e, s.ChargedEnergy)
+		}
+		return e
+	}
+
+	tc := []struct {
+		query    string
+		expected []float64
+		total    string
+	}{
+		{"", []float64{10, 9, 8, 7, 6, 5, 4, 3, 2, 1}, ""},
+		{"?limit=3", []float64{10, 9, 8}, "10"},
+		{"?limit=3&offset=3", []float64{7, 6, 5}, "10"},
+		{"?limit=3&offset=9", []float64{1}, "10"},
+		{"?limit=3&offset=10", nil, "10"},
+		{"?offset=8", []float64{2, 1}, "10"},
+		{"?loadpoint=garage", []float64{9, 7, 5, 3, 1}, ""},
+		{"?vehicle=red&limit=2&offset=1", []float64{8, 6}, "5"},
+		{"?from=2024-01-01&to=2024-01-03", []float64{6, 5}, ""},
+		{"?from=" + start.AddDate(0, 0, 8).Format(time.RFC3339), []float64{10, 9}, ""},
+		{"?year=2023", []float64{4, 3, 2, 1}, ""},
+		{"?year=2024&loadpoint=carport&limit=1", []float64{10}, "3"},
+	}
+
+	for _, tc := range tc {
+		res, w := get(tc.query)
+		require.Equal(t, http.StatusOK, w.Code, tc.query)
+		assert.Equal(t, tc.expected, energies(res), tc.query)
+		assert.Equal(t, tc.total, w.Header().Get("X-Total-Count"), tc.query)
+	}
+
+	// limit is capped
+	_, w := get(fmt.Sprintf("?limit=%d", 10*sessionsMaxLimit))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	for _, query := range []string{"?limit=0", "?limit=foo", "?offset=-1", "?from=yesterday", "?to=2024-13-01"} {
+		_, w := get(query)
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+	}
+}
+
+func TestSessionTags(t *testing.T) {
+	var err error
+	db.Instance, err = db.New("sqlite", ":memory:")
+	require.NoError(t, err)
+	defer func() { db.Instance = nil }()
+
+	sqlDB, err := db.Instance.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+
+	_, err = session.NewStore("garage", db.Instance)
+	require.NoError(t, err)
+
+	for i, tag := range []string{"blue", session.TagGuest, "blue", "0815"} {
+		s := session.Session{
+			Created:       time.Date(2024, 1, i+1, 12, 0, 0, 0, time.UTC),
+			Loadpoint:     "garage",
+			Tag:           tag,
+			ChargedEnergy: float64(i + 1),
+		}
+		require.NoError(t, db.Instance.Create(&s).Error)
+	}
+
+	get := func(query string) []session.TagSummary {
+		w := httptest.NewRecorder()
+		sessionTagsHandler(w, httptest.NewRequest(http.MethodGet, "/sessions/tags"+query, nil))
+		require.Equal(t, http.StatusOK, w.Code, query)
+
+		var res struct{ Result []session.TagSummary }
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res), query)
+		return res.Result
+	}
+
+	assert.Equal(t, []session.TagSummary{
+		{Tag: "0815", Sessions: 1, ChargedEnergy: 4},
+		{Tag: "blue", Sessions: 2, ChargedEnergy: 4},
+		{Tag: session.TagGuest, Sessions: 1, ChargedEnergy: 2},
+	}, get(""))
+
+	assert.Equal(t, []session.TagSummary{
+		{Tag: "blue", Sessions: 1, ChargedEnergy: 3},
+	}, get("?tag=blue&from=2024-01-02"))
+}
@@ -16,51 +16,127 @@ import (
 	influxlog "github.com/influxdata/influxdb-client-go/v2/log"
 )
 
+// influxDrop is the measurement name discarding a value
+const influxDrop = "-"
+
 // InfluxConfig is the influx db configuration
 type InfluxConfig struct {
-	URL      string
-	Database string
-	Token    string
-	Org      string
-	User     string
-	Password string
-	Interval time.Duration
+	URL          string
+	Database     string
+	Token        string
+	Org          string
+	User         string
+	Password     string
+	Interval     time.Duration
+	Prefix       string            // prefix added to all measurement names
+	Measurements map[string]string // measurement names by value key, "-" discards the value
+	Tags         map[string]string // static tags added to all points
+	TagNames     InfluxTagNames    // names of the loadpoint and vehicle tags
+	Batch        InfluxBatch       // write batching and retry
+}
+
+// InfluxTagNames are the names of the loadpoint and vehicle tags
+type InfluxTagNames struct {
+	Loadpoint, Vehicle string
+}
+
+// InfluxBatch configures write batching and the retry buffer, client defaults are used if zero
+type InfluxBatch struct {
+	Size          uint          // points per batch
+	FlushInterval time.Duration // maximum time before a batch is written
+	RetryInterval time.Duration // initial delay before failed batches are retried
+	RetryBuffer   uint          // maximum number of points buffered while writes fail
+	MaxRetries    uint          // maximum number of retries before failed batches are discarded
+}
+
+// options applies the batch configuration to the client options
+func (c InfluxBatch) options(o *influxdb2.Options) *influxdb2.Options {
+	if c.Size > 0 {
+		o.SetBatchSize(c.Size)
+	}
+	if c.FlushInterval > 0 {
+		o.SetFlushInterval(uint(c.FlushInterval.Milliseconds()))
+	}
+	if c.RetryInterval > 0 {
+		o.SetRetryInterval(uint(c.RetryInterval.Milliseconds()))
+	}
+	if c.RetryBuffer > 0 {
+		o.SetRetryBufferLimit(c.RetryBuffer)
+	}
+	if c.MaxRetries > 0 {
+		o.SetMaxRetries(c.MaxRetries)
+	}
+	return o
 }
 
 // Influx is a influx publisher
 type Influx struct {
 	sync.Mutex
-	log      *util.Logger
-	clock    clock.Clock
-	client   influxdb2.Client
-	org      string
-	database string
+	log          *util.Logger
+	clock        clock.Clock
+	client       influxdb2.Client
+	org          string
+	database     string
+	prefix       string
+	measurements map[string]string // lower case keys
+	tags         map[string]string
+	tagNames     InfluxTagNames
 }
 
 // NewInfluxClient creates new publisher for influx
-func NewInfluxClient(url, token, org, user, password, database string) *Influx {
+func NewInfluxClient(conf InfluxConfig) *Influx {
 	log := util.NewLogger("influx")
 
 	// InfluxDB v1 compatibility
-	if token == "" && user != "" {
-		token = fmt.Sprintf("%s:%s", user, password)
+	token := conf.Token
+	if token == "" && conf.User != "" {
+		token = fmt.Sprintf("%s:%s", conf.User, conf.Password)
 	}
 
-	options := influxdb2.DefaultOptions().SetPrecision(time.Second)
-	client := influxdb2.NewClientWithOptions(url, token, options)
+	options := conf.Batch.options(influxdb2.DefaultOptions().SetPrecision(time.Second))
+	client := influxdb2.NewClientWithOptions(conf.URL, token, options)
 
 	// handle error logging in writer
 	influxlog.Log = nil
 
+	// config keys are case-insensitive
+	measurements := make(map[string]string, len(conf.Measurements))
+	for k, v := range conf.Measurements {
+		measurements[strings.ToLower(k)] = v
+	}
+
+	tagNames := conf.TagNames
+	if tagNames.Loadpoint == "" {
+		tagNames.Loadpoint = "loadpoint"
+	}
+	if tagNames.Vehicle == "" {
+		tagNames.Vehicle = "vehicle"
+	}
+
 	return &Influx{
-		log:      log,
-		clock:    clock.New(),
-		client:   client,
-		org:      org,
-		database: database,
+		log:          log,
+		clock:        clock.New(),
+		client:       client,
+		org:          conf.Org,
+		database:     conf.Database,
+		prefix:       conf.Prefix,
+		measurements: measurements,
+		tags:         conf.Tags,
+		tagNames:     tagNames,
 	}
 }
 
+// measurement returns the measurement name for the value key, false if the value is discarded
+func (m *Influx) measurement(key string) (string, bool) {
+	if name, ok := m.measurements[strings.ToLower(key)]; ok {
+		if name == influxDrop {
+			return "", false
+		}
+		key = name
+	}
+	return m.prefix + key, true
+}
+
 // pointWriter is the minimal interface for influxdb2 api.Writer
 type pointWriter interface {
 	WritePoint(point *write.Point)
@@ -68,8 +144,13 @@ type pointWriter interface {
 
 // writePoint asynchronously writes a point to influx
 func (m *Influx) writePoint(writer pointWriter, key string, fields map[string]any, tags map[string]string) {
-	m.log.TRACE.Printf("write %s=%v (%v)", key, fields, tags)
-	writer.WritePoint(influxdb2.NewPoint(key, tags, fields, m.clock.Now()))
+	name, ok := m.measurement(key)
+	if !ok {
+		return
+	}
+
+	m.log.TRACE.Printf("write %s=%v (%v)", name, fields, tags)
+	writer.WritePoint(influxdb2.NewPoint(name, tags, fields, m.clock.Now()))
 }
 
 // writeComplexPoint asynchronously writes a point to influx
@@ -149,13 +230,17 @@ func (m *Influx) Run(site site.API, in <-chan util.Param) {
 
 	// add points to batch for async writing
 	for param := range in {
-		tags := make(map[string]string)
+		tags := make(map[string]string, len(m.tags)+2)
+		for k, v := range m.tags {
+			tags[k] = v
+		}
+
 		if param.Loadpoint != nil {
 			lp := site.Loadpoints()[*param.Loadpoint]
 
-			tags["loadpoint"] = lp.Title()
+			tags[m.tagNames.Loadpoint] = lp.Title()
 			if v := lp.GetVehicle(); v != nil {
-				tags["vehicle"] = v.Title()
+				tags[m.tagNames.Vehicle] = v.Title()
 			}
 		}
 
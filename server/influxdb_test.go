@@ -1,13 +1,20 @@
 package server
 
 import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/benbjohnson/clock"
 	"github.com/evcc-io/evcc/util"
 	inf2 "github.com/influxdata/influxdb-client-go/v2"
 	"github.com/influxdata/influxdb-client-go/v2/api/write"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 type influxWriter struct {
@@ -88,3 +95,99 @@ func TestInfluxTypes(t *testing.T) {
 		w.finish()
 	}
 }
+
+type lineWriter []string
+
+func (w *lineWriter) WritePoint(p *write.Point) {
+	*w = append(*w, strings.TrimSpace(write.PointToLineProtocol(p, time.Second)))
+}
+
+func TestInfluxMeasurements(t *testing.T) {
+	m := NewInfluxClient(InfluxConfig{
+		Prefix: "evcc_",
+		Measurements: map[string]string{
+			"chargepower":     "power", // lower case keys from config
+			"vehicleOdometer": influxDrop,
+		},
+	})
+	m.clock = clock.NewMock()
+
+	var w lineWriter
+	m.writeComplexPoint(&w, util.Param{Key: "chargePower", Val: 1200.0}, map[string]string{"loadpoint": "Garage"})
+	m.writeComplexPoint(&w, util.Param{Key: "vehicleOdometer", Val: 12345.0}, nil)
+	m.writeComplexPoint(&w, util.Param{Key: "pvPower", Val: 3000}, map[string]string{"site": "home"})
+
+	assert.Equal(t, lineWriter{
+		"evcc_power,loadpoint=Garage value=1200 0",
+		"evcc_pvPower,site=home value=3000i 0",
+	}, w)
+}
+
+func TestInfluxBatchRetry(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		requests int
+		batches  []string
+	)
+
+	failed := make(chan struct{})
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		b, _ := io.ReadAll(req.Body)
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		// first write fails and is buffered for retry
+		if requests++; requests == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			close(failed)
+			return
+		}
+
+		batches = append(batches, strings.TrimSpace(string(b)))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	m := NewInfluxClient(InfluxConfig{
+		URL:      srv.URL,
+		Database: "evcc",
+		Tags:     map[string]string{"site": "home"},
+		Batch: InfluxBatch{
+			Size:          2,
+			FlushInterval: time.Hour,
+			RetryInterval: time.Millisecond,
+		},
+	})
+	m.clock = clock.NewMock()
+
+	in := make(chan util.Param)
+	done := make(chan struct{})
+
+	go func() {
+		m.Run(nil, in)
+		close(done)
+	}()
+
+	in <- util.Param{Key: "pvPower", Val: 1000.0}
+	in <- util.Param{Key: "gridPower", Val: -200.0}
+
+	<-failed
+	time.Sleep(10 * time.Millisecond)
+
+	in <- util.Param{Key: "pvPower", Val: 1100.0}
+	in <- util.Param{Key: "gridPower", Val: -300.0}
+
+	close(in)
+	<-done
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	require.Equal(t, 3, requests)
+	assert.Equal(t, []string{
+		"pvPower,site=home value=1000 0\ngridPower,site=home value=-200 0",
+		"pvPower,site=home value=1100 0\ngridPower,site=home value=-300 0",
+	}, batches)
+}
@@ -136,6 +136,13 @@ func (m *MQTT) listenSetters(topic string, site site.API, lp loadpoint.API) {
 		}
 		return err
 	})
+	m.Handler.ListenSetter(topic+"/boost", func(payload string) error {
+		minutes, err := strconv.Atoi(payload)
+		if err == nil {
+			err = lp.Boost(time.Duration(minutes) * time.Minute)
+		}
+		return err
+	})
 	m.Handler.ListenSetter(topic+"/minSoc", func(payload string) error {
 		soc, err := strconv.Atoi(payload)
 		if err == nil {
@@ -202,6 +209,9 @@ func (m *MQTT) listenSetters(topic string, site site.API, lp loadpoint.API) {
 		}
 		return err
 	})
+	m.Handler.ListenSetter(topic+"/vehicleRefresh", func(payload string) error {
+		return lp.RefreshVehicle()
+	})
 	m.Handler.ListenSetter(topic+"/enableThreshold", func(payload string) error {
 		threshold, err := parseFloat(payload)
 		if err == nil {
@@ -39,6 +39,10 @@ func NewEleringFromConfig(other map[string]interface{}) (api.Tariff, error) {
 		return nil, err
 	}
 
+	if err := cc.embed.init(); err != nil {
+		return nil, err
+	}
+
 	if cc.Region == "" {
 		return nil, errors.New("missing region")
 	}
@@ -88,7 +92,7 @@ func (t *Elering) run(done chan error) {
 			ar := api.Rate{
 				Start: ts.Local(),
 				End:   ts.Add(time.Hour).Local(),
-				Price: t.totalPrice(r.Price / 1e3),
+				Price: t.totalPrice(r.Price/1e3, ts),
 			}
 			data = append(data, ar)
 		}
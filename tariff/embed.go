@@ -1,10 +1,76 @@
 package tariff
 
+import (
+	"fmt"
+	"time"
+
+	"github.com/evcc-io/evcc/tariff/fixed"
+)
+
 type embed struct {
 	Charges float64 `mapstructure:"charges"`
 	Tax     float64 `mapstructure:"tax"`
+	Fees    []Fee   `mapstructure:"fees"` // time-varying charges, e.g. day/night grid fees
+
+	fees fixed.Zones
+}
+
+// Fee is an additional per-kWh charge applied during the given days and hours
+type Fee struct {
+	Charges     float64
+	Days, Hours string
+}
+
+// init parses the fee schedules
+func (t *embed) init() error {
+	for i, f := range t.Fees {
+		days, err := fixed.ParseDays(f.Days)
+		if err != nil {
+			return fmt.Errorf("fee %d: %w", i+1, err)
+		}
+
+		hours, err := fixed.ParseTimeRanges(f.Hours)
+		if err != nil && f.Hours != "" {
+			return fmt.Errorf("fee %d: %w", i+1, err)
+		}
+
+		if len(hours) == 0 {
+			hours = []fixed.TimeRange{{}}
+		}
+
+		for _, h := range hours {
+			t.fees = append(t.fees, fixed.Zone{
+				Price: f.Charges,
+				Days:  days,
+				Hours: h,
+			})
+		}
+	}
+
+	return nil
+}
+
+// adjusted returns true if charges, fees or tax are configured
+func (t *embed) adjusted() bool {
+	return t.Charges != 0 || t.Tax != 0 || len(t.fees) > 0
+}
+
+// fee returns the time-varying charges at the given time, later fees take precedence over earlier ones
+func (t *embed) fee(ts time.Time) float64 {
+	ts = ts.Local()
+	hm := fixed.HourMin{Hour: ts.Hour(), Min: ts.Minute()}
+
+	zones := t.fees.ForDay(fixed.Day(ts.Weekday()))
+	for i := len(zones) - 1; i >= 0; i-- {
+		if zones[i].Hours.Contains(hm) {
+			return zones[i].Price
+		}
+	}
+
+	return 0
 }
 
-func (t *embed) totalPrice(price float64) float64 {
-	return (price + t.Charges) * (1 + t.Tax)
+// totalPrice returns the price including charges and tax for the rate starting at the given time
+func (t *embed) totalPrice(price float64, ts time.Time) float64 {
+	return (price + t.Charges + t.fee(ts)) * (1 + t.Tax)
 }
@@ -0,0 +1,79 @@
+package tariff
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evcc-io/evcc/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTotalPrice(t *testing.T) {
+	// monday
+	at := func(day, hour int) time.Time {
+		return time.Date(2024, 1, day, hour, 0, 0, 0, time.Local)
+	}
+
+	var cc struct {
+		embed `mapstructure:",squash"`
+	}
+
+	require.NoError(t, util.DecodeOther(map[string]interface{}{
+		"charges": 0.1,
+		"tax":     0.2,
+		"fees": []map[string]interface{}{
+			{"charges": 0.05}, // base grid fee
+			{"charges": 0.08, "hours": "6-22", "days": "Mon-Fri"},     // weekday day fee
+			{"charges": 0.02, "hours": "0-6,22-0", "days": "Sat,Sun"}, // weekend night fee
+		},
+	}, &cc))
+	require.NoError(t, cc.embed.init())
+
+	tc := []struct {
+		ts    time.Time
+		price float64
+	}{
+		{at(1, 3), (0.2 + 0.1 + 0.05) * 1.2},  // weekday night
+		{at(1, 12), (0.2 + 0.1 + 0.08) * 1.2}, // weekday day
+		{at(1, 22), (0.2 + 0.1 + 0.05) * 1.2}, // weekday night
+		{at(6, 3), (0.2 + 0.1 + 0.02) * 1.2},  // weekend night
+		{at(6, 12), (0.2 + 0.1 + 0.05) * 1.2}, // weekend day
+		{at(7, 23), (0.2 + 0.1 + 0.02) * 1.2}, // weekend night
+	}
+
+	for _, tc := range tc {
+		assert.InDelta(t, tc.price, cc.embed.totalPrice(0.2, tc.ts), 1e-9, tc.ts)
+	}
+}
+
+func TestTotalPriceInvalidFee(t *testing.T) {
+	e := embed{Fees: []Fee{{Charges: 0.1, Hours: "22-6"}}}
+	assert.Error(t, e.init())
+}
+
+func TestConfigurableFees(t *testing.T) {
+	tf, err := NewConfigurableFromConfig(map[string]interface{}{
+		"price": map[string]interface{}{
+			"source": "const",
+			"value":  0.3,
+		},
+		"fees": []map[string]interface{}{
+			{"charges": 0.1, "hours": "0-6"},
+		},
+	})
+	require.NoError(t, err)
+
+	rates, err := tf.Rates()
+	require.NoError(t, err)
+	require.Len(t, rates, 48)
+
+	for _, r := range rates {
+		price := 0.3
+		if r.Start.Hour() < 6 {
+			price = 0.4
+		}
+		assert.InDelta(t, price, r.Price, 1e-9, r.Start)
+		assert.Equal(t, time.Hour, r.End.Sub(r.Start))
+	}
+}
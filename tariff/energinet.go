@@ -38,6 +38,10 @@ func NewEnerginetFromConfig(other map[string]interface{}) (api.Tariff, error) {
 		return nil, err
 	}
 
+	if err := cc.embed.init(); err != nil {
+		return nil, err
+	}
+
 	if cc.Region == "" {
 		return nil, errors.New("missing region")
 	}
@@ -87,7 +91,7 @@ func (t *Energinet) run(done chan error) {
 			ar := api.Rate{
 				Start: date.Local(),
 				End:   date.Add(time.Hour).Local(),
-				Price: t.totalPrice(r.SpotPriceDKK / 1e3),
+				Price: t.totalPrice(r.SpotPriceDKK/1e3, date),
 			}
 			data = append(data, ar)
 		}
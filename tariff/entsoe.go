@@ -44,6 +44,10 @@ func NewEntsoeFromConfig(other map[string]interface{}) (api.Tariff, error) {
 		return nil, err
 	}
 
+	if err := cc.embed.init(); err != nil {
+		return nil, err
+	}
+
 	if cc.Securitytoken == "" {
 		return nil, errors.New("missing securitytoken")
 	}
@@ -160,7 +164,7 @@ func (t *Entsoe) run(done chan error) {
 			ar := api.Rate{
 				Start: r.Start,
 				End:   r.End,
-				Price: t.totalPrice(r.Value),
+				Price: t.totalPrice(r.Value, r.Start),
 			}
 			data = append(data, ar)
 		}
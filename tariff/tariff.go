@@ -31,6 +31,10 @@ func NewConfigurableFromConfig(other map[string]interface{}) (api.Tariff, error)
 		return nil, err
 	}
 
+	if err := cc.embed.init(); err != nil {
+		return nil, err
+	}
+
 	priceG, err := provider.NewFloatGetterFromConfig(cc.Price)
 	if err != nil {
 		return nil, fmt.Errorf("price: %w", err)
@@ -51,12 +55,29 @@ func (t *Tariff) Rates() (api.Rates, error) {
 		return nil, err
 	}
 
-	res := api.Rates{
-		{
-			Start: now.BeginningOfHour(),
-			End:   now.BeginningOfHour().Add(48 * time.Hour),
-			Price: t.totalPrice(price),
-		},
+	start := now.BeginningOfHour()
+
+	if len(t.fees) == 0 {
+		res := api.Rates{
+			{
+				Start: start,
+				End:   start.Add(48 * time.Hour),
+				Price: t.totalPrice(price, start),
+			},
+		}
+
+		return res, nil
+	}
+
+	// split into hourly rates to apply time-varying fees
+	res := make(api.Rates, 0, 48)
+	for i := 0; i < 48; i++ {
+		ts := start.Add(time.Duration(i) * time.Hour)
+		res = append(res, api.Rate{
+			Start: ts,
+			End:   ts.Add(time.Hour),
+			Price: t.totalPrice(price, ts),
+		})
 	}
 
 	return res, nil
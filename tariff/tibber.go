@@ -40,6 +40,10 @@ func NewTibberFromConfig(other map[string]interface{}) (api.Tariff, error) {
 		return nil, err
 	}
 
+	if err := cc.embed.init(); err != nil {
+		return nil, err
+	}
+
 	if cc.Token == "" {
 		return nil, errors.New("missing token")
 	}
@@ -114,8 +118,8 @@ func (t *Tibber) rates(pi []tibber.Price) api.Rates {
 	data := make(api.Rates, 0, len(pi))
 	for _, r := range pi {
 		price := r.Total
-		if t.Charges != 0 || t.Tax != 0 {
-			price = t.totalPrice(r.Energy)
+		if t.adjusted() {
+			price = t.totalPrice(r.Energy, r.StartsAt)
 		}
 		ar := api.Rate{
 			Start: r.StartsAt.Local(),
@@ -0,0 +1,166 @@
+package oauth
+
+import (
+	"errors"
+	"math"
+	"sync"
+	"time"
+
+	"dario.cat/mergo"
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/util"
+	"golang.org/x/oauth2"
+)
+
+// backoffDuration is the initial retry delay after a failed background refresh
+const backoffDuration = 5 * time.Second
+
+// RefresherFunc is an adapter to use a function as TokenRefresher
+type RefresherFunc func(token *oauth2.Token) (*oauth2.Token, error)
+
+func (f RefresherFunc) RefreshToken(token *oauth2.Token) (*oauth2.Token, error) {
+	return f(token)
+}
+
+// BackgroundTokenSource refreshes the token in background the margin before it expires.
+// Token reads return the current token without waiting for the network unless the token has expired.
+type BackgroundTokenSource struct {
+	mu        sync.Mutex
+	log       *util.Logger
+	clock     clock.Clock
+	token     *oauth2.Token
+	refresher TokenRefresher
+	margin    time.Duration
+	timer     *clock.Timer
+	failed    int
+	stopped   bool
+}
+
+// NewBackgroundTokenSource creates a token source refreshing the token the margin before expiry
+func NewBackgroundTokenSource(log *util.Logger, token *oauth2.Token, refresher TokenRefresher, margin time.Duration) *BackgroundTokenSource {
+	return newBackgroundTokenSource(log, clock.New(), token, refresher, margin)
+}
+
+func newBackgroundTokenSource(log *util.Logger, clock clock.Clock, token *oauth2.Token, refresher TokenRefresher, margin time.Duration) *BackgroundTokenSource {
+	ts := &BackgroundTokenSource{
+		log:       log,
+		clock:     clock,
+		token:     token,
+		refresher: refresher,
+		margin:    margin,
+	}
+
+	ts.mu.Lock()
+	ts.schedule(ts.next())
+	ts.mu.Unlock()
+
+	return ts
+}
+
+// Token implements the oauth2.TokenSource interface
+func (ts *BackgroundTokenSource) Token() (*oauth2.Token, error) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	if ts.valid() {
+		return ts.token, nil
+	}
+
+	// background refresh has not succeeded in time
+	token, err := ts.refresher.RefreshToken(ts.token)
+	if err == nil {
+		err = ts.update(token)
+	}
+
+	return ts.token, err
+}
+
+// Stop stops the background refresh
+func (ts *BackgroundTokenSource) Stop() {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	ts.stopped = true
+	if ts.timer != nil {
+		ts.timer.Stop()
+	}
+}
+
+// valid returns true if the token has not expired (no mutex)
+func (ts *BackgroundTokenSource) valid() bool {
+	return ts.token != nil && ts.token.AccessToken != "" &&
+		(ts.token.Expiry.IsZero() || ts.clock.Now().Before(ts.token.Expiry))
+}
+
+// next returns the duration until the background refresh is due (no mutex)
+func (ts *BackgroundTokenSource) next() time.Duration {
+	if ts.token == nil || ts.token.Expiry.IsZero() {
+		return -1
+	}
+	return max(0, ts.token.Expiry.Add(-ts.margin).Sub(ts.clock.Now()))
+}
+
+// schedule schedules the background refresh, negative durations disable refreshing (no mutex)
+func (ts *BackgroundTokenSource) schedule(d time.Duration) {
+	if ts.timer != nil {
+		ts.timer.Stop()
+	}
+
+	if d < 0 || ts.stopped {
+		return
+	}
+
+	ts.timer = ts.clock.AfterFunc(d, ts.refresh)
+}
+
+// update replaces the token and schedules the next refresh (no mutex)
+func (ts *BackgroundTokenSource) update(token *oauth2.Token) error {
+	if token.AccessToken == "" {
+		return errors.New("token refresh failed to obtain access token")
+	}
+
+	// prevent wiping the refresh token
+	res := new(oauth2.Token)
+	if ts.token != nil {
+		*res = *ts.token
+	}
+	if err := mergo.Merge(res, token, mergo.WithOverride); err != nil {
+		return err
+	}
+
+	ts.token = res
+	ts.failed = 0
+	ts.schedule(ts.next())
+
+	return nil
+}
+
+// refresh refreshes the token without blocking token reads. Failed refreshes are retried with back-off
+// while the current token remains in use until it expires.
+func (ts *BackgroundTokenSource) refresh() {
+	ts.mu.Lock()
+	current := ts.token
+	ts.mu.Unlock()
+
+	token, err := ts.refresher.RefreshToken(current)
+
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	// token has been refreshed on the request path meanwhile
+	if ts.token != current {
+		return
+	}
+
+	if err == nil {
+		err = ts.update(token)
+	}
+
+	if err != nil {
+		d := min(backoffDuration*time.Duration(math.Pow(2, float64(ts.failed))), max(ts.margin, backoffDuration))
+		ts.failed++
+
+		ts.log.WARN.Printf("token refresh: %v, retry in %v", err, d)
+		ts.schedule(d)
+	}
+}
@@ -0,0 +1,131 @@
+package oauth
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+)
+
+// testRefresher records refreshes and returns tokens valid for an hour
+type testRefresher struct {
+	clock   *clock.Mock
+	err     error
+	calls   chan *oauth2.Token
+	counter int
+}
+
+func (r *testRefresher) RefreshToken(token *oauth2.Token) (*oauth2.Token, error) {
+	defer func() { r.calls <- token }()
+
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	r.counter++
+	return &oauth2.Token{
+		AccessToken: string(rune('a' + r.counter)),
+		Expiry:      r.clock.Now().Add(time.Hour),
+	}, nil
+}
+
+// waitFailed waits until the background refresh has failed the given number of times
+func waitFailed(t *testing.T, ts *BackgroundTokenSource, failed int) {
+	assert.Eventually(t, func() bool {
+		ts.mu.Lock()
+		defer ts.mu.Unlock()
+		return ts.failed == failed
+	}, time.Second, time.Millisecond)
+}
+
+// waitToken waits until the token source returns the given access token
+func waitToken(t *testing.T, ts *BackgroundTokenSource, access string) {
+	assert.Eventually(t, func() bool {
+		res, err := ts.Token()
+		return err == nil && res.AccessToken == access
+	}, time.Second, time.Millisecond)
+}
+
+func TestBackgroundTokenSource(t *testing.T) {
+	clock := clock.NewMock()
+	r := &testRefresher{clock: clock, calls: make(chan *oauth2.Token, 10)}
+
+	token := &oauth2.Token{AccessToken: "a", RefreshToken: "refresh", Expiry: clock.Now().Add(time.Hour)}
+	ts := newBackgroundTokenSource(util.NewLogger("foo"), clock, token, r, 5*time.Minute)
+	t.Cleanup(ts.Stop)
+
+	// no refresh before margin
+	clock.Add(54 * time.Minute)
+	res, err := ts.Token()
+	require.NoError(t, err)
+	assert.Equal(t, "a", res.AccessToken)
+	assert.Empty(t, r.calls)
+
+	// refresh before expiry
+	clock.Add(time.Minute)
+	<-r.calls
+	waitToken(t, ts, "b")
+
+	res, err = ts.Token()
+	require.NoError(t, err)
+	assert.Equal(t, "refresh", res.RefreshToken, "refresh token retained")
+
+	// reads don't refresh
+	assert.Empty(t, r.calls)
+}
+
+func TestBackgroundTokenSourceRetry(t *testing.T) {
+	clock := clock.NewMock()
+	r := &testRefresher{clock: clock, calls: make(chan *oauth2.Token, 10), err: errors.New("unavailable")}
+
+	token := &oauth2.Token{AccessToken: "a", RefreshToken: "refresh", Expiry: clock.Now().Add(time.Hour)}
+	ts := newBackgroundTokenSource(util.NewLogger("foo"), clock, token, r, 5*time.Minute)
+	t.Cleanup(ts.Stop)
+
+	clock.Add(55 * time.Minute)
+	<-r.calls
+	waitFailed(t, ts, 1)
+
+	// failed refresh keeps the token valid
+	res, err := ts.Token()
+	require.NoError(t, err)
+	assert.Equal(t, "a", res.AccessToken)
+
+	// retry with back-off
+	clock.Add(backoffDuration)
+	<-r.calls
+	waitFailed(t, ts, 2)
+
+	clock.Add(backoffDuration)
+	assert.Empty(t, r.calls, "back-off doubled")
+
+	r.err = nil
+	clock.Add(backoffDuration)
+	<-r.calls
+	waitToken(t, ts, "b")
+}
+
+func TestBackgroundTokenSourceExpired(t *testing.T) {
+	clock := clock.NewMock()
+	r := &testRefresher{clock: clock, calls: make(chan *oauth2.Token, 10), err: errors.New("unavailable")}
+
+	token := &oauth2.Token{AccessToken: "a", RefreshToken: "refresh", Expiry: clock.Now().Add(time.Minute)}
+	ts := newBackgroundTokenSource(util.NewLogger("foo"), clock, token, r, 5*time.Minute)
+	ts.Stop()
+
+	// expired token is refreshed on read
+	clock.Add(2 * time.Minute)
+	_, err := ts.Token()
+	require.Error(t, err)
+	<-r.calls
+
+	r.err = nil
+	res, err := ts.Token()
+	require.NoError(t, err)
+	assert.Equal(t, "b", res.AccessToken)
+}
@@ -0,0 +1,45 @@
+package request
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/evcc-io/evcc/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// hangingServer accepts requests but never responds until the test ends
+func hangingServer(t *testing.T) *httptest.Server {
+	done := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-done:
+		case <-r.Context().Done():
+		}
+	}))
+
+	t.Cleanup(func() {
+		close(done)
+		srv.Close()
+	})
+
+	return srv
+}
+
+func TestTimeout(t *testing.T) {
+	srv := hangingServer(t)
+
+	defer func(d time.Duration) { Timeout = d }(Timeout)
+	Timeout = 100 * time.Millisecond
+
+	start := time.Now()
+	_, err := NewHelper(util.NewLogger("foo")).GetBody(srv.URL)
+	require.Error(t, err)
+
+	elapsed := time.Since(start)
+	assert.GreaterOrEqual(t, elapsed, Timeout)
+	assert.Less(t, elapsed, 10*Timeout)
+}
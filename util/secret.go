@@ -0,0 +1,18 @@
+package util
+
+import "strings"
+
+// secretParts are normalized key fragments identifying configuration values holding secrets
+var secretParts = []string{"password", "secret", "token", "key", "authorization", "user", "clientid"}
+
+// IsSecretKey returns true if the configuration key holds a secret.
+// Keys are compared in lower case without dashes and underscores, e.g. X-Api-Key matches as xapikey.
+func IsSecretKey(key string) bool {
+	key = strings.NewReplacer("-", "", "_", "").Replace(strings.ToLower(key))
+	for _, part := range secretParts {
+		if strings.Contains(key, part) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,17 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsSecretKey(t *testing.T) {
+	for _, key := range []string{"password", "Authorization", "apikey", "X-Api-Key", "key", "apiKey", "accessToken", "access_token", "clientSecret", "client_id", "user", "username"} {
+		assert.True(t, IsSecretKey(key), key)
+	}
+
+	for _, key := range []string{"uri", "jq", "cache", "title"} {
+		assert.False(t, IsSecretKey(key), key)
+	}
+}
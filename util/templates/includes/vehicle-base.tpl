@@ -19,4 +19,7 @@ phases: {{ .phases }}
 {{- if .cache }}
 cache: {{ .cache }}
 {{- end }}
+{{- if .socSmoothing }}
+socSmoothing: {{ .socSmoothing }}
+{{- end }}
 {{- end }}
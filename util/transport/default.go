@@ -7,14 +7,24 @@ import (
 	"time"
 )
 
+var (
+	// DialTimeout is the connection timeout of the default transport
+	DialTimeout = 5 * time.Second // reduced from 30s
+	// KeepAlive is the TCP keepalive period of the default transport
+	KeepAlive = 30 * time.Second
+	// ResponseHeaderTimeout limits waiting for the response headers after sending the request. Zero means no limit.
+	ResponseHeaderTimeout time.Duration
+)
+
 // Default returns an http.DefaultTransport as http.Transport with reduced dial timeout
 func Default() *http.Transport {
 	return &http.Transport{
 		Proxy: http.ProxyFromEnvironment,
 		DialContext: (&net.Dialer{
-			Timeout:   5 * time.Second, // reduced from 30s
-			KeepAlive: 30 * time.Second,
+			Timeout:   DialTimeout,
+			KeepAlive: KeepAlive,
 		}).DialContext,
+		ResponseHeaderTimeout: ResponseHeaderTimeout,
 		ForceAttemptHTTP2:     true,
 		MaxIdleConns:          100,
 		IdleConnTimeout:       90 * time.Second,
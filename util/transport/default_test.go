@@ -0,0 +1,37 @@
+package transport
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResponseHeaderTimeout(t *testing.T) {
+	done := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-done:
+		case <-r.Context().Done():
+		}
+	}))
+	defer srv.Close()
+	defer close(done)
+
+	defer func(d time.Duration) { ResponseHeaderTimeout = d }(ResponseHeaderTimeout)
+	ResponseHeaderTimeout = 100 * time.Millisecond
+
+	// no total client timeout
+	client := &http.Client{Transport: Default()}
+
+	start := time.Now()
+	_, err := client.Get(srv.URL)
+	require.Error(t, err)
+
+	elapsed := time.Since(start)
+	assert.GreaterOrEqual(t, elapsed, ResponseHeaderTimeout)
+	assert.Less(t, elapsed, 10*ResponseHeaderTimeout)
+}
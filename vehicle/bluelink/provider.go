@@ -186,6 +186,22 @@ func (v *Provider) TargetSoc() (float64, error) {
 	return 0, err
 }
 
+var _ api.VehicleHealthReporter = (*Provider)(nil)
+
+// Health implements the api.VehicleHealthReporter interface
+func (v *Provider) Health() (api.VehicleHealth, error) {
+	res, err := v.statusG()
+	if err != nil {
+		return api.VehicleHealth{}, err
+	}
+
+	if res.Battery == nil {
+		return api.VehicleHealth{}, api.ErrNotAvailable
+	}
+
+	return api.VehicleHealth{AuxBatterySoc: res.Battery.BatSoc}, nil
+}
+
 var _ api.VehiclePosition = (*Provider)(nil)
 
 // Position implements the api.VehiclePosition interface
@@ -203,3 +219,28 @@ func (v *Provider) WakeUp() error {
 	_, err := v.refreshG()
 	return err
 }
+
+var _ api.VehicleFuelReporter = (*Provider)(nil)
+
+// Fuel implements the api.VehicleFuelReporter interface
+func (v *Provider) Fuel() (api.VehicleFuel, error) {
+	res, err := v.statusG()
+	if err != nil {
+		return api.VehicleFuel{}, err
+	}
+
+	if res.FuelLevel == nil {
+		return api.VehicleFuel{}, api.ErrNotAvailable
+	}
+
+	fuel := api.VehicleFuel{
+		Level: *res.FuelLevel,
+	}
+
+	if dist := res.EvStatus.DrvDistance; len(dist) == 1 {
+		fuel.Range = int64(dist[0].RangeByFuel.GasModeRange.Value)
+		fuel.TotalRange = int64(dist[0].RangeByFuel.TotalAvailableRange.Value)
+	}
+
+	return fuel, nil
+}
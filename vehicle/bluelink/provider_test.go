@@ -0,0 +1,66 @@
+package bluelink
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHealth(t *testing.T) {
+	for _, tc := range []struct {
+		response string
+		expected float64
+		err      error
+	}{
+		{`{"time":"20231118094002","evStatus":{"batteryStatus":64}}`, 0, api.ErrNotAvailable},
+		{`{"time":"20231118094002","evStatus":{"batteryStatus":64},"battery":{"batSoc":86,"batState":0}}`, 86, nil},
+	} {
+		var res VehicleStatus
+		require.NoError(t, json.Unmarshal([]byte(tc.response), &res))
+
+		v := &Provider{
+			statusG: func() (VehicleStatus, error) { return res, nil },
+		}
+
+		health, err := v.Health()
+		assert.ErrorIs(t, err, tc.err)
+		assert.Equal(t, tc.expected, health.AuxBatterySoc)
+		assert.Nil(t, health.TirePressure)
+	}
+}
+
+func TestFuel(t *testing.T) {
+	for _, tc := range []struct {
+		response string
+		soc      float64
+		rng      int64
+		fuel     api.VehicleFuel
+		err      error
+	}{
+		{`{"time":"20231118094002","evStatus":{"batteryStatus":64,"drvDistance":[{"rangeByFuel":{"evModeRange":{"value":251}}}]}}`, 64, 251, api.VehicleFuel{}, api.ErrNotAvailable},
+		{`{"time":"20231118094002","evStatus":{"batteryStatus":90,"drvDistance":[{"rangeByFuel":{"evModeRange":{"value":52,"unit":1},"gasModeRange":{"value":610,"unit":1},"totalAvailableRange":{"value":662,"unit":1}},"type":2}]},"fuelLevel":72}`, 90, 52, api.VehicleFuel{Level: 72, Range: 610, TotalRange: 662}, nil},
+	} {
+		var res VehicleStatus
+		require.NoError(t, json.Unmarshal([]byte(tc.response), &res))
+
+		v := &Provider{
+			statusG: func() (VehicleStatus, error) { return res, nil },
+		}
+
+		// battery values exclude the fuel portion
+		soc, err := v.Soc()
+		require.NoError(t, err)
+		assert.Equal(t, tc.soc, soc)
+
+		rng, err := v.Range()
+		require.NoError(t, err)
+		assert.Equal(t, tc.rng, rng)
+
+		fuel, err := v.Fuel()
+		assert.ErrorIs(t, err, tc.err)
+		assert.Equal(t, tc.fuel, fuel)
+	}
+}
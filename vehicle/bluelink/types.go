@@ -42,7 +42,11 @@ type VehicleStatus struct {
 		DrvDistance              []DrivingDistance
 		ReservChargeInfos        ReservChargeInfo
 	}
-	Vehicles []Vehicle
+	Battery *struct {
+		BatSoc float64 // 12V battery
+	}
+	FuelLevel *float64 // plug-in hybrids only
+	Vehicles  []Vehicle
 }
 
 type VehicleLocation struct {
@@ -70,7 +74,7 @@ func (d *VehicleStatus) Updated() (time.Time, error) {
 
 type DrivingDistance struct {
 	RangeByFuel struct {
-		EvModeRange struct {
+		EvModeRange, GasModeRange, TotalAvailableRange struct {
 			Value int
 		}
 	}
@@ -6,6 +6,7 @@ import (
 	"github.com/evcc-io/evcc/api"
 	"github.com/evcc-io/evcc/util"
 	"github.com/evcc-io/evcc/vehicle/bmw"
+	"golang.org/x/oauth2"
 )
 
 // BMW is an api.Vehicle implementation for BMW and Mini cars
@@ -34,6 +35,7 @@ func NewBMWMiniFromConfig(brand string, other map[string]interface{}) (api.Vehic
 	cc := struct {
 		embed               `mapstructure:",squash"`
 		User, Password, VIN string
+		Tokens              Tokens
 		Region              string
 		Cache               time.Duration
 	}{
@@ -45,7 +47,8 @@ func NewBMWMiniFromConfig(brand string, other map[string]interface{}) (api.Vehic
 		return nil, err
 	}
 
-	if cc.User == "" || cc.Password == "" {
+	// tokens bypass the password login
+	if cc.Tokens.Refresh == "" && (cc.User == "" || cc.Password == "") {
 		return nil, api.ErrMissingCredentials
 	}
 
@@ -53,11 +56,27 @@ func NewBMWMiniFromConfig(brand string, other map[string]interface{}) (api.Vehic
 		embed: &cc.embed,
 	}
 
-	log := util.NewLogger(brand).Redact(cc.User, cc.Password, cc.VIN)
+	log := util.NewLogger(brand).Redact(cc.User, cc.Password, cc.VIN, cc.Tokens.Access, cc.Tokens.Refresh)
+
+	// respect BMW's request quota
+	if cc.Cache < bmw.MinCache {
+		log.WARN.Printf("cache %v below minimum, using %v to respect the request quota", cc.Cache, bmw.MinCache)
+		cc.Cache = bmw.MinCache
+	}
 	identity := bmw.NewIdentity(log, cc.Region)
 
-	ts, err := identity.Login(cc.User, cc.Password)
-	if err != nil {
+	var (
+		ts  oauth2.TokenSource
+		err error
+	)
+
+	if cc.Tokens.Refresh != "" {
+		ts = identity.WithToken(&oauth2.Token{
+			AccessToken:  cc.Tokens.Access,
+			RefreshToken: cc.Tokens.Refresh,
+			Expiry:       time.Now(),
+		})
+	} else if ts, err = identity.Login(cc.User, cc.Password); err != nil {
 		return nil, err
 	}
 
@@ -66,7 +85,7 @@ func NewBMWMiniFromConfig(brand string, other map[string]interface{}) (api.Vehic
 	cc.VIN, err = ensureVehicle(cc.VIN, api.Vehicles)
 
 	if err == nil {
-		v.Provider = bmw.NewProvider(api, cc.VIN, cc.Cache)
+		v.Provider = bmw.NewProvider(log, api, cc.VIN, cc.Cache)
 	}
 
 	return v, err
@@ -79,3 +79,27 @@ func (v *API) Status(vin string) (VehicleStatus, error) {
 
 	return res, err
 }
+
+const (
+	ChargingStart = "start-charging"
+	ChargingStop  = "stop-charging"
+)
+
+// Action implements the /eadrax-crccs/v1/vehicles/<vin>/<action> remote service api
+func (v *API) Action(vin, action string) error {
+	var res struct {
+		EventID string
+	}
+
+	uri := fmt.Sprintf("%s/eadrax-crccs/v1/vehicles/%s/%s", regions[v.region].CocoApiURI, vin, action)
+
+	req, err := request.New(http.MethodPost, uri, nil, map[string]string{
+		"Content-Type": request.JSONContent,
+		"X-User-Agent": v.xUserAgent,
+	})
+	if err == nil {
+		err = v.DoJSON(req, &res)
+	}
+
+	return err
+}
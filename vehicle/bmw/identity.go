@@ -121,9 +121,13 @@ func (v *Identity) Login(user, password string) (oauth2.TokenSource, error) {
 		return nil, err
 	}
 
-	ts := oauth2.ReuseTokenSourceWithExpiry(token, oauth.RefreshTokenSource(token, v), 15*time.Minute)
+	return v.WithToken(token), nil
+}
 
-	return ts, nil
+// WithToken creates a token source from an existing token, e.g. obtained from the BMW app.
+// Use this to bypass the password login if it is blocked by captcha or regional restrictions.
+func (v *Identity) WithToken(token *oauth2.Token) oauth2.TokenSource {
+	return oauth2.ReuseTokenSourceWithExpiry(token, oauth.RefreshTokenSource(token, v), 15*time.Minute)
 }
 
 func (v *Identity) retrieveToken(data url.Values) (*oauth2.Token, error) {
@@ -4,20 +4,24 @@ import (
 	"time"
 
 	"github.com/evcc-io/evcc/api"
-	"github.com/evcc-io/evcc/provider"
+	"github.com/evcc-io/evcc/util"
 )
 
 // Provider implements the vehicle api
 type Provider struct {
 	statusG func() (VehicleStatus, error)
+	action  func(action string) error
 }
 
 // NewProvider creates a vehicle api provider
-func NewProvider(api *API, vin string, cache time.Duration) *Provider {
+func NewProvider(log *util.Logger, api *API, vin string, cache time.Duration) *Provider {
 	impl := &Provider{
-		statusG: provider.Cached(func() (VehicleStatus, error) {
+		statusG: newQuotaCache(log, func() (VehicleStatus, error) {
 			return api.Status(vin)
-		}, cache),
+		}, cache).Get,
+		action: func(action string) error {
+			return api.Action(vin, action)
+		},
 	}
 	return impl
 }
@@ -90,3 +94,61 @@ func (v *Provider) Odometer() (float64, error) {
 
 	return float64(res.State.CurrentMileage), nil
 }
+
+var _ api.VehicleHealthReporter = (*Provider)(nil)
+
+// Health implements the api.VehicleHealthReporter interface
+func (v *Provider) Health() (api.VehicleHealth, error) {
+	res, err := v.statusG()
+	if err != nil {
+		return api.VehicleHealth{}, err
+	}
+
+	ts := res.State.TireState
+	if ts == nil {
+		return api.VehicleHealth{}, api.ErrNotAvailable
+	}
+
+	// kPa to bar
+	return api.VehicleHealth{
+		TirePressure: &api.TirePressure{
+			FrontLeft:  ts.FrontLeft.Status.CurrentPressure / 100,
+			FrontRight: ts.FrontRight.Status.CurrentPressure / 100,
+			RearLeft:   ts.RearLeft.Status.CurrentPressure / 100,
+			RearRight:  ts.RearRight.Status.CurrentPressure / 100,
+		},
+	}, nil
+}
+
+var _ api.VehicleFuelReporter = (*Provider)(nil)
+
+// Fuel implements the api.VehicleFuelReporter interface
+func (v *Provider) Fuel() (api.VehicleFuel, error) {
+	res, err := v.statusG()
+	if err != nil {
+		return api.VehicleFuel{}, err
+	}
+
+	fl := res.State.CombustionFuelLevel
+	if fl == nil {
+		return api.VehicleFuel{}, api.ErrNotAvailable
+	}
+
+	return api.VehicleFuel{
+		Level:      fl.RemainingFuelPercent,
+		Range:      fl.Range,
+		TotalRange: res.State.Range,
+	}, nil
+}
+
+var _ api.VehicleChargeController = (*Provider)(nil)
+
+// StartCharge implements the api.VehicleChargeController interface
+func (v *Provider) StartCharge() error {
+	return v.action(ChargingStart)
+}
+
+// StopCharge implements the api.VehicleChargeController interface
+func (v *Provider) StopCharge() error {
+	return v.action(ChargingStop)
+}
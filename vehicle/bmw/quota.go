@@ -0,0 +1,91 @@
+package bmw
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/util"
+	"github.com/evcc-io/evcc/util/request"
+)
+
+// BMW enforces a strict daily request quota. Once exceeded, requests are suspended
+// with exponentially increasing backoff and the last known status is served instead.
+const (
+	MinCache = 5 * time.Minute // minimum status cache duration
+
+	minQuotaBackoff = 30 * time.Minute
+	maxQuotaBackoff = 6 * time.Hour
+)
+
+// ErrQuotaExceeded is returned if the request quota is exceeded and no status is known yet
+var ErrQuotaExceeded = errors.New("request quota exceeded")
+
+// isQuotaError returns true if the error indicates an exceeded request quota
+func isQuotaError(err error) bool {
+	var se request.StatusError
+	return errors.As(err, &se) && se.HasStatus(http.StatusTooManyRequests, http.StatusForbidden)
+}
+
+// quotaCache wraps a getter with a cache that backs off once the request quota is exceeded.
+// Unlike provider.Cached, it is not reset via provider.ResetCached to protect the quota.
+type quotaCache[T any] struct {
+	mu      sync.Mutex
+	log     *util.Logger
+	clock   clock.Clock
+	cache   time.Duration
+	g       func() (T, error)
+	next    time.Time
+	backoff time.Duration
+	valid   bool
+	val     T
+	err     error
+}
+
+func newQuotaCache[T any](log *util.Logger, g func() (T, error), cache time.Duration) *quotaCache[T] {
+	return &quotaCache[T]{
+		log:   log,
+		clock: clock.New(),
+		cache: cache,
+		g:     g,
+	}
+}
+
+// Get returns the cached value, updating it if required
+func (c *quotaCache[T]) Get() (T, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.clock.Now()
+	if now.Before(c.next) {
+		return c.val, c.err
+	}
+
+	val, err := c.g()
+
+	switch {
+	case isQuotaError(err):
+		c.backoff = min(max(2*c.backoff, minQuotaBackoff), maxQuotaBackoff)
+		c.next = now.Add(c.backoff)
+		c.log.WARN.Printf("request quota exceeded, suspending requests for %v", c.backoff)
+
+		// keep serving last known value
+		if !c.valid {
+			c.err = ErrQuotaExceeded
+		}
+
+	case err != nil:
+		c.next = now.Add(c.cache)
+		c.err = err
+
+	default:
+		c.backoff = 0
+		c.next = now.Add(c.cache)
+		c.valid = true
+		c.val, c.err = val, nil
+	}
+
+	return c.val, c.err
+}
@@ -0,0 +1,217 @@
+package bmw
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/util"
+	"github.com/evcc-io/evcc/util/request"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const statusResponse = `{
+	"state": {
+		"currentMileage": 12345,
+		"range": 210,
+		"electricChargingState": {
+			"chargingLevelPercent": 64,
+			"range": 210,
+			"isChargerConnected": true,
+			"chargingStatus": "CHARGING",
+			"chargingTarget": 80
+		}
+	}
+}`
+
+func TestStatusResponse(t *testing.T) {
+	var res VehicleStatus
+	require.NoError(t, json.Unmarshal([]byte(statusResponse), &res))
+
+	v := &Provider{
+		statusG: func() (VehicleStatus, error) { return res, nil },
+	}
+
+	soc, err := v.Soc()
+	require.NoError(t, err)
+	assert.Equal(t, 64.0, soc)
+
+	status, err := v.Status()
+	require.NoError(t, err)
+	assert.Equal(t, api.StatusC, status)
+
+	rng, err := v.Range()
+	require.NoError(t, err)
+	assert.Equal(t, int64(210), rng)
+
+	odo, err := v.Odometer()
+	require.NoError(t, err)
+	assert.Equal(t, 12345.0, odo)
+}
+
+const tireStateResponse = `{
+	"state": {
+		"tireState": {
+			"frontLeft": {"status": {"currentPressure": 241, "targetPressure": 250}},
+			"frontRight": {"status": {"currentPressure": 245, "targetPressure": 250}},
+			"rearLeft": {"status": {"currentPressure": 270, "targetPressure": 280}},
+			"rearRight": {"status": {"currentPressure": 268, "targetPressure": 280}}
+		}
+	}
+}`
+
+func TestHealth(t *testing.T) {
+	for _, tc := range []struct {
+		response string
+		expected *api.TirePressure
+	}{
+		{statusResponse, nil},
+		{tireStateResponse, &api.TirePressure{FrontLeft: 2.41, FrontRight: 2.45, RearLeft: 2.7, RearRight: 2.68}},
+	} {
+		var res VehicleStatus
+		require.NoError(t, json.Unmarshal([]byte(tc.response), &res))
+
+		v := &Provider{
+			statusG: func() (VehicleStatus, error) { return res, nil },
+		}
+
+		health, err := v.Health()
+		if tc.expected == nil {
+			assert.ErrorIs(t, err, api.ErrNotAvailable)
+			continue
+		}
+
+		require.NoError(t, err)
+		assert.Equal(t, tc.expected, health.TirePressure)
+		assert.Zero(t, health.AuxBatteryVoltage)
+	}
+}
+
+const phevResponse = `{
+	"state": {
+		"currentMileage": 23456,
+		"range": 512,
+		"electricChargingState": {
+			"chargingLevelPercent": 80,
+			"range": 48,
+			"isChargerConnected": true,
+			"chargingStatus": "CHARGING",
+			"chargingTarget": 100
+		},
+		"combustionFuelLevel": {
+			"remainingFuelPercent": 65,
+			"remainingFuelLiters": 26,
+			"range": 464
+		}
+	}
+}`
+
+func TestFuel(t *testing.T) {
+	var res VehicleStatus
+	require.NoError(t, json.Unmarshal([]byte(phevResponse), &res))
+
+	v := &Provider{
+		statusG: func() (VehicleStatus, error) { return res, nil },
+	}
+
+	// battery values exclude the fuel portion
+	soc, err := v.Soc()
+	require.NoError(t, err)
+	assert.Equal(t, 80.0, soc)
+
+	rng, err := v.Range()
+	require.NoError(t, err)
+	assert.Equal(t, int64(48), rng)
+
+	fuel, err := v.Fuel()
+	require.NoError(t, err)
+	assert.Equal(t, api.VehicleFuel{Level: 65, Range: 464, TotalRange: 512}, fuel)
+
+	// bev
+	res = VehicleStatus{}
+	require.NoError(t, json.Unmarshal([]byte(statusResponse), &res))
+	_, err = v.Fuel()
+	assert.ErrorIs(t, err, api.ErrNotAvailable)
+}
+
+func TestQuotaBackoff(t *testing.T) {
+	var (
+		calls int
+		err   error
+		soc   int64 = 50
+	)
+
+	c := newQuotaCache(util.NewLogger("foo"), func() (int64, error) {
+		calls++
+		return soc, err
+	}, time.Minute)
+
+	clock := clock.NewMock()
+	c.clock = clock
+
+	quotaErr := request.NewStatusError(&http.Response{StatusCode: http.StatusTooManyRequests})
+
+	// quota exceeded before first status
+	err = quotaErr
+	_, res := c.Get()
+	assert.ErrorIs(t, res, ErrQuotaExceeded)
+	assert.Equal(t, 1, calls)
+
+	// suspended during backoff
+	clock.Add(minQuotaBackoff - time.Second)
+	_, res = c.Get()
+	assert.ErrorIs(t, res, ErrQuotaExceeded)
+	assert.Equal(t, 1, calls)
+
+	// backoff elapsed
+	err = nil
+	clock.Add(time.Second)
+	val, res := c.Get()
+	assert.NoError(t, res)
+	assert.Equal(t, int64(50), val)
+	assert.Equal(t, 2, calls)
+
+	// cached
+	soc = 60
+	val, _ = c.Get()
+	assert.Equal(t, int64(50), val)
+	assert.Equal(t, 2, calls)
+
+	// quota exceeded serves last known value with doubling backoff
+	err = quotaErr
+	for i, backoff := range []time.Duration{minQuotaBackoff, 2 * minQuotaBackoff, 4 * minQuotaBackoff} {
+		clock.Add(time.Minute)
+		val, res = c.Get()
+		assert.NoError(t, res)
+		assert.Equal(t, int64(50), val)
+		assert.Equal(t, backoff, c.backoff, i)
+
+		clock.Add(backoff - time.Minute)
+	}
+
+	// backoff capped
+	for i := 0; i < 10; i++ {
+		clock.Add(c.backoff)
+		c.Get()
+	}
+	assert.Equal(t, maxQuotaBackoff, c.backoff)
+
+	// other errors are not backed off
+	err = errors.New("foo")
+	clock.Add(c.backoff)
+	_, res = c.Get()
+	assert.Equal(t, err, res)
+
+	// recovery resets backoff
+	err = nil
+	clock.Add(time.Minute)
+	val, res = c.Get()
+	assert.NoError(t, res)
+	assert.Equal(t, int64(60), val)
+	assert.Equal(t, time.Duration(0), c.backoff)
+}
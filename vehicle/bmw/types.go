@@ -9,7 +9,7 @@ type Vehicle struct {
 type VehicleStatus struct {
 	State struct {
 		CurrentMileage        int64
-		Range                 int64
+		Range                 int64 // combined electric and fuel range
 		ElectricChargingState struct {
 			ChargingLevelPercent int64
 			Range                int64
@@ -17,5 +17,19 @@ type VehicleStatus struct {
 			ChargingStatus       string
 			ChargingTarget       int64
 		}
+		TireState *struct {
+			FrontLeft, FrontRight, RearLeft, RearRight Tire
+		}
+		CombustionFuelLevel *struct { // plug-in hybrids only
+			RemainingFuelPercent float64
+			RemainingFuelLiters  float64
+			Range                int64
+		}
+	}
+}
+
+type Tire struct {
+	Status struct {
+		CurrentPressure float64 // kPa
 	}
 }
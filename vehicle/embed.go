@@ -12,6 +12,10 @@ type embed struct {
 	Identifiers_ []string         `mapstructure:"identifiers"`
 	Features_    []api.Feature    `mapstructure:"features"`
 	OnIdentify   api.ActionConfig `mapstructure:"onIdentify"`
+	Smoothing_   float64          `mapstructure:"socSmoothing"`
+	Efficiency_  float64          `mapstructure:"chargeEfficiency"`
+	MinCurrent_  float64          `mapstructure:"minCurrent"`
+	MaxCurrent_  float64          `mapstructure:"maxCurrent"`
 }
 
 // Title implements the api.Vehicle interface
@@ -67,3 +71,24 @@ var _ api.FeatureDescriber = (*embed)(nil)
 func (v *embed) Features() []api.Feature {
 	return v.Features_
 }
+
+var _ api.SocSmoother = (*embed)(nil)
+
+// SocSmoothing implements the api.SocSmoother interface
+func (v *embed) SocSmoothing() float64 {
+	return v.Smoothing_
+}
+
+var _ api.ChargeEfficiencyProvider = (*embed)(nil)
+
+// ChargeEfficiency implements the api.ChargeEfficiencyProvider interface
+func (v *embed) ChargeEfficiency() float64 {
+	return v.Efficiency_
+}
+
+var _ api.ChargeCurrentLimiter = (*embed)(nil)
+
+// ChargeCurrentLimits implements the api.ChargeCurrentLimiter interface
+func (v *embed) ChargeCurrentLimits() (float64, float64) {
+	return v.MinCurrent_, v.MaxCurrent_
+}
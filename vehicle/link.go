@@ -0,0 +1,114 @@
+package vehicle
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/util"
+)
+
+const (
+	linkBackoff    = time.Minute
+	linkMaxBackoff = 30 * time.Minute
+)
+
+// vehicleLink resolves a vehicle which is not yet listed by the account. Right after setup, some cloud APIs
+// return empty vehicle lists until the vehicle has been linked in the manufacturer's app.
+// Vehicle enumeration is retried with back-off while reads return api.ErrPendingLink.
+type vehicleLink[T any] struct {
+	mu      sync.Mutex
+	log     *util.Logger
+	clock   clock.Clock
+	resolve func() (T, error)
+	vehicle T
+	linked  bool
+	failed  int
+	retry   time.Time
+}
+
+// ensureVehicleLink returns the vehicle with matching VIN. If the account does not list any vehicle yet,
+// the vehicle is resolved once it appears instead of failing.
+func ensureVehicleLink(log *util.Logger, vin string, list func() ([]string, error)) (*vehicleLink[string], error) {
+	return ensureVehicleLinkEx(log, vin, list, func(v string) string {
+		return v
+	})
+}
+
+// ensureVehicleLinkEx returns the vehicle with matching VIN. If the account does not list any vehicle yet,
+// the vehicle is resolved once it appears instead of failing.
+func ensureVehicleLinkEx[Vehicle any](
+	log *util.Logger,
+	vin string,
+	list func() ([]Vehicle, error),
+	extract func(Vehicle) string,
+) (*vehicleLink[Vehicle], error) {
+	return newVehicleLink(log, clock.New(), vin, list, extract)
+}
+
+func newVehicleLink[Vehicle any](
+	log *util.Logger,
+	clock clock.Clock,
+	vin string,
+	list func() ([]Vehicle, error),
+	extract func(Vehicle) string,
+) (*vehicleLink[Vehicle], error) {
+	vehicles, err := list()
+	if err != nil {
+		return nil, fmt.Errorf("cannot get vehicles: %w", err)
+	}
+
+	v := &vehicleLink[Vehicle]{
+		log:   log,
+		clock: clock,
+		resolve: func() (Vehicle, error) {
+			return ensureVehicleEx(vin, list, extract)
+		},
+	}
+
+	if len(vehicles) == 0 {
+		log.WARN.Println("no vehicles found, waiting for vehicle to be linked to the account")
+		v.retry = clock.Now().Add(linkBackoff)
+		return v, nil
+	}
+
+	v.vehicle, err = ensureVehicleEx(vin, func() ([]Vehicle, error) {
+		return vehicles, nil
+	}, extract)
+	v.linked = err == nil
+
+	return v, err
+}
+
+// Vehicle returns the linked vehicle or api.ErrPendingLink
+func (v *vehicleLink[T]) Vehicle() (T, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if v.linked {
+		return v.vehicle, nil
+	}
+
+	if v.clock.Now().Before(v.retry) {
+		return v.vehicle, api.ErrPendingLink
+	}
+
+	vehicle, err := v.resolve()
+	if err != nil {
+		v.failed++
+		d := min(linkBackoff*time.Duration(math.Pow(2, float64(v.failed))), linkMaxBackoff)
+		v.retry = v.clock.Now().Add(d)
+
+		v.log.DEBUG.Printf("vehicle link: %v, retry in %v", err, d)
+
+		return v.vehicle, fmt.Errorf("%w: %v", api.ErrPendingLink, err)
+	}
+
+	v.log.INFO.Println("vehicle linked")
+	v.vehicle, v.linked = vehicle, true
+
+	return vehicle, nil
+}
@@ -0,0 +1,75 @@
+package vehicle
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVehicleLink(t *testing.T) {
+	clock := clock.NewMock()
+
+	var calls int
+	list := func() ([]string, error) {
+		calls++
+		switch {
+		case calls == 3:
+			return nil, errors.New("timeout")
+		case calls < 5:
+			return nil, nil
+		default:
+			return []string{"WP0ZZZ"}, nil
+		}
+	}
+
+	link, err := newVehicleLink(util.NewLogger("foo"), clock, "", list, func(v string) string { return v })
+	require.NoError(t, err)
+
+	// vehicle pending link
+	_, err = link.Vehicle()
+	assert.ErrorIs(t, err, api.ErrPendingLink)
+	assert.Equal(t, 1, calls)
+
+	for i := 2; i < 5; i++ {
+		clock.Add(linkMaxBackoff)
+
+		_, err := link.Vehicle()
+		assert.ErrorIs(t, err, api.ErrPendingLink)
+		assert.Equal(t, i, calls)
+
+		// enumeration is not retried before back-off elapsed
+		_, err = link.Vehicle()
+		assert.ErrorIs(t, err, api.ErrPendingLink)
+		assert.Equal(t, i, calls)
+	}
+
+	clock.Add(linkMaxBackoff)
+
+	// vehicle appeared
+	vin, err := link.Vehicle()
+	require.NoError(t, err)
+	assert.Equal(t, "WP0ZZZ", vin)
+
+	// no more enumeration
+	_, _ = link.Vehicle()
+	assert.Equal(t, 5, calls)
+}
+
+func TestVehicleLinkMismatch(t *testing.T) {
+	list := func() ([]string, error) {
+		return []string{"WP0ZZZ"}, nil
+	}
+
+	_, err := newVehicleLink(util.NewLogger("foo"), clock.NewMock(), "foo", list, func(v string) string { return v })
+	assert.Error(t, err)
+
+	_, err = newVehicleLink(util.NewLogger("foo"), clock.NewMock(), "", func() ([]string, error) {
+		return nil, errors.New("unauthorized")
+	}, func(v string) string { return v })
+	assert.Error(t, err)
+}
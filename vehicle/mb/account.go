@@ -0,0 +1,110 @@
+package mb
+
+import (
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Account is the listing entry of a logged in account
+type Account struct {
+	Name     string `json:"name"`     // account name, defaults to the user
+	Imported bool   `json:"imported"` // token imported from the app
+	Vehicles int    `json:"vehicles"` // vehicles sharing the account
+}
+
+// account is a logged in identity shared by the account's vehicles
+type account struct {
+	*Identity
+	name     string
+	vehicles int
+}
+
+// accounts shares logged in identities by account. Vehicles of the same account use a single identity such that
+// concurrent refreshes do not invalidate each other's rotated refresh token, while accounts of the same brand
+// keep independent tokens and login flows.
+var accounts = struct {
+	sync.Mutex
+	identities map[string]*account
+}{
+	identities: make(map[string]*account),
+}
+
+// WithAccount sets the account name keying the persisted token. It defaults to the user and must be set to
+// tell apart multiple accounts configured with app tokens only.
+func (v *Identity) WithAccount(account string) *Identity {
+	v.account = account
+	return v
+}
+
+// accountName returns the account name, defaulting to the user
+func (v *Identity) accountName(user string) string {
+	if v.account != "" {
+		return v.account
+	}
+	return user
+}
+
+// accountKey is the settings key of the account's persisted token
+func (v *Identity) accountKey(user string) string {
+	return tokenKey(v.oc.ClientID, v.accountName(user))
+}
+
+// Shared returns the identity already logged in for the account or logs in the given identity.
+// Logins of different accounts do not block each other.
+func Shared(v *Identity, user, password string) (*Identity, error) {
+	key := v.accountKey(user)
+
+	accounts.Lock()
+	acc, ok := accounts.identities[key]
+	if ok {
+		acc.vehicles++
+	}
+	accounts.Unlock()
+
+	if ok {
+		return acc.Identity, nil
+	}
+
+	if err := v.Login(user, password); err != nil {
+		return nil, err
+	}
+
+	accounts.Lock()
+	defer accounts.Unlock()
+
+	// another vehicle of the account may have logged in meanwhile
+	if acc, ok := accounts.identities[key]; ok {
+		acc.vehicles++
+		return acc.Identity, nil
+	}
+
+	accounts.identities[key] = &account{
+		Identity: v,
+		name:     v.accountName(user),
+		vehicles: 1,
+	}
+
+	return v, nil
+}
+
+// Accounts lists the logged in accounts ordered by name
+func Accounts() []Account {
+	accounts.Lock()
+	defer accounts.Unlock()
+
+	res := make([]Account, 0, len(accounts.identities))
+	for _, acc := range accounts.identities {
+		res = append(res, Account{
+			Name:     acc.name,
+			Imported: acc.imported != nil,
+			Vehicles: acc.vehicles,
+		})
+	}
+
+	slices.SortFunc(res, func(a, b Account) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return res
+}
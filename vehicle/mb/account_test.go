@@ -0,0 +1,67 @@
+package mb
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/evcc-io/evcc/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+)
+
+func TestSharedAccounts(t *testing.T) {
+	refreshs := make(map[string]int)
+
+	// each account's app token rotates to its own access and refresh token
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		account := r.PostFormValue("refresh_token")
+		refreshs[account]++
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"access_token":"` + account + `","refresh_token":"` + account + `","token_type":"Bearer","expires_in":3600}`))
+	}))
+	defer srv.Close()
+
+	oc := &oauth2.Config{
+		ClientID: "accounts",
+		Endpoint: oauth2.Endpoint{TokenURL: srv.URL},
+	}
+
+	login := func(account string) *Identity {
+		v, err := Shared(NewIdentity(util.NewLogger("foo"), oc).WithToken(account).WithAccount(account), "", "")
+		require.NoError(t, err)
+		return v
+	}
+
+	anna := login("anna")
+	bob := login("bob")
+
+	// accounts authenticate independently
+	assert.NotSame(t, anna, bob)
+	assert.NotEqual(t, anna.key, bob.key)
+	assert.Equal(t, map[string]int{"anna": 1, "bob": 1}, refreshs)
+
+	for account, v := range map[string]*Identity{"anna": anna, "bob": bob} {
+		token, err := v.Token()
+		require.NoError(t, err)
+		assert.Equal(t, account, token.AccessToken)
+		assert.Equal(t, account, v.restoreToken().RefreshToken)
+	}
+
+	// further vehicles of an account share its identity
+	assert.Same(t, anna, login("anna"))
+	assert.Equal(t, map[string]int{"anna": 1, "bob": 1}, refreshs)
+
+	// accounts are listed distinctly
+	assert.Equal(t, []Account{
+		{Name: "anna", Imported: true, Vehicles: 2},
+		{Name: "bob", Imported: true, Vehicles: 1},
+	}, Accounts())
+
+	// account defaults to the user
+	v := NewIdentity(util.NewLogger("foo"), oc)
+	assert.Equal(t, tokenKey("accounts", "user"), v.accountKey("user"))
+	assert.Equal(t, tokenKey("accounts", "anna"), v.WithAccount("anna").accountKey("user"))
+}
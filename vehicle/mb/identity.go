@@ -24,14 +24,20 @@ const OAuthURI = "https://id.mercedes-benz.com"
 
 type Identity struct {
 	*request.Helper
-	oc *oauth2.Config
+	log     *util.Logger
+	oc      *oauth2.Config
+	key     string // settings key of persisted token
+	account string // account name keying the persisted token, defaults to the user
 	oauth2.TokenSource
+
+	imported *oauth2.Token // token obtained from the app
 }
 
 // NewIdentity creates Mercedes Benz identity
 func NewIdentity(log *util.Logger, oc *oauth2.Config) *Identity {
 	return &Identity{
 		Helper: request.NewHelper(log),
+		log:    log,
 		oc:     oc,
 	}
 }
@@ -45,7 +51,80 @@ func state() string {
 	return base64.RawURLEncoding.EncodeToString(b[:])
 }
 
+// WithToken imports a refresh token obtained from the app, bypassing the interactive login.
+// The token is validated and persisted on login.
+func (v *Identity) WithToken(refresh string) *Identity {
+	if refresh != "" {
+		v.log.Redact(refresh)
+		v.imported = &oauth2.Token{RefreshToken: refresh}
+	}
+	return v
+}
+
+// Login restores the persisted token, imports the app token or logs in using the credentials
 func (v *Identity) Login(user, password string) error {
+	v.key = v.accountKey(user)
+
+	if token := v.restoreToken(); token != nil {
+		ts := v.tokenSource(token)
+		if _, err := ts.Token(); err == nil {
+			v.setTokenSource(ts)
+			return nil
+		}
+
+		v.log.WARN.Println("persisted token expired, login required")
+	}
+
+	if v.imported != nil {
+		err := v.importToken(v.imported)
+		if err == nil || password == "" {
+			return err
+		}
+
+		v.log.WARN.Printf("imported token: %v, login required", err)
+	}
+
+	token, err := v.login(user, password)
+	if err == nil {
+		v.persistToken(token)
+		v.setTokenSource(v.tokenSource(token))
+	}
+
+	return err
+}
+
+// tokenSource creates a refreshing token source using the identity's http client and its timeouts
+func (v *Identity) tokenSource(token *oauth2.Token) oauth2.TokenSource {
+	ctx := context.WithValue(context.Background(), oauth2.HTTPClient, v.Client)
+	return v.oc.TokenSource(ctx, token)
+}
+
+// importToken validates the imported token by refreshing it
+func (v *Identity) importToken(token *oauth2.Token) error {
+	ts := v.tokenSource(token)
+
+	t, err := ts.Token()
+	if err != nil {
+		return fmt.Errorf("refresh failed: %w", err)
+	}
+
+	v.log.INFO.Println("imported token accepted")
+	v.persistToken(t)
+	v.setTokenSource(ts)
+
+	return nil
+}
+
+// setTokenSource sets a token source that persists refreshed tokens
+func (v *Identity) setTokenSource(ts oauth2.TokenSource) {
+	v.TokenSource = &persistingTokenSource{
+		ts:      ts,
+		persist: v.persistToken,
+		clear:   v.clearToken,
+	}
+}
+
+func (v *Identity) login(user, password string) (*oauth2.Token, error) {
 	if v.Client.Jar == nil {
 		v.Client.Jar, _ = cookiejar.New(&cookiejar.Options{
 			PublicSuffixList: publicsuffix.List,
@@ -59,12 +138,12 @@ func (v *Identity) Login(user, password string) error {
 
 	uri := v.oc.AuthCodeURL(state(), oauth2.AccessTypeOffline, oauth2.S256ChallengeOption(cv))
 	if _, err := v.Get(uri); err != nil {
-		return err
+		return nil, err
 	}
 
 	resume, err := param()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	v.Client.CheckRedirect = nil
@@ -134,9 +213,5 @@ func (v *Identity) Login(user, password string) error {
 		token, err = v.oc.Exchange(ctx, code, oauth2.VerifierOption(cv))
 	}
 
-	if err == nil {
-		v.TokenSource = v.oc.TokenSource(context.Background(), token)
-	}
-
-	return err
+	return token, err
 }
@@ -0,0 +1,100 @@
+package mb
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/evcc-io/evcc/server/db/settings"
+	"github.com/evcc-io/evcc/util"
+	"github.com/evcc-io/evcc/util/request"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+)
+
+func TestTokenRefreshTimeout(t *testing.T) {
+	done := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-done:
+		case <-r.Context().Done():
+		}
+	}))
+	defer srv.Close()
+	defer close(done)
+
+	defer func(d time.Duration) { request.Timeout = d }(request.Timeout)
+	request.Timeout = 100 * time.Millisecond
+
+	v := NewIdentity(util.NewLogger("foo"), &oauth2.Config{
+		Endpoint: oauth2.Endpoint{TokenURL: srv.URL},
+	})
+
+	// expired token requires refresh
+	ts := v.tokenSource(&oauth2.Token{AccessToken: "access", RefreshToken: "refresh", Expiry: time.Now()})
+
+	start := time.Now()
+	_, err := ts.Token()
+	require.Error(t, err)
+
+	elapsed := time.Since(start)
+	assert.GreaterOrEqual(t, elapsed, request.Timeout)
+	assert.Less(t, elapsed, 10*request.Timeout)
+}
+
+func TestImportToken(t *testing.T) {
+	var refreshs int
+	reject := false
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		refreshs++
+		w.Header().Set("Content-Type", "application/json")
+
+		if reject || r.PostFormValue("refresh_token") != "app" && r.PostFormValue("refresh_token") != "rotated" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error":"invalid_grant"}`))
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"access_token":"access","refresh_token":"rotated","token_type":"Bearer","expires_in":3600}`))
+	}))
+	defer srv.Close()
+
+	oc := &oauth2.Config{
+		ClientID: "import",
+		Endpoint: oauth2.Endpoint{TokenURL: srv.URL},
+	}
+
+	// supplied token is validated and persisted
+	v := NewIdentity(util.NewLogger("foo"), oc).WithToken("app")
+	require.NoError(t, v.Login("user", ""))
+	assert.Equal(t, 1, refreshs)
+
+	token, err := v.Token()
+	require.NoError(t, err)
+	assert.Equal(t, "access", token.AccessToken)
+
+	var st storedToken
+	require.NoError(t, settings.Json(v.key, &st))
+	assert.Equal(t, "rotated", st.Token.RefreshToken)
+
+	// persisted token takes precedence over the consumed app token
+	v = NewIdentity(util.NewLogger("foo"), oc).WithToken("app")
+	require.NoError(t, v.Login("user", ""))
+	assert.Equal(t, 1, refreshs)
+
+	// rejected refresh clears the persisted token
+	reject = true
+	v = NewIdentity(util.NewLogger("foo"), oc)
+	v.key = tokenKey("import", "user")
+	v.setTokenSource(v.tokenSource(&oauth2.Token{RefreshToken: "rotated", Expiry: time.Now()}))
+	_, err = v.Token()
+	require.Error(t, err)
+	assert.Nil(t, v.restoreToken())
+
+	// invalid app token without credentials fails
+	v = NewIdentity(util.NewLogger("foo"), oc).WithToken("invalid")
+	assert.Error(t, v.Login("user", ""))
+}
@@ -0,0 +1,163 @@
+package mb
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/evcc-io/evcc/server/db/settings"
+	"golang.org/x/oauth2"
+)
+
+// tokenVersion is the current token storage format
+const tokenVersion = 2
+
+// storedToken is the versioned token storage format
+type storedToken struct {
+	Version int           `json:"version"`
+	Token   *oauth2.Token `json:"token"`
+}
+
+// migrations convert blobs of previous storage versions to tokens
+var migrations = map[int]func([]byte) (*oauth2.Token, error){
+	1: migrateV1,
+}
+
+// migrateV1 reads the unversioned plain oauth2 token
+func migrateV1(b []byte) (*oauth2.Token, error) {
+	var token oauth2.Token
+	if err := json.Unmarshal(b, &token); err != nil {
+		return nil, err
+	}
+
+	if token.AccessToken == "" && token.RefreshToken == "" {
+		return nil, errors.New("empty token")
+	}
+
+	return &token, nil
+}
+
+// tokenKey is the settings key of the user's token for the given oauth client
+func tokenKey(clientID, user string) string {
+	return fmt.Sprintf("mb.token.%x", sha256.Sum256([]byte(clientID+user)))
+}
+
+// encodeToken creates the current storage format
+func encodeToken(token *oauth2.Token) ([]byte, error) {
+	return json.Marshal(storedToken{
+		Version: tokenVersion,
+		Token:   token,
+	})
+}
+
+// decodeToken reads a stored token of any known version. It returns the storage version found.
+func decodeToken(b []byte) (*oauth2.Token, int, error) {
+	var res struct {
+		Version int `json:"version"`
+	}
+
+	if err := json.Unmarshal(b, &res); err != nil {
+		return nil, 0, err
+	}
+
+	// unversioned blobs predate the storage format
+	if res.Version == 0 {
+		res.Version = 1
+	}
+
+	if res.Version == tokenVersion {
+		var st storedToken
+		if err := json.Unmarshal(b, &st); err != nil {
+			return nil, res.Version, err
+		}
+
+		if st.Token == nil {
+			return nil, res.Version, errors.New("empty token")
+		}
+
+		return st.Token, res.Version, nil
+	}
+
+	migrate, ok := migrations[res.Version]
+	if !ok {
+		return nil, res.Version, fmt.Errorf("unsupported token version: %d", res.Version)
+	}
+
+	token, err := migrate(b)
+	return token, res.Version, err
+}
+
+// restoreToken loads the persisted token, migrating it to the current format if required
+func (v *Identity) restoreToken() *oauth2.Token {
+	s, err := settings.String(v.key)
+	if err != nil || s == "" {
+		return nil
+	}
+
+	token, version, err := decodeToken([]byte(s))
+	if err != nil {
+		v.log.WARN.Printf("cannot read persisted token (version %d): %v, login required", version, err)
+		return nil
+	}
+
+	if version != tokenVersion {
+		v.log.INFO.Printf("migrated persisted token from version %d to %d", version, tokenVersion)
+		v.persistToken(token)
+	}
+
+	return token
+}
+
+// persistToken stores the token in the current format
+func (v *Identity) persistToken(token *oauth2.Token) {
+	b, err := encodeToken(token)
+	if err != nil {
+		v.log.ERROR.Printf("persist token: %v", err)
+		return
+	}
+
+	settings.SetString(v.key, string(b))
+}
+
+// clearToken removes the persisted token
+func (v *Identity) clearToken() {
+	if s, err := settings.String(v.key); err == nil && s != "" {
+		v.log.WARN.Println("token refresh rejected, persisted token cleared")
+		settings.SetString(v.key, "")
+	}
+}
+
+// persistingTokenSource persists each refreshed token. Tokens rejected by the
+// identity provider are cleared such that they are not reused.
+type persistingTokenSource struct {
+	mu      sync.Mutex
+	ts      oauth2.TokenSource
+	persist func(*oauth2.Token)
+	clear   func()
+	access  string
+}
+
+func (ts *persistingTokenSource) Token() (*oauth2.Token, error) {
+	token, err := ts.ts.Token()
+
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	if err != nil {
+		var re *oauth2.RetrieveError
+		if errors.As(err, &re) && ts.clear != nil {
+			ts.access = ""
+			ts.clear()
+		}
+		return nil, err
+	}
+
+	if token.AccessToken != ts.access {
+		ts.access = token.AccessToken
+		ts.persist(token)
+	}
+
+	return token, nil
+}
@@ -0,0 +1,68 @@
+package mb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evcc-io/evcc/server/db/settings"
+	"github.com/evcc-io/evcc/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+)
+
+const v1Token = `{"access_token":"access","token_type":"Bearer","refresh_token":"refresh","expiry":"2030-01-01T00:00:00Z"}`
+
+func TestDecodeTokenV1(t *testing.T) {
+	token, version, err := decodeToken([]byte(v1Token))
+	require.NoError(t, err)
+	assert.Equal(t, 1, version)
+	assert.Equal(t, "access", token.AccessToken)
+	assert.Equal(t, "refresh", token.RefreshToken)
+	assert.Equal(t, time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC), token.Expiry.UTC())
+}
+
+func TestDecodeTokenCurrent(t *testing.T) {
+	b, err := encodeToken(&oauth2.Token{AccessToken: "access", RefreshToken: "refresh"})
+	require.NoError(t, err)
+
+	token, version, err := decodeToken(b)
+	require.NoError(t, err)
+	assert.Equal(t, tokenVersion, version)
+	assert.Equal(t, "refresh", token.RefreshToken)
+}
+
+func TestDecodeTokenInvalid(t *testing.T) {
+	for _, s := range []string{`foo`, `{}`, `{"version":99,"token":{}}`, `{"version":2}`} {
+		_, _, err := decodeToken([]byte(s))
+		assert.Error(t, err, s)
+	}
+}
+
+func TestRestoreMigratesToken(t *testing.T) {
+	v := NewIdentity(util.NewLogger("foo"), &oauth2.Config{ClientID: "client"})
+	v.key = tokenKey("client", "user")
+
+	settings.SetString(v.key, v1Token)
+
+	token := v.restoreToken()
+	require.NotNil(t, token)
+	assert.Equal(t, "refresh", token.RefreshToken)
+
+	// stored in current format
+	s, err := settings.String(v.key)
+	require.NoError(t, err)
+
+	var st storedToken
+	require.NoError(t, settings.Json(v.key, &st))
+	assert.Equal(t, tokenVersion, st.Version, s)
+	assert.Equal(t, "refresh", st.Token.RefreshToken)
+}
+
+func TestRestoreUnreadableToken(t *testing.T) {
+	v := NewIdentity(util.NewLogger("foo"), &oauth2.Config{ClientID: "client"})
+	v.key = tokenKey("client", "other")
+
+	settings.SetString(v.key, `{"version":99}`)
+	assert.Nil(t, v.restoreToken())
+}
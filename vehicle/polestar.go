@@ -0,0 +1,78 @@
+package vehicle
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/util"
+	"github.com/evcc-io/evcc/vehicle/polestar"
+)
+
+// Polestar is an api.Vehicle implementation for Polestar cars
+type Polestar struct {
+	*embed
+	*polestar.Provider
+	identity *polestar.Identity
+}
+
+func init() {
+	registry.Add("polestar", NewPolestarFromConfig)
+}
+
+// NewPolestarFromConfig creates a new vehicle
+func NewPolestarFromConfig(other map[string]interface{}) (api.Vehicle, error) {
+	cc := struct {
+		embed          `mapstructure:",squash"`
+		User, Password string
+		VIN            string
+		Region         string
+		Cache          time.Duration
+	}{
+		Region: "eu",
+		Cache:  interval,
+	}
+
+	if err := util.DecodeOther(other, &cc); err != nil {
+		return nil, err
+	}
+
+	if cc.User == "" || cc.Password == "" {
+		return nil, api.ErrMissingCredentials
+	}
+
+	region, ok := polestar.Regions[strings.ToLower(cc.Region)]
+	if !ok {
+		return nil, fmt.Errorf("invalid region: %s", cc.Region)
+	}
+
+	log := util.NewLogger("polestar").Redact(cc.User, cc.Password, cc.VIN)
+
+	identity := polestar.NewIdentity(log, region)
+	if err := identity.Login(cc.User, cc.Password); err != nil {
+		return nil, fmt.Errorf("login failed: %w", err)
+	}
+
+	api := polestar.NewAPI(log, region.API, identity)
+
+	link, err := ensureVehicleLink(log, cc.VIN, api.Vehicles)
+	if err != nil {
+		return nil, err
+	}
+
+	v := &Polestar{
+		embed:    &cc.embed,
+		Provider: polestar.NewProvider(api, link.Vehicle, cc.Cache),
+		identity: identity,
+	}
+
+	return v, nil
+}
+
+var _ api.AuthProber = (*Polestar)(nil)
+
+// ProbeAuth implements the api.AuthProber interface
+func (v *Polestar) ProbeAuth() error {
+	return v.identity.Probe()
+}
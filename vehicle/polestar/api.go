@@ -0,0 +1,101 @@
+package polestar
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/evcc-io/evcc/util"
+	"github.com/evcc-io/evcc/util/request"
+	"github.com/samber/lo"
+	"golang.org/x/oauth2"
+)
+
+const (
+	carsQuery    = `query getCars { getConsumerCarsV2 { vin internalVehicleIdentifier } }`
+	batteryQuery = `query GetBatteryData($vin: String!) { getBatteryData(vin: $vin) { batteryChargeLevelPercentage chargerConnectionStatus chargingStatus estimatedDistanceToEmptyKm estimatedChargingTimeToFullMinutes eventUpdatedTimestamp { iso } } }`
+)
+
+// API is the Polestar graphql api client
+type API struct {
+	*request.Helper
+	uri string
+}
+
+// NewAPI creates a new api client
+func NewAPI(log *util.Logger, uri string, ts oauth2.TokenSource) *API {
+	v := &API{
+		Helper: request.NewHelper(log),
+		uri:    uri + "/mystar-v2",
+	}
+
+	v.Client.Transport = &oauth2.Transport{
+		Source: ts,
+		Base:   v.Client.Transport,
+	}
+
+	return v
+}
+
+// query executes the graphql query and decodes the data into the result
+func (v *API) query(query string, variables map[string]any, res any) error {
+	data := struct {
+		Query     string         `json:"query"`
+		Variables map[string]any `json:"variables,omitempty"`
+	}{
+		Query:     query,
+		Variables: variables,
+	}
+
+	req, err := request.New(http.MethodPost, v.uri, request.MarshalJSON(data), request.JSONEncoding)
+	if err != nil {
+		return err
+	}
+
+	var resp struct {
+		Data   json.RawMessage
+		Errors []graphqlError
+	}
+
+	if err := v.DoJSON(req, &resp); err != nil {
+		return err
+	}
+
+	if len(resp.Errors) > 0 {
+		return errors.New(resp.Errors[0].Message)
+	}
+
+	return json.Unmarshal(resp.Data, res)
+}
+
+// Vehicles returns the vehicle VINs
+func (v *API) Vehicles() ([]string, error) {
+	var res struct {
+		GetConsumerCarsV2 []Vehicle
+	}
+
+	err := v.query(carsQuery, nil, &res)
+
+	return lo.Map(res.GetConsumerCarsV2, func(v Vehicle, _ int) string {
+		return v.VIN
+	}), err
+}
+
+// BatteryData returns the vehicle's battery status
+func (v *API) BatteryData(vin string) (BatteryData, error) {
+	var res struct {
+		GetBatteryData *BatteryData
+	}
+
+	err := v.query(batteryQuery, map[string]any{"vin": vin}, &res)
+	if err == nil && res.GetBatteryData == nil {
+		err = fmt.Errorf("missing battery data: %s", vin)
+	}
+
+	if err != nil {
+		return BatteryData{}, err
+	}
+
+	return *res.GetBatteryData, nil
+}
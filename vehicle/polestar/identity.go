@@ -0,0 +1,202 @@
+package polestar
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http/cookiejar"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/util"
+	"github.com/evcc-io/evcc/util/request"
+	"golang.org/x/net/publicsuffix"
+	"golang.org/x/oauth2"
+)
+
+// https://github.com/pypolestar/polestar_api
+
+// Region contains the regional identity and api endpoints
+type Region struct {
+	Auth string // identity provider
+	API  string // graphql api
+}
+
+// Regions are the supported regional endpoints
+var Regions = map[string]Region{
+	"eu": {Auth: "https://polestarid.eu.polestar.com", API: "https://pc-api.polestar.com/eu-north-1"},
+	"us": {Auth: "https://polestarid.us.polestar.com", API: "https://pc-api.polestar.com/us-east-1"},
+}
+
+// android app client
+const (
+	ClientID    = "l3oopkc_10"
+	RedirectURI = "https://www.polestar.com/sign-in-callback"
+)
+
+// Identity logs in using the app's authorization code flow. Refresh tokens expire after some days
+// and refreshing is not possible anymore, in which case the identity logs in again using the credentials.
+type Identity struct {
+	*request.Helper
+	log            *util.Logger
+	uri            string
+	oc             *oauth2.Config
+	user, password string
+	key            string // settings key of persisted token
+
+	mu       sync.Mutex
+	token    *oauth2.Token
+	verified bool // token has been obtained or refreshed since startup
+}
+
+// NewIdentity creates Polestar identity
+func NewIdentity(log *util.Logger, region Region) *Identity {
+	return &Identity{
+		Helper: request.NewHelper(log),
+		log:    log,
+		uri:    region.Auth,
+		oc: &oauth2.Config{
+			ClientID:    ClientID,
+			RedirectURL: RedirectURI,
+			Endpoint: oauth2.Endpoint{
+				AuthURL:   region.Auth + "/as/authorization.oauth2",
+				TokenURL:  region.Auth + "/as/token.oauth2",
+				AuthStyle: oauth2.AuthStyleInParams,
+			},
+			Scopes: []string{"openid", "profile", "email", "customer:attributes"},
+		},
+	}
+}
+
+// Login restores the persisted token or logs in using the credentials
+func (v *Identity) Login(user, password string) error {
+	v.user, v.password = user, password
+	v.key = tokenKey(user)
+	v.token = v.restoreToken()
+
+	_, err := v.Token()
+	return err
+}
+
+// Token implements the oauth2.TokenSource interface
+func (v *Identity) Token() (*oauth2.Token, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if v.token.Valid() {
+		return v.token, nil
+	}
+
+	var token *oauth2.Token
+	if v.token != nil && v.token.RefreshToken != "" {
+		var err error
+		if token, err = v.refresh(v.token); err != nil {
+			v.log.DEBUG.Printf("token refresh: %v, login required", err)
+		}
+	}
+
+	if token == nil {
+		var err error
+		if token, err = v.login(); err != nil {
+			return nil, err
+		}
+	}
+
+	v.token = token
+	v.verified = true
+	v.persistToken(token)
+
+	return token, nil
+}
+
+// Probe verifies that the restored token has not been revoked by refreshing it. Revoked tokens are
+// discarded and require login. Tokens obtained since startup are not probed again.
+func (v *Identity) Probe() error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if v.verified || v.token == nil {
+		return nil
+	}
+
+	token, err := v.refresh(v.token)
+	if err != nil {
+		v.token = nil
+		return fmt.Errorf("%w: %v", api.ErrLoginRequired, err)
+	}
+
+	v.token = token
+	v.verified = true
+	v.persistToken(token)
+
+	return nil
+}
+
+// context returns a context using the identity's http client and its timeouts
+func (v *Identity) context() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(
+		context.WithValue(context.Background(), oauth2.HTTPClient, v.Client),
+		request.Timeout)
+}
+
+func (v *Identity) refresh(token *oauth2.Token) (*oauth2.Token, error) {
+	ctx, cancel := v.context()
+	defer cancel()
+
+	return v.oc.TokenSource(ctx, &oauth2.Token{RefreshToken: token.RefreshToken}).Token()
+}
+
+func (v *Identity) login() (*oauth2.Token, error) {
+	if v.user == "" || v.password == "" {
+		return nil, errors.New("missing credentials")
+	}
+
+	if v.Client.Jar == nil {
+		v.Client.Jar, _ = cookiejar.New(&cookiejar.Options{
+			PublicSuffixList: publicsuffix.List,
+		})
+	}
+	defer func() { v.Client.CheckRedirect = nil }()
+
+	cv := oauth2.GenerateVerifier()
+
+	var param request.InterceptResult
+	v.Client.CheckRedirect, param = request.InterceptRedirect("resumePath", true)
+
+	uri := v.oc.AuthCodeURL(oauth2.GenerateVerifier(), oauth2.S256ChallengeOption(cv))
+	resp, err := v.Get(uri)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+
+	resume, err := param()
+	if err != nil {
+		return nil, err
+	}
+
+	v.Client.CheckRedirect, param = request.InterceptRedirect("code", true)
+
+	data := url.Values{
+		"pf.username": {v.user},
+		"pf.pass":     {v.password},
+	}
+
+	uri = fmt.Sprintf("%s/as/%s/resume/as/authorization.ping?client_id=%s", v.uri, resume, ClientID)
+	if resp, err = v.Post(uri, request.FormContent, strings.NewReader(data.Encode())); err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+
+	code, err := param()
+	if err != nil {
+		return nil, fmt.Errorf("login failed: %w", err)
+	}
+
+	ctx, cancel := v.context()
+	defer cancel()
+
+	return v.oc.Exchange(ctx, code, oauth2.VerifierOption(cv))
+}
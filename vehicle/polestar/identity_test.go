@@ -0,0 +1,142 @@
+package polestar
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/server/db/settings"
+	"github.com/evcc-io/evcc/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// identityServer emulates the identity provider using the recorded token response
+type identityServer struct {
+	*httptest.Server
+	logins, refreshs int
+	refreshExpired   bool
+}
+
+func newIdentityServer(t *testing.T) *identityServer {
+	token, err := os.ReadFile("samples/token.json")
+	require.NoError(t, err)
+
+	s := new(identityServer)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/as/authorization.oauth2", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, ClientID, r.URL.Query().Get("client_id"))
+		assert.NotEmpty(t, r.URL.Query().Get("code_challenge"))
+		http.Redirect(w, r, "/PolestarLogin/login?resumePath=r3sum3&client_id="+ClientID, http.StatusSeeOther)
+	})
+	mux.HandleFunc("/as/r3sum3/resume/as/authorization.ping", func(w http.ResponseWriter, r *http.Request) {
+		if r.PostFormValue("pf.username") != "user" || r.PostFormValue("pf.pass") != "password" {
+			http.Redirect(w, r, "/PolestarLogin/login?error=invalid", http.StatusSeeOther)
+			return
+		}
+		http.Redirect(w, r, RedirectURI+"?code=c0d3&state=state", http.StatusSeeOther)
+	})
+	mux.HandleFunc("/PolestarLogin/login", func(w http.ResponseWriter, r *http.Request) {})
+	mux.HandleFunc("/as/token.oauth2", func(w http.ResponseWriter, r *http.Request) {
+		switch r.PostFormValue("grant_type") {
+		case "authorization_code":
+			assert.Equal(t, "c0d3", r.PostFormValue("code"))
+			assert.NotEmpty(t, r.PostFormValue("code_verifier"))
+			s.logins++
+		case "refresh_token":
+			s.refreshs++
+			if s.refreshExpired {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte(`{"error":"invalid_grant","error_description":"refresh token expired"}`))
+				return
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(token)
+	})
+
+	s.Server = httptest.NewServer(mux)
+	t.Cleanup(s.Close)
+
+	return s
+}
+
+func TestIdentityLogin(t *testing.T) {
+	srv := newIdentityServer(t)
+	region := Region{Auth: srv.URL, API: srv.URL}
+
+	v := NewIdentity(util.NewLogger("foo"), region)
+	require.NoError(t, v.Login("user", "password"))
+	assert.Equal(t, 1, srv.logins)
+
+	token, err := v.Token()
+	require.NoError(t, err)
+	assert.Equal(t, "nXhEm3t6ocYlvnFtWfVKxMnpmhmSTdyiW8SnbaHCDK", token.RefreshToken)
+
+	// expired token is refreshed
+	v.token.Expiry = time.Now().Add(-time.Minute)
+	_, err = v.Token()
+	require.NoError(t, err)
+	assert.Equal(t, 1, srv.refreshs)
+	assert.Equal(t, 1, srv.logins)
+
+	// expired refresh token requires login
+	srv.refreshExpired = true
+	v.token.Expiry = time.Now().Add(-time.Minute)
+	_, err = v.Token()
+	require.NoError(t, err)
+	assert.Equal(t, 2, srv.refreshs)
+	assert.Equal(t, 2, srv.logins)
+
+	// persisted token is restored
+	v = NewIdentity(util.NewLogger("foo"), region)
+	require.NoError(t, v.Login("user", "password"))
+	assert.Equal(t, 2, srv.logins)
+}
+
+func TestIdentityProbe(t *testing.T) {
+	srv := newIdentityServer(t)
+	region := Region{Auth: srv.URL, API: srv.URL}
+
+	// discard token persisted by other tests
+	settings.SetString(tokenKey("user"), "")
+
+	// fresh login is not probed
+	v := NewIdentity(util.NewLogger("foo"), region)
+	require.NoError(t, v.Login("user", "password"))
+	require.NoError(t, v.Probe())
+	assert.Equal(t, 0, srv.refreshs)
+
+	// restored token is refreshed
+	v = NewIdentity(util.NewLogger("foo"), region)
+	require.NoError(t, v.Login("user", "password"))
+	require.NoError(t, v.Probe())
+	assert.Equal(t, 1, srv.refreshs)
+	assert.Equal(t, 1, srv.logins)
+
+	// revoked token requires login
+	srv.refreshExpired = true
+	v = NewIdentity(util.NewLogger("foo"), region)
+	require.NoError(t, v.Login("user", "password"))
+	assert.ErrorIs(t, v.Probe(), api.ErrLoginRequired)
+	assert.Equal(t, 2, srv.refreshs)
+	assert.Nil(t, v.token)
+
+	// login on next use
+	_, err := v.Token()
+	require.NoError(t, err)
+	assert.Equal(t, 2, srv.logins)
+}
+
+func TestIdentityInvalidCredentials(t *testing.T) {
+	srv := newIdentityServer(t)
+
+	v := NewIdentity(util.NewLogger("foo"), Region{Auth: srv.URL})
+	require.Error(t, v.Login("other", "password"))
+	assert.Equal(t, 0, srv.logins)
+}
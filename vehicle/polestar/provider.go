@@ -0,0 +1,72 @@
+package polestar
+
+import (
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/provider"
+)
+
+// Provider implements the vehicle api
+type Provider struct {
+	statusG func() (BatteryData, error)
+}
+
+// NewProvider creates a vehicle api provider. The vin is resolved on each update to support vehicles pending link.
+func NewProvider(api *API, vin func() (string, error), cache time.Duration) *Provider {
+	impl := &Provider{
+		statusG: provider.Cached(func() (BatteryData, error) {
+			vin, err := vin()
+			if err != nil {
+				return BatteryData{}, err
+			}
+			return api.BatteryData(vin)
+		}, cache),
+	}
+	return impl
+}
+
+// Soc implements the api.Vehicle interface
+func (v *Provider) Soc() (float64, error) {
+	res, err := v.statusG()
+	return res.BatteryChargeLevelPercentage, err
+}
+
+var _ api.ChargeState = (*Provider)(nil)
+
+// Status implements the api.ChargeState interface
+func (v *Provider) Status() (api.ChargeStatus, error) {
+	res, err := v.statusG()
+	if err != nil {
+		return api.StatusNone, err
+	}
+
+	status := api.StatusA // disconnected
+	if res.ChargerConnectionStatus == "CHARGER_CONNECTION_STATUS_CONNECTED" {
+		status = api.StatusB
+	}
+	if res.ChargingStatus == "CHARGING_STATUS_CHARGING" {
+		status = api.StatusC
+	}
+
+	return status, nil
+}
+
+var _ api.VehicleRange = (*Provider)(nil)
+
+// Range implements the api.VehicleRange interface
+func (v *Provider) Range() (int64, error) {
+	res, err := v.statusG()
+	return res.EstimatedDistanceToEmptyKm, err
+}
+
+var _ api.VehicleFinishTimer = (*Provider)(nil)
+
+// FinishTime implements the api.VehicleFinishTimer interface
+func (v *Provider) FinishTime() (time.Time, error) {
+	res, err := v.statusG()
+	if err == nil && res.ChargingStatus != "CHARGING_STATUS_CHARGING" {
+		err = api.ErrNotAvailable
+	}
+	return res.EventUpdatedTimestamp.ISO.Add(time.Duration(res.EstimatedChargingTimeToFullMinutes) * time.Minute), err
+}
@@ -0,0 +1,75 @@
+package polestar
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+)
+
+func TestProvider(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/mystar-v2", r.URL.Path)
+		assert.Equal(t, "Bearer access", r.Header.Get("Authorization"))
+
+		var req struct{ Query string }
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+
+		file := "samples/batterydata.json"
+		if req.Query == carsQuery {
+			file = "samples/cars.json"
+		}
+
+		b, err := os.ReadFile(file)
+		require.NoError(t, err)
+		_, _ = w.Write(b)
+	}))
+	defer srv.Close()
+
+	ts := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "access"})
+	a := NewAPI(util.NewLogger("foo"), srv.URL, ts)
+
+	vins, err := a.Vehicles()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"LPSVSEDEEML000001"}, vins)
+
+	v := NewProvider(a, func() (string, error) {
+		return vins[0], nil
+	}, time.Minute)
+
+	soc, err := v.Soc()
+	require.NoError(t, err)
+	assert.Equal(t, 63.0, soc)
+
+	status, err := v.Status()
+	require.NoError(t, err)
+	assert.Equal(t, api.StatusC, status)
+
+	rng, err := v.Range()
+	require.NoError(t, err)
+	assert.Equal(t, int64(285), rng)
+
+	finish, err := v.FinishTime()
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 3, 12, 19, 49, 5, 0, time.UTC), finish.UTC())
+}
+
+func TestGraphqlError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"errors":[{"message":"Unauthorized"}],"data":null}`))
+	}))
+	defer srv.Close()
+
+	a := NewAPI(util.NewLogger("foo"), srv.URL, oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "access"}))
+
+	_, err := a.BatteryData("vin")
+	assert.EqualError(t, err, "Unauthorized")
+}
@@ -0,0 +1,36 @@
+package polestar
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+
+	"github.com/evcc-io/evcc/server/db/settings"
+	"golang.org/x/oauth2"
+)
+
+// tokenKey is the settings key of the user's persisted token
+func tokenKey(user string) string {
+	return fmt.Sprintf("polestar.token.%x", sha256.Sum256([]byte(user)))
+}
+
+// restoreToken loads the persisted token
+func (v *Identity) restoreToken() *oauth2.Token {
+	var token oauth2.Token
+	if err := settings.Json(v.key, &token); err != nil || token.RefreshToken == "" {
+		return nil
+	}
+
+	return &token
+}
+
+// persistToken stores the token
+func (v *Identity) persistToken(token *oauth2.Token) {
+	b, err := json.Marshal(token)
+	if err != nil {
+		v.log.ERROR.Printf("persist token: %v", err)
+		return
+	}
+
+	settings.SetString(v.key, string(b))
+}
@@ -0,0 +1,26 @@
+package polestar
+
+import "time"
+
+// Vehicle is a consumer car
+type Vehicle struct {
+	VIN                       string
+	InternalVehicleIdentifier string
+}
+
+// BatteryData is the battery status
+type BatteryData struct {
+	BatteryChargeLevelPercentage       float64
+	ChargerConnectionStatus            string
+	ChargingStatus                     string
+	EstimatedDistanceToEmptyKm         int64
+	EstimatedChargingTimeToFullMinutes int64
+	EventUpdatedTimestamp              struct {
+		ISO time.Time
+	}
+}
+
+// graphqlError is the error returned by the graphql api
+type graphqlError struct {
+	Message string
+}
@@ -0,0 +1,323 @@
+package vehicle
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/evcc-io/evcc/provider"
+	"github.com/evcc-io/evcc/provider/pipeline"
+	"github.com/evcc-io/evcc/util"
+	"github.com/evcc-io/evcc/util/request"
+	"github.com/evcc-io/evcc/util/transport"
+	"golang.org/x/oauth2"
+)
+
+// Rest is an api.Vehicle implementation reading vehicle data from a json REST api.
+// Values are extracted using jq, responses are cached per request path.
+type Rest struct {
+	*embed
+	*request.Helper
+	log     *util.Logger
+	uri     string
+	headers map[string]string
+	cache   time.Duration
+	dataG   map[string]func() ([]byte, error)
+	socG    func() (float64, error)
+}
+
+// restValue is a value extracted from an api response
+type restValue struct {
+	Path string // request path relative to the api uri
+	Jq   string // value extraction
+}
+
+// restLogin requests an access token used as bearer authorization for all api requests
+type restLogin struct {
+	URI, Method string
+	Headers     map[string]string
+	Body        string
+	Jq          string        // access token extraction
+	Expiry      time.Duration // access token lifetime
+}
+
+func init() {
+	registry.Add("rest", NewRestFromConfig)
+}
+
+// secretHeaderValues returns the values of headers holding credentials like api keys or authorization
+func secretHeaderValues(headers map[string]string) []string {
+	var res []string
+	for k, v := range headers {
+		if util.IsSecretKey(k) {
+			res = append(res, v)
+		}
+	}
+	return res
+}
+
+// NewRestFromConfig creates a new vehicle
+func NewRestFromConfig(other map[string]interface{}) (api.Vehicle, error) {
+	cc := struct {
+		embed    `mapstructure:",squash"`
+		URI      string
+		Headers  map[string]string
+		Auth     provider.Auth
+		Login    *restLogin
+		Soc      restValue
+		Status   *restValue
+		Range    *restValue
+		Odometer *restValue
+		Climater *restValue
+		Timeout  time.Duration
+		Cache    time.Duration
+	}{
+		Timeout: request.Timeout,
+		Cache:   interval,
+	}
+
+	if err := util.DecodeOther(other, &cc); err != nil {
+		return nil, err
+	}
+
+	if cc.URI == "" {
+		return nil, errors.New("missing uri")
+	}
+
+	log := util.NewLogger("rest").Redact(cc.Auth.User, cc.Auth.Password)
+	log.Redact(secretHeaderValues(cc.Headers)...)
+	if cc.Login != nil {
+		log.Redact(secretHeaderValues(cc.Login.Headers)...)
+	}
+
+	v := &Rest{
+		embed:   &cc.embed,
+		Helper:  request.NewHelper(log),
+		log:     log,
+		uri:     strings.TrimSuffix(util.DefaultScheme(cc.URI, "https"), "/"),
+		headers: cc.Headers,
+		cache:   cc.Cache,
+		dataG:   make(map[string]func() ([]byte, error)),
+	}
+
+	v.Client.Timeout = cc.Timeout
+
+	switch strings.ToLower(cc.Auth.Type) {
+	case "":
+	case "basic":
+		v.Client.Transport = transport.BasicAuth(cc.Auth.User, cc.Auth.Password, v.Client.Transport)
+	case "bearer":
+		log.Redact(cc.Auth.Password)
+		v.Client.Transport = transport.BearerAuth(cc.Auth.Password, v.Client.Transport)
+	default:
+		return nil, fmt.Errorf("unknown auth type '%s'", cc.Auth.Type)
+	}
+
+	if cc.Login != nil {
+		ts, err := v.loginTokenSource(*cc.Login)
+		if err != nil {
+			return nil, fmt.Errorf("login: %w", err)
+		}
+
+		v.Client.Transport = &oauth2.Transport{
+			Source: ts,
+			Base:   v.Client.Transport,
+		}
+	}
+
+	socG, err := v.stringGetter(cc.Soc)
+	if err != nil {
+		return nil, fmt.Errorf("soc: %w", err)
+	}
+	v.socG = floatGetter(socG)
+
+	// decorate status
+	var status func() (api.ChargeStatus, error)
+	if cc.Status != nil {
+		statusG, err := v.stringGetter(*cc.Status)
+		if err != nil {
+			return nil, fmt.Errorf("status: %w", err)
+		}
+		status = func() (api.ChargeStatus, error) {
+			s, err := statusG()
+			if err != nil {
+				return api.StatusNone, err
+			}
+			return api.ChargeStatusString(s)
+		}
+	}
+
+	// decorate range
+	var rng func() (int64, error)
+	if cc.Range != nil {
+		rangeG, err := v.stringGetter(*cc.Range)
+		if err != nil {
+			return nil, fmt.Errorf("range: %w", err)
+		}
+		f := floatGetter(rangeG)
+		rng = func() (int64, error) {
+			res, err := f()
+			return int64(res), err
+		}
+	}
+
+	// decorate odometer
+	var odo func() (float64, error)
+	if cc.Odometer != nil {
+		odoG, err := v.stringGetter(*cc.Odometer)
+		if err != nil {
+			return nil, fmt.Errorf("odometer: %w", err)
+		}
+		odo = floatGetter(odoG)
+	}
+
+	// decorate climater
+	var climater func() (bool, error)
+	if cc.Climater != nil {
+		climateG, err := v.stringGetter(*cc.Climater)
+		if err != nil {
+			return nil, fmt.Errorf("climater: %w", err)
+		}
+		climater = func() (bool, error) {
+			s, err := climateG()
+			return util.Truish(s), err
+		}
+	}
+
+	return decorateVehicle(v, status, rng, odo, climater, nil), nil
+}
+
+// loginTokenSource creates a token source requesting a new access token once the previous one has expired
+func (v *Rest) loginTokenSource(login restLogin) (oauth2.TokenSource, error) {
+	if login.URI == "" {
+		return nil, errors.New("missing uri")
+	}
+
+	pipe, err := pipeline.New(v.log, pipeline.Settings{Jq: login.Jq})
+	if err != nil {
+		return nil, err
+	}
+
+	if login.Expiry == 0 {
+		login.Expiry = time.Hour
+	}
+
+	// login uses the plain client without authorization
+	client := request.NewHelper(v.log)
+	client.Client.Timeout = v.Client.Timeout
+
+	ts := tokenSourceFunc(func() (*oauth2.Token, error) {
+		var body io.Reader
+		if login.Body != "" {
+			body = strings.NewReader(login.Body)
+		}
+
+		method := strings.ToUpper(login.Method)
+		if method == "" {
+			method = http.MethodPost
+		}
+
+		req, err := request.New(method, login.URI, body, login.Headers)
+		if err != nil {
+			return nil, err
+		}
+
+		b, err := client.DoBody(req)
+		if err == nil {
+			b, err = pipe.Process(b)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		token := strings.TrimSpace(string(b))
+		if token == "" {
+			return nil, errors.New("missing access token")
+		}
+		v.log.Redact(token)
+
+		return &oauth2.Token{
+			AccessToken: token,
+			TokenType:   "Bearer",
+			Expiry:      time.Now().Add(login.Expiry),
+		}, nil
+	})
+
+	return oauth2.ReuseTokenSource(nil, ts), nil
+}
+
+// tokenSourceFunc is an adapter to use a function as oauth2.TokenSource
+type tokenSourceFunc func() (*oauth2.Token, error)
+
+func (f tokenSourceFunc) Token() (*oauth2.Token, error) {
+	return f()
+}
+
+// data returns the cached response getter of the request path
+func (v *Rest) data(path string) func() ([]byte, error) {
+	path = strings.TrimPrefix(path, "/")
+
+	if g, ok := v.dataG[path]; ok {
+		return g
+	}
+
+	uri := v.uri
+	if path != "" {
+		uri += "/" + path
+	}
+
+	g := provider.Cached(func() ([]byte, error) {
+		req, err := request.New(http.MethodGet, uri, nil, request.AcceptJSON, v.headers)
+		if err != nil {
+			return nil, err
+		}
+		return v.DoBody(req)
+	}, v.cache)
+
+	v.dataG[path] = g
+
+	return g
+}
+
+// stringGetter creates a getter extracting the value from the api response
+func (v *Rest) stringGetter(val restValue) (func() (string, error), error) {
+	if val.Jq == "" {
+		return nil, errors.New("missing jq")
+	}
+
+	pipe, err := pipeline.New(v.log, pipeline.Settings{Jq: val.Jq})
+	if err != nil {
+		return nil, err
+	}
+
+	dataG := v.data(val.Path)
+
+	return func() (string, error) {
+		b, err := dataG()
+		if err == nil {
+			b, err = pipe.Process(b)
+		}
+		return string(b), err
+	}, nil
+}
+
+// floatGetter parses the string value as float
+func floatGetter(g func() (string, error)) func() (float64, error) {
+	return func() (float64, error) {
+		s, err := g()
+		if err != nil {
+			return 0, err
+		}
+		return strconv.ParseFloat(s, 64)
+	}
+}
+
+// Soc implements the api.Vehicle interface
+func (v *Rest) Soc() (float64, error) {
+	return v.socG()
+}
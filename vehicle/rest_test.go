@@ -0,0 +1,94 @@
+package vehicle
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/evcc-io/evcc/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRest(t *testing.T) {
+	var logins, requests int
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/login", func(w http.ResponseWriter, r *http.Request) {
+		logins++
+		_, _ = w.Write([]byte(`{"access_token":"secret"}`))
+	})
+	mux.HandleFunc("/api/car/status", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		requests++
+		_, _ = w.Write([]byte(`{"battery":{"soc":64.5,"range":251},"charging":"C"}`))
+	})
+	mux.HandleFunc("/api/car/odometer", func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		_, _ = w.Write([]byte(`{"km":14237}`))
+	})
+
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	v, err := NewRestFromConfig(map[string]interface{}{
+		"uri": srv.URL + "/api/car/",
+		"login": map[string]interface{}{
+			"uri": srv.URL + "/login",
+			"jq":  ".access_token",
+		},
+		"soc":      map[string]interface{}{"path": "status", "jq": ".battery.soc"},
+		"range":    map[string]interface{}{"path": "/status", "jq": ".battery.range"},
+		"status":   map[string]interface{}{"path": "status", "jq": ".charging"},
+		"odometer": map[string]interface{}{"path": "odometer", "jq": ".km"},
+	})
+	require.NoError(t, err)
+
+	soc, err := v.Soc()
+	require.NoError(t, err)
+	assert.Equal(t, 64.5, soc)
+
+	rng, err := v.(api.VehicleRange).Range()
+	require.NoError(t, err)
+	assert.Equal(t, int64(251), rng)
+
+	status, err := v.(api.ChargeState).Status()
+	require.NoError(t, err)
+	assert.Equal(t, api.StatusC, status)
+
+	odo, err := v.(api.VehicleOdometer).Odometer()
+	require.NoError(t, err)
+	assert.Equal(t, 14237.0, odo)
+
+	_, ok := v.(api.VehicleClimater)
+	assert.False(t, ok)
+
+	// token is reused and responses are cached per path
+	assert.Equal(t, 1, logins)
+	assert.Equal(t, 2, requests)
+}
+
+func TestRestConfigErrors(t *testing.T) {
+	for _, other := range []map[string]interface{}{
+		{"soc": map[string]interface{}{"jq": ".soc"}},
+		{"uri": "http://localhost"},
+		{"uri": "http://localhost", "soc": map[string]interface{}{"jq": ".["}},
+		{"uri": "http://localhost", "soc": map[string]interface{}{"jq": ".soc"}, "auth": map[string]interface{}{"type": "foo"}},
+		{"uri": "http://localhost", "soc": map[string]interface{}{"jq": ".soc"}, "login": map[string]interface{}{"jq": ".token"}},
+	} {
+		_, err := NewRestFromConfig(other)
+		assert.Error(t, err, other)
+	}
+}
+
+func TestRestSecretHeaders(t *testing.T) {
+	res := secretHeaderValues(map[string]string{
+		"Authorization": "Bearer secret",
+		"X-Api-Key":     "key",
+		"Accept":        "application/json",
+	})
+	assert.ElementsMatch(t, []string{"Bearer secret", "key"}, res)
+}
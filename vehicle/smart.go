@@ -25,7 +25,9 @@ func NewSmartFromConfig(other map[string]interface{}) (api.Vehicle, error) {
 	cc := struct {
 		embed          `mapstructure:",squash"`
 		User, Password string
+		Account        string // tells apart multiple accounts using app tokens only
 		VIN            string
+		Tokens         Tokens
 		Expiry         time.Duration
 		Cache          time.Duration
 	}{
@@ -37,18 +39,18 @@ func NewSmartFromConfig(other map[string]interface{}) (api.Vehicle, error) {
 		return nil, err
 	}
 
-	if cc.User == "" || cc.Password == "" {
+	// app token bypasses the password login
+	if cc.Tokens.Refresh == "" && (cc.User == "" || cc.Password == "") {
 		return nil, api.ErrMissingCredentials
 	}
 
-	log := util.NewLogger("smart").Redact(cc.User, cc.Password, cc.VIN)
+	log := util.NewLogger("smart").Redact(cc.User, cc.Password, cc.VIN, cc.Tokens.Refresh)
 
 	v := &Smart{
 		embed: &cc.embed,
 	}
 
-	identity := mb.NewIdentity(log, smart.OAuth2Config)
-	err := identity.Login(cc.User, cc.Password)
+	identity, err := mb.Shared(mb.NewIdentity(log, smart.OAuth2Config).WithToken(cc.Tokens.Refresh).WithAccount(cc.Account), cc.User, cc.Password)
 	if err != nil {
 		return v, fmt.Errorf("login failed: %w", err)
 	}
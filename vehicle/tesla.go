@@ -195,6 +195,24 @@ func (v *Tesla) TargetSoc() (float64, error) {
 	return float64(res.Response.ChargeState.ChargeLimitSoc), nil
 }
 
+var _ api.SocLimitController = (*Tesla)(nil)
+
+// SetTargetSoc implements the api.SocLimitController interface
+func (v *Tesla) SetTargetSoc(soc int) error {
+	return v.apiError(v.vehicle.SetChargeLimit(soc))
+}
+
+var _ api.ChargeCurrentLimitReporter = (*Tesla)(nil)
+
+// ChargeCurrentLimit implements the api.ChargeCurrentLimitReporter interface
+func (v *Tesla) ChargeCurrentLimit() (float64, error) {
+	res, err := v.dataG()
+	if err != nil {
+		return 0, err
+	}
+	return float64(res.Response.ChargeState.ChargeCurrentRequestMax), nil
+}
+
 var _ api.CurrentLimiter = (*Tesla)(nil)
 
 // StartCharge implements the api.VehicleChargeController interface
@@ -0,0 +1,80 @@
+package loginapps
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/evcc-io/evcc/server/db/settings"
+	"golang.org/x/oauth2"
+)
+
+// TokenKey is the settings key of the user's persisted token
+func TokenKey(user string) string {
+	return fmt.Sprintf("loginapps.token.%x", sha256.Sum256([]byte(user)))
+}
+
+// RestoreToken loads the persisted token
+func RestoreToken(key string) (*Token, error) {
+	s, err := settings.String(key)
+	if err != nil {
+		return nil, err
+	}
+
+	var token oauth2.Token
+	if err := json.Unmarshal([]byte(s), &token); err != nil {
+		return nil, err
+	}
+
+	if token.RefreshToken == "" {
+		return nil, errors.New("missing refresh token")
+	}
+
+	return (*Token)(&token), nil
+}
+
+// persistToken stores the token
+func persistToken(key string, token *oauth2.Token) error {
+	b, err := json.Marshal(token)
+	if err == nil {
+		settings.SetString(key, string(b))
+	}
+	return err
+}
+
+// persistingTokenSource persists each new token
+type persistingTokenSource struct {
+	mu     sync.Mutex
+	ts     oauth2.TokenSource
+	key    string
+	access string
+}
+
+func (ts *persistingTokenSource) Token() (*oauth2.Token, error) {
+	token, err := ts.ts.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	if token.AccessToken != ts.access {
+		if err := persistToken(ts.key, token); err != nil {
+			return nil, err
+		}
+		ts.access = token.AccessToken
+	}
+
+	return token, nil
+}
+
+// PersistingTokenSource creates a refreshing oauth2 token source that persists new tokens using the given key
+func (v *Service) PersistingTokenSource(key string, token *Token) oauth2.TokenSource {
+	return &persistingTokenSource{
+		ts:  v.TokenSource(token),
+		key: key,
+	}
+}
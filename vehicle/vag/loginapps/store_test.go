@@ -0,0 +1,41 @@
+package loginapps
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+)
+
+func TestPersistToken(t *testing.T) {
+	key := TokenKey("user")
+	assert.NotEqual(t, key, TokenKey("other"))
+
+	_, err := RestoreToken(key)
+	assert.Error(t, err)
+
+	// recorded token response
+	var token Token
+	require.NoError(t, json.Unmarshal([]byte(`{"accessToken":"access","refreshToken":"refresh","idToken":"id"}`), &token))
+
+	ts := New(nil).PersistingTokenSource(key, &token)
+	_, err = ts.Token()
+	require.NoError(t, err)
+
+	res, err := RestoreToken(key)
+	require.NoError(t, err)
+	assert.Equal(t, "access", res.AccessToken)
+	assert.Equal(t, "refresh", res.RefreshToken)
+	assert.WithinDuration(t, token.Expiry, res.Expiry, time.Second)
+}
+
+func TestRestoreTokenWithoutRefreshToken(t *testing.T) {
+	key := TokenKey("empty")
+	require.NoError(t, persistToken(key, &oauth2.Token{AccessToken: "access"}))
+
+	_, err := RestoreToken(key)
+	assert.Error(t, err)
+}
@@ -67,10 +67,8 @@ func (v *Identity) Login(user, password string) (oauth2.TokenSource, error) {
 	}
 
 	token := (*oauth2.Token)(&tok)
-	ts := oauth2.ReuseTokenSourceWithExpiry(token, oauth.RefreshTokenSource(token, v), 15*time.Minute)
-	go oauth.Refresh(v.log, token, ts)
 
-	return ts, nil
+	return oauth.NewBackgroundTokenSource(v.log, token, v, 15*time.Minute), nil
 }
 
 func (v *Identity) RefreshToken(token *oauth2.Token) (*oauth2.Token, error) {
@@ -9,6 +9,7 @@ import (
 	"github.com/evcc-io/evcc/vehicle/vag/loginapps"
 	"github.com/evcc-io/evcc/vehicle/vag/vwidentity"
 	"github.com/evcc-io/evcc/vehicle/vw/id"
+	"golang.org/x/oauth2"
 )
 
 // https://github.com/TA2k/ioBroker.vw-connect
@@ -49,18 +50,34 @@ func NewIDFromConfig(other map[string]interface{}) (api.Vehicle, error) {
 
 	log := util.NewLogger("id").Redact(cc.User, cc.Password, cc.VIN)
 
-	q, err := vwidentity.LoginWithAuthURL(log, id.LoginURL, id.AuthParams, cc.User, cc.Password)
-	if err != nil {
-		return nil, err
+	apps := loginapps.New(log)
+	key := loginapps.TokenKey(cc.User)
+
+	// restore persisted token to avoid the multi-step login
+	var ts oauth2.TokenSource
+	token, err := loginapps.RestoreToken(key)
+	if err == nil {
+		ts = apps.PersistingTokenSource(key, token)
+		if _, err = ts.Token(); err != nil {
+			log.DEBUG.Printf("persisted token: %v, login required", err)
+		}
 	}
 
-	apps := loginapps.New(log)
-	token, err := apps.Exchange(q)
 	if err != nil {
-		return nil, err
+		q, err := vwidentity.LoginWithAuthURL(log, id.LoginURL, id.AuthParams, cc.User, cc.Password)
+		if err != nil {
+			return nil, err
+		}
+
+		token, err := apps.Exchange(q)
+		if err != nil {
+			return nil, err
+		}
+
+		ts = apps.PersistingTokenSource(key, token)
 	}
 
-	api := id.NewAPI(log, apps.TokenSource(token))
+	api := id.NewAPI(log, ts)
 	api.Client.Timeout = cc.Timeout
 
 	vehicle, err := ensureVehicleEx(
@@ -3,24 +3,39 @@ package id
 import (
 	"errors"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/benbjohnson/clock"
 	"github.com/evcc-io/evcc/api"
 	"github.com/evcc-io/evcc/provider"
 )
 
+// pendingTimeout is the maximum time for a charge command to be reflected by the vehicle status
+const pendingTimeout = 2 * time.Minute
+
 // Provider is an api.Vehicle implementation for VW ID cars
 type Provider struct {
+	clock   clock.Clock
 	statusG func() (Status, error)
+	reset   func()
 	action  func(action, value string) error
+
+	mu       sync.Mutex
+	pending  time.Time // charge command not yet reflected by status until this time
+	charging bool      // expected charging state of pending command
 }
 
 // NewProvider creates a vehicle api provider
 func NewProvider(api *API, vin string, cache time.Duration) *Provider {
+	statusG := provider.ResettableCached(func() (Status, error) {
+		return api.Status(vin)
+	}, cache)
+
 	impl := &Provider{
-		statusG: provider.Cached(func() (Status, error) {
-			return api.Status(vin)
-		}, cache),
+		clock:   clock.New(),
+		statusG: statusG.Get,
+		reset:   statusG.Reset,
 		action: func(action, value string) error {
 			return api.Action(vin, action, value)
 		},
@@ -28,6 +43,45 @@ func NewProvider(api *API, vin string, cache time.Duration) *Provider {
 	return impl
 }
 
+// expectedCharging returns the charging state of a pending charge command until the vehicle status reflects it.
+// The api is eventually consistent and may report the previous state for some time after a command.
+func (v *Provider) expectedCharging(charging bool) (bool, bool) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if v.pending.IsZero() {
+		return false, false
+	}
+
+	if charging == v.charging || v.clock.Now().After(v.pending) {
+		v.pending = time.Time{}
+		return false, false
+	}
+
+	return v.charging, true
+}
+
+// charge executes the charge command and expects the vehicle status to follow
+func (v *Provider) charge(start bool) error {
+	value := ActionChargeStop
+	if start {
+		value = ActionChargeStart
+	}
+
+	if err := v.action(ActionCharge, value); err != nil {
+		return err
+	}
+
+	v.mu.Lock()
+	v.pending = v.clock.Now().Add(pendingTimeout)
+	v.charging = start
+	v.mu.Unlock()
+
+	v.reset()
+
+	return nil
+}
+
 var _ api.Battery = (*Provider)(nil)
 
 // Soc implements the api.Vehicle interface
@@ -60,7 +114,13 @@ func (v *Provider) Status() (api.ChargeStatus, error) {
 		if res.Charging.PlugStatus.Value.PlugConnectionState == "connected" {
 			status = api.StatusB
 		}
-		if res.Charging.ChargingStatus.Value.ChargingState == "charging" {
+
+		charging := res.Charging.ChargingStatus.Value.ChargingState == "charging"
+		if expected, ok := v.expectedCharging(charging); ok && status != api.StatusA {
+			charging = expected
+		}
+
+		if charging {
 			status = api.StatusC
 		}
 	}
@@ -68,6 +128,64 @@ func (v *Provider) Status() (api.ChargeStatus, error) {
 	return status, err
 }
 
+var _ api.VehicleCableReporter = (*Provider)(nil)
+
+// Cable implements the api.VehicleCableReporter interface
+func (v *Provider) Cable() (api.VehicleCable, error) {
+	var res api.VehicleCable
+
+	status, err := v.statusG()
+	if err == nil && status.Charging == nil {
+		err = api.ErrNotAvailable
+	}
+	if err != nil {
+		return res, err
+	}
+
+	plug := status.Charging.PlugStatus.Value
+	res.Plugged = cableState(plug.PlugConnectionState, "connected", "disconnected")
+	res.Locked = cableState(plug.PlugLockState, "locked", "unlocked")
+
+	if res.Plugged == nil && res.Locked == nil {
+		err = api.ErrNotAvailable
+	}
+
+	return res, err
+}
+
+// cableState maps the reported state to true or false, unknown states are nil
+func cableState(state, on, off string) *bool {
+	var res bool
+	switch state {
+	case on:
+		res = true
+	case off:
+	default:
+		return nil
+	}
+	return &res
+}
+
+var _ api.VehicleTemperatureReporter = (*Provider)(nil)
+
+// Temperatures implements the api.VehicleTemperatureReporter interface. The cabin temperature is not reported.
+func (v *Provider) Temperatures() (api.VehicleTemperatures, error) {
+	var res api.VehicleTemperatures
+
+	status, err := v.statusG()
+	if err == nil && (status.Measurements == nil || status.Measurements.TemperatureOutsideStatus.Value.TemperatureOutsideK == nil) {
+		err = api.ErrNotAvailable
+	}
+	if err != nil {
+		return res, err
+	}
+
+	outside := *status.Measurements.TemperatureOutsideStatus.Value.TemperatureOutsideK - 273.15
+	res.Outside = &outside
+
+	return res, nil
+}
+
 var _ api.VehicleFinishTimer = (*Provider)(nil)
 
 // FinishTime implements the api.VehicleFinishTimer interface
@@ -85,6 +203,38 @@ func (v *Provider) FinishTime() (time.Time, error) {
 	return time.Time{}, err
 }
 
+var _ api.VehicleDepartureTimer = (*Provider)(nil)
+
+// Departure implements the api.VehicleDepartureTimer interface
+func (v *Provider) Departure() (time.Time, error) {
+	res, err := v.statusG()
+	if err == nil && res.ChargingTimers == nil {
+		err = api.ErrNotAvailable
+	}
+
+	if err == nil {
+		return res.ChargingTimers.ChargingTimersStatus.Value.NextDeparture(v.clock.Now())
+	}
+
+	return time.Time{}, err
+}
+
+var _ api.VehicleChargeSchedule = (*Provider)(nil)
+
+// ChargeSchedule implements the api.VehicleChargeSchedule interface
+func (v *Provider) ChargeSchedule() (time.Time, error) {
+	res, err := v.statusG()
+	if err == nil && res.ChargingProfiles == nil {
+		err = api.ErrNotAvailable
+	}
+
+	if err == nil {
+		return res.ChargingProfiles.ChargingProfilesStatus.Value.NextChargeStart(v.clock.Now())
+	}
+
+	return time.Time{}, err
+}
+
 var _ api.VehicleRange = (*Provider)(nil)
 
 // Range implements the api.VehicleRange interface
@@ -164,12 +314,12 @@ var _ api.VehicleChargeController = (*Provider)(nil)
 
 // StartCharge implements the api.VehicleChargeController interface
 func (v *Provider) StartCharge() error {
-	return v.action(ActionCharge, ActionChargeStart)
+	return v.charge(true)
 }
 
 // StopCharge implements the api.VehicleChargeController interface
 func (v *Provider) StopCharge() error {
-	return v.action(ActionCharge, ActionChargeStop)
+	return v.charge(false)
 }
 
 var _ api.Resurrector = (*Provider)(nil)
@@ -0,0 +1,296 @@
+package id
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/evcc-io/evcc/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testProvider creates a provider serving the recorded status using the given charging state
+func testProvider(t *testing.T, clock clock.Clock, state *string, actions *[]string) *Provider {
+	b, err := os.ReadFile("samples/status.json")
+	require.NoError(t, err)
+
+	return &Provider{
+		clock: clock,
+		statusG: func() (Status, error) {
+			var res Status
+			err := json.Unmarshal(b, &res)
+			if err == nil && *state != "" {
+				res.Charging.ChargingStatus.Value.ChargingState = *state
+			}
+			return res, err
+		},
+		reset: func() {},
+		action: func(action, value string) error {
+			*actions = append(*actions, action+"/"+value)
+			return nil
+		},
+	}
+}
+
+func TestStatus(t *testing.T) {
+	var (
+		state   string
+		actions []string
+	)
+
+	v := testProvider(t, clock.NewMock(), &state, &actions)
+
+	soc, err := v.Soc()
+	require.NoError(t, err)
+	assert.Equal(t, 64.0, soc)
+
+	rng, err := v.Range()
+	require.NoError(t, err)
+	assert.Equal(t, int64(251), rng)
+
+	odo, err := v.Odometer()
+	require.NoError(t, err)
+	assert.Equal(t, 14237.0, odo)
+
+	limit, err := v.TargetSoc()
+	require.NoError(t, err)
+	assert.Equal(t, 80.0, limit)
+
+	climate, err := v.Climater()
+	require.NoError(t, err)
+	assert.False(t, climate)
+
+	status, err := v.Status()
+	require.NoError(t, err)
+	assert.Equal(t, api.StatusB, status)
+}
+
+func TestCable(t *testing.T) {
+	var (
+		state   string
+		actions []string
+	)
+
+	v := testProvider(t, clock.NewMock(), &state, &actions)
+
+	res, err := v.Cable()
+	require.NoError(t, err)
+	require.NotNil(t, res.Plugged)
+	require.NotNil(t, res.Locked)
+	assert.True(t, *res.Plugged)
+	assert.True(t, *res.Locked)
+
+	// vehicle not reporting plug status
+	statusG := v.statusG
+	v.statusG = func() (Status, error) {
+		res, err := statusG()
+		res.Charging.PlugStatus.Value.PlugConnectionState = "invalid"
+		res.Charging.PlugStatus.Value.PlugLockState = ""
+		return res, err
+	}
+
+	_, err = v.Cable()
+	assert.ErrorIs(t, err, api.ErrNotAvailable)
+
+	// vehicle not reporting charging status
+	v.statusG = func() (Status, error) {
+		return Status{}, nil
+	}
+
+	_, err = v.Cable()
+	assert.ErrorIs(t, err, api.ErrNotAvailable)
+}
+
+func TestTemperatures(t *testing.T) {
+	var (
+		state   string
+		actions []string
+	)
+
+	v := testProvider(t, clock.NewMock(), &state, &actions)
+
+	res, err := v.Temperatures()
+	require.NoError(t, err)
+	require.NotNil(t, res.Outside)
+	assert.InDelta(t, 3.0, *res.Outside, 1e-9)
+	assert.Nil(t, res.Cabin)
+
+	// vehicle not reporting outside temperature
+	statusG := v.statusG
+	v.statusG = func() (Status, error) {
+		res, err := statusG()
+		res.Measurements.TemperatureOutsideStatus.Value.TemperatureOutsideK = nil
+		return res, err
+	}
+
+	_, err = v.Temperatures()
+	assert.ErrorIs(t, err, api.ErrNotAvailable)
+
+	// vehicle not reporting measurements
+	v.statusG = func() (Status, error) {
+		return Status{}, nil
+	}
+
+	_, err = v.Temperatures()
+	assert.ErrorIs(t, err, api.ErrNotAvailable)
+}
+
+func TestPendingCommand(t *testing.T) {
+	var (
+		state   string
+		actions []string
+	)
+
+	clock := clock.NewMock()
+	v := testProvider(t, clock, &state, &actions)
+
+	// command not yet reflected
+	require.NoError(t, v.StartCharge())
+	assert.Equal(t, []string{"charging/start"}, actions)
+
+	status, err := v.Status()
+	require.NoError(t, err)
+	assert.Equal(t, api.StatusC, status)
+
+	// command reflected
+	state = "charging"
+	status, err = v.Status()
+	require.NoError(t, err)
+	assert.Equal(t, api.StatusC, status)
+
+	// command not yet reflected
+	require.NoError(t, v.StopCharge())
+	status, err = v.Status()
+	require.NoError(t, err)
+	assert.Equal(t, api.StatusB, status)
+
+	// command not reflected within timeout
+	clock.Add(pendingTimeout + time.Second)
+	status, err = v.Status()
+	require.NoError(t, err)
+	assert.Equal(t, api.StatusC, status)
+}
+
+func TestDeparture(t *testing.T) {
+	b, err := os.ReadFile("samples/timers.json")
+	require.NoError(t, err)
+
+	var res Status
+	require.NoError(t, json.Unmarshal(b, &res))
+	require.NotNil(t, res.ChargingTimers)
+
+	timers := res.ChargingTimers.ChargingTimersStatus.Value
+	require.Len(t, timers.Timers, 3)
+
+	// car is in utc+2, monday 23:48 car time
+	now := time.Date(2023, 1, 2, 21, 48, 0, 0, time.UTC)
+
+	tc := []struct {
+		now       time.Time
+		departure time.Time
+	}{
+		// tuesday 07:30 car time, disabled tuesday timer ignored
+		{now, time.Date(2023, 1, 3, 5, 30, 0, 0, time.UTC)},
+		// friday after departure, next is saturday
+		{time.Date(2023, 1, 6, 6, 0, 0, 0, time.UTC), time.Date(2023, 1, 7, 8, 0, 0, 0, time.UTC)},
+		// saturday after departure, next is monday
+		{time.Date(2023, 1, 7, 8, 0, 0, 0, time.UTC), time.Date(2023, 1, 9, 5, 30, 0, 0, time.UTC)},
+	}
+
+	for _, tc := range tc {
+		res, err := timers.NextDeparture(tc.now)
+		require.NoError(t, err)
+		assert.True(t, tc.departure.Equal(res), "expected %v, got %v", tc.departure, res)
+	}
+
+	// evcc in a different time zone than the car
+	loc := time.FixedZone("evcc", -5*3600)
+	departure, err := timers.NextDeparture(now.In(loc))
+	require.NoError(t, err)
+	assert.True(t, departure.Equal(time.Date(2023, 1, 3, 5, 30, 0, 0, time.UTC)))
+
+	// provider
+	clock := clock.NewMock()
+	clock.Set(now)
+
+	v := &Provider{
+		clock: clock,
+		statusG: func() (Status, error) {
+			return res, nil
+		},
+	}
+
+	departure, err = v.Departure()
+	require.NoError(t, err)
+	assert.True(t, departure.Equal(time.Date(2023, 1, 3, 5, 30, 0, 0, time.UTC)))
+
+	// no timers
+	res.ChargingTimers = nil
+	_, err = v.Departure()
+	assert.ErrorIs(t, err, api.ErrNotAvailable)
+}
+
+func TestChargeSchedule(t *testing.T) {
+	b, err := os.ReadFile("samples/profiles.json")
+	require.NoError(t, err)
+
+	var res Status
+	require.NoError(t, json.Unmarshal(b, &res))
+	require.NotNil(t, res.ChargingProfiles)
+
+	profiles := res.ChargingProfiles.ChargingProfilesStatus.Value
+
+	// car is in utc+2, enabled preferred charging time 02:00-06:00 car time
+	tc := []struct {
+		now, start time.Time
+	}{
+		// monday 23:48 car time, car waits until 02:00
+		{time.Date(2023, 1, 2, 21, 48, 0, 0, time.UTC), time.Date(2023, 1, 3, 0, 0, 0, 0, time.UTC)},
+		// within preferred time
+		{time.Date(2023, 1, 3, 1, 0, 0, 0, time.UTC), time.Time{}},
+		// after preferred time, disabled noon window ignored
+		{time.Date(2023, 1, 3, 10, 0, 0, 0, time.UTC), time.Date(2023, 1, 4, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tc := range tc {
+		start, err := profiles.NextChargeStart(tc.now)
+		require.NoError(t, err)
+		assert.True(t, tc.start.Equal(start), "expected %v, got %v", tc.start, start)
+	}
+
+	// window spanning midnight, 22:00-04:00 car time
+	profiles.Profiles[0].PreferredChargingTimes[0].StartTime = "22:00"
+	profiles.Profiles[0].PreferredChargingTimes[0].EndTime = "04:00"
+
+	for _, now := range []time.Time{
+		time.Date(2023, 1, 2, 21, 48, 0, 0, time.UTC), // 23:48 car time
+		time.Date(2023, 1, 3, 1, 0, 0, 0, time.UTC),   // 03:00 car time
+	} {
+		start, err := profiles.NextChargeStart(now)
+		require.NoError(t, err)
+		assert.True(t, start.IsZero(), now)
+	}
+
+	// provider, 22:00 car time window
+	clock := clock.NewMock()
+	clock.Set(time.Date(2023, 1, 3, 10, 0, 0, 0, time.UTC))
+
+	v := &Provider{
+		clock: clock,
+		statusG: func() (Status, error) {
+			return res, nil
+		},
+	}
+
+	start, err := v.ChargeSchedule()
+	require.NoError(t, err)
+	assert.True(t, start.Equal(time.Date(2023, 1, 3, 20, 0, 0, 0, time.UTC)), start)
+
+	// no profiles
+	res.ChargingProfiles = nil
+	_, err = v.ChargeSchedule()
+	assert.ErrorIs(t, err, api.ErrNotAvailable)
+}
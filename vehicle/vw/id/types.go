@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"strings"
 	"time"
+
+	"github.com/evcc-io/evcc/api"
 )
 
 // Vehicles is the /vehicles api
@@ -213,6 +215,12 @@ type Status struct {
 				Odometer             float64   `json:"odometer"`
 			} `json:"value"`
 		} `json:"odometerStatus"`
+		TemperatureOutsideStatus struct {
+			Value struct {
+				CarCapturedTimestamp Timestamp `json:"carCapturedTimestamp"`
+				TemperatureOutsideK  *float64  `json:"temperatureOutside_K"`
+			} `json:"value"`
+		} `json:"temperatureOutsideStatus"`
 	} `json:"measurements"`
 	FuelStatus *FuelStatus `json:"fuelStatus"`
 	Readiness  *struct {
@@ -242,45 +250,12 @@ type Status struct {
 	} `json:"vehicleHealthWarnings"`
 	ChargingTimers *struct {
 		ChargingTimersStatus struct {
-			Value struct {
-				CarCapturedTimestamp Timestamp `json:"carCapturedTimestamp"`
-				TimeInCar            string    `json:"timeInCar"`
-				Timers               []struct {
-					ID             int  `json:"id"`
-					Enabled        bool `json:"enabled"`
-					Climatisation  bool `json:"climatisation"`
-					RecurringTimer struct {
-						DepartureTime  string   `json:"departureTime"`
-						RepetitionDays []string `json:"repetitionDays"`
-					} `json:"recurringTimer"`
-				} `json:"timers"`
-			} `json:"value"`
+			Value ChargingTimers `json:"value"`
 		} `json:"chargingTimersStatus"`
 	} `json:"chargingTimers"`
 	ChargingProfiles *struct {
 		ChargingProfilesStatus struct {
-			Value struct {
-				CarCapturedTimestamp Timestamp `json:"carCapturedTimestamp"`
-				TimeInCar            string    `json:"timeInCar"`
-				Profiles             []struct {
-					ID                 int    `json:"id"`
-					Name               string `json:"name"`
-					MaxChargingCurrent string `json:"maxChargingCurrent"`
-					MinSOCPct          int    `json:"minSOC_pct"`
-					TargetSOCPct       int    `json:"targetSOC_pct"`
-					Options            struct {
-						AutoUnlockPlugWhenCharged string `json:"autoUnlockPlugWhenCharged"`
-					} `json:"options"`
-					PreferredChargingTimes []struct {
-						ID        int    `json:"id"`
-						Enabled   bool   `json:"enabled"`
-						StartTime string `json:"startTime"`
-						EndTime   string `json:"endTime"`
-					} `json:"preferredChargingTimes"`
-					Timers        []interface{} `json:"timers"`
-					MinSOCEnabled bool          `json:"minSOC_enabled"`
-				} `json:"profiles"`
-			} `json:"value"`
+			Value ChargingProfiles `json:"value"`
 		} `json:"chargingProfilesStatus"`
 	} `json:"chargingProfiles"`
 }
@@ -336,3 +311,146 @@ func (ct *Timestamp) UnmarshalJSON(data []byte) error {
 
 	return err
 }
+
+// ChargingProfiles are the charging profiles configured in the car
+type ChargingProfiles struct {
+	CarCapturedTimestamp Timestamp `json:"carCapturedTimestamp"`
+	TimeInCar            string    `json:"timeInCar"` // car local time including utc offset
+	Profiles             []struct {
+		ID                 int    `json:"id"`
+		Name               string `json:"name"`
+		MaxChargingCurrent string `json:"maxChargingCurrent"`
+		MinSOCPct          int    `json:"minSOC_pct"`
+		TargetSOCPct       int    `json:"targetSOC_pct"`
+		Options            struct {
+			AutoUnlockPlugWhenCharged string `json:"autoUnlockPlugWhenCharged"`
+		} `json:"options"`
+		PreferredChargingTimes []struct {
+			ID        int    `json:"id"`
+			Enabled   bool   `json:"enabled"`
+			StartTime string `json:"startTime"` // car local time (hh:mm)
+			EndTime   string `json:"endTime"`   // car local time (hh:mm)
+		} `json:"preferredChargingTimes"`
+		Timers        []interface{} `json:"timers"`
+		MinSOCEnabled bool          `json:"minSOC_enabled"`
+	} `json:"profiles"`
+}
+
+// NextChargeStart returns the start of the next enabled preferred charging time if the given time is outside all of them.
+// Zero time is returned if charging is not restricted to preferred times or the given time is within one of them.
+func (t ChargingProfiles) NextChargeStart(now time.Time) (time.Time, error) {
+	now = now.In(carLocation(t.TimeInCar))
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	var res time.Time
+	for _, p := range t.Profiles {
+		for _, pt := range p.PreferredChargingTimes {
+			if !pt.Enabled {
+				continue
+			}
+
+			start, err := time.Parse("15:04", pt.StartTime)
+			if err != nil {
+				return time.Time{}, fmt.Errorf("invalid start time: %s", pt.StartTime)
+			}
+
+			end, err := time.Parse("15:04", pt.EndTime)
+			if err != nil {
+				return time.Time{}, fmt.Errorf("invalid end time: %s", pt.EndTime)
+			}
+
+			from := midnight.Add(time.Duration(start.Hour())*time.Hour + time.Duration(start.Minute())*time.Minute)
+			to := midnight.Add(time.Duration(end.Hour())*time.Hour + time.Duration(end.Minute())*time.Minute)
+
+			// window spanning midnight
+			if !to.After(from) {
+				if now.Before(to) {
+					return time.Time{}, nil
+				}
+				to = to.AddDate(0, 0, 1)
+			}
+
+			if !now.Before(from) && now.Before(to) {
+				return time.Time{}, nil
+			}
+
+			if !from.After(now) {
+				from = from.AddDate(0, 0, 1)
+			}
+
+			if res.IsZero() || from.Before(res) {
+				res = from
+			}
+		}
+	}
+
+	return res, nil
+}
+
+// ChargingTimers are the departure timers configured in the car
+type ChargingTimers struct {
+	CarCapturedTimestamp Timestamp `json:"carCapturedTimestamp"`
+	TimeInCar            string    `json:"timeInCar"` // car local time including utc offset
+	Timers               []struct {
+		ID             int  `json:"id"`
+		Enabled        bool `json:"enabled"`
+		Climatisation  bool `json:"climatisation"`
+		RecurringTimer struct {
+			DepartureTime  string   `json:"departureTime"`  // car local time (hh:mm)
+			RepetitionDays []string `json:"repetitionDays"` // weekdays (monday..sunday)
+		} `json:"recurringTimer"`
+	} `json:"timers"`
+}
+
+// carLocation returns the car's time zone. Timer times are car local times which may differ from evcc.
+func carLocation(timeInCar string) *time.Location {
+	if ts, err := time.Parse(time.RFC3339, timeInCar); err == nil {
+		_, offset := ts.Zone()
+		return time.FixedZone("car", offset)
+	}
+	return time.Local
+}
+
+// NextDeparture returns the earliest departure of all enabled timers after the given time
+func (t ChargingTimers) NextDeparture(now time.Time) (time.Time, error) {
+	loc := carLocation(t.TimeInCar)
+	now = now.In(loc)
+
+	var res time.Time
+	for _, timer := range t.Timers {
+		if !timer.Enabled {
+			continue
+		}
+
+		hm, err := time.Parse("15:04", timer.RecurringTimer.DepartureTime)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid departure time: %s", timer.RecurringTimer.DepartureTime)
+		}
+
+		for day := 0; day <= 7; day++ {
+			ts := time.Date(now.Year(), now.Month(), now.Day()+day, hm.Hour(), hm.Minute(), 0, 0, loc)
+			if ts.After(now) && repeatsOn(timer.RecurringTimer.RepetitionDays, ts.Weekday()) {
+				if res.IsZero() || ts.Before(res) {
+					res = ts
+				}
+				break
+			}
+		}
+	}
+
+	if res.IsZero() {
+		return time.Time{}, api.ErrNotAvailable
+	}
+
+	return res, nil
+}
+
+// repeatsOn returns true if the weekday is contained in the repetition days
+func repeatsOn(days []string, day time.Weekday) bool {
+	for _, d := range days {
+		if strings.EqualFold(d, day.String()) {
+			return true
+		}
+	}
+	return false
+}